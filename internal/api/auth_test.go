@@ -0,0 +1,197 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/R4yL-dev/pkmc/internal/dto"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_Authorization(t *testing.T) {
+	a := newTestApp(t)
+	s := NewServer(a)
+
+	issue := func(role models.Role) string {
+		_, secret, err := a.Container.TokenService.IssueToken(context.Background(), string(role), role)
+		require.NoError(t, err)
+		return secret
+	}
+	readonly := issue(models.RoleReadOnly)
+	editor := issue(models.RoleEditor)
+	admin := issue(models.RoleAdmin)
+
+	revokedToken, revoked, err := a.Container.TokenService.IssueToken(context.Background(), "old laptop", models.RoleAdmin)
+	require.NoError(t, err)
+	require.NoError(t, a.Container.TokenService.RevokeToken(context.Background(), revokedToken.ID))
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		header   string
+		expected int
+	}{
+		{"spec is public", http.MethodGet, "/openapi.json", "", "", http.StatusOK},
+		{"missing token", http.MethodGet, "/api/v1/items", "", "", http.StatusUnauthorized},
+		{"wrong scheme", http.MethodGet, "/api/v1/items", "", "Basic " + readonly, http.StatusUnauthorized},
+		{"unknown token", http.MethodGet, "/api/v1/items", "", "Bearer pkmc_nope", http.StatusUnauthorized},
+		{"revoked token", http.MethodGet, "/api/v1/items", "", "Bearer " + revoked, http.StatusUnauthorized},
+		{"readonly can read", http.MethodGet, "/api/v1/items", "", "Bearer " + readonly, http.StatusOK},
+		{"readonly cannot write", http.MethodPost, "/api/v1/items", `{"extension_code":"DRI","language_code":"fr","type":"Display"}`, "Bearer " + readonly, http.StatusForbidden},
+		{"editor can write", http.MethodPost, "/api/v1/items", `{"extension_code":"DRI","language_code":"fr","type":"Display"}`, "bearer " + editor, http.StatusCreated},
+		{"editor cannot manage tokens", http.MethodGet, "/api/v1/tokens", "", "Bearer " + editor, http.StatusForbidden},
+		{"readonly cannot undo", http.MethodPost, "/api/v1/undo", `{"last":1}`, "Bearer " + readonly, http.StatusForbidden},
+		{"readonly cannot refresh prices", http.MethodPost, "/api/v1/prices/refresh", `{}`, "Bearer " + readonly, http.StatusForbidden},
+		{"readonly cannot create alerts", http.MethodPost, "/api/v1/alerts", `{}`, "Bearer " + readonly, http.StatusForbidden},
+		{"admin can manage tokens", http.MethodGet, "/api/v1/tokens", "", "Bearer " + admin, http.StatusOK},
+		{"editor cannot manage webhooks", http.MethodGet, "/api/v1/webhooks", "", "Bearer " + editor, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expected, rec.Code, rec.Body.String())
+			if tt.expected == http.StatusUnauthorized {
+				assert.Contains(t, rec.Header().Get("WWW-Authenticate"), "Bearer")
+			}
+		})
+	}
+}
+
+func TestServer_TokenManagement(t *testing.T) {
+	s := NewServer(newTestApp(t), WithoutAuth())
+
+	var issued dto.IssuedToken
+	rec := do(t, s, http.MethodPost, "/api/v1/tokens", `{"name":"phone","role":"editor"}`, &issued)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, "editor", issued.Role)
+	assert.Contains(t, issued.Token, "pkmc_")
+
+	var errBody ErrorBody
+	rec = do(t, s, http.MethodPost, "/api/v1/tokens", `{"name":"phone","role":"owner"}`, &errBody)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, "unknown role 'owner'", errBody.Error.Message)
+
+	rec = do(t, s, http.MethodDelete, "/api/v1/tokens/1", "", nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = do(t, s, http.MethodDelete, "/api/v1/tokens/1", "", &errBody)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "active token 1 not found", errBody.Error.Message)
+}
+
+func TestServer_AuditActor(t *testing.T) {
+	a := newTestApp(t)
+	s := NewServer(a)
+
+	_, editor, err := a.Container.TokenService.IssueToken(context.Background(), "phone", models.RoleEditor)
+	require.NoError(t, err)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+editor)
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send(http.MethodPost, "/api/v1/items", `{"extension_code":"DRI","language_code":"fr","type":"Display","price":180}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	rec = send(http.MethodPatch, "/api/v1/items/1", `{"price":210}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = send(http.MethodGet, "/api/v1/items/1/history", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var changes []dto.AuditChange
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &changes))
+
+	last := changes[len(changes)-1]
+	assert.Equal(t, "update", last.Action)
+	assert.Equal(t, "price", last.Field)
+	assert.Equal(t, "180", *last.Old)
+	assert.Equal(t, "210", *last.New)
+	assert.Equal(t, "token:phone", last.Actor)
+
+	// The full audit trail is reserved to admins
+	rec = send(http.MethodGet, "/api/v1/audit", "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestServer_Undo(t *testing.T) {
+	s := NewServer(newTestApp(t), WithoutAuth())
+
+	rec := do(t, s, http.MethodPost, "/api/v1/items", `{"extension_code":"DRI","language_code":"fr","type":"Display","price":180}`, nil)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	rec = do(t, s, http.MethodPatch, "/api/v1/items/1", `{"price":210}`, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var errBody ErrorBody
+	for _, body := range []string{`{}`, `{"last":1,"operation_id":"abc"}`} {
+		rec = do(t, s, http.MethodPost, "/api/v1/undo", body, &errBody)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+	}
+
+	var changes []dto.AuditChange
+	rec = do(t, s, http.MethodPost, "/api/v1/undo", `{"last":1}`, &changes)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.Len(t, changes, 1)
+	assert.Equal(t, "210", *changes[0].Old)
+	assert.Equal(t, "180", *changes[0].New)
+
+	// Undoing the creation conflicts once the item changed again
+	rec = do(t, s, http.MethodPatch, "/api/v1/items/1", `{"price":250}`, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = do(t, s, http.MethodGet, "/api/v1/items/1/history", "", &changes)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = do(t, s, http.MethodPost, "/api/v1/undo", `{"operation_id":"`+changes[0].OperationID+`"}`, &errBody)
+	assert.Equal(t, http.StatusConflict, rec.Code, rec.Body.String())
+}
+
+func TestServer_Webhooks(t *testing.T) {
+	s := NewServer(newTestApp(t), WithoutAuth())
+
+	var created dto.CreatedWebhook
+	rec := do(t, s, http.MethodPost, "/api/v1/webhooks", `{"url":"http://home/hook","events":["item.created"],"price_threshold":200}`, &created)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, "home", created.Name)
+	assert.Contains(t, created.Secret, "whsec_")
+
+	var errBody ErrorBody
+	rec = do(t, s, http.MethodPost, "/api/v1/webhooks", `{"url":"home","events":["item.created"]}`, &errBody)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, errBody.Error.Message, "invalid webhook URL")
+
+	var webhooks []dto.Webhook
+	rec = do(t, s, http.MethodGet, "/api/v1/webhooks", "", &webhooks)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, webhooks, 1)
+	assert.Equal(t, "item.created", webhooks[0].Events)
+
+	var deliveries []dto.WebhookDelivery
+	rec = do(t, s, http.MethodGet, "/api/v1/webhooks/1/deliveries?status=pending", "", &deliveries)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Empty(t, deliveries)
+
+	rec = do(t, s, http.MethodGet, "/api/v1/webhooks/1/deliveries?status=lost", "", &errBody)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	rec = do(t, s, http.MethodDelete, "/api/v1/webhooks/1", "", nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = do(t, s, http.MethodGet, "/api/v1/webhooks/1/deliveries", "", &errBody)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
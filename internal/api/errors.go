@@ -0,0 +1,161 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+)
+
+// ErrorBody is the JSON body of every error response.
+type ErrorBody struct {
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail describes an error. Code is a stable machine-readable kind
+// of error, such as ITEM_NOT_FOUND, which clients can branch on rather than
+// on the message. Fields lists the invalid fields of a validation failure,
+// and Entries the failed entries of a batch.
+type ErrorDetail struct {
+	Status  int          `json:"status"`
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Fields  []FieldError `json:"fields,omitempty"`
+	Entries []EntryError `json:"entries,omitempty"`
+}
+
+// EntryError describes why the entry at Index of a batch failed.
+type EntryError struct {
+	Index   int          `json:"index"`
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Fields  []FieldError `json:"fields,omitempty"`
+}
+
+// FieldError describes an invalid field of a request: Rule is the rule it
+// breaks, such as required or max_length.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// requestError reports a malformed request: bad JSON, query or path value.
+type requestError struct {
+	msg string
+}
+
+func (e *requestError) Error() string {
+	return e.msg
+}
+
+func (e *requestError) ErrorCode() customErr.Code {
+	return customErr.CodeBadRequest
+}
+
+func newRequestError(format string, args ...interface{}) error {
+	return &requestError{msg: fmt.Sprintf(format, args...)}
+}
+
+// statusCodes maps the families of error codes to HTTP status codes.
+var statusCodes = map[customErr.Code]int{
+	customErr.CodeBadRequest:       http.StatusBadRequest,
+	customErr.CodeInvalidToken:     http.StatusUnauthorized,
+	customErr.CodePermissionDenied: http.StatusForbidden,
+	customErr.CodeNotFound:         http.StatusNotFound,
+	customErr.CodeValidationFailed: http.StatusUnprocessableEntity,
+	customErr.CodeConflict:         http.StatusConflict,
+	customErr.CodeTimeout:          http.StatusGatewayTimeout,
+	customErr.CodeCanceled:         http.StatusServiceUnavailable,
+	customErr.CodeUnavailable:      http.StatusServiceUnavailable,
+}
+
+// statusCode maps an error from the internal/errors hierarchy to an HTTP
+// status code, after the family of its code.
+func statusCode(err error) int {
+	if status, ok := statusCodes[customErr.CodeOf(err).Family()]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// errorMessage returns a message safe to show to API clients: the error
+// itself for request errors, the invalid fields for validation errors, the
+// service's own message when there is one, the violated constraint for
+// constraint errors, and the status text otherwise.
+func errorMessage(err error, status int) string {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		return reqErr.msg
+	}
+
+	if status >= http.StatusInternalServerError {
+		return http.StatusText(status)
+	}
+
+	var validationErr *customErr.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.Error()
+	}
+
+	var svcErr *customErr.ServiceError
+	if errors.As(err, &svcErr) && svcErr.Message != "" {
+		return svcErr.Message
+	}
+	var constraintErr *customErr.ConstraintError
+	if errors.As(err, &constraintErr) {
+		return constraintErr.Error()
+	}
+	return http.StatusText(status)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := statusCode(err)
+	writeJSON(w, status, ErrorBody{Error: ErrorDetail{
+		Status:  status,
+		Code:    string(customErr.CodeOf(err)),
+		Message: errorMessage(err, status),
+		Fields:  fieldErrors(err),
+		Entries: entryErrors(err),
+	}})
+}
+
+// entryErrors returns the failed entries of a batch reported by err, if
+// any.
+func entryErrors(err error) []EntryError {
+	var batchErr *customErr.BatchError
+	if !errors.As(err, &batchErr) {
+		return nil
+	}
+	entries := make([]EntryError, len(batchErr.Entries))
+	for i, entry := range batchErr.Entries {
+		entries[i] = EntryError{
+			Index:   entry.Index,
+			Code:    string(customErr.CodeOf(entry.Err)),
+			Message: errorMessage(entry.Err, statusCode(entry.Err)),
+			Fields:  fieldErrors(entry.Err),
+		}
+	}
+	return entries
+}
+
+// fieldErrors returns the invalid fields reported by err, if any.
+func fieldErrors(err error) []FieldError {
+	var validationErr *customErr.ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil
+	}
+	fields := make([]FieldError, len(validationErr.Fields))
+	for i, field := range validationErr.Fields {
+		fields[i] = FieldError{Field: field.Field, Rule: field.Rule, Message: field.Message}
+	}
+	return fields
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
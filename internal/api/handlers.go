@@ -0,0 +1,725 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/R4yL-dev/pkmc/internal/dto"
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+	"github.com/R4yL-dev/pkmc/internal/service"
+)
+
+func (s *Server) listItems(w http.ResponseWriter, r *http.Request) {
+	filter, err := itemFilter(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	items, err := s.app.Container.ItemService.ListItems(ctx, filter)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.FromItems(items))
+}
+
+// DuplicatesHeader lists the IDs of the likely duplicates of a created
+// item, separated by commas. For a batch, each ID follows the index of its
+// entry, as in "0=12,2=12".
+const DuplicatesHeader = "X-Pkmc-Duplicates"
+
+// IdempotencyKeyHeader carries the key making an item creation idempotent,
+// and IdempotentReplayedHeader marks the response replayed for a key
+// already used.
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+func duplicateIDs(items []models.Item) string {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = strconv.FormatUint(uint64(item.ID), 10)
+	}
+	return strings.Join(ids, ",")
+}
+
+// batchDuplicateIDs lists the likely duplicates of each entry of a batch
+// as INDEX=ID pairs, separated by commas, or "" when there is none.
+func batchDuplicateIDs(reports []service.DuplicateReport) string {
+	var pairs []string
+	for i, report := range reports {
+		for _, match := range report.Matches {
+			pairs = append(pairs, fmt.Sprintf("%d=%d", i, match.ID))
+		}
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (s *Server) createItem(w http.ResponseWriter, r *http.Request) {
+	var body dto.ItemCreate
+	if err := decodeJSON(w, r, &body); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	var report service.DuplicateReport
+	var replayed bool
+	opts := []service.CreateOption{service.ReportDuplicates(&report), service.ReportReplayed(&replayed)}
+	if key := r.Header.Get(IdempotencyKeyHeader); key != "" {
+		opts = append(opts, service.WithIdempotencyKey(key))
+	}
+	if body.Quantity != nil {
+		opts = append(opts, service.WithQuantity(*body.Quantity))
+	}
+	if body.PurchasedAt != nil {
+		opts = append(opts, service.WithPurchasedAt(*body.PurchasedAt))
+	}
+	if body.DuplicatePolicy != "" {
+		opts = append(opts, service.WithDuplicatePolicy(service.DuplicatePolicy(strings.ToLower(body.DuplicatePolicy))))
+	}
+
+	item, err := s.app.Container.ItemService.CreateItem(ctx, body.ExtensionCode, body.LanguageCode, body.Type, body.Price, opts...)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if len(report.Matches) > 0 {
+		w.Header().Set(DuplicatesHeader, duplicateIDs(report.Matches))
+	}
+	if replayed {
+		w.Header().Set(IdempotentReplayedHeader, "true")
+	}
+	if report.Incremented {
+		writeJSON(w, http.StatusOK, dto.FromItem(item))
+		return
+	}
+	w.Header().Set("Location", fmt.Sprintf("%s/items/%d", BasePath, item.ID))
+	writeJSON(w, http.StatusCreated, dto.FromItem(item))
+}
+
+func (s *Server) createItems(w http.ResponseWriter, r *http.Request) {
+	var body dto.ItemBatchCreate
+	if err := decodeJSON(w, r, &body); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	var reports []service.DuplicateReport
+	var replayed bool
+	opts := []service.CreateOption{service.ReportBatchDuplicates(&reports), service.ReportReplayed(&replayed)}
+	if key := r.Header.Get(IdempotencyKeyHeader); key != "" {
+		opts = append(opts, service.WithIdempotencyKey(key))
+	}
+	if body.DuplicatePolicy != "" {
+		opts = append(opts, service.WithDuplicatePolicy(service.DuplicatePolicy(strings.ToLower(body.DuplicatePolicy))))
+	}
+
+	items, err := s.app.Container.ItemService.CreateItems(ctx, body.Specs(), opts...)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if ids := batchDuplicateIDs(reports); ids != "" {
+		w.Header().Set(DuplicatesHeader, ids)
+	}
+	if replayed {
+		w.Header().Set(IdempotentReplayedHeader, "true")
+	}
+	writeJSON(w, http.StatusCreated, dto.FromItems(items))
+}
+
+func (s *Server) getItem(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	item, err := s.app.Container.ItemService.GetItem(ctx, id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.FromItem(item))
+}
+
+func (s *Server) updateItem(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var body dto.ItemPatch
+	if err := decodeJSON(w, r, &body); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	update := service.ItemUpdate{
+		ExtensionCode: body.ExtensionCode,
+		LanguageCode:  body.LanguageCode,
+		TypeName:      body.Type,
+	}
+	if body.Price.Set {
+		update.Price = body.Price.Value
+		update.ClearPrice = body.Price.Value == nil
+	}
+	update.Quantity = body.Quantity
+	update.PurchasedAt = body.PurchasedAt
+	if update == (service.ItemUpdate{}) {
+		writeError(w, newRequestError("nothing to update"))
+		return
+	}
+
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	item, err := s.app.Container.ItemService.UpdateItem(ctx, id, update)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.FromItem(item))
+}
+
+func (s *Server) deleteItem(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	if err := s.app.Container.ItemService.DeleteItem(ctx, id); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) sellItem(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var body dto.ItemSale
+	if err := decodeJSON(w, r, &body); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	item, err := s.app.Container.ItemService.SellItem(ctx, id, body.Price, body.SoldAt)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.FromItem(item))
+}
+
+func (s *Server) listExtensions(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	exts, err := s.app.Container.CatalogService.ListExtensions(ctx, r.URL.Query().Get("block"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.FromExtensions(exts))
+}
+
+func (s *Server) getExtension(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	ext, err := s.app.Container.CatalogService.GetExtension(ctx, r.PathValue("code"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.FromExtension(ext))
+}
+
+func (s *Server) listBlocks(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	blocks, err := s.app.Container.CatalogService.ListBlocks(ctx)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.FromBlocks(blocks))
+}
+
+func (s *Server) listLanguages(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	langs, err := s.app.Container.CatalogService.ListLanguages(ctx)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.FromLanguages(langs))
+}
+
+func (s *Server) listItemTypes(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	itemTypes, err := s.app.Container.CatalogService.ListItemTypes(ctx)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.FromItemTypes(itemTypes))
+}
+
+func (s *Server) stats(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	stats, err := s.app.Container.StatsService.CollectionStats(ctx)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.FromStats(stats))
+}
+
+func (s *Server) itemHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	entries, err := s.app.Container.AuditService.ItemHistory(ctx, id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.FromAuditEntries(entries))
+}
+
+func (s *Server) listChanges(w http.ResponseWriter, r *http.Request) {
+	filter, err := auditFilter(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	entries, err := s.app.Container.AuditService.ListChanges(ctx, filter)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.FromAuditEntries(entries))
+}
+
+func (s *Server) undo(w http.ResponseWriter, r *http.Request) {
+	var body dto.UndoRequest
+	if err := decodeJSON(w, r, &body); err != nil {
+		writeError(w, err)
+		return
+	}
+	if (body.Last == 0) == (body.OperationID == "") {
+		writeError(w, newRequestError("exactly one of last and operation_id is required"))
+		return
+	}
+
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	var entries []models.AuditEntry
+	var err error
+	if body.OperationID != "" {
+		entries, err = s.app.Container.AuditService.UndoOperation(ctx, body.OperationID)
+	} else {
+		entries, err = s.app.Container.AuditService.UndoLast(ctx, body.Last)
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.FromAuditEntries(entries))
+}
+
+func (s *Server) itemPriceHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	limit, err := queryInt(r.URL.Query().Get("limit"), "limit")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	records, err := s.app.Container.PriceService.PriceHistory(ctx, id, limit)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.FromPriceRecords(records))
+}
+
+func (s *Server) refreshPrices(w http.ResponseWriter, r *http.Request) {
+	var body dto.PriceRefreshRequest
+	if err := decodeJSON(w, r, &body); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	refreshes, err := s.app.Container.PriceService.RefreshPrices(ctx, body.Provider)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.FromPriceRefreshes(refreshes))
+}
+
+func (s *Server) listTokens(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	tokens, err := s.app.Container.TokenService.ListTokens(ctx)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.FromAPITokens(tokens))
+}
+
+func (s *Server) issueToken(w http.ResponseWriter, r *http.Request) {
+	var body dto.TokenCreate
+	if err := decodeJSON(w, r, &body); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	token, secret, err := s.app.Container.TokenService.IssueToken(ctx, body.Name, models.Role(body.Role))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, dto.FromIssuedToken(token, secret))
+}
+
+func (s *Server) revokeToken(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	if err := s.app.Container.TokenService.RevokeToken(ctx, id); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listAlerts(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	rules, err := s.app.Container.AlertService.ListRules(ctx)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.FromAlertRules(rules))
+}
+
+func (s *Server) createAlert(w http.ResponseWriter, r *http.Request) {
+	var body dto.AlertRuleCreate
+	if err := decodeJSON(w, r, &body); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	rule, err := s.app.Container.AlertService.CreateRule(ctx, service.AlertRuleInput{
+		Name:          body.Name,
+		ItemID:        body.ItemID,
+		ExtensionCode: body.ExtensionCode,
+		ItemType:      body.Type,
+		LanguageCode:  body.LanguageCode,
+		Condition:     body.Condition,
+		Threshold:     body.Threshold,
+		Window:        body.Window,
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, dto.FromAlertRule(rule))
+}
+
+func (s *Server) deleteAlert(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	if err := s.app.Container.AlertService.DeleteRule(ctx, id); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listWebhooks(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	webhooks, err := s.app.Container.WebhookService.ListWebhooks(ctx)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.FromWebhooks(webhooks))
+}
+
+func (s *Server) createWebhook(w http.ResponseWriter, r *http.Request) {
+	var body dto.WebhookCreate
+	if err := decodeJSON(w, r, &body); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	webhook, err := s.app.Container.WebhookService.CreateWebhook(ctx, service.WebhookInput{
+		Name:           body.Name,
+		URL:            body.URL,
+		Events:         body.Events,
+		Secret:         body.Secret,
+		PriceThreshold: body.PriceThreshold,
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, dto.FromCreatedWebhook(webhook))
+}
+
+func (s *Server) deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	if err := s.app.Container.WebhookService.DeleteWebhook(ctx, id); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	limit, err := queryInt(r.URL.Query().Get("limit"), "limit")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ctx, cancel := s.operationContext(r)
+	defer cancel()
+
+	deliveries, err := s.app.Container.WebhookService.ListDeliveries(ctx, repository.WebhookDeliveryFilter{
+		WebhookID: id,
+		Status:    models.DeliveryStatus(r.URL.Query().Get("status")),
+		Limit:     limit,
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.FromWebhookDeliveries(deliveries))
+}
+
+func (s *Server) notFound(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusNotFound, ErrorBody{Error: ErrorDetail{
+		Status:  http.StatusNotFound,
+		Code:    string(customErr.CodeNotFound),
+		Message: fmt.Sprintf("no route for %s %s", r.Method, r.URL.Path),
+	}})
+}
+
+// decodeJSON decodes a single JSON object from the request body, rejecting
+// unknown fields and oversized bodies.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(v); err != nil {
+		if errors.Is(err, io.EOF) {
+			return newRequestError("request body is empty")
+		}
+		return newRequestError("invalid request body: %v", err)
+	}
+	if dec.More() {
+		return newRequestError("request body must contain a single JSON object")
+	}
+	return nil
+}
+
+func pathID(r *http.Request) (uint, error) {
+	raw := r.PathValue("id")
+	id, err := strconv.ParseUint(raw, 10, 0)
+	if err != nil || id == 0 {
+		return 0, newRequestError("invalid ID '%s'", raw)
+	}
+	return uint(id), nil
+}
+
+// itemFilter reads the list filters from the query string. Parameter names
+// match the CLI flags.
+func itemFilter(r *http.Request) (repository.ItemFilter, error) {
+	q := r.URL.Query()
+	filter := repository.ItemFilter{
+		ExtensionCode: q.Get("ext"),
+		BlockCode:     q.Get("block"),
+		LanguageCode:  q.Get("lang"),
+		TypeName:      q.Get("type"),
+	}
+
+	var err error
+	if filter.MinPrice, err = queryFloat(q.Get("min_price"), "min_price"); err != nil {
+		return filter, err
+	}
+	if filter.MaxPrice, err = queryFloat(q.Get("max_price"), "max_price"); err != nil {
+		return filter, err
+	}
+	if filter.Limit, err = queryInt(q.Get("limit"), "limit"); err != nil {
+		return filter, err
+	}
+	if filter.Offset, err = queryInt(q.Get("offset"), "offset"); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+func auditFilter(r *http.Request) (repository.AuditFilter, error) {
+	q := r.URL.Query()
+	filter := repository.AuditFilter{
+		Entity:      q.Get("entity"),
+		OperationID: q.Get("operation"),
+	}
+
+	var err error
+	if filter.Since, err = queryTime(q.Get("since"), "since"); err != nil {
+		return filter, err
+	}
+	if filter.Until, err = queryTime(q.Get("until"), "until"); err != nil {
+		return filter, err
+	}
+	if filter.Limit, err = queryInt(q.Get("limit"), "limit"); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// queryTime accepts RFC 3339 timestamps and dates, which are taken as
+// midnight UTC.
+func queryTime(raw, name string) (*time.Time, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, strings.TrimSpace(raw)); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, newRequestError("invalid %s '%s'", name, raw)
+}
+
+func queryFloat(raw, name string) (*float64, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	if err != nil {
+		return nil, newRequestError("invalid %s '%s'", name, raw)
+	}
+	return &v, nil
+}
+
+func queryInt(raw, name string) (int, error) {
+	if raw == "" {
+		return 0, nil
+	}
+	v, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil || v < 0 {
+		return 0, newRequestError("invalid %s '%s'", name, raw)
+	}
+	return v, nil
+}
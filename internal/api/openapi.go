@@ -0,0 +1,323 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/R4yL-dev/pkmc/internal/dto"
+	"github.com/R4yL-dev/pkmc/internal/models"
+)
+
+const (
+	specTitle          = "pkmc API"
+	specVersion        = "1.0.0"
+	securitySchemeName = "bearerAuth"
+)
+
+// operation describes one route. Every route is registered through
+// Server.handle with its operation, which is what the OpenAPI document is
+// generated from.
+type operation struct {
+	method   string
+	path     string
+	id       string
+	tag      string
+	role     models.Role // minimum token role, empty for public routes
+	summary  string
+	params   []param
+	body     interface{} // zero value of the request body type, nil if none
+	status   int         // success status
+	response interface{} // zero value of the response type, nil if none
+	errors   []int       // expected error statuses besides the default
+}
+
+type param struct {
+	name        string
+	in          string // "path", "query" or "header"
+	kind        string // "string", "integer" or "number"
+	description string
+}
+
+// OpenAPI 3 document types, limited to what the generator uses.
+
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+	Tags       []Tag               `json:"tags,omitempty"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Tag struct {
+	Name string `json:"name"`
+}
+
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme"`
+}
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	nullableFloatType = reflect.TypeOf(dto.NullableFloat{})
+	pathParamPattern  = regexp.MustCompile(`\{([^}]+)\}`)
+)
+
+// buildSpec generates the OpenAPI document for ops.
+func buildSpec(ops []operation) *Document {
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: specTitle, Version: specVersion},
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			Schemas: map[string]*Schema{},
+			SecuritySchemes: map[string]*SecurityScheme{
+				securitySchemeName: {Type: "http", Scheme: "bearer"},
+			},
+		},
+	}
+
+	tags := map[string]bool{}
+	for _, op := range ops {
+		item, ok := doc.Paths[op.path]
+		if !ok {
+			item = PathItem{}
+			doc.Paths[op.path] = item
+		}
+		item[strings.ToLower(op.method)] = doc.operation(op)
+		if op.tag != "" && !tags[op.tag] {
+			tags[op.tag] = true
+			doc.Tags = append(doc.Tags, Tag{Name: op.tag})
+		}
+	}
+	doc.schemaFor(reflect.TypeOf(ErrorBody{}))
+
+	return doc
+}
+
+func (doc *Document) operation(op operation) *Operation {
+	out := &Operation{
+		OperationID: op.id,
+		Summary:     op.summary,
+		Responses:   map[string]Response{},
+	}
+	if op.tag != "" {
+		out.Tags = []string{op.tag}
+	}
+
+	for _, p := range op.params {
+		out.Parameters = append(out.Parameters, Parameter{
+			Name:        p.name,
+			In:          p.in,
+			Description: p.description,
+			Required:    p.in == "path",
+			Schema:      &Schema{Type: p.kind},
+		})
+	}
+
+	if op.body != nil {
+		out.RequestBody = &RequestBody{
+			Required: true,
+			Content:  jsonContent(doc.schemaFor(reflect.TypeOf(op.body))),
+		}
+	}
+
+	success := Response{Description: http.StatusText(op.status)}
+	if op.response != nil {
+		success.Content = jsonContent(doc.schemaFor(reflect.TypeOf(op.response)))
+	}
+	out.Responses[strconv.Itoa(op.status)] = success
+
+	errorRef := &Schema{Ref: "#/components/schemas/ErrorBody"}
+	errorStatuses := op.errors
+	if op.role != "" {
+		out.Description = fmt.Sprintf("Requires a token with the %s role or higher.", op.role)
+		out.Security = []map[string][]string{{securitySchemeName: {}}}
+		errorStatuses = append(append([]int{}, errorStatuses...), http.StatusUnauthorized, http.StatusForbidden)
+	}
+	for _, status := range errorStatuses {
+		out.Responses[strconv.Itoa(status)] = Response{
+			Description: http.StatusText(status),
+			Content:     jsonContent(errorRef),
+		}
+	}
+	out.Responses["default"] = Response{Description: "Unexpected error", Content: jsonContent(errorRef)}
+
+	return out
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+// schemaFor returns the schema of t, registering named structs as
+// components and referring to them.
+func (doc *Document) schemaFor(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == nullableFloatType:
+		return &Schema{Type: "number", Format: "double", Nullable: true}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		s := *doc.schemaFor(t.Elem())
+		if s.Ref != "" {
+			return &s
+		}
+		s.Nullable = true
+		return &s
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: doc.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: doc.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return doc.structSchema(t)
+		}
+		if _, ok := doc.Components.Schemas[t.Name()]; !ok {
+			// Register first so recursive types terminate.
+			doc.Components.Schemas[t.Name()] = &Schema{}
+			doc.Components.Schemas[t.Name()] = doc.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + t.Name()}
+	default:
+		return &Schema{}
+	}
+}
+
+// structSchema describes the JSON encoding of t. Fields that are neither
+// pointers, nullable nor omitempty are required.
+func (doc *Document) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, omitempty := jsonField(field)
+		if name == "-" {
+			continue
+		}
+		s.Properties[name] = doc.schemaFor(field.Type)
+		if !omitempty && field.Type.Kind() != reflect.Ptr && field.Type != nullableFloatType {
+			s.Required = append(s.Required, name)
+		}
+	}
+	sort.Strings(s.Required)
+	return s
+}
+
+func jsonField(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	omitempty := false
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty
+}
+
+// pathParams returns the wildcard names in a route path.
+func pathParams(path string) []string {
+	var names []string
+	for _, m := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+func (s *Server) openAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(s.spec)
+}
+
+func marshalSpec(doc *Document) []byte {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		panic("api: cannot encode OpenAPI document: " + err.Error())
+	}
+	return data
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/R4yL-dev/pkmc/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPI_DescribesEveryRoute(t *testing.T) {
+	s := NewServer(newTestApp(t))
+
+	var doc Document
+	rec := do(t, s, http.MethodGet, "/openapi.json", "", &doc)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+
+	ids := map[string]bool{}
+	for _, op := range s.operations {
+		name := op.method + " " + op.path
+		t.Run(name, func(t *testing.T) {
+			assert.NotEmpty(t, op.id, "operation id")
+			assert.False(t, ids[op.id], "duplicate operation id %s", op.id)
+			ids[op.id] = true
+			assert.NotEmpty(t, op.summary, "summary")
+			assert.NotZero(t, op.status, "success status")
+
+			described, ok := doc.Paths[op.path][strings.ToLower(op.method)]
+			require.True(t, ok, "missing from /openapi.json")
+			assert.Contains(t, described.Responses, strconv.Itoa(op.status))
+			assert.Contains(t, described.Responses, "default")
+
+			for _, name := range pathParams(op.path) {
+				found := false
+				for _, p := range op.params {
+					found = found || (p.in == "path" && p.name == name)
+				}
+				assert.True(t, found, "path parameter {%s} is not described", name)
+			}
+
+			if op.method == http.MethodPost || op.method == http.MethodPatch || op.method == http.MethodPut {
+				assert.NotNil(t, described.RequestBody, "request body")
+			}
+		})
+	}
+}
+
+// TestOpenAPI_RoutesAreRegisteredThroughHandle fails when a handler is
+// registered on the mux directly, bypassing the description in handle.
+func TestOpenAPI_RoutesAreRegisteredThroughHandle(t *testing.T) {
+	paths, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+
+	fset := token.NewFileSet()
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		require.NoError(t, err)
+
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil || fn.Name.Name == "handle" {
+				continue
+			}
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				sel, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok || (sel.Sel.Name != "Handle" && sel.Sel.Name != "HandleFunc") {
+					return true
+				}
+				if len(call.Args) == 2 && isUnlistedHandler(call.Args[1]) {
+					return true
+				}
+				t.Errorf("%s: route registered outside Server.handle is missing from the OpenAPI document", fset.Position(call.Pos()))
+				return true
+			})
+		}
+	}
+}
+
+// isUnlistedHandler reports whether expr is one of the handlers that are
+// deliberately not API operations: the catch-all 404 and the web interface.
+func isUnlistedHandler(expr ast.Expr) bool {
+	if call, ok := expr.(*ast.CallExpr); ok {
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return false
+		}
+		pkg, ok := sel.X.(*ast.Ident)
+		return ok && pkg.Name == "web" && sel.Sel.Name == "Handler"
+	}
+	sel, ok := expr.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "notFound"
+}
+
+func TestOpenAPI_SchemasMatchDTOs(t *testing.T) {
+	doc := buildSpec(NewServer(newTestApp(t)).operations)
+
+	for _, v := range []interface{}{dto.Item{}, dto.ItemCreate{}, dto.ItemPatch{}, dto.Extension{}, dto.Stats{}, ErrorBody{}} {
+		typ := reflect.TypeOf(v)
+		t.Run(typ.Name(), func(t *testing.T) {
+			schema, ok := doc.Components.Schemas[typ.Name()]
+			require.True(t, ok, "schema not generated")
+
+			var expected []string
+			for i := 0; i < typ.NumField(); i++ {
+				name, _ := jsonField(typ.Field(i))
+				expected = append(expected, name)
+			}
+			var actual []string
+			for name := range schema.Properties {
+				actual = append(actual, name)
+			}
+			sort.Strings(expected)
+			sort.Strings(actual)
+			assert.Equal(t, expected, actual)
+		})
+	}
+
+	item := doc.Components.Schemas["Item"]
+	assert.Equal(t, &Schema{Type: "number", Format: "double", Nullable: true}, item.Properties["price"])
+	assert.Equal(t, &Schema{Type: "string", Format: "date-time"}, item.Properties["created_at"])
+	assert.NotContains(t, item.Required, "price")
+
+	create := doc.Components.Schemas["ItemCreate"]
+	assert.Equal(t, []string{"extension_code", "language_code", "type"}, create.Required)
+
+	patch := doc.Components.Schemas["ItemPatch"]
+	assert.Empty(t, patch.Required)
+	assert.True(t, patch.Properties["price"].Nullable)
+
+	stats := doc.Components.Schemas["Stats"]
+	assert.Equal(t, "#/components/schemas/Aggregate", stats.Properties["by_block"].Items.Ref)
+}
+
+func TestOpenAPI_IsValidJSON(t *testing.T) {
+	s := NewServer(newTestApp(t))
+
+	rec := do(t, s, http.MethodGet, "/openapi.json", "", nil)
+
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.True(t, json.Valid(rec.Body.Bytes()))
+}
@@ -0,0 +1,475 @@
+// Package api exposes the collection over a JSON HTTP API built on the
+// service layer.
+package api
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/R4yL-dev/pkmc/internal/app"
+	"github.com/R4yL-dev/pkmc/internal/dto"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+	"github.com/R4yL-dev/pkmc/internal/web"
+)
+
+const (
+	// BasePath prefixes every API route.
+	BasePath = "/api/v1"
+
+	maxBodyBytes      = 1 << 20
+	readHeaderTimeout = 10 * time.Second
+	shutdownTimeout   = 10 * time.Second
+)
+
+// Server routes HTTP requests to the application services.
+type Server struct {
+	app          *app.Application
+	mux          *http.ServeMux
+	operations   []operation
+	spec         []byte
+	authDisabled bool
+}
+
+func NewServer(application *app.Application, opts ...Option) *Server {
+	s := &Server{
+		app: application,
+		mux: http.NewServeMux(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.routes()
+	s.spec = marshalSpec(buildSpec(s.operations))
+	return s
+}
+
+var itemFilterParams = []param{
+	{name: "ext", in: "query", kind: "string", description: "Only items of this extension code"},
+	{name: "block", in: "query", kind: "string", description: "Only items of this block code"},
+	{name: "lang", in: "query", kind: "string", description: "Only items in this language code"},
+	{name: "type", in: "query", kind: "string", description: "Only items of this type"},
+	{name: "min_price", in: "query", kind: "number", description: "Minimum price"},
+	{name: "max_price", in: "query", kind: "number", description: "Maximum price"},
+	{name: "limit", in: "query", kind: "integer", description: "Maximum number of items, 0 for no limit"},
+	{name: "offset", in: "query", kind: "integer", description: "Number of items to skip"},
+}
+
+var itemIDParam = param{name: "id", in: "path", kind: "integer", description: "Item ID"}
+
+var webhookIDParam = param{name: "id", in: "path", kind: "integer", description: "Webhook ID"}
+
+var alertIDParam = param{name: "id", in: "path", kind: "integer", description: "Alert rule ID"}
+
+func (s *Server) routes() {
+	s.handle(operation{
+		method:   http.MethodGet,
+		path:     BasePath + "/items",
+		id:       "listItems",
+		tag:      "items",
+		role:     models.RoleReadOnly,
+		summary:  "List items, optionally filtered",
+		params:   itemFilterParams,
+		status:   http.StatusOK,
+		response: []dto.Item{},
+		errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity},
+	}, s.listItems)
+	s.handle(operation{
+		method:   http.MethodPost,
+		path:     BasePath + "/items",
+		id:       "createItem",
+		tag:      "items",
+		role:     models.RoleEditor,
+		summary:  "Add an item to the collection; a likely duplicate is reported in the X-Pkmc-Duplicates header, or raises the quantity of the existing item with a 200",
+		params:   []param{{name: IdempotencyKeyHeader, in: "header", kind: "string", description: "Key under which a retried request returns the item created first"}},
+		body:     dto.ItemCreate{},
+		status:   http.StatusCreated,
+		response: dto.Item{},
+		errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity},
+	}, s.createItem)
+	s.handle(operation{
+		method:   http.MethodPost,
+		path:     BasePath + "/items/batch",
+		id:       "createItems",
+		tag:      "items",
+		role:     models.RoleEditor,
+		summary:  "Add up to 1000 items at once, applying the duplicate policy to each entry; when an entry fails, none is added and the error lists every failed entry",
+		params:   []param{{name: IdempotencyKeyHeader, in: "header", kind: "string", description: "Key under which a retried request returns the items created first"}},
+		body:     dto.ItemBatchCreate{},
+		status:   http.StatusCreated,
+		response: []dto.Item{},
+		errors:   []int{http.StatusBadRequest, http.StatusConflict, http.StatusUnprocessableEntity},
+	}, s.createItems)
+	s.handle(operation{
+		method:   http.MethodGet,
+		path:     BasePath + "/items/{id}",
+		id:       "getItem",
+		tag:      "items",
+		role:     models.RoleReadOnly,
+		summary:  "Get one item",
+		params:   []param{itemIDParam},
+		status:   http.StatusOK,
+		response: dto.Item{},
+		errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+	}, s.getItem)
+	s.handle(operation{
+		method:   http.MethodPatch,
+		path:     BasePath + "/items/{id}",
+		id:       "updateItem",
+		tag:      "items",
+		role:     models.RoleEditor,
+		summary:  "Update an item; a null price removes the price",
+		params:   []param{itemIDParam},
+		body:     dto.ItemPatch{},
+		status:   http.StatusOK,
+		response: dto.Item{},
+		errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity},
+	}, s.updateItem)
+	s.handle(operation{
+		method:  http.MethodDelete,
+		path:    BasePath + "/items/{id}",
+		id:      "deleteItem",
+		tag:     "items",
+		role:    models.RoleEditor,
+		summary: "Delete an item",
+		params:  []param{itemIDParam},
+		status:  http.StatusNoContent,
+		errors:  []int{http.StatusBadRequest, http.StatusNotFound},
+	}, s.deleteItem)
+	s.handle(operation{
+		method:   http.MethodPost,
+		path:     BasePath + "/items/{id}/sell",
+		id:       "sellItem",
+		tag:      "items",
+		role:     models.RoleEditor,
+		summary:  "Mark an item as sold, now unless sold_at is given; the item stays in the collection",
+		params:   []param{itemIDParam},
+		body:     dto.ItemSale{},
+		status:   http.StatusOK,
+		response: dto.Item{},
+		errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity},
+	}, s.sellItem)
+	s.handle(operation{
+		method:   http.MethodGet,
+		path:     BasePath + "/items/{id}/history",
+		id:       "itemHistory",
+		tag:      "audit",
+		role:     models.RoleReadOnly,
+		summary:  "Changes of one item, oldest first, one record per changed field",
+		params:   []param{itemIDParam},
+		status:   http.StatusOK,
+		response: []dto.AuditChange{},
+		errors:   []int{http.StatusBadRequest},
+	}, s.itemHistory)
+	s.handle(operation{
+		method:   http.MethodGet,
+		path:     BasePath + "/items/{id}/prices",
+		id:       "itemPriceHistory",
+		tag:      "prices",
+		role:     models.RoleReadOnly,
+		summary:  "Market prices quoted for one item, newest first",
+		params:   []param{itemIDParam, {name: "limit", in: "query", kind: "integer", description: "Maximum number of quotes"}},
+		status:   http.StatusOK,
+		response: []dto.PriceRecord{},
+		errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+	}, s.itemPriceHistory)
+	s.handle(operation{
+		method:  http.MethodGet,
+		path:    BasePath + "/audit",
+		id:      "listChanges",
+		tag:     "audit",
+		role:    models.RoleAdmin,
+		summary: "Changes to the collection and tokens, oldest first, one record per changed field",
+		params: []param{
+			{name: "since", in: "query", kind: "string", description: "Only changes at or after this RFC 3339 time or date"},
+			{name: "until", in: "query", kind: "string", description: "Only changes before this RFC 3339 time or date"},
+			{name: "entity", in: "query", kind: "string", description: "Only changes of this entity: item, api_token, webhook, alert_rule or price_record"},
+			{name: "operation", in: "query", kind: "string", description: "Only changes made by this operation"},
+			{name: "limit", in: "query", kind: "integer", description: "Maximum number of entries, 0 for no limit"},
+		},
+		status:   http.StatusOK,
+		response: []dto.AuditChange{},
+		errors:   []int{http.StatusBadRequest, http.StatusUnprocessableEntity},
+	}, s.listChanges)
+	s.handle(operation{
+		method:   http.MethodPost,
+		path:     BasePath + "/undo",
+		id:       "undo",
+		tag:      "audit",
+		role:     models.RoleEditor,
+		summary:  "Revert the last operations on items, or one operation, unless later changes conflict",
+		body:     dto.UndoRequest{},
+		status:   http.StatusOK,
+		response: []dto.AuditChange{},
+		errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity},
+	}, s.undo)
+	s.handle(operation{
+		method:   http.MethodPost,
+		path:     BasePath + "/prices/refresh",
+		id:       "refreshPrices",
+		tag:      "prices",
+		role:     models.RoleEditor,
+		summary:  "Quote every item not sold with the price providers and record the quotes as their current values; the prices paid are left alone",
+		body:     dto.PriceRefreshRequest{},
+		status:   http.StatusOK,
+		response: []dto.PriceRefresh{},
+		errors:   []int{http.StatusBadRequest, http.StatusUnprocessableEntity},
+	}, s.refreshPrices)
+
+	s.handle(operation{
+		method:   http.MethodGet,
+		path:     BasePath + "/extensions",
+		id:       "listExtensions",
+		tag:      "catalog",
+		role:     models.RoleReadOnly,
+		summary:  "List extensions",
+		params:   []param{{name: "block", in: "query", kind: "string", description: "Only extensions of this block code"}},
+		status:   http.StatusOK,
+		response: []dto.Extension{},
+		errors:   []int{http.StatusNotFound},
+	}, s.listExtensions)
+	s.handle(operation{
+		method:   http.MethodGet,
+		path:     BasePath + "/extensions/{code}",
+		id:       "getExtension",
+		tag:      "catalog",
+		role:     models.RoleReadOnly,
+		summary:  "Get one extension",
+		params:   []param{{name: "code", in: "path", kind: "string", description: "Extension code"}},
+		status:   http.StatusOK,
+		response: dto.Extension{},
+		errors:   []int{http.StatusNotFound},
+	}, s.getExtension)
+	s.handle(operation{
+		method:   http.MethodGet,
+		path:     BasePath + "/blocks",
+		id:       "listBlocks",
+		tag:      "catalog",
+		role:     models.RoleReadOnly,
+		summary:  "List blocks",
+		status:   http.StatusOK,
+		response: []dto.Block{},
+	}, s.listBlocks)
+	s.handle(operation{
+		method:   http.MethodGet,
+		path:     BasePath + "/languages",
+		id:       "listLanguages",
+		tag:      "catalog",
+		role:     models.RoleReadOnly,
+		summary:  "List languages",
+		status:   http.StatusOK,
+		response: []dto.Language{},
+	}, s.listLanguages)
+	s.handle(operation{
+		method:   http.MethodGet,
+		path:     BasePath + "/item-types",
+		id:       "listItemTypes",
+		tag:      "catalog",
+		role:     models.RoleReadOnly,
+		summary:  "List item types",
+		status:   http.StatusOK,
+		response: []dto.ItemType{},
+	}, s.listItemTypes)
+	s.handle(operation{
+		method:   http.MethodGet,
+		path:     BasePath + "/stats",
+		id:       "collectionStats",
+		tag:      "stats",
+		role:     models.RoleReadOnly,
+		summary:  "Collection statistics",
+		status:   http.StatusOK,
+		response: dto.Stats{},
+	}, s.stats)
+
+	s.handle(operation{
+		method:   http.MethodGet,
+		path:     BasePath + "/tokens",
+		id:       "listTokens",
+		tag:      "tokens",
+		role:     models.RoleAdmin,
+		summary:  "List API tokens",
+		status:   http.StatusOK,
+		response: []dto.APIToken{},
+	}, s.listTokens)
+	s.handle(operation{
+		method:   http.MethodPost,
+		path:     BasePath + "/tokens",
+		id:       "issueToken",
+		tag:      "tokens",
+		role:     models.RoleAdmin,
+		summary:  "Issue an API token; the secret is only returned here",
+		body:     dto.TokenCreate{},
+		status:   http.StatusCreated,
+		response: dto.IssuedToken{},
+		errors:   []int{http.StatusBadRequest, http.StatusUnprocessableEntity},
+	}, s.issueToken)
+	s.handle(operation{
+		method:  http.MethodDelete,
+		path:    BasePath + "/tokens/{id}",
+		id:      "revokeToken",
+		tag:     "tokens",
+		role:    models.RoleAdmin,
+		summary: "Revoke an API token",
+		params:  []param{{name: "id", in: "path", kind: "integer", description: "Token ID"}},
+		status:  http.StatusNoContent,
+		errors:  []int{http.StatusBadRequest, http.StatusNotFound},
+	}, s.revokeToken)
+
+	s.handle(operation{
+		method:   http.MethodGet,
+		path:     BasePath + "/alerts",
+		id:       "listAlerts",
+		tag:      "alerts",
+		role:     models.RoleReadOnly,
+		summary:  "List price alert rules and whether they are triggered",
+		status:   http.StatusOK,
+		response: []dto.AlertRule{},
+	}, s.listAlerts)
+	s.handle(operation{
+		method:   http.MethodPost,
+		path:     BasePath + "/alerts",
+		id:       "createAlert",
+		tag:      "alerts",
+		role:     models.RoleEditor,
+		summary:  "Create a price alert rule on an item or a product",
+		body:     dto.AlertRuleCreate{},
+		status:   http.StatusCreated,
+		response: dto.AlertRule{},
+		errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity},
+	}, s.createAlert)
+	s.handle(operation{
+		method:  http.MethodDelete,
+		path:    BasePath + "/alerts/{id}",
+		id:      "deleteAlert",
+		tag:     "alerts",
+		role:    models.RoleEditor,
+		summary: "Delete a price alert rule",
+		params:  []param{alertIDParam},
+		status:  http.StatusNoContent,
+		errors:  []int{http.StatusBadRequest, http.StatusNotFound},
+	}, s.deleteAlert)
+
+	s.handle(operation{
+		method:   http.MethodGet,
+		path:     BasePath + "/webhooks",
+		id:       "listWebhooks",
+		tag:      "webhooks",
+		role:     models.RoleAdmin,
+		summary:  "List webhooks",
+		status:   http.StatusOK,
+		response: []dto.Webhook{},
+	}, s.listWebhooks)
+	s.handle(operation{
+		method:   http.MethodPost,
+		path:     BasePath + "/webhooks",
+		id:       "createWebhook",
+		tag:      "webhooks",
+		role:     models.RoleAdmin,
+		summary:  "Create a webhook; the signing secret is only returned here",
+		body:     dto.WebhookCreate{},
+		status:   http.StatusCreated,
+		response: dto.CreatedWebhook{},
+		errors:   []int{http.StatusBadRequest, http.StatusUnprocessableEntity},
+	}, s.createWebhook)
+	s.handle(operation{
+		method:  http.MethodDelete,
+		path:    BasePath + "/webhooks/{id}",
+		id:      "deleteWebhook",
+		tag:     "webhooks",
+		role:    models.RoleAdmin,
+		summary: "Delete a webhook; its pending deliveries are dropped",
+		params:  []param{webhookIDParam},
+		status:  http.StatusNoContent,
+		errors:  []int{http.StatusBadRequest, http.StatusNotFound},
+	}, s.deleteWebhook)
+	s.handle(operation{
+		method:  http.MethodGet,
+		path:    BasePath + "/webhooks/{id}/deliveries",
+		id:      "listWebhookDeliveries",
+		tag:     "webhooks",
+		role:    models.RoleAdmin,
+		summary: "Delivery log of a webhook, newest first",
+		params: []param{
+			webhookIDParam,
+			{name: "status", in: "query", kind: "string", description: "Only deliveries with this status: pending, succeeded or failed"},
+			{name: "limit", in: "query", kind: "integer", description: "Maximum number of deliveries, 0 for no limit"},
+		},
+		status:   http.StatusOK,
+		response: []dto.WebhookDelivery{},
+		errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity},
+	}, s.listWebhookDeliveries)
+
+	s.handle(operation{
+		method:   http.MethodGet,
+		path:     "/openapi.json",
+		id:       "openAPI",
+		tag:      "meta",
+		summary:  "This OpenAPI document",
+		status:   http.StatusOK,
+		response: map[string]interface{}{},
+	}, s.openAPI)
+
+	s.mux.HandleFunc(BasePath+"/", s.notFound)
+	// The browser interface is not part of the API: its pages are public
+	// and call the API with the user's token.
+	s.mux.Handle("/", web.Handler())
+}
+
+// handle registers h for op. Routes must be registered here rather than on
+// the mux directly so that they appear in the OpenAPI document and get the
+// role check of op.
+func (s *Server) handle(op operation, h http.HandlerFunc) {
+	s.operations = append(s.operations, op)
+	s.mux.HandleFunc(op.method+" "+op.path, s.authorize(op.role, h))
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// operationContext derives the context of one request from the
+// application's operation context, so the configured timeout applies, and
+// cancels it as soon as the client goes away. It carries the actor set by
+// authorize for the audit trail.
+func (s *Server) operationContext(r *http.Request) (context.Context, context.CancelFunc) {
+	ctx, cancel := s.app.NewOperationContext()
+	ctx = repository.WithActor(ctx, repository.ActorFrom(r.Context()))
+	stop := context.AfterFunc(r.Context(), cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// Serve accepts connections on ln until ctx is cancelled, then stops
+// accepting new requests and waits for in-flight ones to finish.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	srv := &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
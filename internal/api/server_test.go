@@ -0,0 +1,531 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/pkmc/internal/app"
+	"github.com/R4yL-dev/pkmc/internal/config"
+	"github.com/R4yL-dev/pkmc/internal/dto"
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/pricing"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+	"github.com/R4yL-dev/pkmc/internal/service"
+	"github.com/R4yL-dev/pkmc/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestApp builds an application over a seeded in-memory database.
+func newTestApp(t *testing.T) *app.Application {
+	t.Helper()
+
+	db := testutil.SetupTestDB(t)
+	t.Cleanup(func() { testutil.CleanupTestDB(t, db) })
+
+	// Every connection to ":memory:" is a separate database.
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	uow := repository.NewUnitOfWork(db)
+	prices := pricing.NewRegistry()
+	return &app.Application{
+		Ctx: context.Background(),
+		Container: &app.Container{
+			DB:             db,
+			UoW:            uow,
+			Config:         config.Load().With(config.WithDefaultTimeout(5 * time.Second)),
+			ItemService:    service.NewItemService(uow),
+			CatalogService: service.NewCatalogService(uow),
+			StatsService:   service.NewStatsService(uow),
+			TokenService:   service.NewTokenService(uow),
+			AuditService:   service.NewAuditService(uow),
+			WebhookService: service.NewWebhookService(uow),
+			PriceService:   service.NewPriceService(uow, prices),
+			AlertService:   service.NewAlertService(uow),
+			Prices:         prices,
+		},
+	}
+}
+
+// do sends a request to s and decodes the JSON response into out when it
+// is not nil.
+func do(t *testing.T, s http.Handler, method, path, body string, out interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var reader io.Reader
+	if body != "" {
+		reader = bytes.NewBufferString(body)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	if out != nil {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), out), rec.Body.String())
+	}
+	return rec
+}
+
+func TestServer_ItemLifecycle(t *testing.T) {
+	s := NewServer(newTestApp(t), WithoutAuth())
+
+	// Create
+	var created dto.Item
+	rec := do(t, s, http.MethodPost, "/api/v1/items", `{"extension_code":"DRI","language_code":"fr","type":"Display","price":189.95}`, &created)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, "/api/v1/items/1", rec.Header().Get("Location"))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "Rivalités Destinées", created.ExtensionName)
+	require.NotNil(t, created.Price)
+	assert.Equal(t, 189.95, *created.Price)
+
+	// List with filters
+	var items []dto.Item
+	rec = do(t, s, http.MethodGet, "/api/v1/items?ext=DRI&min_price=100", "", &items)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, items, 1)
+
+	rec = do(t, s, http.MethodGet, "/api/v1/items?lang=en", "", &items)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, items)
+
+	// Patch: change language, clear price with an explicit null
+	var updated dto.Item
+	rec = do(t, s, http.MethodPatch, "/api/v1/items/1", `{"language_code":"en","price":null}`, &updated)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "en", updated.LanguageCode)
+	assert.Nil(t, updated.Price)
+	assert.Equal(t, "Display", updated.Type)
+
+	// Get
+	var got dto.Item
+	rec = do(t, s, http.MethodGet, "/api/v1/items/1", "", &got)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, updated.LanguageCode, got.LanguageCode)
+
+	// Sell, once
+	var sold dto.Item
+	rec = do(t, s, http.MethodPost, "/api/v1/items/1/sell", `{"price":240,"sold_at":"2026-10-01T00:00:00Z"}`, &sold)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NotNil(t, sold.SoldPrice)
+	assert.Equal(t, 240.0, *sold.SoldPrice)
+	assert.Nil(t, sold.Price, "the purchase price is left alone")
+
+	rec = do(t, s, http.MethodPost, "/api/v1/items/1/sell", `{}`, nil)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	// Delete, then the item is gone
+	rec = do(t, s, http.MethodDelete, "/api/v1/items/1", "", nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	var errBody ErrorBody
+	rec = do(t, s, http.MethodGet, "/api/v1/items/1", "", &errBody)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, ErrorBody{Error: ErrorDetail{Status: http.StatusNotFound, Code: "ITEM_NOT_FOUND", Message: "item 1 not found"}}, errBody)
+}
+
+func TestServer_Duplicates(t *testing.T) {
+	s := NewServer(newTestApp(t), WithoutAuth())
+
+	body := `{"extension_code":"DRI","language_code":"fr","type":"Display","price":180,"duplicate_policy":"%s"}`
+	rec := do(t, s, http.MethodPost, "/api/v1/items", fmt.Sprintf(body, "warn"), nil)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Empty(t, rec.Header().Get(DuplicatesHeader))
+
+	// Warn: created, with the duplicate listed
+	rec = do(t, s, http.MethodPost, "/api/v1/items", fmt.Sprintf(body, "warn"), nil)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, "1", rec.Header().Get(DuplicatesHeader))
+
+	// Reject
+	var errBody ErrorBody
+	rec = do(t, s, http.MethodPost, "/api/v1/items", fmt.Sprintf(body, "reject"), &errBody)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "DUPLICATE_ITEM", errBody.Error.Code)
+
+	// Increment: the oldest duplicate is returned with its new quantity
+	var item dto.Item
+	rec = do(t, s, http.MethodPost, "/api/v1/items", fmt.Sprintf(body, "Increment"), &item)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "1,2", rec.Header().Get(DuplicatesHeader))
+	assert.Empty(t, rec.Header().Get("Location"))
+	assert.Equal(t, uint(1), item.ID)
+	assert.Equal(t, 2, item.Quantity)
+
+	// Unknown policy
+	rec = do(t, s, http.MethodPost, "/api/v1/items", fmt.Sprintf(body, "merge"), &errBody)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, "duplicate_policy", errBody.Error.Fields[0].Field)
+}
+
+func TestServer_IdempotencyKey(t *testing.T) {
+	s := NewServer(newTestApp(t), WithoutAuth())
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/items", bytes.NewBufferString(body))
+		req.Header.Set(IdempotencyKeyHeader, "7c1e6a58")
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		return rec
+	}
+	body := `{"extension_code":"DRI","language_code":"fr","type":"Display","price":180}`
+
+	rec := post(body)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Empty(t, rec.Header().Get(IdempotentReplayedHeader))
+
+	// The retry gets the same item
+	rec = post(body)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, "true", rec.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, "/api/v1/items/1", rec.Header().Get("Location"))
+
+	var items []dto.Item
+	do(t, s, http.MethodGet, "/api/v1/items", "", &items)
+	assert.Len(t, items, 1)
+
+	// The key of another request
+	rec = post(`{"extension_code":"SVI","language_code":"fr","type":"Display"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	var errBody ErrorBody
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errBody))
+	assert.Equal(t, "IDEMPOTENCY_KEY_REUSED", errBody.Error.Code)
+}
+
+func TestServer_CreateItems(t *testing.T) {
+	s := NewServer(newTestApp(t), WithoutAuth())
+
+	// Every failed entry is reported, and nothing is added
+	var errBody ErrorBody
+	rec := do(t, s, http.MethodPost, "/api/v1/items/batch", `{"items":[
+		{"extension_code":"DRI","language_code":"fr","type":"Display"},
+		{"extension_code":"DRI","language_code":"fr","type":"Display","price":-1},
+		{"extension_code":"XXX","language_code":"fr","type":"Display"}
+	]}`, &errBody)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, "BATCH_FAILED", errBody.Error.Code)
+	require.Len(t, errBody.Error.Entries, 1, "references are looked up once every entry is valid")
+	assert.Equal(t, 1, errBody.Error.Entries[0].Index)
+	assert.Equal(t, "VALIDATION_FAILED", errBody.Error.Entries[0].Code)
+	require.Len(t, errBody.Error.Entries[0].Fields, 1)
+	assert.Equal(t, "price", errBody.Error.Entries[0].Fields[0].Field)
+
+	rec = do(t, s, http.MethodPost, "/api/v1/items/batch", `{"items":[
+		{"extension_code":"DRI","language_code":"fr","type":"Display"},
+		{"extension_code":"XXX","language_code":"fr","type":"Display"}
+	]}`, &errBody)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	require.Len(t, errBody.Error.Entries, 1)
+	assert.Equal(t, 1, errBody.Error.Entries[0].Index)
+	assert.Equal(t, "EXTENSION_NOT_FOUND", errBody.Error.Entries[0].Code)
+	assert.Equal(t, "extension 'XXX' not found", errBody.Error.Entries[0].Message)
+
+	var items []dto.Item
+	do(t, s, http.MethodGet, "/api/v1/items", "", &items)
+	assert.Empty(t, items)
+
+	// A valid batch is added at once
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/items/batch", bytes.NewBufferString(body))
+		req.Header.Set(IdempotencyKeyHeader, "import-1")
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		return rec
+	}
+	body := `{"items":[
+		{"extension_code":"DRI","language_code":"fr","type":"Display","price":180},
+		{"extension_code":"SVI","language_code":"en","type":"Display","quantity":2}
+	]}`
+	rec = post(body)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &items))
+	require.Len(t, items, 2)
+	assert.Equal(t, "SVI", items[1].ExtensionCode)
+	assert.Equal(t, 2, items[1].Quantity)
+
+	// The retry gets the same items
+	rec = post(body)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, "true", rec.Header().Get(IdempotentReplayedHeader))
+
+	do(t, s, http.MethodGet, "/api/v1/items", "", &items)
+	assert.Len(t, items, 2)
+
+	// The duplicate policy applies to each entry
+	rec = do(t, s, http.MethodPost, "/api/v1/items/batch", `{"items":[
+		{"extension_code":"SVI","language_code":"en","type":"Display"},
+		{"extension_code":"DRI","language_code":"fr","type":"Display","price":181}
+	], "duplicate_policy": "increment"}`, &items)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, "0=2,1=1", rec.Header().Get(DuplicatesHeader))
+	require.Len(t, items, 2)
+	assert.Equal(t, 3, items[0].Quantity)
+	assert.Equal(t, 2, items[1].Quantity)
+}
+
+func TestServer_ReferenceData(t *testing.T) {
+	s := NewServer(newTestApp(t), WithoutAuth())
+
+	var exts []dto.Extension
+	rec := do(t, s, http.MethodGet, "/api/v1/extensions?block=ME", "", &exts)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.NotEmpty(t, exts)
+	for _, ext := range exts {
+		assert.Equal(t, "ME", ext.BlockCode)
+	}
+
+	var ext dto.Extension
+	rec = do(t, s, http.MethodGet, "/api/v1/extensions/DRI", "", &ext)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "Rivalités Destinées", ext.Name)
+
+	var blocks []dto.Block
+	rec = do(t, s, http.MethodGet, "/api/v1/blocks", "", &blocks)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotEmpty(t, blocks)
+
+	var langs []dto.Language
+	rec = do(t, s, http.MethodGet, "/api/v1/languages", "", &langs)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, langs, dto.Language{Code: "fr", Name: "Français"})
+
+	var itemTypes []dto.ItemType
+	rec = do(t, s, http.MethodGet, "/api/v1/item-types", "", &itemTypes)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, itemTypes, dto.ItemType{Name: "Sleeve Booster"})
+
+	var stats dto.Stats
+	rec = do(t, s, http.MethodGet, "/api/v1/stats", "", &stats)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, int64(0), stats.Totals.Items)
+}
+
+func TestServer_Errors(t *testing.T) {
+	s := NewServer(newTestApp(t), WithoutAuth())
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		expected int
+		code     string
+	}{
+		{"invalid id", http.MethodGet, "/api/v1/items/abc", "", http.StatusBadRequest, "BAD_REQUEST"},
+		{"invalid query", http.MethodGet, "/api/v1/items?limit=-1", "", http.StatusBadRequest, "BAD_REQUEST"},
+		{"empty body", http.MethodPost, "/api/v1/items", "", http.StatusBadRequest, "BAD_REQUEST"},
+		{"malformed body", http.MethodPost, "/api/v1/items", `{"extension_code":`, http.StatusBadRequest, "BAD_REQUEST"},
+		{"unknown field", http.MethodPost, "/api/v1/items", `{"extension_code":"DRI","colour":"red"}`, http.StatusBadRequest, "BAD_REQUEST"},
+		{"missing fields", http.MethodPost, "/api/v1/items", `{"extension_code":"DRI"}`, http.StatusUnprocessableEntity, "VALIDATION_FAILED"},
+		{"nothing to update", http.MethodPatch, "/api/v1/items/1", `{}`, http.StatusBadRequest, "BAD_REQUEST"},
+		{"unknown extension", http.MethodPost, "/api/v1/items", `{"extension_code":"NOPE","language_code":"fr","type":"Display"}`, http.StatusNotFound, "EXTENSION_NOT_FOUND"},
+		{"unknown block", http.MethodGet, "/api/v1/extensions?block=XX", "", http.StatusNotFound, "BLOCK_NOT_FOUND"},
+		{"missing item", http.MethodDelete, "/api/v1/items/42", "", http.StatusNotFound, "ITEM_NOT_FOUND"},
+		{"invalid audit time", http.MethodGet, "/api/v1/audit?since=yesterday", "", http.StatusBadRequest, "BAD_REQUEST"},
+		{"empty audit range", http.MethodGet, "/api/v1/audit?since=2025-03-02&until=2025-03-01", "", http.StatusUnprocessableEntity, "VALIDATION_FAILED"},
+		{"unknown route", http.MethodGet, "/api/v1/cards", "", http.StatusNotFound, "NOT_FOUND"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body ErrorBody
+			rec := do(t, s, tt.method, tt.path, tt.body, &body)
+
+			assert.Equal(t, tt.expected, rec.Code)
+			assert.Equal(t, tt.expected, body.Error.Status)
+			assert.Equal(t, tt.code, body.Error.Code)
+			assert.NotEmpty(t, body.Error.Message)
+		})
+	}
+}
+
+func TestServer_ValidationErrors(t *testing.T) {
+	s := NewServer(newTestApp(t), WithoutAuth())
+
+	var body ErrorBody
+	rec := do(t, s, http.MethodPost, "/api/v1/items", `{"extension_code":" ","language_code":"fr","type":"Display","price":-3}`, &body)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, "VALIDATION_FAILED", body.Error.Code)
+	assert.Equal(t, "extension_code is required; price must not be negative", body.Error.Message)
+	assert.Equal(t, []FieldError{
+		{Field: "extension_code", Rule: "required", Message: "extension_code is required"},
+		{Field: "price", Rule: "min", Message: "price must not be negative"},
+	}, body.Error.Fields)
+}
+
+func TestStatusCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"request", newRequestError("bad"), http.StatusBadRequest},
+		{"invalid token", customErr.NewServiceError("authenticate", "token_service", "", customErr.ErrInvalidToken), http.StatusUnauthorized},
+		{"permission denied", customErr.NewServiceError("authorize", "api", "", customErr.ErrPermissionDenied), http.StatusForbidden},
+		{"not found", customErr.NewRepositoryError("find", "item", "1", customErr.ErrEntityNotFound), http.StatusNotFound},
+		{"validation", customErr.NewServiceError("create_item", "item_service", "", customErr.ErrValidationFailed), http.StatusUnprocessableEntity},
+		{"constraint", customErr.NewRepositoryError("create", "item", "new", customErr.ErrConstraintViolation), http.StatusConflict},
+		{"conflict", customErr.NewServiceError("undo", "audit_service", "", customErr.ErrConflict), http.StatusConflict},
+		{"timeout", fmt.Errorf("wrapped: %w", context.DeadlineExceeded), http.StatusGatewayTimeout},
+		{"busy", customErr.NewUOWBusyError(3, errors.New("database is locked")), http.StatusServiceUnavailable},
+		{"canceled", fmt.Errorf("wrapped: %w", context.Canceled), http.StatusServiceUnavailable},
+		{"database", customErr.NewDBError("open", errors.New("boom")), http.StatusServiceUnavailable},
+		{"unit of work", customErr.NewUOWError("commit", errors.New("boom")), http.StatusServiceUnavailable},
+		{"other", errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, statusCode(tt.err))
+		})
+	}
+}
+
+func TestErrorMessage_HidesInternalErrors(t *testing.T) {
+	err := customErr.NewServiceError("list_items", "item_service", "failed to list items", errors.New("disk I/O error"))
+
+	assert.Equal(t, "Internal Server Error", errorMessage(err, http.StatusInternalServerError))
+}
+
+func TestErrorMessage_ShowsConstraint(t *testing.T) {
+	violation := &customErr.ConstraintError{Kind: customErr.ConstraintUnique, Table: "webhooks", Columns: []string{"url"}, Cause: errors.New("UNIQUE constraint failed: webhooks.url")}
+	err := customErr.NewRepositoryError("create", "webhook", "new", violation)
+
+	assert.Equal(t, http.StatusConflict, statusCode(err))
+	assert.Equal(t, "unique constraint violated on webhooks.url", errorMessage(err, http.StatusConflict))
+}
+
+func TestServer_GracefulShutdown(t *testing.T) {
+	s := NewServer(newTestApp(t), WithoutAuth())
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(ctx, ln) }()
+
+	resp, err := http.Get("http://" + ln.Addr().String() + "/api/v1/languages")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+}
+
+func TestServer_WebInterface(t *testing.T) {
+	s := NewServer(newTestApp(t))
+
+	// The pages are public even when the API requires a token.
+	rec := do(t, s, http.MethodGet, "/", "", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "<title>pkmc</title>")
+
+	rec = do(t, s, http.MethodGet, "/api/v1/items", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// Unknown API paths still get a JSON error rather than a page.
+	var body ErrorBody
+	rec = do(t, s, http.MethodGet, "/api/v1/cards", "", &body)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, http.StatusNotFound, body.Error.Status)
+}
+
+func TestServer_Prices(t *testing.T) {
+	application := newTestApp(t)
+	s := NewServer(application, WithoutAuth())
+
+	var errBody ErrorBody
+	rec := do(t, s, http.MethodPost, "/api/v1/prices/refresh", `{}`, &errBody)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, errBody.Error.Message, "no price provider is configured")
+
+	// A local stand-in of a marketplace API
+	market := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("extension") != "DRI" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"price":205,"currency":"EUR"}`)
+	}))
+	defer market.Close()
+	require.NoError(t, application.Container.Prices.Register(pricing.NewHTTPProvider(market.URL, pricing.WithName("market"))))
+
+	rec = do(t, s, http.MethodPost, "/api/v1/items", `{"extension_code":"DRI","language_code":"fr","type":"Display","price":189.95}`, nil)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	rec = do(t, s, http.MethodPost, "/api/v1/items", `{"extension_code":"SVI","language_code":"en","type":"ETB"}`, nil)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = do(t, s, http.MethodPost, "/api/v1/prices/refresh", `{"provider":"tcgplayer"}`, &errBody)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	var refreshes []dto.PriceRefresh
+	rec = do(t, s, http.MethodPost, "/api/v1/prices/refresh", `{"provider":"market"}`, &refreshes)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.Len(t, refreshes, 2)
+	assert.Equal(t, "updated", refreshes[0].Status)
+	assert.Equal(t, 205.0, *refreshes[0].NewPrice)
+	assert.Equal(t, "missing", refreshes[1].Status)
+
+	var history []dto.PriceRecord
+	rec = do(t, s, http.MethodGet, "/api/v1/items/1/prices", "", &history)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, history, 1)
+	assert.Equal(t, "market", history[0].Source)
+
+	rec = do(t, s, http.MethodGet, "/api/v1/items/99/prices", "", &errBody)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestServer_Alerts(t *testing.T) {
+	s := NewServer(newTestApp(t), WithoutAuth())
+
+	rec := do(t, s, http.MethodPost, "/api/v1/items", `{"extension_code":"DRI","language_code":"fr","type":"Display"}`, nil)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var errBody ErrorBody
+	rec = do(t, s, http.MethodPost, "/api/v1/alerts", `{"item_id":1,"condition":"drop","threshold":15}`, &errBody)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, errBody.Error.Message, "a window is required for drop alerts")
+
+	rec = do(t, s, http.MethodPost, "/api/v1/alerts", `{"item_id":99,"condition":"above","threshold":250}`, &errBody)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	var created dto.AlertRule
+	rec = do(t, s, http.MethodPost, "/api/v1/alerts", `{"item_id":1,"condition":"drop","threshold":15,"window":"7d"}`, &created)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, "item 1 drop 15% in 7d", created.Name)
+	assert.Equal(t, "7d", created.Window)
+
+	rec = do(t, s, http.MethodPost, "/api/v1/alerts", `{"extension_code":"DRI","type":"Display","language_code":"fr","condition":"above","threshold":250}`, &created)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, "DRI Display (fr) above 250", created.Name)
+
+	var rules []dto.AlertRule
+	rec = do(t, s, http.MethodGet, "/api/v1/alerts", "", &rules)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, rules, 2)
+
+	rec = do(t, s, http.MethodDelete, "/api/v1/alerts/1", "", nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = do(t, s, http.MethodDelete, "/api/v1/alerts/1", "", &errBody)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -14,12 +14,12 @@ type Application struct {
 	Container *Container
 }
 
-func Initialize() (*Application, error) {
+func Initialize(opts ...config.Option) (*Application, error) {
 	ctx := context.Background()
 
-	config.Load()
+	cfg := config.Load().With(opts...)
 
-	container, err := NewContainer()
+	container, err := NewContainer(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -29,9 +29,11 @@ func Initialize() (*Application, error) {
 		return nil, err
 	}
 
-	if err := seed.Seed(container.DB); err != nil {
-		container.Close()
-		return nil, err
+	if !cfg.GetSkipSeed() {
+		if err := seed.Seed(container.DB); err != nil {
+			container.Close()
+			return nil, err
+		}
 	}
 
 	return &Application{
@@ -1,10 +1,18 @@
 package app
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/R4yL-dev/pkmc/internal/config"
 	"github.com/R4yL-dev/pkmc/internal/database"
+	"github.com/R4yL-dev/pkmc/internal/events"
+	"github.com/R4yL-dev/pkmc/internal/notify"
+	"github.com/R4yL-dev/pkmc/internal/pricing"
 	"github.com/R4yL-dev/pkmc/internal/repository"
+	"github.com/R4yL-dev/pkmc/internal/scheduler"
 	"github.com/R4yL-dev/pkmc/internal/service"
+	"github.com/R4yL-dev/pkmc/internal/webhook"
 	"gorm.io/gorm"
 )
 
@@ -13,27 +21,137 @@ type Container struct {
 	UoW    repository.UnitOfWork
 	Config *config.Config
 
-	ItemService service.ItemService
+	ItemService    service.ItemService
+	CatalogService service.CatalogService
+	StatsService   service.StatsService
+	TokenService   service.TokenService
+	AuditService   service.AuditService
+	WebhookService service.WebhookService
+	PriceService   service.PriceService
+	AlertService   service.AlertService
+
+	// Prices holds the price providers used by PriceService.
+	Prices *pricing.Registry
+	// Notifier tells about the alerts triggered by price refreshes.
+	Notifier notify.Notifier
+
+	// Events delivers the domain events of the outbox to subscribers
+	// while it runs.
+	Events *events.Dispatcher
+	// Webhooks posts the deliveries queued by the webhooks subscriber of
+	// Events while it runs.
+	Webhooks *webhook.Sender
+	// Jobs runs the periodic jobs, such as price refreshes and backups,
+	// while it runs.
+	Jobs *scheduler.Scheduler
 }
 
-func NewContainer() (*Container, error) {
-	cfg := config.Get()
+func NewContainer(cfg *config.Config) (*Container, error) {
+	prices, err := newPriceRegistry(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	duplicatePolicy, err := service.ParseDuplicatePolicy(cfg.GetDuplicatePolicy())
+	if err != nil {
+		return nil, fmt.Errorf("ITEM_DUPLICATE_POLICY: %w", err)
+	}
+
+	db, err := database.InitDB(cfg.GetDBPath(), dbSettings(cfg))
+	if err != nil {
+		return nil, err
+	}
+
+	uow := repository.NewUnitOfWork(db, repository.WithBusyRetry(cfg.GetBusyRetry(), 0, 0))
+
+	itemService := service.NewItemService(uow,
+		service.WithDefaultDuplicatePolicy(duplicatePolicy),
+		service.WithIdempotencyRetention(cfg.GetIdempotencyRetention()),
+	)
+	catalogService := service.NewCatalogService(uow)
+	statsService := service.NewStatsService(uow)
+	tokenService := service.NewTokenService(uow)
+	auditService := service.NewAuditService(uow)
+	webhookService := service.NewWebhookService(uow)
+	priceService := service.NewPriceService(uow, prices)
+	alertService := service.NewAlertService(uow)
+	notifier := newNotifier(cfg)
+	dispatcher := events.NewDispatcher(uow,
+		events.WithPollInterval(cfg.GetEventPollInterval()),
+		events.WithLogOutput(os.Stderr),
+	)
+	dispatcher.Subscribe(events.AllEvents, "webhooks", webhook.Enqueuer(uow))
+	dispatcher.Subscribe(events.AlertTriggered, "alerts", notify.AlertHandler(notifier))
+	sender := webhook.NewSender(uow,
+		webhook.WithPollInterval(cfg.GetEventPollInterval()),
+		webhook.WithLogOutput(os.Stderr),
+	)
 
-	db, err := database.InitDB(cfg.GetDBPath())
+	c := &Container{
+		DB:             db,
+		UoW:            uow,
+		Config:         cfg,
+		ItemService:    itemService,
+		CatalogService: catalogService,
+		StatsService:   statsService,
+		TokenService:   tokenService,
+		AuditService:   auditService,
+		WebhookService: webhookService,
+		PriceService:   priceService,
+		AlertService:   alertService,
+		Prices:         prices,
+		Notifier:       notifier,
+		Events:         dispatcher,
+		Webhooks:       sender,
+	}
+
+	c.Jobs, err = newScheduler(cfg, c)
 	if err != nil {
+		database.CloseDB(db)
 		return nil, err
 	}
+	return c, nil
+}
 
-	uow := repository.NewUnitOfWork(db)
+func dbSettings(cfg *config.Config) database.Settings {
+	return database.Settings{
+		ForeignKeys:     cfg.GetDBForeignKeys(),
+		JournalMode:     cfg.GetDBJournalMode(),
+		Synchronous:     cfg.GetDBSynchronous(),
+		BusyTimeout:     cfg.GetDBBusyTimeout(),
+		CacheSize:       cfg.GetDBCacheSize(),
+		MaxOpenConns:    cfg.GetDBMaxOpenConns(),
+		MaxIdleConns:    cfg.GetDBMaxIdleConns(),
+		ConnMaxLifetime: cfg.GetDBConnMaxLifetime(),
+	}
+}
 
-	itemService := service.NewItemService(uow)
+// newPriceRegistry registers the price providers configured by PRICE_FILE
+// and PRICE_API_URL, in that order.
+func newPriceRegistry(cfg *config.Config) (*pricing.Registry, error) {
+	registry := pricing.NewRegistry()
+	if path := cfg.GetPriceFile(); path != "" {
+		if err := registry.Register(pricing.NewFileProvider(path)); err != nil {
+			return nil, err
+		}
+	}
+	if url := cfg.GetPriceAPIURL(); url != "" {
+		if err := registry.Register(pricing.NewHTTPProvider(url, pricing.WithToken(cfg.GetPriceAPIToken()))); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
 
-	return &Container{
-		DB:          db,
-		UoW:         uow,
-		Config:      cfg,
-		ItemService: itemService,
-	}, nil
+// newNotifier sends alerts by email when SMTP_ADDR is set and writes them
+// to stderr otherwise.
+func newNotifier(cfg *config.Config) notify.Notifier {
+	if cfg.GetSMTPAddr() == "" {
+		return notify.NewLogNotifier(os.Stderr)
+	}
+	return notify.NewSMTPNotifier(cfg.GetSMTPAddr(), cfg.GetSMTPFrom(), cfg.GetSMTPTo(),
+		notify.WithAuth(cfg.GetSMTPUsername(), cfg.GetSMTPPassword()),
+	)
 }
 
 func (c *Container) Close() error {
@@ -0,0 +1,107 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/R4yL-dev/pkmc/internal/backup"
+	"github.com/R4yL-dev/pkmc/internal/config"
+	"github.com/R4yL-dev/pkmc/internal/scheduler"
+	"github.com/R4yL-dev/pkmc/internal/service"
+	"gorm.io/gorm"
+)
+
+// Names of the jobs registered by the container.
+const (
+	JobRefreshPrices = "refresh_prices"
+	JobBackup        = "backup"
+	JobPurgeDeleted  = "purge_deleted"
+)
+
+// scheduleOff disables a job in place of its schedule.
+const scheduleOff = "off"
+
+// newScheduler registers the periodic jobs enabled by the configuration:
+// price refreshes when a price provider is configured, backups when
+// BACKUP_DIR is set, and the purge of old deleted items.
+func newScheduler(cfg *config.Config, c *Container) (*scheduler.Scheduler, error) {
+	jobs := scheduler.NewScheduler(c.UoW, scheduler.WithLogOutput(os.Stderr))
+	logger := log.New(os.Stderr, "jobs: ", log.LstdFlags)
+
+	register := func(name, spec string, run func(ctx context.Context) error) error {
+		if strings.EqualFold(strings.TrimSpace(spec), scheduleOff) {
+			return nil
+		}
+		schedule, err := scheduler.Parse(spec)
+		if err != nil {
+			return fmt.Errorf("job %s: %w", name, err)
+		}
+		return jobs.Register(scheduler.Job{Name: name, Schedule: schedule, Run: run})
+	}
+
+	if len(c.Prices.Names()) > 0 {
+		err := register(JobRefreshPrices, cfg.GetPriceRefreshSchedule(), func(ctx context.Context) error {
+			return refreshPrices(ctx, c.PriceService)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if dir := cfg.GetBackupDir(); dir != "" {
+		err := register(JobBackup, cfg.GetBackupSchedule(), func(ctx context.Context) error {
+			return writeBackup(ctx, c.DB, dir, cfg.GetBackupKeep(), logger)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := register(JobPurgeDeleted, cfg.GetPurgeSchedule(), func(ctx context.Context) error {
+		purged, err := c.ItemService.PurgeDeleted(ctx, time.Now().Add(-cfg.GetPurgeAfter()))
+		if err != nil {
+			return err
+		}
+		if purged > 0 {
+			logger.Printf("%s: purged %d deleted items", JobPurgeDeleted, purged)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// refreshPrices refreshes every price and fails when an item could not be
+// quoted, so the failure shows in the job state.
+func refreshPrices(ctx context.Context, prices service.PriceService) error {
+	refreshes, err := prices.RefreshPrices(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, refresh := range refreshes {
+		if refresh.Status == service.PriceFailed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d items could not be quoted", failed, len(refreshes))
+	}
+	return nil
+}
+
+func writeBackup(ctx context.Context, db *gorm.DB, dir string, keep int, logger *log.Logger) error {
+	path, err := backup.ExportToDir(ctx, db, dir, keep)
+	if err != nil {
+		return err
+	}
+	logger.Printf("%s: wrote %s", JobBackup, path)
+	return nil
+}
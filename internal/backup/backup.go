@@ -0,0 +1,427 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	FormatName    = "pkmc-dump"
+	FormatVersion = 1
+)
+
+// Dump is the on-disk representation of a full database export. Tables are
+// listed in dependency order so that a table only references tables that
+// appear before it.
+type Dump struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	Tables     []Table   `json:"tables"`
+}
+
+// Table holds every row of one table, soft-deleted rows included, keyed by
+// column name.
+type Table struct {
+	Name string                       `json:"name"`
+	Rows []map[string]json.RawMessage `json:"rows"`
+}
+
+// Export writes every table of models.GetModels() to w as a JSON Dump.
+func Export(ctx context.Context, db *gorm.DB, w io.Writer) error {
+	schemas, err := orderedSchemas(db)
+	if err != nil {
+		return customErr.NewBackupError("export", err)
+	}
+
+	dump := Dump{
+		Format:     FormatName,
+		Version:    FormatVersion,
+		ExportedAt: time.Now().UTC(),
+	}
+
+	// The dump is read from one snapshot, without blocking writers.
+	err = repository.ReadTransaction(ctx, db, func(tx *gorm.DB) error {
+		for _, s := range schemas {
+			table, err := exportTable(ctx, tx, s)
+			if err != nil {
+				return customErr.NewBackupError("export", err, s.Table)
+			}
+			dump.Tables = append(dump.Tables, table)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(dump); err != nil {
+		return customErr.NewBackupError("export", err)
+	}
+	return nil
+}
+
+// Import reads a Dump from r and recreates it in db, which must be empty.
+// Primary keys are reassigned by the database and every belongs-to foreign
+// key and loose reference is remapped to the new identifiers, so
+// relationships survive the trip.
+func Import(ctx context.Context, db *gorm.DB, r io.Reader) error {
+	var dump Dump
+	if err := json.NewDecoder(r).Decode(&dump); err != nil {
+		return customErr.NewBackupError("import", err)
+	}
+	if dump.Format != FormatName || dump.Version != FormatVersion {
+		return customErr.NewBackupError("import", fmt.Errorf("%w: %s v%d", customErr.ErrBackupUnsupportedFormat, dump.Format, dump.Version))
+	}
+
+	schemas, err := orderedSchemas(db)
+	if err != nil {
+		return customErr.NewBackupError("import", err)
+	}
+	tables := tableNames(schemas)
+
+	rows := make(map[string][]map[string]json.RawMessage, len(dump.Tables))
+	for _, table := range dump.Tables {
+		rows[table.Name] = table.Rows
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, s := range schemas {
+			var count int64
+			if err := tx.Unscoped().Table(s.Table).Count(&count).Error; err != nil {
+				return customErr.NewBackupError("import", err, s.Table)
+			}
+			if count > 0 {
+				return customErr.NewBackupError("import", customErr.ErrBackupTargetNotEmpty, s.Table)
+			}
+		}
+
+		ids := make(map[string]map[uint]uint, len(schemas))
+		for _, s := range schemas {
+			mapping, err := importTable(ctx, tx, s, rows[s.Table], ids, tables)
+			if err != nil {
+				return customErr.NewBackupError("import", err, s.Table)
+			}
+			ids[s.Table] = mapping
+		}
+		return nil
+	})
+}
+
+func exportTable(ctx context.Context, tx *gorm.DB, s *schema.Schema) (Table, error) {
+	table := Table{Name: s.Table, Rows: []map[string]json.RawMessage{}}
+
+	records := reflect.New(reflect.SliceOf(s.ModelType))
+	if err := tx.Unscoped().Omit(clause.Associations).Order(s.PrioritizedPrimaryField.DBName).Find(records.Interface()).Error; err != nil {
+		return table, err
+	}
+
+	records = records.Elem()
+	for i := 0; i < records.Len(); i++ {
+		row := make(map[string]json.RawMessage, len(s.DBNames))
+		for _, dbName := range s.DBNames {
+			value, _ := s.FieldsByDBName[dbName].ValueOf(ctx, records.Index(i))
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return table, fmt.Errorf("column %s: %w", dbName, err)
+			}
+			row[dbName] = raw
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table, nil
+}
+
+func importTable(ctx context.Context, tx *gorm.DB, s *schema.Schema, rows []map[string]json.RawMessage, ids map[string]map[uint]uint, tables map[reflect.Type]string) (map[uint]uint, error) {
+	mapping := make(map[uint]uint, len(rows))
+	references := belongsTo(s)
+	loose := looseReferences[s.ModelType]
+	primary := s.PrioritizedPrimaryField
+
+	// References to rows of s itself are set once every row is created,
+	// as they may point to later rows.
+	type selfReference struct {
+		id       uint
+		column   string
+		value    reflect.Value
+		optional bool
+	}
+	var selfReferences []selfReference
+
+	for _, row := range rows {
+		record := reflect.New(s.ModelType)
+		var oldID uint
+		var deferred []selfReference
+
+		for column, raw := range row {
+			field := s.LookUpField(column)
+			if field == nil || field.DBName == "" {
+				return nil, fmt.Errorf("%w: %s", customErr.ErrBackupUnknownColumn, column)
+			}
+
+			value := reflect.New(field.FieldType)
+			if err := json.Unmarshal(raw, value.Interface()); err != nil {
+				return nil, fmt.Errorf("column %s: %w", column, err)
+			}
+
+			if field == primary {
+				oldID = toUint(value.Elem())
+				continue
+			}
+
+			if parent, ok := references[column]; ok {
+				if err := remap(value.Elem(), column, ids[parent], false); err != nil {
+					return nil, err
+				}
+			}
+
+			for _, ref := range loose {
+				if ref.column != column {
+					continue
+				}
+				table, err := ref.table(row, tables)
+				if err != nil {
+					return nil, err
+				}
+				if table == s.Table {
+					deferred = append(deferred, selfReference{column: column, value: value, optional: ref.optional})
+					value = reflect.New(field.FieldType)
+					break
+				}
+				if err := remap(value.Elem(), column, ids[table], ref.optional); err != nil {
+					return nil, err
+				}
+			}
+
+			if err := field.Set(ctx, record.Elem(), value.Elem().Interface()); err != nil {
+				return nil, fmt.Errorf("column %s: %w", column, err)
+			}
+		}
+
+		if err := tx.Omit(clause.Associations).Create(record.Interface()).Error; err != nil {
+			return nil, err
+		}
+
+		newID, _ := primary.ValueOf(ctx, record.Elem())
+		mapping[oldID] = toUint(reflect.ValueOf(newID))
+		for _, self := range deferred {
+			self.id = mapping[oldID]
+			selfReferences = append(selfReferences, self)
+		}
+	}
+
+	for _, self := range selfReferences {
+		if err := remap(self.value.Elem(), self.column, mapping, self.optional); err != nil {
+			return nil, err
+		}
+		err := tx.Table(s.Table).Where(primary.DBName+" = ?", self.id).Update(self.column, self.value.Elem().Interface()).Error
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", self.column, err)
+		}
+	}
+	return mapping, nil
+}
+
+// looseReference is an ID column that refers to rows without a belongs-to
+// relationship, which Import remaps too: either to rows of model, or to
+// rows of the entity named by the column entity of the same row. The
+// column holds one ID or a list of them. Optional references may point to
+// rows missing from the dump, such as purged items: they are cleared rather
+// than rejected.
+type looseReference struct {
+	column   string
+	model    interface{}
+	entity   string
+	optional bool
+}
+
+// looseReferences lists the loose references of each model.
+var looseReferences = map[reflect.Type][]looseReference{
+	reflect.TypeOf(models.Item{}):            {{column: "merged_into_id", model: models.Item{}, optional: true}},
+	reflect.TypeOf(models.AuditEntry{}):      {{column: "entity_id", entity: "entity", optional: true}},
+	reflect.TypeOf(models.OutboxEvent{}):     {{column: "entity_id", entity: "entity", optional: true}},
+	reflect.TypeOf(models.WebhookDelivery{}): {{column: "event_id", model: models.OutboxEvent{}}},
+	reflect.TypeOf(models.IdempotencyKey{}):  {{column: "item_ids", model: models.Item{}, optional: true}},
+}
+
+// entityModels maps the entities named by the audit trail and the outbox
+// to their models.
+var entityModels = map[string]interface{}{
+	repository.AuditEntityItem:        models.Item{},
+	repository.AuditEntityAPIToken:    models.APIToken{},
+	repository.AuditEntityWebhook:     models.Webhook{},
+	repository.AuditEntityAlert:       models.AlertRule{},
+	repository.AuditEntityPriceRecord: models.PriceRecord{},
+}
+
+// table returns the table referred to by the reference in row.
+func (r looseReference) table(row map[string]json.RawMessage, tables map[reflect.Type]string) (string, error) {
+	model := r.model
+	if r.entity != "" {
+		var entity string
+		if err := json.Unmarshal(row[r.entity], &entity); err != nil {
+			return "", fmt.Errorf("column %s: %w", r.entity, err)
+		}
+		var ok bool
+		if model, ok = entityModels[entity]; !ok {
+			return "", fmt.Errorf("column %s: unknown entity '%s'", r.entity, entity)
+		}
+	}
+	return tables[reflect.TypeOf(model)], nil
+}
+
+// tables returns every table the reference may refer to.
+func (r looseReference) tables(tables map[reflect.Type]string) []string {
+	if r.entity == "" {
+		return []string{tables[reflect.TypeOf(r.model)]}
+	}
+	names := make([]string, 0, len(entityModels))
+	for _, model := range entityModels {
+		names = append(names, tables[reflect.TypeOf(model)])
+	}
+	return names
+}
+
+// remap replaces the IDs held by v, a column referring to the rows whose
+// new IDs are in mapping. A reference to a row missing from mapping is
+// cleared when optional, and rejected otherwise.
+func remap(v reflect.Value, column string, mapping map[uint]uint, optional bool) error {
+	if v.Kind() == reflect.Slice {
+		for i := 0; i < v.Len(); i++ {
+			if err := remap(v.Index(i), column, mapping, optional); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	ref := toUint(v)
+	if ref == 0 {
+		return nil
+	}
+	newRef, found := mapping[ref]
+	switch {
+	case found:
+		setUint(v, newRef)
+	case optional:
+		v.Set(reflect.Zero(v.Type()))
+	default:
+		return fmt.Errorf("%w: %s=%d", customErr.ErrBackupDanglingReference, column, ref)
+	}
+	return nil
+}
+
+// orderedSchemas parses models.GetModels() and sorts them so that every
+// table comes after the tables it belongs to or refers to loosely.
+func orderedSchemas(db *gorm.DB) ([]*schema.Schema, error) {
+	var pending []*schema.Schema
+	for _, model := range models.GetModels() {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return nil, err
+		}
+		pending = append(pending, stmt.Schema)
+	}
+	tables := tableNames(pending)
+
+	placed := make(map[string]bool, len(pending))
+	ordered := make([]*schema.Schema, 0, len(pending))
+	for len(pending) > 0 {
+		progress := false
+		for i, s := range pending {
+			ready := true
+			for _, parent := range dependencies(s, tables) {
+				if parent != s.Table && !placed[parent] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				ordered = append(ordered, s)
+				placed[s.Table] = true
+				pending = append(pending[:i], pending[i+1:]...)
+				progress = true
+				break
+			}
+		}
+		if !progress {
+			return nil, fmt.Errorf("cyclic table dependencies involving %s", pending[0].Table)
+		}
+	}
+	return ordered, nil
+}
+
+// tableNames maps the model of each schema to its table.
+func tableNames(schemas []*schema.Schema) map[reflect.Type]string {
+	tables := make(map[reflect.Type]string, len(schemas))
+	for _, s := range schemas {
+		tables[s.ModelType] = s.Table
+	}
+	return tables
+}
+
+// dependencies returns the tables whose rows s refers to.
+func dependencies(s *schema.Schema, tables map[reflect.Type]string) []string {
+	var parents []string
+	for _, parent := range belongsTo(s) {
+		parents = append(parents, parent)
+	}
+	for _, ref := range looseReferences[s.ModelType] {
+		parents = append(parents, ref.tables(tables)...)
+	}
+	return parents
+}
+
+// belongsTo maps each foreign key column of s to the table it references.
+func belongsTo(s *schema.Schema) map[string]string {
+	refs := make(map[string]string)
+	for _, rel := range s.Relationships.BelongsTo {
+		for _, ref := range rel.References {
+			if ref.ForeignKey != nil && ref.ForeignKey.Schema == s {
+				refs[ref.ForeignKey.DBName] = rel.FieldSchema.Table
+			}
+		}
+	}
+	return refs
+}
+
+func toUint(v reflect.Value) uint {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return 0
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return uint(v.Uint())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return uint(v.Int())
+	}
+	return 0
+}
+
+func setUint(v reflect.Value, id uint) {
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(uint64(id))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(id))
+	}
+}
@@ -0,0 +1,335 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+	"github.com/R4yL-dev/pkmc/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type itemSnapshot struct {
+	Extension string
+	Block     string
+	Type      string
+	Language  string
+	Price     *float64
+	CreatedAt int64
+	UpdatedAt int64
+	Deleted   bool
+}
+
+func snapshotItems(t *testing.T, db *gorm.DB) []itemSnapshot {
+	t.Helper()
+
+	var items []models.Item
+	err := db.Unscoped().
+		Preload("Extension.Block").
+		Preload("Type").
+		Preload("Language").
+		Order("created_at, price").
+		Find(&items).Error
+	require.NoError(t, err)
+
+	snapshots := make([]itemSnapshot, 0, len(items))
+	for _, item := range items {
+		snapshots = append(snapshots, itemSnapshot{
+			Extension: item.Extension.Code,
+			Block:     item.Extension.Block.Code,
+			Type:      item.Type.Name,
+			Language:  item.Language.Code,
+			Price:     item.Price,
+			CreatedAt: item.CreatedAt.UnixNano(),
+			UpdatedAt: item.UpdatedAt.UnixNano(),
+			Deleted:   item.DeletedAt.Valid,
+		})
+	}
+	return snapshots
+}
+
+// newIDs maps the IDs of the rows of model in source to their IDs in
+// target, which Import creates in the same order.
+func newIDs(t *testing.T, source, target *gorm.DB, model interface{}) map[uint]uint {
+	t.Helper()
+
+	var before, after []uint
+	require.NoError(t, source.Unscoped().Model(model).Order("id").Pluck("id", &before).Error)
+	require.NoError(t, target.Unscoped().Model(model).Order("id").Pluck("id", &after).Error)
+	require.Len(t, after, len(before))
+
+	ids := make(map[uint]uint, len(before))
+	for i := range before {
+		ids[before[i]] = after[i]
+	}
+	return ids
+}
+
+func TestExportImport_RoundTrip(t *testing.T) {
+	// Setup - source database with gaps in the identifiers and a soft-deleted item
+	source := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, source)
+
+	ctx := context.Background()
+
+	var english, german models.Language
+	require.NoError(t, source.Where("code = ?", "en").First(&english).Error)
+	require.NoError(t, source.Where("code = ?", "de").First(&german).Error)
+	require.NoError(t, source.Unscoped().Delete(&english).Error)
+
+	var display, etb models.ItemType
+	require.NoError(t, source.Where("name = ?", "Display").First(&display).Error)
+	require.NoError(t, source.Where("name = ?", "ETB").First(&etb).Error)
+
+	var dri, svi models.Extension
+	require.NoError(t, source.Where("code = ?", "DRI").First(&dri).Error)
+	require.NoError(t, source.Where("code = ?", "SVI").First(&svi).Error)
+
+	kept := testutil.CreateTestItem(dri.ID, display.ID, german.ID)
+	unpriced := testutil.CreateTestItem(svi.ID, etb.ID, german.ID, func(i *models.Item) { i.Price = nil })
+	sold := testutil.CreateTestItem(dri.ID, etb.ID, german.ID, func(i *models.Item) { i.Price = testutil.FloatPtr(59.5) })
+	require.NoError(t, source.Create(kept).Error)
+	require.NoError(t, source.Create(unpriced).Error)
+	require.NoError(t, source.Create(sold).Error)
+	require.NoError(t, source.Delete(sold).Error)
+
+	// Setup - loose references: an item merged into a later one, and the
+	// audit entries, events, deliveries and idempotency keys of items, one
+	// of them purged
+	items := repository.NewItemRepository(source)
+	purged := testutil.CreateTestItem(dri.ID, display.ID, german.ID)
+	merged := testutil.CreateTestItem(dri.ID, display.ID, german.ID)
+	keep := testutil.CreateTestItem(dri.ID, display.ID, german.ID)
+	for _, item := range []*models.Item{purged, merged, keep} {
+		require.NoError(t, items.Create(ctx, item))
+	}
+	require.NoError(t, items.Merge(ctx, keep, merged.ID))
+
+	outbox := repository.NewOutboxRepository(source)
+	var events []*models.OutboxEvent
+	for _, item := range []*models.Item{purged, keep} {
+		event := &models.OutboxEvent{Type: "item.created", Entity: repository.AuditEntityItem, EntityID: item.ID, Payload: "{}"}
+		require.NoError(t, outbox.Append(ctx, event))
+		events = append(events, event)
+	}
+
+	webhook := &models.Webhook{Name: "shop", URL: "https://example.com/hook", Events: "item.created", Secret: "s"}
+	require.NoError(t, repository.NewWebhookRepository(source).Create(ctx, webhook))
+	require.NoError(t, repository.NewWebhookDeliveryRepository(source).Enqueue(ctx, &models.WebhookDelivery{
+		WebhookID: webhook.ID, EventID: events[1].ID, EventType: events[1].Type, Payload: "{}",
+	}))
+
+	require.NoError(t, repository.NewIdempotencyKeyRepository(source).Create(ctx, &models.IdempotencyKey{
+		Operation: "create_items", Key: "k1", RequestHash: strings.Repeat("0", 64),
+		ItemIDs: models.IDList{keep.ID, purged.ID}, ExpiresAt: time.Now().Add(time.Hour),
+	}))
+
+	require.NoError(t, source.Unscoped().Delete(purged).Error)
+	require.NoError(t, source.Delete(events[0]).Error)
+
+	// Execute - export then import into an empty database
+	var buf bytes.Buffer
+	require.NoError(t, Export(ctx, source, &buf))
+
+	target := testutil.SetupTestDBWithoutSeed(t)
+	defer testutil.CleanupTestDB(t, target)
+
+	require.NoError(t, Import(ctx, target, bytes.NewReader(buf.Bytes())))
+
+	// Assert - every table has the same number of rows, soft-deleted ones included
+	for _, model := range models.GetModels() {
+		var want, got int64
+		require.NoError(t, source.Unscoped().Model(model).Count(&want).Error)
+		require.NoError(t, target.Unscoped().Model(model).Count(&got).Error)
+		assert.Equal(t, want, got, "row count for %T", model)
+	}
+
+	// Assert - identifiers were remapped while relationships and timestamps survived
+	var importedGerman models.Language
+	require.NoError(t, target.Where("code = ?", "de").First(&importedGerman).Error)
+	assert.NotEqual(t, german.ID, importedGerman.ID, "language IDs should be reassigned")
+	assert.True(t, importedGerman.CreatedAt.Equal(german.CreatedAt))
+
+	assert.Equal(t, snapshotItems(t, source), snapshotItems(t, target))
+
+	var visible int64
+	require.NoError(t, target.Model(&models.Item{}).Count(&visible).Error)
+	assert.Equal(t, int64(3), visible, "soft-deleted items should stay deleted")
+
+	// Assert - loose references follow the remapped rows, and those of the
+	// purged item are cleared
+	itemIDs := newIDs(t, source, target, &models.Item{})
+	eventIDs := newIDs(t, source, target, &models.OutboxEvent{})
+	entityIDs := map[string]map[uint]uint{
+		repository.AuditEntityItem:    itemIDs,
+		repository.AuditEntityWebhook: newIDs(t, source, target, &models.Webhook{}),
+	}
+	assert.NotEqual(t, keep.ID, itemIDs[keep.ID], "item IDs should be reassigned")
+	assert.NotEqual(t, events[1].ID, eventIDs[events[1].ID], "event IDs should be reassigned")
+
+	var importedMerged models.Item
+	require.NoError(t, target.Unscoped().First(&importedMerged, itemIDs[merged.ID]).Error)
+	require.NotNil(t, importedMerged.MergedIntoID)
+	assert.Equal(t, itemIDs[keep.ID], *importedMerged.MergedIntoID)
+
+	var sourceEntries, targetEntries []models.AuditEntry
+	require.NoError(t, source.Order("id").Find(&sourceEntries).Error)
+	require.NoError(t, target.Order("id").Find(&targetEntries).Error)
+	require.Len(t, targetEntries, len(sourceEntries))
+	for i, entry := range sourceEntries {
+		assert.Equal(t, entityIDs[entry.Entity][entry.EntityID], targetEntries[i].EntityID, "audit entry %d of %s %d", entry.ID, entry.Entity, entry.EntityID)
+	}
+
+	var importedEvents []models.OutboxEvent
+	require.NoError(t, target.Order("id").Find(&importedEvents).Error)
+	require.Len(t, importedEvents, 1)
+	assert.Equal(t, itemIDs[keep.ID], importedEvents[0].EntityID)
+
+	var delivery models.WebhookDelivery
+	require.NoError(t, target.First(&delivery).Error)
+	assert.Equal(t, importedEvents[0].ID, delivery.EventID)
+
+	var key models.IdempotencyKey
+	require.NoError(t, target.First(&key).Error)
+	assert.Equal(t, models.IDList{itemIDs[keep.ID], 0}, key.ItemIDs)
+}
+
+func TestImport_RemapsEveryReference(t *testing.T) {
+	db := testutil.SetupTestDBWithoutSeed(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	schemas, err := orderedSchemas(db)
+	require.NoError(t, err)
+
+	// Every ID column but the primary key must be remapped, unlike string
+	// IDs such as operation IDs
+	for _, s := range schemas {
+		declared := make(map[string]bool)
+		for column := range belongsTo(s) {
+			declared[column] = true
+		}
+		for _, ref := range looseReferences[s.ModelType] {
+			declared[ref.column] = true
+		}
+		for _, column := range s.DBNames {
+			if column == s.PrioritizedPrimaryField.DBName || s.FieldsByDBName[column].FieldType.Kind() == reflect.String {
+				continue
+			}
+			if strings.HasSuffix(column, "_id") || strings.HasSuffix(column, "_ids") {
+				assert.True(t, declared[column], "%s.%s is neither a belongs-to nor a loose reference", s.Table, column)
+			}
+		}
+	}
+}
+
+func TestExport_CoversEveryModel(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	var buf bytes.Buffer
+	require.NoError(t, Export(context.Background(), db, &buf))
+
+	var dump Dump
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &dump))
+	assert.Equal(t, FormatName, dump.Format)
+	assert.Equal(t, FormatVersion, dump.Version)
+	assert.Len(t, dump.Tables, len(models.GetModels()))
+
+	position := make(map[string]int, len(dump.Tables))
+	for i, table := range dump.Tables {
+		position[table.Name] = i
+	}
+	assert.Less(t, position["blocks"], position["extensions"])
+	assert.Less(t, position["extensions"], position["items"])
+	assert.Less(t, position["languages"], position["items"])
+	assert.Less(t, position["item_types"], position["items"])
+}
+
+func TestImport_TargetNotEmpty(t *testing.T) {
+	source := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, source)
+
+	var buf bytes.Buffer
+	require.NoError(t, Export(context.Background(), source, &buf))
+
+	target := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, target)
+
+	err := Import(context.Background(), target, &buf)
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, customErr.ErrBackupTargetNotEmpty))
+
+	var backupErr *customErr.BackupError
+	assert.True(t, errors.As(err, &backupErr), "expected BackupError")
+	assert.Equal(t, "import", backupErr.Op)
+}
+
+func TestImport_UnsupportedFormat(t *testing.T) {
+	db := testutil.SetupTestDBWithoutSeed(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	err := Import(context.Background(), db, bytes.NewBufferString(`{"format":"other","version":1}`))
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, customErr.ErrBackupUnsupportedFormat))
+}
+
+func TestImport_DanglingReference(t *testing.T) {
+	db := testutil.SetupTestDBWithoutSeed(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	dump := `{"format":"pkmc-dump","version":1,"tables":[
+		{"name":"extensions","rows":[{"id":1,"name":"Orphan","code":"ORP","block_id":42}]}
+	]}`
+
+	err := Import(context.Background(), db, bytes.NewBufferString(dump))
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, customErr.ErrBackupDanglingReference))
+
+	var count int64
+	db.Model(&models.Extension{}).Count(&count)
+	assert.Zero(t, count, "failed import should be rolled back")
+}
+
+func TestExportToDir_KeepsRecentBackups(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	dir := t.TempDir()
+	for _, name := range []string{"pkmc-20260101-030000.json", "pkmc-20260102-030000.json", "pkmc-20260103-030000.json", "notes.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0o644))
+	}
+
+	// Execute
+	path, err := ExportToDir(context.Background(), db, dir, 2)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, dir, filepath.Dir(path))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var dump Dump
+	require.NoError(t, json.Unmarshal(data, &dump))
+	assert.Equal(t, FormatName, dump.Format)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.ElementsMatch(t, []string{"pkmc-20260103-030000.json", filepath.Base(path), "notes.txt"}, names)
+}
@@ -0,0 +1,83 @@
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"gorm.io/gorm"
+)
+
+const (
+	filePrefix = "pkmc-"
+	fileSuffix = ".json"
+	// fileTime names backup files so that they sort by date.
+	fileTime = "20060102-150405"
+)
+
+// ExportToDir exports db to a new file of dir, named after the time of the
+// export, then removes the oldest backups of dir beyond the keep most
+// recent ones, keeping them all when keep is 0. It returns the path of the
+// new file. A failed export leaves no partial file behind.
+func ExportToDir(ctx context.Context, db *gorm.DB, dir string, keep int) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", customErr.NewBackupError("export", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".pkmc-*.tmp")
+	if err != nil {
+		return "", customErr.NewBackupError("export", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := Export(ctx, db, tmp); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", customErr.NewBackupError("export", err)
+	}
+
+	path := filepath.Join(dir, filePrefix+time.Now().Format(fileTime)+fileSuffix)
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", customErr.NewBackupError("export", err)
+	}
+
+	if keep > 0 {
+		if err := prune(dir, keep); err != nil {
+			return path, err
+		}
+	}
+	return path, nil
+}
+
+// prune removes the oldest backup files of dir beyond the keep most recent.
+func prune(dir string, keep int) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return customErr.NewBackupError("prune", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.Type().IsRegular() && strings.HasPrefix(name, filePrefix) && strings.HasSuffix(name, fileSuffix) {
+			names = append(names, name)
+		}
+	}
+	if len(names) <= keep {
+		return nil
+	}
+
+	sort.Strings(names)
+	for _, name := range names[:len(names)-keep] {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			return customErr.NewBackupError("prune", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"context"
+	"flag"
+
+	"github.com/R4yL-dev/pkmc/internal/dto"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/service"
+)
+
+type alertCmd struct {
+	name     string
+	item     uint
+	ext      string
+	lang     string
+	itemType string
+	above    optionalFloat
+	below    optionalFloat
+	rise     optionalFloat
+	drop     optionalFloat
+	window   string
+}
+
+func (c *alertCmd) Name() string     { return "alert" }
+func (c *alertCmd) Synopsis() string { return "Manage price alert rules" }
+func (c *alertCmd) Usage() string {
+	return "alert add (--item ID | --ext CODE --lang CODE --type NAME) (--above PRICE | --below PRICE | --rise PCT --window 7d | --drop PCT --window 7d) [--name NAME] | alert list | alert remove ID"
+}
+
+func (c *alertCmd) SetFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.name, "name", "", "alert name, describes the rule when empty (add)")
+	fs.UintVar(&c.item, "item", 0, "watch this item (add)")
+	fs.StringVar(&c.ext, "ext", "", "watch the product with this extension code, e.g. DRI (add)")
+	fs.StringVar(&c.lang, "lang", "", "language code of the product, e.g. fr (add)")
+	fs.StringVar(&c.itemType, "type", "", "item type of the product, e.g. Display (add)")
+	fs.Var(&c.above, "above", "fire when the price reaches this price (add)")
+	fs.Var(&c.below, "below", "fire when the price falls to this price (add)")
+	fs.Var(&c.rise, "rise", "fire when the price rises by this percentage within --window (add)")
+	fs.Var(&c.drop, "drop", "fire when the price drops by this percentage within --window (add)")
+	fs.StringVar(&c.window, "window", "", "period of --rise and --drop, e.g. 7d or 36h (add)")
+}
+
+func (c *alertCmd) Run(ctx context.Context, env *env, args []string) error {
+	if len(args) == 0 {
+		return newUsageError("missing alert subcommand")
+	}
+
+	alerts := env.app.Container.AlertService
+	switch sub, rest := args[0], args[1:]; sub {
+	case "add":
+		if len(rest) > 0 {
+			return newUsageError("unexpected arguments: %v", rest)
+		}
+		input := service.AlertRuleInput{
+			Name:          c.name,
+			ExtensionCode: c.ext,
+			ItemType:      c.itemType,
+			LanguageCode:  c.lang,
+			Window:        c.window,
+		}
+		if c.item != 0 {
+			input.ItemID = &c.item
+		}
+
+		conditions := 0
+		for condition, flag := range map[models.AlertCondition]*optionalFloat{
+			models.AlertAbove: &c.above,
+			models.AlertBelow: &c.below,
+			models.AlertRise:  &c.rise,
+			models.AlertDrop:  &c.drop,
+		} {
+			if flag.value != nil {
+				conditions++
+				input.Condition, input.Threshold = string(condition), *flag.value
+			}
+		}
+		if conditions != 1 {
+			return newUsageError("exactly one of --above, --below, --rise and --drop is required")
+		}
+
+		rule, err := alerts.CreateRule(ctx, input)
+		if err != nil {
+			return err
+		}
+		return env.render(dto.FromAlertRule(rule))
+
+	case "list":
+		if len(rest) > 0 {
+			return newUsageError("unexpected arguments: %v", rest)
+		}
+		rules, err := alerts.ListRules(ctx)
+		if err != nil {
+			return err
+		}
+		return env.render(dto.FromAlertRules(rules))
+
+	case "remove":
+		id, err := parseID(rest)
+		if err != nil {
+			return err
+		}
+		if err := alerts.DeleteRule(ctx, id); err != nil {
+			return err
+		}
+		return env.render(dto.Deletion{ID: id, Deleted: true})
+
+	default:
+		return newUsageError("unknown alert subcommand '%s'", sub)
+	}
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/R4yL-dev/pkmc/internal/backup"
+	"github.com/R4yL-dev/pkmc/internal/config"
+)
+
+type backupCmd struct{}
+
+func (c *backupCmd) Name() string { return "backup" }
+func (c *backupCmd) Synopsis() string {
+	return "Export the whole database to JSON, or import such a backup into an empty database"
+}
+func (c *backupCmd) Usage() string             { return "backup export [FILE] | backup import FILE" }
+func (c *backupCmd) SetFlags(fs *flag.FlagSet) {}
+
+// configOptions leaves the database of an import unseeded: the backup
+// holds the reference data, and Import needs an empty database.
+func (c *backupCmd) configOptions(args []string) []config.Option {
+	if len(args) > 0 && args[0] == "import" {
+		return []config.Option{config.WithoutSeed()}
+	}
+	return nil
+}
+
+func (c *backupCmd) Run(ctx context.Context, env *env, args []string) error {
+	if len(args) == 0 {
+		return newUsageError("missing backup subcommand")
+	}
+
+	db := env.app.Container.DB
+	switch sub, rest := args[0], args[1:]; sub {
+	case "export":
+		if len(rest) > 1 {
+			return newUsageError("unexpected arguments: %v", rest[1:])
+		}
+		if len(rest) == 0 || rest[0] == "-" {
+			return backup.Export(ctx, db, env.stdout)
+		}
+		f, err := os.Create(rest[0])
+		if err != nil {
+			return newUsageError("%v", err)
+		}
+		if err := backup.Export(ctx, db, f); err != nil {
+			f.Close()
+			os.Remove(rest[0])
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		fmt.Fprintf(env.stderr, "Database exported to %s\n", rest[0])
+		return nil
+
+	case "import":
+		if len(rest) != 1 {
+			return newUsageError("expected the backup file to import, or - for the standard input")
+		}
+		var in io.Reader = env.stdin
+		if rest[0] != "-" {
+			f, err := os.Open(rest[0])
+			if err != nil {
+				return newUsageError("%v", err)
+			}
+			defer f.Close()
+			in = f
+		} else if in == nil {
+			return newUsageError("no standard input to import")
+		}
+		if err := backup.Import(ctx, db, in); err != nil {
+			return err
+		}
+		fmt.Fprintf(env.stderr, "Backup imported into %s\n", env.app.Container.Config.GetDBPath())
+		return nil
+
+	default:
+		return newUsageError("unknown backup subcommand '%s'", sub)
+	}
+}
@@ -0,0 +1,279 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os/user"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/R4yL-dev/pkmc/internal/app"
+	"github.com/R4yL-dev/pkmc/internal/config"
+	"github.com/R4yL-dev/pkmc/internal/output"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+)
+
+// command is a single pkmc subcommand. A fresh value is built for every
+// invocation so flag state never leaks between runs.
+type command interface {
+	Name() string
+	Synopsis() string
+	Usage() string
+	SetFlags(fs *flag.FlagSet)
+	Run(ctx context.Context, env *env, args []string) error
+}
+
+// sessionCommand is implemented by commands that run until the user stops
+// them. They receive the application context instead of a single operation
+// context and derive one per operation themselves.
+type sessionCommand interface {
+	command
+	session()
+}
+
+// configuredCommand is implemented by commands that need the application
+// configured for their arguments, such as a backup import, which needs the
+// database left unseeded.
+type configuredCommand interface {
+	command
+	configOptions(args []string) []config.Option
+}
+
+// env carries what a command needs to do its job.
+type env struct {
+	app    *app.Application
+	format output.Format
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// render writes a command result to stdout in the selected output format.
+func (e *env) render(v interface{}) error {
+	return output.Render(e.stdout, e.format, v)
+}
+
+// globalOptions are accepted before the subcommand and by every subcommand.
+type globalOptions struct {
+	dbPath  string
+	timeout time.Duration
+	output  string
+}
+
+func newGlobalOptions() *globalOptions {
+	return &globalOptions{output: string(output.FormatTable)}
+}
+
+func (o *globalOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.dbPath, "db", o.dbPath, "database file path (overrides DB_PATH)")
+	fs.DurationVar(&o.timeout, "timeout", o.timeout, "operation timeout, e.g. 30s (overrides DEFAULT_TIMEOUT)")
+	fs.StringVar(&o.output, "output", o.output, "output format: table, json, ndjson, csv or text")
+}
+
+func (o *globalOptions) configOptions() []config.Option {
+	return []config.Option{
+		config.WithDBPath(o.dbPath),
+		config.WithDefaultTimeout(o.timeout),
+	}
+}
+
+func commands() []command {
+	return []command{
+		&addCmd{},
+		&listCmd{},
+		&showCmd{},
+		&updateCmd{},
+		&deleteCmd{},
+		&sellCmd{},
+		&duplicatesCmd{},
+		&mergeCmd{},
+		&importCmd{},
+		&historyCmd{},
+		&undoCmd{},
+		&statsCmd{},
+		&extensionsCmd{},
+		&languagesCmd{},
+		&typesCmd{},
+		&shellCmd{},
+		&serveCmd{},
+		&tokenCmd{},
+		&webhookCmd{},
+		&pricesCmd{},
+		&alertCmd{},
+		&jobsCmd{},
+		&backupCmd{},
+	}
+}
+
+func findCommand(name string) command {
+	for _, cmd := range commands() {
+		if cmd.Name() == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// Run executes the pkmc command line and returns the process exit code.
+func Run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	opts := newGlobalOptions()
+
+	root := flag.NewFlagSet("pkmc", flag.ContinueOnError)
+	root.SetOutput(stderr)
+	root.Usage = func() { printUsage(stderr) }
+	opts.register(root)
+
+	if err := root.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		return ExitUsage
+	}
+
+	if root.NArg() == 0 {
+		printUsage(stderr)
+		return ExitUsage
+	}
+
+	name, rest := root.Arg(0), root.Args()[1:]
+	if name == "help" {
+		return runHelp(rest, stdout, stderr)
+	}
+
+	cmd := findCommand(name)
+	if cmd == nil {
+		fmt.Fprintf(stderr, "pkmc: unknown command '%s'\n\n", name)
+		printUsage(stderr)
+		return ExitUsage
+	}
+
+	fs := newFlagSet(cmd, stderr)
+	opts.register(fs)
+	positional, err := parseInterspersed(fs, rest)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		return ExitUsage
+	}
+
+	format, err := output.ParseFormat(opts.output)
+	if err != nil {
+		fmt.Fprintf(stderr, "pkmc: %v\n", err)
+		return ExitUsage
+	}
+
+	configOpts := opts.configOptions()
+	if configured, ok := cmd.(configuredCommand); ok {
+		configOpts = append(configOpts, configured.configOptions(positional)...)
+	}
+
+	application, err := app.Initialize(configOpts...)
+	if err != nil {
+		fmt.Fprintf(stderr, "pkmc: failed to initialize application: %v\n", err)
+		return exitCode(err)
+	}
+	defer application.Close()
+	application.Ctx = repository.WithActor(application.Ctx, cliActor())
+
+	e := &env{app: application, format: format, stdin: stdin, stdout: stdout, stderr: stderr}
+	return execute(e, cmd, positional)
+}
+
+// cliActor names the local user in the audit trail.
+func cliActor() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return "cli:" + u.Username
+	}
+	return "cli"
+}
+
+// execute runs cmd within a fresh operation context and reports any error.
+func execute(e *env, cmd command, args []string) int {
+	ctx, cancel := e.app.NewOperationContext()
+	if _, ok := cmd.(sessionCommand); ok {
+		ctx, cancel = context.WithCancel(e.app.Ctx)
+	}
+	defer cancel()
+
+	if err := cmd.Run(ctx, e, args); err != nil {
+		fmt.Fprintf(e.stderr, "pkmc %s: %v\n", cmd.Name(), err)
+		var usageErr *usageError
+		if errors.As(err, &usageErr) {
+			fmt.Fprintf(e.stderr, "usage: pkmc %s\n", cmd.Usage())
+		}
+		return exitCode(err)
+	}
+	return ExitOK
+}
+
+// parseInterspersed parses fs allowing positional arguments to appear
+// before, between or after flags, so that "update 3 --price 10" works.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+func newFlagSet(cmd command, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd.Name(), flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: pkmc %s\n\n%s\n\nflags:\n", cmd.Usage(), cmd.Synopsis())
+		fs.PrintDefaults()
+	}
+	cmd.SetFlags(fs)
+	return fs
+}
+
+func runHelp(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		printUsage(stdout)
+		return ExitOK
+	}
+
+	cmd := findCommand(args[0])
+	if cmd == nil {
+		fmt.Fprintf(stderr, "pkmc: unknown command '%s'\n", args[0])
+		return ExitUsage
+	}
+
+	fs := newFlagSet(cmd, stdout)
+	newGlobalOptions().register(fs)
+	fs.Usage()
+	return ExitOK
+}
+
+func printUsage(w io.Writer) {
+	cmds := commands()
+	sort.Slice(cmds, func(i, j int) bool { return cmds[i].Name() < cmds[j].Name() })
+
+	width := 0
+	for _, cmd := range cmds {
+		width = max(width, len(cmd.Name()))
+	}
+
+	var b strings.Builder
+	b.WriteString("usage: pkmc [--db PATH] [--timeout DURATION] [--output FORMAT] <command> [flags] [args]\n\ncommands:\n")
+	for _, cmd := range cmds {
+		fmt.Fprintf(&b, "  %-*s  %s\n", width, cmd.Name(), cmd.Synopsis())
+	}
+	b.WriteString("\nRun 'pkmc help <command>' for details on a command.\n")
+	fmt.Fprint(w, b.String())
+}
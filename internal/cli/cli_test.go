@@ -0,0 +1,539 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/pkmc/internal/dto"
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runCLI executes the command line against dbPath and captures its output.
+func runCLI(t *testing.T, dbPath string, args ...string) (int, string, string) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	code := Run(append([]string{"--db", dbPath}, args...), nil, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun_ItemLifecycle(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "pkmc.db")
+
+	// Add
+	code, out, errOut := runCLI(t, dbPath, "add", "--ext", "DRI", "--lang", "fr", "--type", "Display", "--price", "189.95")
+	assert.Equal(t, ExitOK, code, errOut)
+	assert.Contains(t, out, "Rivalités Destinées")
+	assert.Contains(t, out, "189.95")
+
+	// List with filters
+	code, out, _ = runCLI(t, dbPath, "list", "--ext", "DRI", "--min-price", "100")
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, out, "Display")
+
+	code, out, _ = runCLI(t, dbPath, "list", "--lang", "en")
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, out, "No results")
+
+	// Update with flags after the ID
+	code, out, errOut = runCLI(t, dbPath, "update", "1", "--lang", "en", "--clear-price", "--output", "json")
+	assert.Equal(t, ExitOK, code, errOut)
+	var item dto.Item
+	require.NoError(t, json.Unmarshal([]byte(out), &item))
+	assert.Equal(t, "en", item.LanguageCode)
+	assert.Nil(t, item.Price)
+
+	// Show
+	code, out, _ = runCLI(t, dbPath, "show", "1")
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, out, "English")
+
+	// Stats
+	code, out, _ = runCLI(t, dbPath, "--output", "json", "stats")
+	assert.Equal(t, ExitOK, code)
+	var stats dto.Stats
+	require.NoError(t, json.Unmarshal([]byte(out), &stats))
+	assert.Equal(t, int64(1), stats.Totals.Items)
+	assert.Equal(t, int64(0), stats.Totals.PricedItems)
+
+	// Sell, once
+	code, out, errOut = runCLI(t, dbPath, "sell", "1", "--price", "240", "--date", "2026-10-01", "--output", "json")
+	assert.Equal(t, ExitOK, code, errOut)
+	require.NoError(t, json.Unmarshal([]byte(out), &item))
+	require.NotNil(t, item.SoldPrice)
+	assert.Equal(t, 240.0, *item.SoldPrice)
+	assert.Equal(t, "2026-10-01", item.SoldAt.Format(time.DateOnly))
+
+	code, _, errOut = runCLI(t, dbPath, "sell", "1")
+	assert.Equal(t, ExitConflict, code)
+	assert.Contains(t, errOut, "item 1 was already sold on 2026-10-01")
+
+	// Delete, then the item is gone
+	code, _, _ = runCLI(t, dbPath, "delete", "1")
+	assert.Equal(t, ExitOK, code)
+
+	code, _, errOut = runCLI(t, dbPath, "show", "1")
+	assert.Equal(t, ExitNotFound, code)
+	assert.Contains(t, errOut, "item 1 not found")
+}
+
+func TestRun_OutputFormats(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "pkmc.db")
+	code, _, errOut := runCLI(t, dbPath, "add", "--ext", "DRI", "--lang", "fr", "--type", "Display", "--price", "189.95")
+	require.Equal(t, ExitOK, code, errOut)
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"table", "ID  EXTENSION CODE"},
+		{"json", `"extension_code": "DRI"`},
+		{"ndjson", `{"id":1,"extension_code":"DRI"`},
+		{"csv", "id,extension_code,extension_name"},
+		{"text", "- id: 1\n  extension_code: DRI\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			code, out, errOut := runCLI(t, dbPath, "list", "--output", tt.format)
+
+			assert.Equal(t, ExitOK, code, errOut)
+			assert.Contains(t, out, tt.expected)
+		})
+	}
+}
+
+func TestRun_ReferenceData(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "pkmc.db")
+
+	code, out, _ := runCLI(t, dbPath, "extensions", "--block", "ME")
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, out, "MEG")
+	assert.NotContains(t, out, "DRI")
+
+	code, out, _ = runCLI(t, dbPath, "languages")
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, out, "Français")
+
+	code, out, _ = runCLI(t, dbPath, "types")
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, out, "Sleeve Booster")
+}
+
+func TestRun_ExitCodes(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "pkmc.db")
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected int
+	}{
+		{"no command", nil, ExitUsage},
+		{"unknown command", []string{"frobnicate"}, ExitUsage},
+		{"unknown flag", []string{"list", "--colour"}, ExitUsage},
+		{"missing required flags", []string{"add", "--ext", "DRI"}, ExitUsage},
+		{"invalid id", []string{"show", "abc"}, ExitUsage},
+		{"nothing to update", []string{"update", "1"}, ExitUsage},
+		{"unknown output format", []string{"--output", "yaml", "types"}, ExitUsage},
+		{"unknown extension", []string{"add", "--ext", "NOPE", "--lang", "fr", "--type", "Display"}, ExitNotFound},
+		{"unknown block", []string{"extensions", "--block", "XX"}, ExitNotFound},
+		{"help", []string{"help", "add"}, ExitOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			args := tt.args
+			if len(args) > 0 && args[0] != "help" {
+				args = append([]string{"--db", dbPath}, args...)
+			}
+
+			code := Run(args, nil, &stdout, &stderr)
+
+			assert.Equal(t, tt.expected, code, stderr.String())
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	notFound := customErr.NewServiceError("get_item", "item_service", "item 1 not found",
+		customErr.NewRepositoryError("find", "item", "1", customErr.ErrEntityNotFound))
+
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"nil", nil, ExitOK},
+		{"usage", newUsageError("bad"), ExitUsage},
+		{"not found", notFound, ExitNotFound},
+		{"validation", customErr.NewServiceError("create_item", "item_service", "", customErr.ErrValidationFailed), ExitInvalid},
+		{"constraint", customErr.NewRepositoryError("create", "item", "new", customErr.ErrConstraintViolation), ExitConflict},
+		{"conflict", customErr.NewServiceError("undo", "audit_service", "", customErr.ErrConflict), ExitConflict},
+		{"database", customErr.NewDBError("open", errors.New("boom")), ExitUnavailable},
+		{"unit of work", customErr.NewUOWError("commit", errors.New("boom")), ExitUnavailable},
+		{"timeout", fmt.Errorf("wrapped: %w", context.DeadlineExceeded), ExitUnavailable},
+		{"busy", customErr.NewUOWBusyError(3, errors.New("database is locked")), ExitUnavailable},
+		{"canceled", fmt.Errorf("wrapped: %w", context.Canceled), ExitFailure},
+		{"invalid token", customErr.NewServiceError("authenticate", "token_service", "", customErr.ErrInvalidToken), ExitFailure},
+		{"other", errors.New("boom"), ExitFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, exitCode(tt.err))
+		})
+	}
+}
+
+func TestRun_Tokens(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "pkmc.db")
+
+	code, out, errOut := runCLI(t, dbPath, "--output", "json", "token", "issue", "--name", "phone", "--role", "editor")
+	require.Equal(t, ExitOK, code, errOut)
+	assert.Contains(t, errOut, "cannot be shown again")
+	var issued dto.IssuedToken
+	require.NoError(t, json.Unmarshal([]byte(out), &issued))
+	assert.Equal(t, "editor", issued.Role)
+	assert.NotEmpty(t, issued.Token)
+
+	code, out, _ = runCLI(t, dbPath, "token", "list")
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, out, "phone")
+	assert.NotContains(t, out, issued.Token, "listings must not show the secret")
+
+	code, _, errOut = runCLI(t, dbPath, "token", "revoke", "1")
+	assert.Equal(t, ExitOK, code, errOut)
+
+	code, _, errOut = runCLI(t, dbPath, "token", "revoke", "1")
+	assert.Equal(t, ExitNotFound, code)
+	assert.Contains(t, errOut, "active token 1 not found")
+
+	code, _, _ = runCLI(t, dbPath, "token", "issue", "--name", "phone", "--role", "owner")
+	assert.Equal(t, ExitInvalid, code)
+
+	code, _, _ = runCLI(t, dbPath, "token", "rotate")
+	assert.Equal(t, ExitUsage, code)
+}
+
+func TestRun_Webhooks(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "pkmc.db")
+
+	code, out, errOut := runCLI(t, dbPath, "--output", "json", "webhook", "add", "--url", "http://192.168.1.20:8123/hook", "--events", "item.created,item.price_changed", "--price-threshold", "200")
+	require.Equal(t, ExitOK, code, errOut)
+	assert.Contains(t, errOut, "not shown again")
+	var created dto.CreatedWebhook
+	require.NoError(t, json.Unmarshal([]byte(out), &created))
+	assert.Equal(t, "192.168.1.20:8123", created.Name)
+	assert.Equal(t, 200.0, *created.PriceThreshold)
+	assert.NotEmpty(t, created.Secret)
+
+	code, out, _ = runCLI(t, dbPath, "webhook", "list")
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, out, "item.created,item.price_changed")
+	assert.NotContains(t, out, created.Secret, "listings must not show the secret")
+
+	// Events of command line changes wait in the outbox until serve runs
+	code, _, errOut = runCLI(t, dbPath, "add", "--ext", "DRI", "--lang", "fr", "--type", "Display")
+	require.Equal(t, ExitOK, code, errOut)
+	code, out, _ = runCLI(t, dbPath, "webhook", "deliveries", "1")
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, out, "No results")
+
+	code, _, errOut = runCLI(t, dbPath, "webhook", "add", "--url", "http://home/hook", "--events", "item.shipped")
+	assert.Equal(t, ExitInvalid, code)
+	assert.Contains(t, errOut, "unknown event type 'item.shipped'")
+
+	code, _, _ = runCLI(t, dbPath, "webhook", "add", "--url", "http://home/hook")
+	assert.Equal(t, ExitUsage, code)
+
+	code, _, errOut = runCLI(t, dbPath, "webhook", "remove", "1")
+	assert.Equal(t, ExitOK, code, errOut)
+
+	code, _, _ = runCLI(t, dbPath, "webhook", "deliveries", "1")
+	assert.Equal(t, ExitNotFound, code)
+}
+
+func TestRun_History(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "pkmc.db")
+
+	code, _, errOut := runCLI(t, dbPath, "add", "--ext", "DRI", "--lang", "fr", "--type", "Display", "--price", "180")
+	require.Equal(t, ExitOK, code, errOut)
+	code, _, errOut = runCLI(t, dbPath, "update", "1", "--price", "210")
+	require.Equal(t, ExitOK, code, errOut)
+
+	code, out, errOut := runCLI(t, dbPath, "--output", "json", "history", "1")
+	require.Equal(t, ExitOK, code, errOut)
+	var changes []dto.AuditChange
+	require.NoError(t, json.Unmarshal([]byte(out), &changes))
+	last := changes[len(changes)-1]
+	assert.Equal(t, "price", last.Field)
+	assert.Equal(t, "210", *last.New)
+	assert.Contains(t, last.Actor, "cli")
+
+	code, out, _ = runCLI(t, dbPath, "history", "--since", "1h", "--entity", "item", "--output", "csv")
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, out, "entry_id,operation_id,at,actor,entity,entity_id,action,field,old,new")
+
+	code, out, _ = runCLI(t, dbPath, "history", "--until", "2000-01-01")
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, out, "No results")
+
+	code, _, _ = runCLI(t, dbPath, "history", "--since", "last week")
+	assert.Equal(t, ExitUsage, code)
+}
+
+func TestRun_Undo(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "pkmc.db")
+
+	code, _, errOut := runCLI(t, dbPath, "add", "--ext", "DRI", "--lang", "fr", "--type", "Display", "--price", "180")
+	require.Equal(t, ExitOK, code, errOut)
+	code, _, errOut = runCLI(t, dbPath, "update", "1", "--price", "1800")
+	require.Equal(t, ExitOK, code, errOut)
+
+	code, out, errOut := runCLI(t, dbPath, "--output", "json", "undo")
+	require.Equal(t, ExitOK, code, errOut)
+	var changes []dto.AuditChange
+	require.NoError(t, json.Unmarshal([]byte(out), &changes))
+	require.Len(t, changes, 1)
+	assert.Equal(t, "1800", *changes[0].Old)
+	assert.Equal(t, "180", *changes[0].New)
+
+	// An undo cannot itself be undone, nor can an operation whose
+	// changes were overwritten since
+	code, _, errOut = runCLI(t, dbPath, "undo", "--operation", changes[0].OperationID)
+	assert.Equal(t, ExitInvalid, code, errOut)
+
+	code, _, errOut = runCLI(t, dbPath, "update", "1", "--price", "200")
+	require.Equal(t, ExitOK, code, errOut)
+
+	code, out, _ = runCLI(t, dbPath, "--output", "json", "history", "1")
+	require.Equal(t, ExitOK, code)
+	require.NoError(t, json.Unmarshal([]byte(out), &changes))
+	createOp := changes[0].OperationID
+
+	code, _, errOut = runCLI(t, dbPath, "undo", "--operation", createOp)
+	assert.Equal(t, ExitConflict, code)
+	assert.Contains(t, errOut, "price was changed since")
+
+	code, _, _ = runCLI(t, dbPath, "undo", "0")
+	assert.Equal(t, ExitUsage, code)
+}
+
+func TestRun_Duplicates(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "pkmc.db")
+	add := []string{"add", "--ext", "DRI", "--lang", "fr", "--type", "Display", "--purchased", "2024-03-28"}
+
+	code, _, errOut := runCLI(t, dbPath, add...)
+	require.Equal(t, ExitOK, code, errOut)
+
+	// The default policy warns
+	code, _, errOut = runCLI(t, dbPath, add...)
+	require.Equal(t, ExitOK, code, errOut)
+	assert.Contains(t, errOut, "looks like a duplicate of item 1")
+
+	code, _, errOut = runCLI(t, dbPath, append(add, "--on-duplicate", "reject")...)
+	assert.Equal(t, ExitConflict, code)
+	assert.Contains(t, errOut, "looks like a duplicate of item 1")
+
+	code, _, errOut = runCLI(t, dbPath, append(add, "--on-duplicate", "sometimes")...)
+	assert.Equal(t, ExitUsage, code, errOut)
+
+	// Scan, then merge the pair found
+	code, out, errOut := runCLI(t, dbPath, "--output", "json", "duplicates")
+	require.Equal(t, ExitOK, code, errOut)
+	var pairs []dto.DuplicatePair
+	require.NoError(t, json.Unmarshal([]byte(out), &pairs))
+	require.Len(t, pairs, 1)
+	assert.Equal(t, uint(1), pairs[0].ItemID)
+	assert.Equal(t, uint(2), pairs[0].DuplicateID)
+
+	code, out, errOut = runCLI(t, dbPath, "--output", "json", "merge", "1", "2")
+	require.Equal(t, ExitOK, code, errOut)
+	var item dto.Item
+	require.NoError(t, json.Unmarshal([]byte(out), &item))
+	assert.Equal(t, 2, item.Quantity)
+
+	code, _, _ = runCLI(t, dbPath, "show", "2")
+	assert.Equal(t, ExitNotFound, code)
+
+	code, _, _ = runCLI(t, dbPath, "merge", "1")
+	assert.Equal(t, ExitUsage, code)
+}
+
+func TestRun_Prices(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "pkmc.db")
+
+	code, _, errOut := runCLI(t, dbPath, "add", "--ext", "DRI", "--lang", "fr", "--type", "Display")
+	require.Equal(t, ExitOK, code, errOut)
+
+	code, _, errOut = runCLI(t, dbPath, "prices", "refresh")
+	assert.Equal(t, ExitInvalid, code)
+	assert.Contains(t, errOut, "no price provider is configured")
+
+	code, out, _ := runCLI(t, dbPath, "prices", "history", "1")
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, out, "No results")
+
+	code, _, _ = runCLI(t, dbPath, "prices", "history", "2")
+	assert.Equal(t, ExitNotFound, code)
+
+	code, _, _ = runCLI(t, dbPath, "prices", "sync")
+	assert.Equal(t, ExitUsage, code)
+}
+
+func TestRun_Alerts(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "pkmc.db")
+
+	code, _, errOut := runCLI(t, dbPath, "add", "--ext", "DRI", "--lang", "fr", "--type", "Display")
+	require.Equal(t, ExitOK, code, errOut)
+
+	code, out, errOut := runCLI(t, dbPath, "alert", "add", "--ext", "DRI", "--lang", "fr", "--type", "Display", "--drop", "15", "--window", "7d")
+	require.Equal(t, ExitOK, code, errOut)
+	assert.Contains(t, out, "DRI Display (fr) drop 15% in 7d")
+
+	code, _, errOut = runCLI(t, dbPath, "alert", "add", "--item", "1", "--above", "250", "--name", "display")
+	require.Equal(t, ExitOK, code, errOut)
+
+	code, out, _ = runCLI(t, dbPath, "alert", "list")
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, out, "DRI Display (fr) drop 15% in 7d")
+	assert.Contains(t, out, "display")
+
+	code, _, errOut = runCLI(t, dbPath, "alert", "add", "--item", "1", "--drop", "15")
+	assert.Equal(t, ExitInvalid, code)
+	assert.Contains(t, errOut, "a window is required")
+
+	code, _, _ = runCLI(t, dbPath, "alert", "add", "--item", "1")
+	assert.Equal(t, ExitUsage, code)
+
+	code, _, _ = runCLI(t, dbPath, "alert", "add", "--item", "1", "--above", "250", "--below", "100")
+	assert.Equal(t, ExitUsage, code)
+
+	code, _, errOut = runCLI(t, dbPath, "alert", "remove", "1")
+	require.Equal(t, ExitOK, code, errOut)
+
+	code, _, _ = runCLI(t, dbPath, "alert", "remove", "1")
+	assert.Equal(t, ExitNotFound, code)
+}
+
+func TestRun_Jobs(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "pkmc.db")
+
+	code, out, errOut := runCLI(t, dbPath, "--output", "json", "jobs")
+	require.Equal(t, ExitOK, code, errOut)
+	assert.Contains(t, out, `"name": "purge_deleted"`)
+	assert.Contains(t, out, `"schedule": "@daily"`)
+	assert.NotContains(t, out, "refresh_prices", "no price provider is configured")
+
+	code, _, errOut = runCLI(t, dbPath, "jobs", "run", "purge_deleted")
+	require.Equal(t, ExitOK, code, errOut)
+	assert.Contains(t, errOut, "Job purge_deleted done")
+
+	code, out, _ = runCLI(t, dbPath, "--output", "json", "jobs", "list")
+	assert.Equal(t, ExitOK, code)
+	assert.Contains(t, out, `"status": "succeeded"`)
+
+	code, _, _ = runCLI(t, dbPath, "jobs", "run", "refresh_prices")
+	assert.Equal(t, ExitNotFound, code)
+
+	code, _, _ = runCLI(t, dbPath, "jobs", "run")
+	assert.Equal(t, ExitUsage, code)
+}
+
+func TestRun_Import(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "pkmc.db")
+	file := filepath.Join(t.TempDir(), "items.json")
+
+	// A failed entry adds nothing
+	require.NoError(t, os.WriteFile(file, []byte(`[
+		{"extension_code":"DRI","language_code":"fr","type":"Display","price":180},
+		{"extension_code":"XXX","language_code":"fr","type":"Display"}
+	]`), 0o600))
+	code, _, errOut := runCLI(t, dbPath, "import", file)
+	assert.Equal(t, ExitInvalid, code, errOut)
+	assert.Contains(t, errOut, "entry 1")
+	assert.Contains(t, errOut, "extension 'XXX' not found")
+
+	code, out, _ := runCLI(t, dbPath, "--output", "json", "list")
+	require.Equal(t, ExitOK, code)
+	assert.JSONEq(t, "[]", out)
+
+	// The fixed file is added at once, and only once for its key
+	require.NoError(t, os.WriteFile(file, []byte(`[
+		{"extension_code":"DRI","language_code":"fr","type":"Display","price":180},
+		{"extension_code":"SVI","language_code":"en","type":"Display","quantity":2}
+	]`), 0o600))
+	for i := 0; i < 2; i++ {
+		code, out, errOut = runCLI(t, dbPath, "--output", "json", "import", "--idempotency-key", "items.json", file)
+		require.Equal(t, ExitOK, code, errOut)
+		var items []dto.Item
+		require.NoError(t, json.Unmarshal([]byte(out), &items))
+		assert.Len(t, items, 2)
+	}
+
+	code, out, _ = runCLI(t, dbPath, "--output", "json", "list")
+	require.Equal(t, ExitOK, code)
+	var items []dto.Item
+	require.NoError(t, json.Unmarshal([]byte(out), &items))
+	assert.Len(t, items, 2)
+
+	// Entries that look like items of the collection follow the policy
+	require.NoError(t, os.WriteFile(file, []byte(`[
+		{"extension_code":"DRI","language_code":"fr","type":"Display","price":182}
+	]`), 0o600))
+	code, _, errOut = runCLI(t, dbPath, "import", file)
+	require.Equal(t, ExitOK, code, errOut)
+	assert.Contains(t, errOut, "warning: entry 0 looks like a duplicate of item 1")
+
+	code, _, errOut = runCLI(t, dbPath, "import", "--on-duplicate", "reject", file)
+	assert.Equal(t, ExitInvalid, code, errOut)
+	assert.Contains(t, errOut, "entry 0")
+
+	code, _, _ = runCLI(t, dbPath, "import", "--on-duplicate", "merge", file)
+	assert.Equal(t, ExitUsage, code)
+
+	code, _, _ = runCLI(t, dbPath, "import")
+	assert.Equal(t, ExitUsage, code)
+}
+
+func TestRun_Backup(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "source.db")
+	file := filepath.Join(dir, "backup.json")
+
+	code, _, errOut := runCLI(t, source, "add", "--ext", "DRI", "--lang", "fr", "--type", "Display", "--price", "189.95")
+	require.Equal(t, ExitOK, code, errOut)
+
+	code, _, errOut = runCLI(t, source, "backup", "export", file)
+	require.Equal(t, ExitOK, code, errOut)
+
+	// The import goes into a new database, which is left unseeded for it
+	target := filepath.Join(dir, "target.db")
+	code, _, errOut = runCLI(t, target, "backup", "import", file)
+	require.Equal(t, ExitOK, code, errOut)
+
+	code, out, errOut := runCLI(t, target, "--output", "json", "list")
+	require.Equal(t, ExitOK, code, errOut)
+	var items []dto.Item
+	require.NoError(t, json.Unmarshal([]byte(out), &items))
+	require.Len(t, items, 1)
+	assert.Equal(t, "DRI", items[0].ExtensionCode)
+
+	// A database in use is not overwritten
+	code, _, errOut = runCLI(t, source, "backup", "import", file)
+	assert.NotEqual(t, ExitOK, code)
+	assert.Contains(t, errOut, "not empty")
+
+	code, _, _ = runCLI(t, source, "backup", "restore", file)
+	assert.Equal(t, ExitUsage, code)
+}
@@ -0,0 +1,329 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/R4yL-dev/pkmc/internal/dto"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+	"github.com/R4yL-dev/pkmc/internal/service"
+)
+
+type addCmd struct {
+	extCode     string
+	langCode    string
+	typeName    string
+	price       optionalFloat
+	quantity    int
+	purchasedAt optionalTime
+	onDuplicate string
+	key         string
+}
+
+func (c *addCmd) Name() string     { return "add" }
+func (c *addCmd) Synopsis() string { return "Add an item to the collection" }
+func (c *addCmd) Usage() string {
+	return "add --ext CODE --lang CODE --type NAME [--price AMOUNT] [--quantity N] [--purchased DATE] [--on-duplicate POLICY] [--idempotency-key KEY]"
+}
+
+func (c *addCmd) SetFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.extCode, "ext", "", "extension code, e.g. DRI")
+	fs.StringVar(&c.langCode, "lang", "", "language code, e.g. fr")
+	fs.StringVar(&c.typeName, "type", "", "item type name, e.g. Display")
+	fs.Var(&c.price, "price", "price paid")
+	fs.IntVar(&c.quantity, "quantity", 1, "number of copies")
+	fs.Var(&c.purchasedAt, "purchased", "purchase date, e.g. 2024-03-28")
+	fs.StringVar(&c.onDuplicate, "on-duplicate", "", "allow, warn, reject or increment the quantity of a likely duplicate (overrides ITEM_DUPLICATE_POLICY)")
+	fs.StringVar(&c.key, "idempotency-key", "", "add the item only once for this key, printing the item added first when run again")
+}
+
+func (c *addCmd) Run(ctx context.Context, env *env, args []string) error {
+	if len(args) > 0 {
+		return newUsageError("unexpected arguments: %v", args)
+	}
+	if c.extCode == "" || c.langCode == "" || c.typeName == "" {
+		return newUsageError("--ext, --lang and --type are required")
+	}
+
+	var report service.DuplicateReport
+	opts := []service.CreateOption{service.WithQuantity(c.quantity), service.ReportDuplicates(&report)}
+	if c.purchasedAt.value != nil {
+		opts = append(opts, service.WithPurchasedAt(*c.purchasedAt.value))
+	}
+	if c.key != "" {
+		opts = append(opts, service.WithIdempotencyKey(c.key))
+	}
+	if c.onDuplicate != "" {
+		policy, err := service.ParseDuplicatePolicy(c.onDuplicate)
+		if err != nil {
+			return newUsageError("%v", err)
+		}
+		opts = append(opts, service.WithDuplicatePolicy(policy))
+	}
+
+	item, err := env.app.Container.ItemService.CreateItem(ctx, c.extCode, c.langCode, c.typeName, c.price.value, opts...)
+	if err != nil {
+		return err
+	}
+	if report.Incremented {
+		fmt.Fprintf(env.stderr, "Item %d looks the same: its quantity was raised to %d\n", item.ID, item.Quantity)
+	} else {
+		for _, match := range report.Matches {
+			fmt.Fprintf(env.stderr, "warning: the item looks like a duplicate of item %d, see 'pkmc duplicates'\n", match.ID)
+		}
+	}
+
+	return env.render(dto.FromItem(item))
+}
+
+type listCmd struct {
+	filter   repository.ItemFilter
+	minPrice optionalFloat
+	maxPrice optionalFloat
+}
+
+func (c *listCmd) Name() string     { return "list" }
+func (c *listCmd) Synopsis() string { return "List items, optionally filtered" }
+func (c *listCmd) Usage() string {
+	return "list [--ext CODE] [--block CODE] [--lang CODE] [--type NAME] [--min-price N] [--max-price N] [--limit N] [--offset N]"
+}
+
+func (c *listCmd) SetFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.filter.ExtensionCode, "ext", "", "only items of this extension code")
+	fs.StringVar(&c.filter.BlockCode, "block", "", "only items of this block code")
+	fs.StringVar(&c.filter.LanguageCode, "lang", "", "only items in this language code")
+	fs.StringVar(&c.filter.TypeName, "type", "", "only items of this type")
+	fs.Var(&c.minPrice, "min-price", "minimum price")
+	fs.Var(&c.maxPrice, "max-price", "maximum price")
+	fs.IntVar(&c.filter.Limit, "limit", 0, "maximum number of items (0 for no limit)")
+	fs.IntVar(&c.filter.Offset, "offset", 0, "number of items to skip")
+}
+
+func (c *listCmd) Run(ctx context.Context, env *env, args []string) error {
+	if len(args) > 0 {
+		return newUsageError("unexpected arguments: %v", args)
+	}
+	if c.filter.Limit < 0 || c.filter.Offset < 0 {
+		return newUsageError("--limit and --offset must not be negative")
+	}
+	c.filter.MinPrice = c.minPrice.value
+	c.filter.MaxPrice = c.maxPrice.value
+
+	items, err := env.app.Container.ItemService.ListItems(ctx, c.filter)
+	if err != nil {
+		return err
+	}
+
+	return env.render(dto.FromItems(items))
+}
+
+type showCmd struct{}
+
+func (c *showCmd) Name() string              { return "show" }
+func (c *showCmd) Synopsis() string          { return "Show one item" }
+func (c *showCmd) Usage() string             { return "show ID" }
+func (c *showCmd) SetFlags(fs *flag.FlagSet) {}
+
+func (c *showCmd) Run(ctx context.Context, env *env, args []string) error {
+	id, err := parseID(args)
+	if err != nil {
+		return err
+	}
+
+	item, err := env.app.Container.ItemService.GetItem(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return env.render(dto.FromItem(item))
+}
+
+type updateCmd struct {
+	extCode    optionalString
+	langCode   optionalString
+	typeName   optionalString
+	price      optionalFloat
+	clearPrice bool
+	quantity   optionalInt
+	purchased  optionalTime
+}
+
+func (c *updateCmd) Name() string     { return "update" }
+func (c *updateCmd) Synopsis() string { return "Update an item" }
+func (c *updateCmd) Usage() string {
+	return "update ID [--ext CODE] [--lang CODE] [--type NAME] [--price AMOUNT | --clear-price] [--quantity N] [--purchased DATE]"
+}
+
+func (c *updateCmd) SetFlags(fs *flag.FlagSet) {
+	fs.Var(&c.extCode, "ext", "new extension code")
+	fs.Var(&c.langCode, "lang", "new language code")
+	fs.Var(&c.typeName, "type", "new item type name")
+	fs.Var(&c.price, "price", "new price")
+	fs.BoolVar(&c.clearPrice, "clear-price", false, "remove the price")
+	fs.Var(&c.quantity, "quantity", "new number of copies")
+	fs.Var(&c.purchased, "purchased", "new purchase date")
+}
+
+func (c *updateCmd) Run(ctx context.Context, env *env, args []string) error {
+	id, err := parseID(args)
+	if err != nil {
+		return err
+	}
+	if c.price.value != nil && c.clearPrice {
+		return newUsageError("--price and --clear-price are mutually exclusive")
+	}
+
+	update := service.ItemUpdate{
+		ExtensionCode: c.extCode.value,
+		LanguageCode:  c.langCode.value,
+		TypeName:      c.typeName.value,
+		Price:         c.price.value,
+		ClearPrice:    c.clearPrice,
+		Quantity:      c.quantity.value,
+		PurchasedAt:   c.purchased.value,
+	}
+	if update == (service.ItemUpdate{}) {
+		return newUsageError("nothing to update")
+	}
+
+	item, err := env.app.Container.ItemService.UpdateItem(ctx, id, update)
+	if err != nil {
+		return err
+	}
+
+	return env.render(dto.FromItem(item))
+}
+
+type deleteCmd struct{}
+
+func (c *deleteCmd) Name() string              { return "delete" }
+func (c *deleteCmd) Synopsis() string          { return "Delete an item" }
+func (c *deleteCmd) Usage() string             { return "delete ID" }
+func (c *deleteCmd) SetFlags(fs *flag.FlagSet) {}
+
+func (c *deleteCmd) Run(ctx context.Context, env *env, args []string) error {
+	id, err := parseID(args)
+	if err != nil {
+		return err
+	}
+
+	if err := env.app.Container.ItemService.DeleteItem(ctx, id); err != nil {
+		return err
+	}
+
+	return env.render(dto.Deletion{ID: id, Deleted: true})
+}
+
+type sellCmd struct {
+	price  optionalFloat
+	soldAt optionalTime
+}
+
+func (c *sellCmd) Name() string     { return "sell" }
+func (c *sellCmd) Synopsis() string { return "Mark an item as sold" }
+func (c *sellCmd) Usage() string    { return "sell ID [--price AMOUNT] [--date DATE]" }
+
+func (c *sellCmd) SetFlags(fs *flag.FlagSet) {
+	fs.Var(&c.price, "price", "price sold for")
+	fs.Var(&c.soldAt, "date", "sale date, e.g. 2026-10-01 (today by default)")
+}
+
+func (c *sellCmd) Run(ctx context.Context, env *env, args []string) error {
+	id, err := parseID(args)
+	if err != nil {
+		return err
+	}
+
+	item, err := env.app.Container.ItemService.SellItem(ctx, id, c.price.value, c.soldAt.value)
+	if err != nil {
+		return err
+	}
+
+	return env.render(dto.FromItem(item))
+}
+
+type statsCmd struct{}
+
+func (c *statsCmd) Name() string              { return "stats" }
+func (c *statsCmd) Synopsis() string          { return "Show collection statistics" }
+func (c *statsCmd) Usage() string             { return "stats" }
+func (c *statsCmd) SetFlags(fs *flag.FlagSet) {}
+
+func (c *statsCmd) Run(ctx context.Context, env *env, args []string) error {
+	if len(args) > 0 {
+		return newUsageError("unexpected arguments: %v", args)
+	}
+
+	stats, err := env.app.Container.StatsService.CollectionStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	return env.render(dto.FromStats(stats))
+}
+
+type extensionsCmd struct {
+	blockCode string
+}
+
+func (c *extensionsCmd) Name() string     { return "extensions" }
+func (c *extensionsCmd) Synopsis() string { return "List known extensions" }
+func (c *extensionsCmd) Usage() string    { return "extensions [--block CODE]" }
+
+func (c *extensionsCmd) SetFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.blockCode, "block", "", "only extensions of this block code")
+}
+
+func (c *extensionsCmd) Run(ctx context.Context, env *env, args []string) error {
+	if len(args) > 0 {
+		return newUsageError("unexpected arguments: %v", args)
+	}
+
+	exts, err := env.app.Container.CatalogService.ListExtensions(ctx, c.blockCode)
+	if err != nil {
+		return err
+	}
+
+	return env.render(dto.FromExtensions(exts))
+}
+
+type languagesCmd struct{}
+
+func (c *languagesCmd) Name() string              { return "languages" }
+func (c *languagesCmd) Synopsis() string          { return "List known languages" }
+func (c *languagesCmd) Usage() string             { return "languages" }
+func (c *languagesCmd) SetFlags(fs *flag.FlagSet) {}
+
+func (c *languagesCmd) Run(ctx context.Context, env *env, args []string) error {
+	if len(args) > 0 {
+		return newUsageError("unexpected arguments: %v", args)
+	}
+
+	langs, err := env.app.Container.CatalogService.ListLanguages(ctx)
+	if err != nil {
+		return err
+	}
+
+	return env.render(dto.FromLanguages(langs))
+}
+
+type typesCmd struct{}
+
+func (c *typesCmd) Name() string              { return "types" }
+func (c *typesCmd) Synopsis() string          { return "List known item types" }
+func (c *typesCmd) Usage() string             { return "types" }
+func (c *typesCmd) SetFlags(fs *flag.FlagSet) {}
+
+func (c *typesCmd) Run(ctx context.Context, env *env, args []string) error {
+	if len(args) > 0 {
+		return newUsageError("unexpected arguments: %v", args)
+	}
+
+	itemTypes, err := env.app.Container.CatalogService.ListItemTypes(ctx)
+	if err != nil {
+		return err
+	}
+
+	return env.render(dto.FromItemTypes(itemTypes))
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"strconv"
+
+	"github.com/R4yL-dev/pkmc/internal/dto"
+)
+
+type duplicatesCmd struct{}
+
+func (c *duplicatesCmd) Name() string              { return "duplicates" }
+func (c *duplicatesCmd) Synopsis() string          { return "List items that look like duplicates" }
+func (c *duplicatesCmd) Usage() string             { return "duplicates" }
+func (c *duplicatesCmd) SetFlags(fs *flag.FlagSet) {}
+
+func (c *duplicatesCmd) Run(ctx context.Context, env *env, args []string) error {
+	if len(args) > 0 {
+		return newUsageError("unexpected arguments: %v", args)
+	}
+
+	pairs, err := env.app.Container.ItemService.FindDuplicates(ctx)
+	if err != nil {
+		return err
+	}
+
+	return env.render(dto.FromDuplicatePairs(pairs))
+}
+
+type mergeCmd struct{}
+
+func (c *mergeCmd) Name() string { return "merge" }
+func (c *mergeCmd) Synopsis() string {
+	return "Merge a duplicate into an item, keeping its copies, history and alerts"
+}
+func (c *mergeCmd) Usage() string             { return "merge KEEP_ID DUPLICATE_ID" }
+func (c *mergeCmd) SetFlags(fs *flag.FlagSet) {}
+
+func (c *mergeCmd) Run(ctx context.Context, env *env, args []string) error {
+	if len(args) != 2 {
+		return newUsageError("expected the ID of the item to keep and of its duplicate")
+	}
+	ids := make([]uint, len(args))
+	for i, arg := range args {
+		id, err := strconv.ParseUint(arg, 10, 0)
+		if err != nil || id == 0 {
+			return newUsageError("invalid ID '%s'", arg)
+		}
+		ids[i] = uint(id)
+	}
+
+	item, err := env.app.Container.ItemService.MergeItems(ctx, ids[0], ids[1])
+	if err != nil {
+		return err
+	}
+
+	return env.render(dto.FromItem(item))
+}
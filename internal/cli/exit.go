@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"fmt"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+)
+
+// Exit codes returned by Run. Scripts can rely on them staying stable.
+const (
+	ExitOK          = 0
+	ExitFailure     = 1
+	ExitUsage       = 2
+	ExitNotFound    = 3
+	ExitInvalid     = 4
+	ExitConflict    = 5
+	ExitUnavailable = 6
+)
+
+// usageError reports a command invoked with missing or malformed arguments.
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func (e *usageError) ErrorCode() customErr.Code {
+	return customErr.CodeBadRequest
+}
+
+func newUsageError(format string, args ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// exitCodes maps the families of error codes to exit codes.
+var exitCodes = map[customErr.Code]int{
+	customErr.CodeBadRequest:       ExitUsage,
+	customErr.CodeNotFound:         ExitNotFound,
+	customErr.CodeValidationFailed: ExitInvalid,
+	customErr.CodeConflict:         ExitConflict,
+	customErr.CodeTimeout:          ExitUnavailable,
+	customErr.CodeUnavailable:      ExitUnavailable,
+}
+
+// exitCode maps an error from the internal/errors hierarchy to an exit
+// code, after the family of its code.
+func exitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	if code, ok := exitCodes[customErr.CodeOf(err).Family()]; ok {
+		return code
+	}
+	return ExitFailure
+}
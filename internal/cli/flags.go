@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// optionalString is a string flag that remembers whether it was given.
+type optionalString struct {
+	value *string
+}
+
+func (f *optionalString) String() string {
+	if f.value == nil {
+		return ""
+	}
+	return *f.value
+}
+
+func (f *optionalString) Set(s string) error {
+	f.value = &s
+	return nil
+}
+
+// optionalFloat is a float flag that remembers whether it was given.
+type optionalFloat struct {
+	value *float64
+}
+
+func (f *optionalFloat) String() string {
+	if f.value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f.value, 'f', -1, 64)
+}
+
+func (f *optionalFloat) Set(s string) error {
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return err
+	}
+	f.value = &v
+	return nil
+}
+
+// optionalInt is an integer flag that remembers whether it was given.
+type optionalInt struct {
+	value *int
+}
+
+func (f *optionalInt) String() string {
+	if f.value == nil {
+		return ""
+	}
+	return strconv.Itoa(*f.value)
+}
+
+func (f *optionalInt) Set(s string) error {
+	v, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return err
+	}
+	f.value = &v
+	return nil
+}
+
+// optionalTime is a time flag that remembers whether it was given. It
+// accepts RFC 3339 timestamps, local dates and times ("2006-01-02",
+// "2006-01-02 15:04") and durations counted back from now ("36h").
+type optionalTime struct {
+	value *time.Time
+}
+
+func (f *optionalTime) String() string {
+	if f.value == nil {
+		return ""
+	}
+	return f.value.Format(time.RFC3339)
+}
+
+func (f *optionalTime) Set(s string) error {
+	s = strings.TrimSpace(s)
+	if d, err := time.ParseDuration(s); err == nil {
+		t := time.Now().Add(-d)
+		f.value = &t
+		return nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		f.value = &t
+		return nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			f.value = &t
+			return nil
+		}
+	}
+	return fmt.Errorf("expected a date, an RFC 3339 time or a duration such as 36h")
+}
+
+func parseID(args []string) (uint, error) {
+	if len(args) != 1 {
+		return 0, newUsageError("expected exactly one ID")
+	}
+	id, err := strconv.ParseUint(args[0], 10, 0)
+	if err != nil || id == 0 {
+		return 0, newUsageError("invalid ID '%s'", args[0])
+	}
+	return uint(id), nil
+}
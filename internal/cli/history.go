@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"context"
+	"flag"
+
+	"github.com/R4yL-dev/pkmc/internal/dto"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+)
+
+type historyCmd struct {
+	filter repository.AuditFilter
+	since  optionalTime
+	until  optionalTime
+}
+
+func (c *historyCmd) Name() string     { return "history" }
+func (c *historyCmd) Synopsis() string { return "Show the audit trail of changes" }
+func (c *historyCmd) Usage() string {
+	return "history [ITEM_ID] [--since TIME] [--until TIME] [--entity item|api_token|webhook|alert_rule|price_record] [--operation ID] [--limit N]"
+}
+
+func (c *historyCmd) SetFlags(fs *flag.FlagSet) {
+	fs.Var(&c.since, "since", "only changes at or after this time (date, RFC 3339 or duration ago, e.g. 24h)")
+	fs.Var(&c.until, "until", "only changes before this time")
+	fs.StringVar(&c.filter.Entity, "entity", "", "only changes of this entity: item, api_token, webhook, alert_rule or price_record")
+	fs.StringVar(&c.filter.OperationID, "operation", "", "only changes made by this operation")
+	fs.IntVar(&c.filter.Limit, "limit", 0, "maximum number of entries (0 for no limit)")
+}
+
+func (c *historyCmd) Run(ctx context.Context, env *env, args []string) error {
+	if c.filter.Limit < 0 {
+		return newUsageError("--limit must not be negative")
+	}
+
+	if len(args) > 0 {
+		id, err := parseID(args)
+		if err != nil {
+			return err
+		}
+		if c.filter.Entity != "" && c.filter.Entity != repository.AuditEntityItem {
+			return newUsageError("--entity cannot be combined with an item ID")
+		}
+		c.filter.Entity = repository.AuditEntityItem
+		c.filter.EntityID = id
+	}
+
+	c.filter.Since = c.since.value
+	c.filter.Until = c.until.value
+	entries, err := env.app.Container.AuditService.ListChanges(ctx, c.filter)
+	if err != nil {
+		return err
+	}
+	return env.render(dto.FromAuditEntries(entries))
+}
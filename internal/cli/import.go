@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/R4yL-dev/pkmc/internal/dto"
+	"github.com/R4yL-dev/pkmc/internal/service"
+)
+
+type importCmd struct {
+	key         string
+	onDuplicate string
+}
+
+func (c *importCmd) Name() string { return "import" }
+func (c *importCmd) Synopsis() string {
+	return "Add the items of a JSON file at once, or none when an entry fails"
+}
+func (c *importCmd) Usage() string {
+	return "import [--on-duplicate POLICY] [--idempotency-key KEY] FILE"
+}
+
+func (c *importCmd) SetFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.onDuplicate, "on-duplicate", "", "allow, warn, reject or increment the quantity of the likely duplicate of each entry (overrides ITEM_DUPLICATE_POLICY)")
+	fs.StringVar(&c.key, "idempotency-key", "", "add the items only once for this key, printing the items added first when run again")
+}
+
+// Run reads a JSON array of items, shaped like the entries of the items
+// batch of the API, from FILE or from the standard input when FILE is "-".
+func (c *importCmd) Run(ctx context.Context, env *env, args []string) error {
+	if len(args) != 1 {
+		return newUsageError("expected the file to import, or - for the standard input")
+	}
+
+	var in io.Reader = env.stdin
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return newUsageError("%v", err)
+		}
+		defer f.Close()
+		in = f
+	} else if in == nil {
+		return newUsageError("no standard input to import")
+	}
+
+	var batch dto.ItemBatchCreate
+	dec := json.NewDecoder(in)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&batch.Items); err != nil {
+		return newUsageError("invalid items in %s: %v", args[0], err)
+	}
+
+	var reports []service.DuplicateReport
+	opts := []service.CreateOption{service.ReportBatchDuplicates(&reports)}
+	if c.key != "" {
+		opts = append(opts, service.WithIdempotencyKey(c.key))
+	}
+	if c.onDuplicate != "" {
+		policy, err := service.ParseDuplicatePolicy(c.onDuplicate)
+		if err != nil {
+			return newUsageError("%v", err)
+		}
+		opts = append(opts, service.WithDuplicatePolicy(policy))
+	}
+
+	items, err := env.app.Container.ItemService.CreateItems(ctx, batch.Specs(), opts...)
+	if err != nil {
+		return err
+	}
+	for i, report := range reports {
+		if report.Incremented {
+			fmt.Fprintf(env.stderr, "Entry %d looks like item %d: its quantity was raised to %d\n", i, items[i].ID, items[i].Quantity)
+			continue
+		}
+		for _, match := range report.Matches {
+			fmt.Fprintf(env.stderr, "warning: entry %d looks like a duplicate of item %d, see 'pkmc duplicates'\n", i, match.ID)
+		}
+	}
+
+	return env.render(dto.FromItems(items))
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/R4yL-dev/pkmc/internal/dto"
+)
+
+type jobsCmd struct{}
+
+func (c *jobsCmd) Name() string     { return "jobs" }
+func (c *jobsCmd) Synopsis() string { return "Show and run the periodic jobs of serve" }
+func (c *jobsCmd) Usage() string    { return "jobs [list] | jobs run NAME" }
+
+func (c *jobsCmd) SetFlags(fs *flag.FlagSet) {}
+
+func (c *jobsCmd) Run(ctx context.Context, env *env, args []string) error {
+	jobs := env.app.Container.Jobs
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	switch sub, rest := args[0], args[1:]; sub {
+	case "list":
+		if len(rest) > 0 {
+			return newUsageError("unexpected arguments: %v", rest)
+		}
+		states, err := jobs.States(ctx)
+		if err != nil {
+			return err
+		}
+		return env.render(dto.FromJobStates(states))
+
+	case "run":
+		if len(rest) != 1 {
+			return newUsageError("expected one job name")
+		}
+		if err := jobs.RunNow(ctx, rest[0]); err != nil {
+			return err
+		}
+		fmt.Fprintf(env.stderr, "Job %s done\n", rest[0])
+
+		// Deliver the events of the run now, so the alerts of a price
+		// refresh are notified without waiting for serve.
+		if err := env.app.Container.Events.DispatchAll(ctx); err != nil {
+			fmt.Fprintf(env.stderr, "pkmc: events not delivered yet: %v\n", err)
+		}
+		return nil
+
+	default:
+		return newUsageError("unknown jobs subcommand '%s'", sub)
+	}
+}
@@ -0,0 +1,299 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// errInterrupted is returned by ReadLine when the user presses Ctrl-C.
+var errInterrupted = errors.New("interrupted")
+
+// lineReader reads one command line at a time.
+type lineReader interface {
+	ReadLine(prompt string) (string, error)
+}
+
+// plainReader reads lines from a non-interactive input such as a pipe.
+// It prints no prompt so piped scripts produce clean output.
+type plainReader struct {
+	scanner *bufio.Scanner
+}
+
+func newPlainReader(r io.Reader) *plainReader {
+	return &plainReader{scanner: bufio.NewScanner(r)}
+}
+
+func (r *plainReader) ReadLine(prompt string) (string, error) {
+	if r.scanner.Scan() {
+		return r.scanner.Text(), nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", io.EOF
+}
+
+// completeFunc returns the raw word before the cursor that is being
+// completed and the candidates that may replace it.
+type completeFunc func(head string) (word string, candidates []string)
+
+// lineEditor is a minimal readline for terminals: cursor movement, history
+// navigation and tab completion.
+type lineEditor struct {
+	fd       int
+	in       *bufio.Reader
+	out      io.Writer
+	history  *history
+	complete completeFunc
+}
+
+func newLineEditor(in *os.File, out io.Writer, hist *history, complete completeFunc) *lineEditor {
+	return &lineEditor{
+		fd:       int(in.Fd()),
+		in:       bufio.NewReader(in),
+		out:      out,
+		history:  hist,
+		complete: complete,
+	}
+}
+
+// editState is the line being edited.
+type editState struct {
+	prompt string
+	buf    []rune
+	pos    int
+}
+
+func (s *editState) insert(runes ...rune) {
+	tail := append([]rune{}, s.buf[s.pos:]...)
+	s.buf = append(append(s.buf[:s.pos], runes...), tail...)
+	s.pos += len(runes)
+}
+
+func (s *editState) set(line string) {
+	s.buf = []rune(line)
+	s.pos = len(s.buf)
+}
+
+func (e *lineEditor) ReadLine(prompt string) (string, error) {
+	state, err := makeRaw(e.fd)
+	if err != nil {
+		return "", err
+	}
+	defer restoreTerminal(e.fd, state)
+
+	s := &editState{prompt: prompt}
+	entries := e.history.entries()
+	histPos := len(entries)
+	draft := ""
+
+	e.redraw(s)
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+
+		switch r {
+		case '\r', '\n':
+			fmt.Fprint(e.out, "\r\n")
+			return string(s.buf), nil
+		case 3: // Ctrl-C
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", errInterrupted
+		case 4: // Ctrl-D
+			if len(s.buf) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			if s.pos < len(s.buf) {
+				s.buf = append(s.buf[:s.pos], s.buf[s.pos+1:]...)
+			}
+		case 127, 8: // Backspace
+			if s.pos > 0 {
+				s.buf = append(s.buf[:s.pos-1], s.buf[s.pos:]...)
+				s.pos--
+			}
+		case 1: // Ctrl-A
+			s.pos = 0
+		case 5: // Ctrl-E
+			s.pos = len(s.buf)
+		case 11: // Ctrl-K
+			s.buf = s.buf[:s.pos]
+		case 21: // Ctrl-U
+			s.buf = s.buf[s.pos:]
+			s.pos = 0
+		case 12: // Ctrl-L
+			fmt.Fprint(e.out, "\x1b[H\x1b[2J")
+		case '\t':
+			e.completeWord(s)
+		case 27: // Escape sequence
+			switch e.readEscape() {
+			case "[A": // Up
+				if histPos > 0 {
+					if histPos == len(entries) {
+						draft = string(s.buf)
+					}
+					histPos--
+					s.set(entries[histPos])
+				}
+			case "[B": // Down
+				if histPos < len(entries) {
+					histPos++
+					if histPos == len(entries) {
+						s.set(draft)
+					} else {
+						s.set(entries[histPos])
+					}
+				}
+			case "[C": // Right
+				if s.pos < len(s.buf) {
+					s.pos++
+				}
+			case "[D": // Left
+				if s.pos > 0 {
+					s.pos--
+				}
+			case "[H", "OH", "[1~": // Home
+				s.pos = 0
+			case "[F", "OF", "[4~": // End
+				s.pos = len(s.buf)
+			case "[3~": // Delete
+				if s.pos < len(s.buf) {
+					s.buf = append(s.buf[:s.pos], s.buf[s.pos+1:]...)
+				}
+			}
+		default:
+			if r >= 32 && r != utf8.RuneError {
+				s.insert(r)
+			}
+		}
+		e.redraw(s)
+	}
+}
+
+// readEscape reads the rest of an ANSI escape sequence after ESC.
+func (e *lineEditor) readEscape() string {
+	var seq strings.Builder
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return seq.String()
+		}
+		seq.WriteRune(r)
+		if seq.Len() > 1 && (r == '~' || (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z')) {
+			return seq.String()
+		}
+		if seq.Len() > 8 {
+			return seq.String()
+		}
+	}
+}
+
+func (e *lineEditor) redraw(s *editState) {
+	fmt.Fprintf(e.out, "\r%s%s\x1b[K", s.prompt, string(s.buf))
+	if back := len(s.buf) - s.pos; back > 0 {
+		fmt.Fprintf(e.out, "\x1b[%dD", back)
+	}
+}
+
+// completeWord replaces the word before the cursor with the single
+// candidate or the longest common prefix, and lists the candidates when
+// there is nothing to insert.
+func (e *lineEditor) completeWord(s *editState) {
+	if e.complete == nil {
+		return
+	}
+	word, candidates := e.complete(string(s.buf[:s.pos]))
+	if len(candidates) == 0 {
+		return
+	}
+
+	wordLen := utf8.RuneCountInString(word)
+	replacement := candidates[0] + " "
+	if len(candidates) > 1 {
+		replacement = commonPrefix(candidates)
+		if utf8.RuneCountInString(replacement) <= wordLen {
+			fmt.Fprintf(e.out, "\r\n%s\r\n", strings.Join(candidates, "  "))
+			return
+		}
+	}
+
+	start := s.pos - wordLen
+	s.buf = append(s.buf[:start], s.buf[s.pos:]...)
+	s.pos = start
+	s.insert([]rune(replacement)...)
+}
+
+func commonPrefix(values []string) string {
+	prefix := []rune(values[0])
+	for _, v := range values[1:] {
+		runes := []rune(v)
+		n := 0
+		for n < len(prefix) && n < len(runes) && prefix[n] == runes[n] {
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	return string(prefix)
+}
+
+// history keeps the lines entered in the shell and appends them to a file
+// so they survive between sessions. An empty path keeps history in memory.
+type history struct {
+	path  string
+	lines []string
+	max   int
+}
+
+func loadHistory(path string, max int) (*history, error) {
+	h := &history{path: path, max: max}
+	if path == "" {
+		return h, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			h.lines = append(h.lines, line)
+		}
+	}
+	if len(h.lines) > max {
+		h.lines = h.lines[len(h.lines)-max:]
+	}
+	return h, nil
+}
+
+func (h *history) entries() []string {
+	return h.lines
+}
+
+// add records line unless it repeats the previous entry.
+func (h *history) add(line string) error {
+	if len(h.lines) > 0 && h.lines[len(h.lines)-1] == line {
+		return nil
+	}
+	h.lines = append(h.lines, line)
+	if len(h.lines) > h.max {
+		h.lines = h.lines[len(h.lines)-h.max:]
+	}
+
+	if h.path == "" {
+		return nil
+	}
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+	defer f.Close()
+	_, err = fmt.Fprintln(f, line)
+	return err
+}
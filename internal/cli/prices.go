@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/R4yL-dev/pkmc/internal/dto"
+	"github.com/R4yL-dev/pkmc/internal/service"
+)
+
+type pricesCmd struct {
+	provider string
+	limit    int
+}
+
+func (c *pricesCmd) Name() string     { return "prices" }
+func (c *pricesCmd) Synopsis() string { return "Refresh market prices and show price history" }
+func (c *pricesCmd) Usage() string {
+	return "prices refresh [--provider NAME] | prices history ID [--limit N] | prices providers"
+}
+
+func (c *pricesCmd) SetFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.provider, "provider", "", "only ask this price provider, e.g. file or http (refresh)")
+	fs.IntVar(&c.limit, "limit", 20, "maximum number of quotes, 0 for no limit (history)")
+}
+
+func (c *pricesCmd) Run(ctx context.Context, env *env, args []string) error {
+	if len(args) == 0 {
+		return newUsageError("missing prices subcommand")
+	}
+
+	prices := env.app.Container.PriceService
+	switch sub, rest := args[0], args[1:]; sub {
+	case "refresh":
+		if len(rest) > 0 {
+			return newUsageError("unexpected arguments: %v", rest)
+		}
+		refreshes, err := prices.RefreshPrices(ctx, c.provider)
+		if err != nil {
+			return err
+		}
+		if err := env.render(dto.FromPriceRefreshes(refreshes)); err != nil {
+			return err
+		}
+		fmt.Fprintln(env.stderr, summarizeRefresh(refreshes))
+
+		// Deliver the events of the refresh now, so triggered alerts are
+		// notified without waiting for serve.
+		if err := env.app.Container.Events.DispatchAll(ctx); err != nil {
+			fmt.Fprintf(env.stderr, "pkmc: alerts not notified yet: %v\n", err)
+		}
+		return nil
+
+	case "history":
+		if c.limit < 0 {
+			return newUsageError("--limit must not be negative")
+		}
+		id, err := parseID(rest)
+		if err != nil {
+			return err
+		}
+		records, err := prices.PriceHistory(ctx, id, c.limit)
+		if err != nil {
+			return err
+		}
+		return env.render(dto.FromPriceRecords(records))
+
+	case "providers":
+		if len(rest) > 0 {
+			return newUsageError("unexpected arguments: %v", rest)
+		}
+		names := prices.Providers()
+		if len(names) == 0 {
+			fmt.Fprintln(env.stderr, "No price provider configured: set PRICE_FILE or PRICE_API_URL.")
+			return nil
+		}
+		return env.render(dto.FromPriceProviders(names))
+
+	default:
+		return newUsageError("unknown prices subcommand '%s'", sub)
+	}
+}
+
+// summarizeRefresh counts the items of a refresh by outcome.
+func summarizeRefresh(refreshes []service.PriceRefresh) string {
+	counts := make(map[service.PriceRefreshStatus]int)
+	for _, refresh := range refreshes {
+		counts[refresh.Status]++
+	}
+	return fmt.Sprintf("%d items: %d updated, %d unchanged, %d without quote, %d failed",
+		len(refreshes), counts[service.PriceUpdated], counts[service.PriceUnchanged], counts[service.PriceMissing], counts[service.PriceFailed])
+}
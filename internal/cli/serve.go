@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/R4yL-dev/pkmc/internal/api"
+	"github.com/R4yL-dev/pkmc/internal/database"
+)
+
+type serveCmd struct {
+	addr   string
+	noAuth bool
+}
+
+func (c *serveCmd) Name() string     { return "serve" }
+func (c *serveCmd) Synopsis() string { return "Serve the REST API" }
+func (c *serveCmd) Usage() string    { return "serve [--addr HOST:PORT] [--no-auth]" }
+func (c *serveCmd) session()         {}
+
+func (c *serveCmd) SetFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.addr, "addr", "", "listen address (overrides HTTP_ADDR)")
+	fs.BoolVar(&c.noAuth, "no-auth", false, "serve without token authentication (trusted networks only)")
+}
+
+func (c *serveCmd) Run(ctx context.Context, env *env, args []string) error {
+	if len(args) > 0 {
+		return newUsageError("unexpected arguments: %v", args)
+	}
+
+	addr := c.addr
+	if addr == "" {
+		addr = env.app.Container.Config.GetHTTPAddr()
+	}
+
+	var opts []api.Option
+	if c.noAuth {
+		opts = append(opts, api.WithoutAuth())
+		fmt.Fprintln(env.stderr, "warning: authentication is disabled, anyone who can reach the server can modify the collection")
+	} else if err := warnWithoutTokens(env); err != nil {
+		return err
+	}
+
+	report, err := database.Inspect(env.app.Container.DB)
+	if err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Deliver the events of changes made through the API, and of those
+	// made by other commands since the last run, post webhooks and run the
+	// periodic jobs.
+	container := env.app.Container
+	var background sync.WaitGroup
+	for _, run := range []func(context.Context){container.Events.Run, container.Webhooks.Run, container.Jobs.Run} {
+		background.Add(1)
+		go func(run func(context.Context)) {
+			defer background.Done()
+			run(ctx)
+		}(run)
+	}
+
+	fmt.Fprintf(env.stdout, "Database: %s\n", report)
+	fmt.Fprintf(env.stdout, "Listening on http://%s%s\n", ln.Addr(), api.BasePath)
+	fmt.Fprintf(env.stdout, "Web interface on http://%s/\n", ln.Addr())
+	err = api.NewServer(env.app, opts...).Serve(ctx, ln)
+	stop()
+	background.Wait()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(env.stdout, "Server stopped")
+	return nil
+}
+
+// warnWithoutTokens tells the user how to get access when no active token
+// exists yet.
+func warnWithoutTokens(env *env) error {
+	opCtx, cancel := env.app.NewOperationContext()
+	defer cancel()
+
+	tokens, err := env.app.Container.TokenService.ListTokens(opCtx)
+	if err != nil {
+		return err
+	}
+	for _, token := range tokens {
+		if token.Active() {
+			return nil
+		}
+	}
+	fmt.Fprintln(env.stderr, "No active API token: issue one with 'pkmc token issue --name NAME --role admin'")
+	return nil
+}
@@ -0,0 +1,325 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/R4yL-dev/pkmc/internal/output"
+)
+
+const (
+	shellPrompt     = "pkmc> "
+	historyFileName = ".pkmc_history"
+	historySize     = 1000
+)
+
+type shellCmd struct {
+	historyPath string
+}
+
+func (c *shellCmd) Name() string     { return "shell" }
+func (c *shellCmd) Synopsis() string { return "Start an interactive shell" }
+func (c *shellCmd) Usage() string    { return "shell [--history PATH]" }
+func (c *shellCmd) session()         {}
+
+func (c *shellCmd) SetFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.historyPath, "history", defaultHistoryPath(), "history file (empty to disable)")
+}
+
+func defaultHistoryPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, historyFileName)
+}
+
+func (c *shellCmd) Run(ctx context.Context, env *env, args []string) error {
+	if len(args) > 0 {
+		return newUsageError("unexpected arguments: %v", args)
+	}
+
+	hist, err := loadHistory(c.historyPath, historySize)
+	if err != nil {
+		return err
+	}
+
+	var reader lineReader
+	if f, ok := env.stdin.(*os.File); ok && isTerminal(int(f.Fd())) {
+		values, err := completionValues(env)
+		if err != nil {
+			return err
+		}
+		reader = newLineEditor(f, env.stdout, hist, newCompleter(commands(), values).Complete)
+		fmt.Fprintln(env.stdout, `pkmc shell - type "help" for commands, "exit" to quit`)
+	} else if env.stdin != nil {
+		reader = newPlainReader(env.stdin)
+	} else {
+		return newUsageError("shell needs an input stream")
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil
+		}
+
+		line, err := reader.ReadLine(shellPrompt)
+		if errors.Is(err, errInterrupted) {
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := hist.add(line); err != nil {
+			fmt.Fprintf(env.stderr, "pkmc: %v\n", err)
+		}
+		if quit := runShellLine(env, line); quit {
+			return nil
+		}
+	}
+}
+
+// runShellLine executes one shell line like a pkmc invocation without the
+// global flags, except --output. It reports whether the shell should exit.
+func runShellLine(e *env, line string) bool {
+	words, err := splitArgs(line)
+	if err != nil {
+		fmt.Fprintf(e.stderr, "pkmc: %v\n", err)
+		return false
+	}
+
+	name, rest := words[0], words[1:]
+	switch name {
+	case "exit", "quit":
+		return true
+	case "help":
+		runHelp(rest, e.stdout, e.stderr)
+		return false
+	}
+
+	cmd := findCommand(name)
+	if _, nested := cmd.(sessionCommand); cmd == nil || nested {
+		fmt.Fprintf(e.stderr, "pkmc: unknown command '%s'\n", name)
+		return false
+	}
+
+	fs := newFlagSet(cmd, e.stderr)
+	format := string(e.format)
+	fs.StringVar(&format, "output", format, "output format: table, json, ndjson, csv or text")
+	positional, err := parseInterspersed(fs, rest)
+	if err != nil {
+		return false
+	}
+
+	lineFormat, err := output.ParseFormat(format)
+	if err != nil {
+		fmt.Fprintf(e.stderr, "pkmc: %v\n", err)
+		return false
+	}
+
+	execute(&env{app: e.app, format: lineFormat, stdin: e.stdin, stdout: e.stdout, stderr: e.stderr}, cmd, positional)
+	return false
+}
+
+// word is one shell word: its value with quotes removed and its raw text.
+type word struct {
+	value string
+	raw   string
+}
+
+// scanWords splits s on whitespace, honouring single and double quotes.
+// open reports an unterminated quote.
+func scanWords(s string) (words []word, open bool) {
+	var value, raw strings.Builder
+	var quote rune
+	inWord := false
+
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			raw.WriteRune(r)
+			if r == quote {
+				quote = 0
+			} else {
+				value.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			raw.WriteRune(r)
+			quote = r
+			inWord = true
+		case unicode.IsSpace(r):
+			if inWord {
+				words = append(words, word{value: value.String(), raw: raw.String()})
+				value.Reset()
+				raw.Reset()
+				inWord = false
+			}
+		default:
+			raw.WriteRune(r)
+			value.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word{value: value.String(), raw: raw.String()})
+	}
+	return words, quote != 0
+}
+
+// splitArgs splits a shell line into arguments.
+func splitArgs(line string) ([]string, error) {
+	words, open := scanWords(line)
+	if open {
+		return nil, errors.New("unterminated quote")
+	}
+	if len(words) == 0 {
+		return nil, errors.New("empty command")
+	}
+
+	args := make([]string, len(words))
+	for i, w := range words {
+		args[i] = w.value
+	}
+	return args, nil
+}
+
+// completer suggests command names, flag names and flag values.
+type completer struct {
+	commands []string
+	flags    map[string][]string
+	values   map[string][]string
+}
+
+// newCompleter builds a completer for cmds. values maps a flag name without
+// dashes (e.g. "ext") to the values it accepts.
+func newCompleter(cmds []command, values map[string][]string) *completer {
+	c := &completer{
+		commands: []string{"exit", "help", "quit"},
+		flags:    make(map[string][]string),
+		values:   values,
+	}
+
+	for _, cmd := range cmds {
+		if _, ok := cmd.(sessionCommand); ok {
+			continue
+		}
+		c.commands = append(c.commands, cmd.Name())
+
+		fs := newFlagSet(cmd, io.Discard)
+		names := []string{"--output"}
+		fs.VisitAll(func(f *flag.Flag) { names = append(names, "--"+f.Name) })
+		sort.Strings(names)
+		c.flags[cmd.Name()] = names
+	}
+	sort.Strings(c.commands)
+
+	return c
+}
+
+// Complete implements completeFunc.
+func (c *completer) Complete(head string) (string, []string) {
+	words, open := scanWords(head)
+
+	current := word{}
+	if len(words) > 0 && (open || !endsWithSpace(head)) {
+		current = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	var pool []string
+	switch {
+	case len(words) == 0:
+		pool = c.commands
+	case len(words) == 1 && words[0].value == "help":
+		pool = c.commands
+	case strings.HasPrefix(current.value, "-"):
+		pool = c.flags[words[0].value]
+	default:
+		flagName := strings.TrimLeft(words[len(words)-1].value, "-")
+		if strings.HasPrefix(words[len(words)-1].value, "-") {
+			pool = c.values[flagName]
+		}
+	}
+
+	var candidates []string
+	for _, candidate := range pool {
+		if hasFoldPrefix(candidate, current.value) {
+			candidates = append(candidates, quoteWord(candidate))
+		}
+	}
+	return current.raw, candidates
+}
+
+func endsWithSpace(s string) bool {
+	return s != "" && unicode.IsSpace(rune(s[len(s)-1]))
+}
+
+func hasFoldPrefix(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+func quoteWord(s string) string {
+	if strings.ContainsAny(s, " \t'\"") {
+		return `"` + s + `"`
+	}
+	return s
+}
+
+// completionValues loads the reference data offered by tab completion.
+func completionValues(e *env) (map[string][]string, error) {
+	ctx, cancel := e.app.NewOperationContext()
+	defer cancel()
+
+	catalog := e.app.Container.CatalogService
+
+	exts, err := catalog.ListExtensions(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := catalog.ListBlocks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	langs, err := catalog.ListLanguages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	itemTypes, err := catalog.ListItemTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string][]string{}
+	for _, ext := range exts {
+		values["ext"] = append(values["ext"], ext.Code)
+	}
+	for _, block := range blocks {
+		values["block"] = append(values["block"], block.Code)
+	}
+	for _, lang := range langs {
+		values["lang"] = append(values["lang"], lang.Code)
+	}
+	for _, itemType := range itemTypes {
+		values["type"] = append(values["type"], itemType.Name)
+	}
+	for _, format := range output.Formats() {
+		values["output"] = append(values["output"], string(format))
+	}
+	return values, nil
+}
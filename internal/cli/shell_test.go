@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		expected []string
+		wantErr  bool
+	}{
+		{"simple", "list --ext DRI", []string{"list", "--ext", "DRI"}, false},
+		{"extra spaces", "  show   1 ", []string{"show", "1"}, false},
+		{"double quotes", `add --type "Sleeve Booster"`, []string{"add", "--type", "Sleeve Booster"}, false},
+		{"single quotes", `add --type 'Sleeve Booster'`, []string{"add", "--type", "Sleeve Booster"}, false},
+		{"empty quotes", `list --ext ""`, []string{"list", "--ext", ""}, false},
+		{"unterminated quote", `add --type "Sleeve`, nil, true},
+		{"empty", "   ", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := splitArgs(tt.line)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, args)
+		})
+	}
+}
+
+func TestCompleter_Complete(t *testing.T) {
+	c := newCompleter(commands(), map[string][]string{
+		"ext":  {"DRI", "DRM", "SVI"},
+		"lang": {"en", "fr"},
+		"type": {"Display", "Sleeve Booster"},
+	})
+
+	tests := []struct {
+		name       string
+		head       string
+		word       string
+		candidates []string
+	}{
+		{"command names", "li", "li", []string{"list"}},
+		{"all commands", "", "", []string{"add", "alert", "backup", "delete", "duplicates", "exit", "extensions", "help", "history", "import", "jobs", "languages", "list", "merge", "prices", "quit", "sell", "show", "stats", "token", "types", "undo", "update", "webhook"}},
+		{"help topic", "help up", "up", []string{"update"}},
+		{"flag names", "add --l", "--l", []string{"--lang"}},
+		{"extension codes", "add --ext dr", "dr", []string{"DRI", "DRM"}},
+		{"value after space", "list --lang ", "", []string{"en", "fr"}},
+		{"quoted type names", "add --type Sl", "Sl", []string{`"Sleeve Booster"`}},
+		{"inside quotes", `add --type "Sleeve B`, `"Sleeve B`, []string{`"Sleeve Booster"`}},
+		{"positional", "show 1", "1", nil},
+		{"unknown flag", "add --frob x", "x", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			word, candidates := c.Complete(tt.head)
+
+			assert.Equal(t, tt.word, word)
+			assert.Equal(t, tt.candidates, candidates)
+		})
+	}
+}
+
+func TestCommonPrefix(t *testing.T) {
+	assert.Equal(t, "DR", commonPrefix([]string{"DRI", "DRM"}))
+	assert.Equal(t, "", commonPrefix([]string{"DRI", "SVI"}))
+	assert.Equal(t, `"Sleeve `, commonPrefix([]string{`"Sleeve Booster"`, `"Sleeve Case"`}))
+}
+
+func TestShell_Session(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "pkmc.db")
+	historyPath := filepath.Join(dir, "history")
+
+	script := strings.Join([]string{
+		"# unboxing session",
+		`add --ext DRI --lang fr --type "Sleeve Booster" --price 5`,
+		"add --ext DRI --lang fr --type Display",
+		"list --output csv",
+		"show 42",
+		"shell",
+		"exit",
+		"stats",
+	}, "\n")
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--db", dbPath, "shell", "--history", historyPath}, strings.NewReader(script), &stdout, &stderr)
+
+	assert.Equal(t, ExitOK, code, stderr.String())
+	assert.Contains(t, stdout.String(), "1,DRI,Rivalités Destinées,EV,Sleeve Booster,fr,Français,5,")
+	assert.Contains(t, stdout.String(), "2,DRI,Rivalités Destinées,EV,Display,fr,Français,,")
+	assert.NotContains(t, stdout.String(), "TOTALS", "commands after exit must not run")
+	assert.Contains(t, stderr.String(), "item 42 not found")
+	assert.Contains(t, stderr.String(), "unknown command 'shell'")
+
+	data, err := os.ReadFile(historyPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Equal(t, `add --ext DRI --lang fr --type "Sleeve Booster" --price 5`, lines[0])
+	assert.Equal(t, "exit", lines[len(lines)-1])
+}
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+
+	hist, err := loadHistory(path, 2)
+	require.NoError(t, err)
+	require.NoError(t, hist.add("list"))
+	require.NoError(t, hist.add("list"))
+	require.NoError(t, hist.add("stats"))
+	require.NoError(t, hist.add("types"))
+	assert.Equal(t, []string{"stats", "types"}, hist.entries())
+
+	reloaded, err := loadHistory(path, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"stats", "types"}, reloaded.entries())
+}
@@ -0,0 +1,22 @@
+//go:build !linux
+
+package cli
+
+import "errors"
+
+// termState is the terminal configuration saved by makeRaw.
+type termState struct{}
+
+// The line editor is only available on Linux; other platforms read plain
+// lines from stdin.
+func isTerminal(fd int) bool {
+	return false
+}
+
+func makeRaw(fd int) (*termState, error) {
+	return nil, errors.New("raw terminal mode is not supported on this platform")
+}
+
+func restoreTerminal(fd int, state *termState) error {
+	return nil
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/R4yL-dev/pkmc/internal/dto"
+	"github.com/R4yL-dev/pkmc/internal/models"
+)
+
+type tokenCmd struct {
+	name string
+	role string
+}
+
+func (c *tokenCmd) Name() string     { return "token" }
+func (c *tokenCmd) Synopsis() string { return "Manage API tokens" }
+func (c *tokenCmd) Usage() string {
+	return "token issue --name NAME [--role readonly|editor|admin] | token list | token revoke ID"
+}
+
+func (c *tokenCmd) SetFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.name, "name", "", "token name, e.g. the person or device using it (issue)")
+	fs.StringVar(&c.role, "role", string(models.RoleReadOnly), "token role: readonly, editor or admin (issue)")
+}
+
+func (c *tokenCmd) Run(ctx context.Context, env *env, args []string) error {
+	if len(args) == 0 {
+		return newUsageError("missing token subcommand")
+	}
+
+	tokens := env.app.Container.TokenService
+	switch sub, rest := args[0], args[1:]; sub {
+	case "issue":
+		if len(rest) > 0 {
+			return newUsageError("unexpected arguments: %v", rest)
+		}
+		if c.name == "" {
+			return newUsageError("--name is required")
+		}
+		token, secret, err := tokens.IssueToken(ctx, c.name, models.Role(c.role))
+		if err != nil {
+			return err
+		}
+		if err := env.render(dto.FromIssuedToken(token, secret)); err != nil {
+			return err
+		}
+		fmt.Fprintln(env.stderr, "Store this token now: it cannot be shown again.")
+		return nil
+
+	case "list":
+		if len(rest) > 0 {
+			return newUsageError("unexpected arguments: %v", rest)
+		}
+		list, err := tokens.ListTokens(ctx)
+		if err != nil {
+			return err
+		}
+		return env.render(dto.FromAPITokens(list))
+
+	case "revoke":
+		id, err := parseID(rest)
+		if err != nil {
+			return err
+		}
+		if err := tokens.RevokeToken(ctx, id); err != nil {
+			return err
+		}
+		return env.render(dto.Deletion{ID: id, Deleted: true})
+
+	default:
+		return newUsageError("unknown token subcommand '%s'", sub)
+	}
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"strconv"
+
+	"github.com/R4yL-dev/pkmc/internal/dto"
+	"github.com/R4yL-dev/pkmc/internal/models"
+)
+
+type undoCmd struct {
+	operation string
+}
+
+func (c *undoCmd) Name() string     { return "undo" }
+func (c *undoCmd) Synopsis() string { return "Revert recent changes to items" }
+func (c *undoCmd) Usage() string    { return "undo [N] | undo --operation ID" }
+
+func (c *undoCmd) SetFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.operation, "operation", "", "revert this operation (see 'pkmc history') instead of the most recent ones")
+}
+
+func (c *undoCmd) Run(ctx context.Context, env *env, args []string) error {
+	if len(args) > 1 {
+		return newUsageError("unexpected arguments: %v", args[1:])
+	}
+
+	var entries []models.AuditEntry
+	var err error
+	if c.operation != "" {
+		if len(args) > 0 {
+			return newUsageError("--operation cannot be combined with a count")
+		}
+		entries, err = env.app.Container.AuditService.UndoOperation(ctx, c.operation)
+	} else {
+		n := 1
+		if len(args) == 1 {
+			if n, err = strconv.Atoi(args[0]); err != nil || n < 1 {
+				return newUsageError("invalid count '%s'", args[0])
+			}
+		}
+		entries, err = env.app.Container.AuditService.UndoLast(ctx, n)
+	}
+	if err != nil {
+		return err
+	}
+
+	return env.render(dto.FromAuditEntries(entries))
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/R4yL-dev/pkmc/internal/dto"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+	"github.com/R4yL-dev/pkmc/internal/service"
+)
+
+type webhookCmd struct {
+	name      string
+	url       string
+	events    string
+	secret    string
+	threshold optionalFloat
+	status    string
+	limit     int
+}
+
+func (c *webhookCmd) Name() string     { return "webhook" }
+func (c *webhookCmd) Synopsis() string { return "Manage webhooks called on collection changes" }
+func (c *webhookCmd) Usage() string {
+	return "webhook add --url URL --events TYPES [--name NAME] [--secret SECRET] [--price-threshold PRICE] | webhook list | webhook remove ID | webhook deliveries [ID] [--status STATUS] [--limit N]"
+}
+
+func (c *webhookCmd) SetFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.url, "url", "", "URL the events are posted to (add)")
+	fs.StringVar(&c.events, "events", "", "comma-separated event types, or * for all, e.g. item.created,item.price_changed (add)")
+	fs.StringVar(&c.name, "name", "", "webhook name, defaults to the host of the URL (add)")
+	fs.StringVar(&c.secret, "secret", "", "signing secret, generated when empty (add)")
+	fs.Var(&c.threshold, "price-threshold", "only send price changes that cross this price (add)")
+	fs.StringVar(&c.status, "status", "", "only deliveries with this status: pending, succeeded or failed (deliveries)")
+	fs.IntVar(&c.limit, "limit", 20, "maximum number of deliveries, 0 for no limit (deliveries)")
+}
+
+func (c *webhookCmd) Run(ctx context.Context, env *env, args []string) error {
+	if len(args) == 0 {
+		return newUsageError("missing webhook subcommand")
+	}
+
+	webhooks := env.app.Container.WebhookService
+	switch sub, rest := args[0], args[1:]; sub {
+	case "add":
+		if len(rest) > 0 {
+			return newUsageError("unexpected arguments: %v", rest)
+		}
+		if c.url == "" || c.events == "" {
+			return newUsageError("--url and --events are required")
+		}
+		webhook, err := webhooks.CreateWebhook(ctx, service.WebhookInput{
+			Name:           c.name,
+			URL:            c.url,
+			Events:         strings.Split(c.events, ","),
+			Secret:         c.secret,
+			PriceThreshold: c.threshold.value,
+		})
+		if err != nil {
+			return err
+		}
+		if err := env.render(dto.FromCreatedWebhook(webhook)); err != nil {
+			return err
+		}
+		if c.secret == "" {
+			fmt.Fprintln(env.stderr, "Configure the receiver with this secret to check signatures: it is not shown again.")
+		}
+		return nil
+
+	case "list":
+		if len(rest) > 0 {
+			return newUsageError("unexpected arguments: %v", rest)
+		}
+		list, err := webhooks.ListWebhooks(ctx)
+		if err != nil {
+			return err
+		}
+		return env.render(dto.FromWebhooks(list))
+
+	case "remove":
+		id, err := parseID(rest)
+		if err != nil {
+			return err
+		}
+		if err := webhooks.DeleteWebhook(ctx, id); err != nil {
+			return err
+		}
+		return env.render(dto.Deletion{ID: id, Deleted: true})
+
+	case "deliveries":
+		if c.limit < 0 {
+			return newUsageError("--limit must not be negative")
+		}
+		filter := repository.WebhookDeliveryFilter{Status: models.DeliveryStatus(c.status), Limit: c.limit}
+		if len(rest) > 0 {
+			id, err := parseID(rest)
+			if err != nil {
+				return err
+			}
+			filter.WebhookID = id
+		}
+		deliveries, err := webhooks.ListDeliveries(ctx, filter)
+		if err != nil {
+			return err
+		}
+		return env.render(dto.FromWebhookDeliveries(deliveries))
+
+	default:
+		return newUsageError("unknown webhook subcommand '%s'", sub)
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -10,8 +11,38 @@ import (
 type Config struct {
 	dbPath         string
 	defaultTimeout time.Duration
+	busyRetry      time.Duration
+	dbForeignKeys  bool
+	dbJournalMode  string
+	dbSynchronous  string
+	dbBusyTimeout  time.Duration
+	dbCacheSize    int
+	dbMaxOpen      int
+	dbMaxIdle      int
+	dbMaxLifetime  time.Duration
+	httpAddr       string
+	eventPoll      time.Duration
+	priceFile      string
+	priceAPIURL    string
+	priceAPIToken  string
+	smtpAddr       string
+	smtpFrom       string
+	smtpTo         []string
+	smtpUsername   string
+	smtpPassword   string
+	priceRefresh   string
+	backupDir      string
+	backupSchedule string
+	backupKeep     int
+	purgeSchedule  string
+	purgeAfter     time.Duration
+	duplicates     string
+	idempotency    time.Duration
+	skipSeed       bool
 }
 
+type Option func(*Config)
+
 var (
 	instance *Config
 	once     sync.Once
@@ -22,6 +53,33 @@ func Load() *Config {
 		instance = &Config{
 			dbPath:         getEnv("DB_PATH", "pkmc.db"),
 			defaultTimeout: getDurationEnv("DEFAULT_TIMEOUT", 30*time.Second),
+			busyRetry:      getDurationEnv("DB_BUSY_RETRY", 5*time.Second),
+			dbForeignKeys:  getBoolEnv("DB_FOREIGN_KEYS", true),
+			dbJournalMode:  getEnv("DB_JOURNAL_MODE", "WAL"),
+			dbSynchronous:  getEnv("DB_SYNCHRONOUS", "NORMAL"),
+			dbBusyTimeout:  getDurationEnv("DB_BUSY_TIMEOUT", 5*time.Second),
+			dbCacheSize:    getIntEnv("DB_CACHE_SIZE", 16*1024),
+			dbMaxOpen:      getIntEnv("DB_MAX_OPEN_CONNS", 8),
+			dbMaxIdle:      getIntEnv("DB_MAX_IDLE_CONNS", 4),
+			dbMaxLifetime:  getDurationEnv("DB_CONN_MAX_LIFETIME", 0),
+			httpAddr:       getEnv("HTTP_ADDR", ":8080"),
+			eventPoll:      getDurationEnv("EVENT_POLL_INTERVAL", 2*time.Second),
+			priceFile:      getEnv("PRICE_FILE", ""),
+			priceAPIURL:    getEnv("PRICE_API_URL", ""),
+			priceAPIToken:  getEnv("PRICE_API_TOKEN", ""),
+			smtpAddr:       getEnv("SMTP_ADDR", ""),
+			smtpFrom:       getEnv("SMTP_FROM", "pkmc@localhost"),
+			smtpTo:         getListEnv("SMTP_TO"),
+			smtpUsername:   getEnv("SMTP_USERNAME", ""),
+			smtpPassword:   getEnv("SMTP_PASSWORD", ""),
+			priceRefresh:   getEnv("PRICE_REFRESH_SCHEDULE", "@every 6h"),
+			backupDir:      getEnv("BACKUP_DIR", ""),
+			backupSchedule: getEnv("BACKUP_SCHEDULE", "@daily"),
+			backupKeep:     getIntEnv("BACKUP_KEEP", 7),
+			purgeSchedule:  getEnv("PURGE_SCHEDULE", "@daily"),
+			purgeAfter:     time.Duration(getIntEnv("PURGE_AFTER_DAYS", 30)) * 24 * time.Hour,
+			duplicates:     getEnv("ITEM_DUPLICATE_POLICY", "warn"),
+			idempotency:    time.Duration(getIntEnv("IDEMPOTENCY_RETENTION_HOURS", 24)) * time.Hour,
 		}
 	})
 	return instance
@@ -34,14 +92,189 @@ func Get() *Config {
 	return instance
 }
 
+func WithDBPath(path string) Option {
+	return func(c *Config) {
+		if path != "" {
+			c.dbPath = path
+		}
+	}
+}
+
+func WithDefaultTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		if timeout > 0 {
+			c.defaultTimeout = timeout
+		}
+	}
+}
+
+func WithHTTPAddr(addr string) Option {
+	return func(c *Config) {
+		if addr != "" {
+			c.httpAddr = addr
+		}
+	}
+}
+
+// WithoutSeed leaves the reference data out of the database, so that a
+// backup can be imported into it.
+func WithoutSeed() Option {
+	return func(c *Config) {
+		c.skipSeed = true
+	}
+}
+
+// With returns a copy of the configuration with the given overrides
+// applied, leaving the shared instance untouched.
+func (c *Config) With(opts ...Option) *Config {
+	cfg := *c
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &cfg
+}
+
 func (c *Config) GetDBPath() string {
 	return c.dbPath
 }
 
+func (c *Config) GetSkipSeed() bool {
+	return c.skipSeed
+}
+
 func (c *Config) GetDefaultTimeout() time.Duration {
 	return c.defaultTimeout
 }
 
+// GetBusyRetry returns how long a transaction is retried while the
+// database is locked by another connection, on top of the busy timeout its
+// first attempt waited.
+func (c *Config) GetBusyRetry() time.Duration {
+	return c.busyRetry
+}
+
+// GetDBForeignKeys reports whether foreign key constraints are enforced.
+func (c *Config) GetDBForeignKeys() bool {
+	return c.dbForeignKeys
+}
+
+func (c *Config) GetDBJournalMode() string {
+	return c.dbJournalMode
+}
+
+func (c *Config) GetDBSynchronous() string {
+	return c.dbSynchronous
+}
+
+// GetDBBusyTimeout returns how long a statement waits for a lock held by
+// another connection before failing.
+func (c *Config) GetDBBusyTimeout() time.Duration {
+	return c.dbBusyTimeout
+}
+
+// GetDBCacheSize returns the page cache of each connection, in KiB.
+func (c *Config) GetDBCacheSize() int {
+	return c.dbCacheSize
+}
+
+// GetDBMaxOpenConns returns the limit of open connections, 0 for none.
+func (c *Config) GetDBMaxOpenConns() int {
+	return c.dbMaxOpen
+}
+
+func (c *Config) GetDBMaxIdleConns() int {
+	return c.dbMaxIdle
+}
+
+// GetDBConnMaxLifetime returns after how long a connection is closed, 0
+// for never.
+func (c *Config) GetDBConnMaxLifetime() time.Duration {
+	return c.dbMaxLifetime
+}
+
+func (c *Config) GetHTTPAddr() string {
+	return c.httpAddr
+}
+
+func (c *Config) GetEventPollInterval() time.Duration {
+	return c.eventPoll
+}
+
+func (c *Config) GetPriceFile() string {
+	return c.priceFile
+}
+
+func (c *Config) GetPriceAPIURL() string {
+	return c.priceAPIURL
+}
+
+func (c *Config) GetPriceAPIToken() string {
+	return c.priceAPIToken
+}
+
+func (c *Config) GetSMTPAddr() string {
+	return c.smtpAddr
+}
+
+func (c *Config) GetSMTPFrom() string {
+	return c.smtpFrom
+}
+
+func (c *Config) GetSMTPTo() []string {
+	return c.smtpTo
+}
+
+func (c *Config) GetSMTPUsername() string {
+	return c.smtpUsername
+}
+
+func (c *Config) GetSMTPPassword() string {
+	return c.smtpPassword
+}
+
+// GetPriceRefreshSchedule returns when prices are refreshed while serving,
+// "off" to never refresh them.
+func (c *Config) GetPriceRefreshSchedule() string {
+	return c.priceRefresh
+}
+
+// GetBackupDir returns where scheduled backups are written, empty to
+// disable them.
+func (c *Config) GetBackupDir() string {
+	return c.backupDir
+}
+
+func (c *Config) GetBackupSchedule() string {
+	return c.backupSchedule
+}
+
+// GetBackupKeep returns how many scheduled backups are kept, 0 for all.
+func (c *Config) GetBackupKeep() int {
+	return c.backupKeep
+}
+
+func (c *Config) GetPurgeSchedule() string {
+	return c.purgeSchedule
+}
+
+// GetPurgeAfter returns how long deleted items are kept before they are
+// purged.
+func (c *Config) GetPurgeAfter() time.Duration {
+	return c.purgeAfter
+}
+
+// GetDuplicatePolicy returns what adding an item that looks like one of the
+// collection does: allow, warn, reject or increment.
+func (c *Config) GetDuplicatePolicy() string {
+	return c.duplicates
+}
+
+// GetIdempotencyRetention returns how long the idempotency keys of item
+// creations are remembered.
+func (c *Config) GetIdempotencyRetention() time.Duration {
+	return c.idempotency
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -57,3 +290,32 @@ func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	}
 	return defaultValue
 }
+
+func getIntEnv(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return defaultValue
+}
+
+func getBoolEnv(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
+// getListEnv reads a comma-separated list.
+func getListEnv(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -1,6 +1,11 @@
 package database
 
 import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -8,8 +13,44 @@ import (
 	customErr "github.com/R4yL-dev/pkmc/internal/errors"
 )
 
-func InitDB(dbPath string) (*gorm.DB, error) {
-	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+// Settings are the pragmas applied to every connection to the database and
+// the limits of the connection pool.
+type Settings struct {
+	ForeignKeys bool
+	// JournalMode is DELETE, TRUNCATE, PERSIST, MEMORY, WAL or OFF.
+	JournalMode string
+	// Synchronous is OFF, NORMAL, FULL or EXTRA.
+	Synchronous string
+	BusyTimeout time.Duration
+	// CacheSize is the page cache of each connection, in KiB.
+	CacheSize int
+	// MaxOpenConns and MaxIdleConns limit the connections of the pool, 0
+	// meaning no limit and the database/sql default respectively.
+	MaxOpenConns int
+	MaxIdleConns int
+	// ConnMaxLifetime closes connections older than it, 0 never.
+	ConnMaxLifetime time.Duration
+}
+
+// DefaultSettings enforces foreign keys and lets readers run alongside the
+// writer, which WAL makes safe to sync less often.
+func DefaultSettings() Settings {
+	return Settings{
+		ForeignKeys:  true,
+		JournalMode:  "WAL",
+		Synchronous:  "NORMAL",
+		BusyTimeout:  5 * time.Second,
+		CacheSize:    16 * 1024,
+		MaxOpenConns: 8,
+		MaxIdleConns: 4,
+	}
+}
+
+// InitDB opens the database at dbPath with settings and checks that they
+// took effect. Parameters of the connection string in dbPath take
+// precedence over settings, and fail the check when they disagree.
+func InitDB(dbPath string, settings Settings) (*gorm.DB, error) {
+	db, err := gorm.Open(sqlite.Open(dsn(dbPath, settings)), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Silent),
 	})
 	if err != nil {
@@ -21,13 +62,93 @@ func InitDB(dbPath string) (*gorm.DB, error) {
 		return nil, customErr.NewDBError("get_sql_db", err)
 	}
 
+	sqlDB.SetMaxOpenConns(settings.MaxOpenConns)
+	if settings.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(settings.MaxIdleConns)
+	}
+	sqlDB.SetConnMaxLifetime(settings.ConnMaxLifetime)
+
 	if err := sqlDB.Ping(); err != nil {
+		sqlDB.Close()
 		return nil, customErr.NewDBError("ping", err)
 	}
 
+	report, err := Inspect(db)
+	if err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+	if err := report.Check(settings); err != nil {
+		sqlDB.Close()
+		return nil, customErr.NewDBError("verify", err, dbPath)
+	}
+
 	return db, nil
 }
 
+// dsn returns the connection string of the database at dbPath, which sets
+// the pragmas of settings on every new connection. Transactions begin with
+// BEGIN IMMEDIATE, so a writer takes the database lock up front: two
+// transactions can then no longer both read and then fail to upgrade to
+// writing, and a busy database is reported when the transaction starts,
+// where it is safe to retry.
+func dsn(dbPath string, settings Settings) string {
+	// A negative cache_size is in KiB rather than pages.
+	cacheSize := ""
+	if settings.CacheSize > 0 {
+		cacheSize = fmt.Sprint(-settings.CacheSize)
+	}
+
+	params := []struct {
+		key, value string
+		// aliases are the other names of the parameter in go-sqlite3.
+		aliases []string
+	}{
+		{"_txlock", "immediate", nil},
+		{"_foreign_keys", boolParam(settings.ForeignKeys), []string{"_fk"}},
+		{"_journal_mode", settings.JournalMode, []string{"_journal"}},
+		{"_synchronous", settings.Synchronous, []string{"_sync"}},
+		{"_busy_timeout", fmt.Sprint(settings.BusyTimeout.Milliseconds()), []string{"_timeout"}},
+		{"_cache_size", cacheSize, nil},
+	}
+
+	_, query, _ := strings.Cut(dbPath, "?")
+	existing, _ := url.ParseQuery(query)
+
+	var added []string
+	for _, param := range params {
+		if param.value == "" || existing.Has(param.key) || hasAny(existing, param.aliases) {
+			continue
+		}
+		added = append(added, param.key+"="+url.QueryEscape(param.value))
+	}
+	if len(added) == 0 {
+		return dbPath
+	}
+
+	separator := "?"
+	if strings.Contains(dbPath, "?") {
+		separator = "&"
+	}
+	return dbPath + separator + strings.Join(added, "&")
+}
+
+func boolParam(on bool) string {
+	if on {
+		return "1"
+	}
+	return "0"
+}
+
+func hasAny(values url.Values, keys []string) bool {
+	for _, key := range keys {
+		if values.Has(key) {
+			return true
+		}
+	}
+	return false
+}
+
 func CloseDB(db *gorm.DB) error {
 	if db == nil {
 		return nil
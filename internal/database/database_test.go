@@ -3,7 +3,9 @@ package database
 import (
 	"errors"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	customErr "github.com/R4yL-dev/pkmc/internal/errors"
 	"github.com/stretchr/testify/assert"
@@ -15,14 +17,146 @@ func TestInitDB_Success(t *testing.T) {
 	require.NoError(t, err)
 	defer os.Remove(tmpFile.Name())
 
-	db, err := InitDB(tmpFile.Name())
+	db, err := InitDB(tmpFile.Name(), DefaultSettings())
 	assert.NoError(t, err)
 	assert.NotNil(t, db)
 	defer CloseDB(db)
 }
 
+func TestInitDB_ImmediateTransactions(t *testing.T) {
+	// Setup
+	path := filepath.Join(t.TempDir(), "pkmc.db")
+	writer, err := InitDB(path, DefaultSettings())
+	require.NoError(t, err)
+	defer CloseDB(writer)
+	settings := DefaultSettings()
+	settings.BusyTimeout = 10 * time.Millisecond
+	other, err := InitDB(path, settings)
+	require.NoError(t, err)
+	defer CloseDB(other)
+
+	// Execute: a transaction that has not written anything yet
+	tx := writer.Begin()
+	require.NoError(t, tx.Error)
+	defer tx.Rollback()
+
+	// Assert: it holds the write lock already
+	blocked := other.Begin()
+	require.Error(t, blocked.Error)
+	assert.Contains(t, blocked.Error.Error(), "database is locked")
+}
+
+func TestDSN(t *testing.T) {
+	settings := DefaultSettings()
+	defaults := "_txlock=immediate&_foreign_keys=1&_journal_mode=WAL&_synchronous=NORMAL&_busy_timeout=5000&_cache_size=-16384"
+
+	tests := []struct {
+		name     string
+		path     string
+		settings Settings
+		expected string
+	}{
+		{"defaults", "pkmc.db", settings, "pkmc.db?" + defaults},
+		{"existing parameters", "pkmc.db?mode=rw", settings, "pkmc.db?mode=rw&" + defaults},
+		{
+			name:     "parameters of the path win",
+			path:     "pkmc.db?_txlock=exclusive&_fk=0&_journal=DELETE&_sync=FULL&_timeout=10&_cache_size=-1",
+			settings: settings,
+			expected: "pkmc.db?_txlock=exclusive&_fk=0&_journal=DELETE&_sync=FULL&_timeout=10&_cache_size=-1",
+		},
+		{
+			name:     "unset settings",
+			path:     "pkmc.db",
+			settings: Settings{},
+			expected: "pkmc.db?_txlock=immediate&_foreign_keys=0&_busy_timeout=0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, dsn(tt.path, tt.settings))
+		})
+	}
+}
+
+func TestInitDB_AppliesSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+		expected Report
+	}{
+		{
+			name:     "defaults",
+			settings: DefaultSettings(),
+			expected: Report{ForeignKeys: true, JournalMode: "WAL", Synchronous: "NORMAL", BusyTimeout: 5 * time.Second, CacheSize: 16384, MaxOpenConns: 8},
+		},
+		{
+			name: "custom",
+			settings: Settings{
+				JournalMode: "delete",
+				Synchronous: "2",
+				BusyTimeout: 250 * time.Millisecond,
+				CacheSize:   1024,
+			},
+			expected: Report{ForeignKeys: false, JournalMode: "DELETE", Synchronous: "FULL", BusyTimeout: 250 * time.Millisecond, CacheSize: 1024},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			path := filepath.Join(t.TempDir(), "pkmc.db")
+
+			// Execute
+			db, err := InitDB(path, tt.settings)
+			require.NoError(t, err)
+			defer CloseDB(db)
+			report, err := Inspect(db)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, path, report.Path)
+			assert.NotEmpty(t, report.SQLiteVersion)
+			report.Path, report.SQLiteVersion = "", ""
+			assert.Equal(t, tt.expected, *report)
+		})
+	}
+}
+
+func TestInitDB_EnforcesForeignKeys(t *testing.T) {
+	// Setup
+	db, err := InitDB(filepath.Join(t.TempDir(), "pkmc.db"), DefaultSettings())
+	require.NoError(t, err)
+	defer CloseDB(db)
+	require.NoError(t, db.Exec("CREATE TABLE parents (id INTEGER PRIMARY KEY)").Error)
+	require.NoError(t, db.Exec("CREATE TABLE children (id INTEGER PRIMARY KEY, parent_id INTEGER REFERENCES parents(id))").Error)
+
+	// Execute
+	err = db.Exec("INSERT INTO children (parent_id) VALUES (42)").Error
+
+	// Assert
+	assert.ErrorContains(t, err, "FOREIGN KEY constraint failed")
+}
+
+func TestInitDB_SettingsNotInEffect(t *testing.T) {
+	// Setup
+	path := filepath.Join(t.TempDir(), "pkmc.db")
+
+	// Execute
+	db, err := InitDB(path+"?_journal_mode=DELETE&_fk=0", DefaultSettings())
+
+	// Assert
+	assert.Nil(t, db)
+	assert.ErrorIs(t, err, customErr.ErrDBSettingsMismatch)
+	var dbErr *customErr.DBError
+	require.True(t, errors.As(err, &dbErr), "expected DBError")
+	assert.Equal(t, "verify", dbErr.Op)
+	assert.ErrorContains(t, err, "foreign_keys is off, expected on")
+	assert.ErrorContains(t, err, "journal_mode is DELETE, expected WAL")
+}
+
 func TestInitDB_InvalidPath(t *testing.T) {
-	_, err := InitDB("/invalid/path/to/db.db")
+	_, err := InitDB("/invalid/path/to/db.db", DefaultSettings())
 	assert.Error(t, err)
 
 	var dbErr *customErr.DBError
@@ -46,7 +180,7 @@ func TestCloseDB_Success(t *testing.T) {
 	require.NoError(t, err)
 	defer os.Remove(tmpFile.Name())
 
-	db, err := InitDB(tmpFile.Name())
+	db, err := InitDB(tmpFile.Name(), DefaultSettings())
 	require.NoError(t, err)
 
 	err = CloseDB(db)
@@ -63,7 +197,7 @@ func TestCloseDB_AlreadyClosed(t *testing.T) {
 	require.NoError(t, err)
 	defer os.Remove(tmpFile.Name())
 
-	db, err := InitDB(tmpFile.Name())
+	db, err := InitDB(tmpFile.Name(), DefaultSettings())
 	require.NoError(t, err)
 
 	CloseDB(db)
@@ -0,0 +1,146 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+)
+
+// Report holds the settings in effect on a database.
+type Report struct {
+	// Path is the file of the database, empty when it lives in memory.
+	Path          string
+	SQLiteVersion string
+	ForeignKeys   bool
+	JournalMode   string
+	Synchronous   string
+	BusyTimeout   time.Duration
+	// CacheSize is the page cache of a connection, in KiB.
+	CacheSize int
+	// MaxOpenConns is the limit of the connection pool, 0 for none.
+	MaxOpenConns int
+}
+
+// synchronousModes are the values of PRAGMA synchronous, by number.
+var synchronousModes = []string{"OFF", "NORMAL", "FULL", "EXTRA"}
+
+// Inspect reads the settings in effect on a connection of db.
+func Inspect(db *gorm.DB) (*Report, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, customErr.NewDBError("get_sql_db", err)
+	}
+
+	ctx := context.Background()
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, customErr.NewDBError("inspect", err)
+	}
+	defer conn.Close()
+
+	var (
+		report                                                 Report
+		foreignKeys, synchronous, busyTimeout, cache, pageSize int
+	)
+	queries := []struct {
+		query string
+		dest  any
+	}{
+		{"SELECT sqlite_version()", &report.SQLiteVersion},
+		{"SELECT file FROM pragma_database_list WHERE name = 'main'", &report.Path},
+		{"PRAGMA foreign_keys", &foreignKeys},
+		{"PRAGMA journal_mode", &report.JournalMode},
+		{"PRAGMA synchronous", &synchronous},
+		{"PRAGMA busy_timeout", &busyTimeout},
+		{"PRAGMA cache_size", &cache},
+		{"PRAGMA page_size", &pageSize},
+	}
+	for _, q := range queries {
+		if err := conn.QueryRowContext(ctx, q.query).Scan(q.dest); err != nil {
+			return nil, customErr.NewDBError("inspect", fmt.Errorf("%s: %w", q.query, err))
+		}
+	}
+
+	report.ForeignKeys = foreignKeys == 1
+	report.JournalMode = strings.ToUpper(report.JournalMode)
+	if synchronous >= 0 && synchronous < len(synchronousModes) {
+		report.Synchronous = synchronousModes[synchronous]
+	} else {
+		report.Synchronous = fmt.Sprint(synchronous)
+	}
+	report.BusyTimeout = time.Duration(busyTimeout) * time.Millisecond
+	// A negative cache_size is in KiB, a positive one in pages.
+	if cache < 0 {
+		report.CacheSize = -cache
+	} else {
+		report.CacheSize = cache * pageSize / 1024
+	}
+	report.MaxOpenConns = sqlDB.Stats().MaxOpenConnections
+	return &report, nil
+}
+
+// Check returns an error caused by ErrDBSettingsMismatch listing the
+// pragmas of settings that are not in effect. The journal mode of a
+// database in memory, which has no journal file, is not checked.
+func (r *Report) Check(settings Settings) error {
+	var mismatches []string
+	expect := func(name, got, want string) {
+		if !strings.EqualFold(got, want) {
+			mismatches = append(mismatches, fmt.Sprintf("%s is %s, expected %s", name, got, want))
+		}
+	}
+
+	expect("foreign_keys", onOff(r.ForeignKeys), onOff(settings.ForeignKeys))
+	if settings.JournalMode != "" && r.Path != "" {
+		expect("journal_mode", r.JournalMode, settings.JournalMode)
+	}
+	if settings.Synchronous != "" {
+		expect("synchronous", r.Synchronous, synchronousName(settings.Synchronous))
+	}
+	expect("busy_timeout", r.BusyTimeout.String(), settings.BusyTimeout.Truncate(time.Millisecond).String())
+	if settings.CacheSize > 0 {
+		expect("cache_size", fmt.Sprintf("%dKiB", r.CacheSize), fmt.Sprintf("%dKiB", settings.CacheSize))
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("%w: %s", customErr.ErrDBSettingsMismatch, strings.Join(mismatches, "; "))
+	}
+	return nil
+}
+
+// String describes the settings on one line, for startup logs.
+func (r *Report) String() string {
+	path := r.Path
+	if path == "" {
+		path = "memory"
+	}
+	maxOpen := "unlimited"
+	if r.MaxOpenConns > 0 {
+		maxOpen = fmt.Sprint(r.MaxOpenConns)
+	}
+	return fmt.Sprintf("SQLite %s at %s: journal_mode=%s synchronous=%s foreign_keys=%s busy_timeout=%s cache_size=%dKiB max_open_conns=%s",
+		r.SQLiteVersion, path, r.JournalMode, r.Synchronous, onOff(r.ForeignKeys), r.BusyTimeout, r.CacheSize, maxOpen)
+}
+
+// synchronousName returns the name of a synchronous mode given by name or
+// by number.
+func synchronousName(mode string) string {
+	for i, name := range synchronousModes {
+		if mode == fmt.Sprint(i) {
+			return name
+		}
+	}
+	return mode
+}
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
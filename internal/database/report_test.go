@@ -0,0 +1,87 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReport_Check(t *testing.T) {
+	report := Report{
+		Path:        "/data/pkmc.db",
+		ForeignKeys: true,
+		JournalMode: "WAL",
+		Synchronous: "NORMAL",
+		BusyTimeout: 5 * time.Second,
+		CacheSize:   16384,
+	}
+
+	tests := []struct {
+		name     string
+		report   func(r *Report)
+		settings func(s *Settings)
+		expected string
+	}{
+		{name: "in effect"},
+		{
+			name:     "names are case insensitive and synchronous may be a number",
+			settings: func(s *Settings) { s.JournalMode, s.Synchronous = "wal", "1" },
+		},
+		{
+			name:     "journal mode of a database in memory",
+			report:   func(r *Report) { r.Path, r.JournalMode = "", "MEMORY" },
+			settings: func(s *Settings) { s.JournalMode = "WAL" },
+		},
+		{
+			name:     "unset cache size keeps the default",
+			settings: func(s *Settings) { s.CacheSize = 0 },
+		},
+		{
+			name:     "mismatches",
+			report:   func(r *Report) { r.Synchronous, r.BusyTimeout = "FULL", time.Second },
+			expected: "synchronous is FULL, expected NORMAL; busy_timeout is 1s, expected 5s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			r := report
+			if tt.report != nil {
+				tt.report(&r)
+			}
+			settings := DefaultSettings()
+			if tt.settings != nil {
+				tt.settings(&settings)
+			}
+
+			// Execute
+			err := r.Check(settings)
+
+			// Assert
+			if tt.expected == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, customErr.ErrDBSettingsMismatch)
+			assert.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
+
+func TestReport_String(t *testing.T) {
+	report := Report{
+		SQLiteVersion: "3.50.4",
+		ForeignKeys:   true,
+		JournalMode:   "MEMORY",
+		Synchronous:   "FULL",
+		BusyTimeout:   time.Second,
+		CacheSize:     2000,
+	}
+
+	assert.Equal(t,
+		"SQLite 3.50.4 at memory: journal_mode=MEMORY synchronous=FULL foreign_keys=on busy_timeout=1s cache_size=2000KiB max_open_conns=unlimited",
+		report.String())
+}
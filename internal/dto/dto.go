@@ -0,0 +1,574 @@
+// Package dto holds the external representation of the domain models.
+// Field names (the json tags) are part of the public contract: every output
+// format, the HTTP API and export code use them, so they must stay stable.
+package dto
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+	"github.com/R4yL-dev/pkmc/internal/service"
+)
+
+type Item struct {
+	ID            uint       `json:"id"`
+	ExtensionCode string     `json:"extension_code"`
+	ExtensionName string     `json:"extension_name"`
+	BlockCode     string     `json:"block_code"`
+	Type          string     `json:"type"`
+	LanguageCode  string     `json:"language_code"`
+	LanguageName  string     `json:"language_name"`
+	Price         *float64   `json:"price"`
+	Quantity      int        `json:"quantity"`
+	PurchasedAt   *time.Time `json:"purchased_at"`
+	SoldAt        *time.Time `json:"sold_at"`
+	SoldPrice     *float64   `json:"sold_price"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+type Extension struct {
+	Code        string     `json:"code"`
+	Name        string     `json:"name"`
+	BlockCode   string     `json:"block_code"`
+	BlockName   string     `json:"block_name"`
+	ReleaseDate *time.Time `json:"release_date"`
+}
+
+type Block struct {
+	Code        string     `json:"code"`
+	Name        string     `json:"name"`
+	ReleaseDate *time.Time `json:"release_date"`
+}
+
+type Language struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+type ItemType struct {
+	Name string `json:"name"`
+}
+
+type Aggregate struct {
+	Key         string  `json:"key"`
+	Label       string  `json:"label"`
+	Items       int64   `json:"items"`
+	PricedItems int64   `json:"priced_items"`
+	TotalValue  float64 `json:"total_value"`
+}
+
+type Stats struct {
+	Totals      Aggregate   `json:"totals"`
+	ByBlock     []Aggregate `json:"by_block"`
+	ByExtension []Aggregate `json:"by_extension"`
+	ByLanguage  []Aggregate `json:"by_language"`
+	ByType      []Aggregate `json:"by_type"`
+}
+
+type Deletion struct {
+	ID      uint `json:"id"`
+	Deleted bool `json:"deleted"`
+}
+
+func FromItem(item *models.Item) Item {
+	return Item{
+		ID:            item.ID,
+		ExtensionCode: item.Extension.Code,
+		ExtensionName: item.Extension.Name,
+		BlockCode:     item.Extension.Block.Code,
+		Type:          item.Type.Name,
+		LanguageCode:  item.Language.Code,
+		LanguageName:  item.Language.Name,
+		Price:         item.Price,
+		Quantity:      item.Quantity,
+		PurchasedAt:   item.PurchasedAt,
+		SoldAt:        item.SoldAt,
+		SoldPrice:     item.SoldPrice,
+		CreatedAt:     item.CreatedAt,
+		UpdatedAt:     item.UpdatedAt,
+	}
+}
+
+func FromItems(items []models.Item) []Item {
+	out := make([]Item, 0, len(items))
+	for i := range items {
+		out = append(out, FromItem(&items[i]))
+	}
+	return out
+}
+
+// DuplicatePair is two items that look like duplicates; merging removes
+// the duplicate.
+type DuplicatePair struct {
+	ItemID               uint       `json:"item_id"`
+	DuplicateID          uint       `json:"duplicate_id"`
+	ExtensionCode        string     `json:"extension_code"`
+	Type                 string     `json:"type"`
+	LanguageCode         string     `json:"language_code"`
+	ItemPrice            *float64   `json:"item_price"`
+	DuplicatePrice       *float64   `json:"duplicate_price"`
+	ItemPurchasedAt      *time.Time `json:"item_purchased_at"`
+	DuplicatePurchasedAt *time.Time `json:"duplicate_purchased_at"`
+}
+
+func FromDuplicatePairs(pairs []service.DuplicatePair) []DuplicatePair {
+	out := make([]DuplicatePair, 0, len(pairs))
+	for _, pair := range pairs {
+		out = append(out, DuplicatePair{
+			ItemID:               pair.Item.ID,
+			DuplicateID:          pair.Duplicate.ID,
+			ExtensionCode:        pair.Item.Extension.Code,
+			Type:                 pair.Item.Type.Name,
+			LanguageCode:         pair.Item.Language.Code,
+			ItemPrice:            pair.Item.Price,
+			DuplicatePrice:       pair.Duplicate.Price,
+			ItemPurchasedAt:      pair.Item.PurchasedAt,
+			DuplicatePurchasedAt: pair.Duplicate.PurchasedAt,
+		})
+	}
+	return out
+}
+
+func FromExtension(ext *models.Extension) Extension {
+	return Extension{
+		Code:        ext.Code,
+		Name:        ext.Name,
+		BlockCode:   ext.Block.Code,
+		BlockName:   ext.Block.Name,
+		ReleaseDate: ext.ReleaseDate,
+	}
+}
+
+func FromExtensions(exts []models.Extension) []Extension {
+	out := make([]Extension, 0, len(exts))
+	for i := range exts {
+		out = append(out, FromExtension(&exts[i]))
+	}
+	return out
+}
+
+func FromBlocks(blocks []models.Block) []Block {
+	out := make([]Block, 0, len(blocks))
+	for _, block := range blocks {
+		out = append(out, Block{Code: block.Code, Name: block.Name, ReleaseDate: block.ReleaseDate})
+	}
+	return out
+}
+
+func FromLanguages(langs []models.Language) []Language {
+	out := make([]Language, 0, len(langs))
+	for _, lang := range langs {
+		out = append(out, Language{Code: lang.Code, Name: lang.Name})
+	}
+	return out
+}
+
+func FromItemTypes(itemTypes []models.ItemType) []ItemType {
+	out := make([]ItemType, 0, len(itemTypes))
+	for _, itemType := range itemTypes {
+		out = append(out, ItemType{Name: itemType.Name})
+	}
+	return out
+}
+
+func FromAggregate(agg repository.ItemAggregate) Aggregate {
+	return Aggregate{
+		Key:         agg.Key,
+		Label:       agg.Label,
+		Items:       agg.Count,
+		PricedItems: agg.PricedCount,
+		TotalValue:  agg.TotalPrice,
+	}
+}
+
+func FromAggregates(aggs []repository.ItemAggregate) []Aggregate {
+	out := make([]Aggregate, 0, len(aggs))
+	for _, agg := range aggs {
+		out = append(out, FromAggregate(agg))
+	}
+	return out
+}
+
+func FromStats(stats *service.CollectionStats) Stats {
+	totals := FromAggregate(stats.Totals)
+	totals.Key = "all"
+	totals.Label = "Collection"
+
+	return Stats{
+		Totals:      totals,
+		ByBlock:     FromAggregates(stats.ByBlock),
+		ByExtension: FromAggregates(stats.ByExtension),
+		ByLanguage:  FromAggregates(stats.ByLanguage),
+		ByType:      FromAggregates(stats.ByType),
+	}
+}
+
+type APIToken struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Role       string     `json:"role"`
+	Prefix     string     `json:"prefix"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+// IssuedToken is returned once, when a token is created; Token is the only
+// copy of the secret.
+type IssuedToken struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Role  string `json:"role"`
+	Token string `json:"token"`
+}
+
+func FromAPIToken(token *models.APIToken) APIToken {
+	return APIToken{
+		ID:         token.ID,
+		Name:       token.Name,
+		Role:       string(token.Role),
+		Prefix:     token.Prefix,
+		CreatedAt:  token.CreatedAt,
+		LastUsedAt: token.LastUsedAt,
+		RevokedAt:  token.RevokedAt,
+	}
+}
+
+func FromAPITokens(tokens []models.APIToken) []APIToken {
+	out := make([]APIToken, 0, len(tokens))
+	for i := range tokens {
+		out = append(out, FromAPIToken(&tokens[i]))
+	}
+	return out
+}
+
+func FromIssuedToken(token *models.APIToken, secret string) IssuedToken {
+	return IssuedToken{ID: token.ID, Name: token.Name, Role: string(token.Role), Token: secret}
+}
+
+// AuditChange is one field of an audit entry. Entries are flattened to one
+// record per changed field so every output format can show them; records
+// of the same entry share EntryID.
+type AuditChange struct {
+	EntryID     uint      `json:"entry_id"`
+	OperationID string    `json:"operation_id"`
+	At          time.Time `json:"at"`
+	Actor       string    `json:"actor"`
+	Entity      string    `json:"entity"`
+	EntityID    uint      `json:"entity_id"`
+	Action      string    `json:"action"`
+	Field       string    `json:"field"`
+	Old         *string   `json:"old"`
+	New         *string   `json:"new"`
+}
+
+// FromAuditEntry lists the fields set by a creation, cleared by a deletion
+// or changed by an update, in field name order.
+func FromAuditEntry(entry *models.AuditEntry) []AuditChange {
+	names := make([]string, 0, len(entry.Before)+len(entry.After))
+	seen := make(map[string]bool, cap(names))
+	for _, image := range []models.AuditFields{entry.Before, entry.After} {
+		for name := range image {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	changes := make([]AuditChange, 0, len(names))
+	for _, name := range names {
+		oldValue, newValue := auditValue(entry.Before, name), auditValue(entry.After, name)
+		if entry.Action == models.AuditUpdate && equalValues(oldValue, newValue) {
+			continue
+		}
+		changes = append(changes, AuditChange{
+			EntryID:     entry.ID,
+			OperationID: entry.OperationID,
+			At:          entry.CreatedAt,
+			Actor:       entry.Actor,
+			Entity:      entry.Entity,
+			EntityID:    entry.EntityID,
+			Action:      string(entry.Action),
+			Field:       name,
+			Old:         oldValue,
+			New:         newValue,
+		})
+	}
+	return changes
+}
+
+func FromAuditEntries(entries []models.AuditEntry) []AuditChange {
+	out := make([]AuditChange, 0, len(entries))
+	for i := range entries {
+		out = append(out, FromAuditEntry(&entries[i])...)
+	}
+	return out
+}
+
+// auditValue formats one field of an image; nil means absent or null.
+func auditValue(image models.AuditFields, name string) *string {
+	value, ok := image[name]
+	if !ok || value == nil {
+		return nil
+	}
+
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		s = fmt.Sprint(v)
+	}
+	return &s
+}
+
+func equalValues(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// Webhook lists the subscribed event types comma-separated, as they are
+// given on the command line.
+type Webhook struct {
+	ID             uint      `json:"id"`
+	Name           string    `json:"name"`
+	URL            string    `json:"url"`
+	Events         string    `json:"events"`
+	PriceThreshold *float64  `json:"price_threshold"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// CreatedWebhook is returned when a webhook is created; it is the only
+// output that shows the signing secret.
+type CreatedWebhook struct {
+	ID             uint     `json:"id"`
+	Name           string   `json:"name"`
+	URL            string   `json:"url"`
+	Events         string   `json:"events"`
+	PriceThreshold *float64 `json:"price_threshold"`
+	Secret         string   `json:"secret"`
+}
+
+type WebhookDelivery struct {
+	ID             uint       `json:"id"`
+	WebhookID      uint       `json:"webhook_id"`
+	EventID        uint       `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	ResponseStatus int        `json:"response_status"`
+	LastError      string     `json:"last_error"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+func FromWebhook(webhook *models.Webhook) Webhook {
+	return Webhook{
+		ID:             webhook.ID,
+		Name:           webhook.Name,
+		URL:            webhook.URL,
+		Events:         webhook.Events,
+		PriceThreshold: webhook.PriceThreshold,
+		CreatedAt:      webhook.CreatedAt,
+	}
+}
+
+func FromWebhooks(webhooks []models.Webhook) []Webhook {
+	out := make([]Webhook, 0, len(webhooks))
+	for i := range webhooks {
+		out = append(out, FromWebhook(&webhooks[i]))
+	}
+	return out
+}
+
+func FromCreatedWebhook(webhook *models.Webhook) CreatedWebhook {
+	return CreatedWebhook{
+		ID:             webhook.ID,
+		Name:           webhook.Name,
+		URL:            webhook.URL,
+		Events:         webhook.Events,
+		PriceThreshold: webhook.PriceThreshold,
+		Secret:         webhook.Secret,
+	}
+}
+
+// FromWebhookDelivery only reports the next attempt of pending deliveries.
+func FromWebhookDelivery(delivery *models.WebhookDelivery) WebhookDelivery {
+	out := WebhookDelivery{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+	if delivery.Status == models.DeliveryPending {
+		next := delivery.NextAttemptAt
+		out.NextAttemptAt = &next
+	}
+	return out
+}
+
+func FromWebhookDeliveries(deliveries []models.WebhookDelivery) []WebhookDelivery {
+	out := make([]WebhookDelivery, 0, len(deliveries))
+	for i := range deliveries {
+		out = append(out, FromWebhookDelivery(&deliveries[i]))
+	}
+	return out
+}
+
+type PriceRecord struct {
+	ID       uint      `json:"id"`
+	ItemID   uint      `json:"item_id"`
+	Price    float64   `json:"price"`
+	Currency string    `json:"currency"`
+	Source   string    `json:"source"`
+	QuotedAt time.Time `json:"quoted_at"`
+}
+
+type PriceRefresh struct {
+	ItemID   uint     `json:"item_id"`
+	Status   string   `json:"status"`
+	OldPrice *float64 `json:"old_price"`
+	NewPrice *float64 `json:"new_price"`
+	Currency string   `json:"currency"`
+	Source   string   `json:"source"`
+	Error    string   `json:"error"`
+}
+
+type PriceProvider struct {
+	Name string `json:"name"`
+}
+
+func FromPriceProviders(names []string) []PriceProvider {
+	out := make([]PriceProvider, 0, len(names))
+	for _, name := range names {
+		out = append(out, PriceProvider{Name: name})
+	}
+	return out
+}
+
+func FromPriceRecords(records []models.PriceRecord) []PriceRecord {
+	out := make([]PriceRecord, 0, len(records))
+	for _, record := range records {
+		out = append(out, PriceRecord{
+			ID:       record.ID,
+			ItemID:   record.ItemID,
+			Price:    record.Price,
+			Currency: record.Currency,
+			Source:   record.Source,
+			QuotedAt: record.QuotedAt,
+		})
+	}
+	return out
+}
+
+func FromPriceRefreshes(refreshes []service.PriceRefresh) []PriceRefresh {
+	out := make([]PriceRefresh, 0, len(refreshes))
+	for _, refresh := range refreshes {
+		out = append(out, PriceRefresh{
+			ItemID:   refresh.ItemID,
+			Status:   string(refresh.Status),
+			OldPrice: refresh.OldPrice,
+			NewPrice: refresh.NewPrice,
+			Currency: refresh.Currency,
+			Source:   refresh.Source,
+			Error:    refresh.Error,
+		})
+	}
+	return out
+}
+
+// AlertRule reports the product fields of product rules and the window of
+// rise and drop rules.
+type AlertRule struct {
+	ID            uint       `json:"id"`
+	Name          string     `json:"name"`
+	ItemID        *uint      `json:"item_id"`
+	ExtensionCode string     `json:"extension_code"`
+	Type          string     `json:"type"`
+	LanguageCode  string     `json:"language_code"`
+	Condition     string     `json:"condition"`
+	Threshold     float64    `json:"threshold"`
+	Window        string     `json:"window"`
+	Triggered     bool       `json:"triggered"`
+	TriggeredAt   *time.Time `json:"triggered_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func FromAlertRule(rule *models.AlertRule) AlertRule {
+	out := AlertRule{
+		ID:            rule.ID,
+		Name:          rule.Name,
+		ItemID:        rule.ItemID,
+		ExtensionCode: rule.ExtensionCode,
+		Type:          rule.ItemType,
+		LanguageCode:  rule.LanguageCode,
+		Condition:     string(rule.Condition),
+		Threshold:     rule.Threshold,
+		Triggered:     rule.Triggered,
+		TriggeredAt:   rule.TriggeredAt,
+		CreatedAt:     rule.CreatedAt,
+	}
+	if rule.Condition.IsChange() {
+		out.Window = models.FormatAlertWindow(rule.Window)
+	}
+	return out
+}
+
+func FromAlertRules(rules []models.AlertRule) []AlertRule {
+	out := make([]AlertRule, 0, len(rules))
+	for i := range rules {
+		out = append(out, FromAlertRule(&rules[i]))
+	}
+	return out
+}
+
+// Job is the state of a scheduled job. Duration is the length of the last
+// run, Running whether a run is in progress.
+type Job struct {
+	Name      string     `json:"name"`
+	Schedule  string     `json:"schedule"`
+	LastRunAt *time.Time `json:"last_run_at"`
+	Duration  string     `json:"duration"`
+	Status    string     `json:"status"`
+	Error     string     `json:"error"`
+	NextRunAt *time.Time `json:"next_run_at"`
+	Running   bool       `json:"running"`
+}
+
+func FromJobStates(states []models.JobState) []Job {
+	out := make([]Job, 0, len(states))
+	for _, state := range states {
+		job := Job{
+			Name:      state.Name,
+			Schedule:  state.Schedule,
+			LastRunAt: state.LastRunAt,
+			Status:    string(state.LastStatus),
+			Error:     state.LastError,
+			NextRunAt: state.NextRunAt,
+			Running:   state.RunningSince != nil,
+		}
+		if state.LastRunAt != nil {
+			job.Duration = state.LastDuration.Round(time.Millisecond).String()
+		}
+		out = append(out, job)
+	}
+	return out
+}
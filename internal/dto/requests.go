@@ -0,0 +1,133 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/R4yL-dev/pkmc/internal/service"
+)
+
+// ItemCreate is the body accepted when adding an item. Quantity defaults
+// to 1 and DuplicatePolicy to that of the server.
+type ItemCreate struct {
+	ExtensionCode   string     `json:"extension_code"`
+	LanguageCode    string     `json:"language_code"`
+	Type            string     `json:"type"`
+	Price           *float64   `json:"price"`
+	Quantity        *int       `json:"quantity,omitempty"`
+	PurchasedAt     *time.Time `json:"purchased_at,omitempty"`
+	DuplicatePolicy string     `json:"duplicate_policy,omitempty"`
+}
+
+// ItemBatchCreate is the body accepted when adding items in bulk.
+type ItemBatchCreate struct {
+	Items           []ItemBatchEntry `json:"items"`
+	DuplicatePolicy string           `json:"duplicate_policy,omitempty"`
+}
+
+// ItemBatchEntry is an item of an ItemBatchCreate. Quantity defaults to 1.
+type ItemBatchEntry struct {
+	ExtensionCode string     `json:"extension_code"`
+	LanguageCode  string     `json:"language_code"`
+	Type          string     `json:"type"`
+	Price         *float64   `json:"price"`
+	Quantity      int        `json:"quantity,omitempty"`
+	PurchasedAt   *time.Time `json:"purchased_at,omitempty"`
+}
+
+// Specs returns the items of the batch as the service takes them.
+func (b ItemBatchCreate) Specs() []service.ItemSpec {
+	specs := make([]service.ItemSpec, len(b.Items))
+	for i, entry := range b.Items {
+		specs[i] = service.ItemSpec{
+			ExtensionCode: entry.ExtensionCode,
+			LanguageCode:  entry.LanguageCode,
+			TypeName:      entry.Type,
+			Price:         entry.Price,
+			Quantity:      entry.Quantity,
+			PurchasedAt:   entry.PurchasedAt,
+		}
+	}
+	return specs
+}
+
+// ItemPatch is the body accepted when updating an item. Omitted fields are
+// left unchanged; a null price removes the price.
+type ItemPatch struct {
+	ExtensionCode *string       `json:"extension_code,omitempty"`
+	LanguageCode  *string       `json:"language_code,omitempty"`
+	Type          *string       `json:"type,omitempty"`
+	Price         NullableFloat `json:"price"`
+	Quantity      *int          `json:"quantity,omitempty"`
+	PurchasedAt   *time.Time    `json:"purchased_at,omitempty"`
+}
+
+// ItemSale is the body accepted when selling an item. SoldAt defaults to
+// now.
+type ItemSale struct {
+	Price  *float64   `json:"price"`
+	SoldAt *time.Time `json:"sold_at,omitempty"`
+}
+
+// NullableFloat distinguishes an absent JSON field from an explicit null.
+type NullableFloat struct {
+	Set   bool
+	Value *float64
+}
+
+func (f *NullableFloat) UnmarshalJSON(data []byte) error {
+	f.Set = true
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		f.Value = nil
+		return nil
+	}
+	return json.Unmarshal(data, &f.Value)
+}
+
+func (f NullableFloat) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.Value)
+}
+
+// TokenCreate is the body accepted when issuing an API token.
+type TokenCreate struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+// UndoRequest selects what to revert: the Last operations or the one with
+// OperationID.
+type UndoRequest struct {
+	Last        int    `json:"last,omitempty"`
+	OperationID string `json:"operation_id,omitempty"`
+}
+
+// WebhookCreate is the body accepted when creating a webhook. An empty
+// secret is generated.
+type WebhookCreate struct {
+	Name           string   `json:"name"`
+	URL            string   `json:"url"`
+	Events         []string `json:"events"`
+	Secret         string   `json:"secret"`
+	PriceThreshold *float64 `json:"price_threshold"`
+}
+
+// PriceRefreshRequest selects the price provider of a refresh; an empty
+// provider asks every configured provider in turn.
+type PriceRefreshRequest struct {
+	Provider string `json:"provider,omitempty"`
+}
+
+// AlertRuleCreate is the body accepted when creating an alert rule: either
+// item_id or the three product fields, a condition (above, below, rise or
+// drop), a threshold and, for rise and drop, a window such as "7d".
+type AlertRuleCreate struct {
+	Name          string  `json:"name"`
+	ItemID        *uint   `json:"item_id"`
+	ExtensionCode string  `json:"extension_code"`
+	Type          string  `json:"type"`
+	LanguageCode  string  `json:"language_code"`
+	Condition     string  `json:"condition"`
+	Threshold     float64 `json:"threshold"`
+	Window        string  `json:"window"`
+}
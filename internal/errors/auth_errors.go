@@ -0,0 +1,8 @@
+package errors
+
+import "errors"
+
+var (
+	ErrInvalidToken     = errors.New("invalid or revoked API token")
+	ErrPermissionDenied = errors.New("permission denied")
+)
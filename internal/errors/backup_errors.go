@@ -0,0 +1,35 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+)
+
+type BackupError struct {
+	*BaseError
+	Table string
+}
+
+func (e BackupError) Error() string {
+	if e.Table != "" {
+		return fmt.Sprintf("backup %s failed for table '%s': %s", e.Op, e.Table, e.BaseError.Error())
+	}
+	return fmt.Sprintf("backup %s failed: %s", e.Op, e.BaseError.Error())
+}
+
+var (
+	ErrBackupUnsupportedFormat = errors.New("unsupported backup format")
+	ErrBackupTargetNotEmpty    = errors.New("backup target database is not empty")
+	ErrBackupUnknownColumn     = errors.New("unknown column in backup")
+	ErrBackupDanglingReference = errors.New("dangling reference in backup")
+)
+
+func NewBackupError(op string, cause error, table ...string) *BackupError {
+	backupErr := &BackupError{
+		BaseError: NewBaseError(op, "backup", "", cause),
+	}
+	if len(table) > 0 {
+		backupErr.Table = table[0]
+	}
+	return backupErr
+}
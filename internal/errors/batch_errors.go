@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// EntryError reports why the entry at Index of a batch, counted from 0,
+// failed.
+type EntryError struct {
+	Index int
+	Err   error
+}
+
+func (e EntryError) Error() string {
+	return fmt.Sprintf("entry %d: %v", e.Index, e.Err)
+}
+
+func (e EntryError) Unwrap() error {
+	return e.Err
+}
+
+// BatchError lists every failed entry of a batch of Total entries, none of
+// which was applied. Its code is BATCH_FAILED, whatever the codes of the
+// entries, and it matches the errors any entry matches.
+type BatchError struct {
+	Total   int
+	Entries []EntryError
+}
+
+func (e *BatchError) Error() string {
+	messages := make([]string, len(e.Entries))
+	for i, entry := range e.Entries {
+		messages[i] = entry.Error()
+	}
+	return fmt.Sprintf("%d of %d entries failed: %s", len(e.Entries), e.Total, strings.Join(messages, "; "))
+}
+
+func (e *BatchError) Is(target error) bool {
+	for _, entry := range e.Entries {
+		if errors.Is(entry.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *BatchError) ErrorCode() Code {
+	return CodeBatchFailed
+}
@@ -0,0 +1,167 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// Code is a stable, machine-readable kind of error, such as
+// EXTENSION_NOT_FOUND, which API clients and scripts can branch on. Codes
+// refine one of the generic codes below, their family.
+type Code string
+
+// Generic codes, each the family of the more specific codes.
+const (
+	CodeInternal         Code = "INTERNAL"
+	CodeBadRequest       Code = "BAD_REQUEST"
+	CodeInvalidToken     Code = "INVALID_TOKEN"
+	CodePermissionDenied Code = "PERMISSION_DENIED"
+	CodeNotFound         Code = "NOT_FOUND"
+	CodeValidationFailed Code = "VALIDATION_FAILED"
+	CodeConflict         Code = "CONFLICT"
+	CodeTimeout          Code = "TIMEOUT"
+	CodeCanceled         Code = "CANCELED"
+	CodeUnavailable      Code = "UNAVAILABLE"
+)
+
+// Specific codes. Repositories also report <ENTITY>_NOT_FOUND codes, such
+// as ITEM_NOT_FOUND or ALERT_RULE_NOT_FOUND, named after the entity.
+const (
+	CodeConstraintViolation  Code = "CONSTRAINT_VIOLATION"
+	CodeUniqueViolation      Code = "UNIQUE_VIOLATION"
+	CodeForeignKeyViolation  Code = "FOREIGN_KEY_VIOLATION"
+	CodeNotNullViolation     Code = "NOT_NULL_VIOLATION"
+	CodeCheckViolation       Code = "CHECK_VIOLATION"
+	CodeDuplicateItem        Code = "DUPLICATE_ITEM"
+	CodeIdempotencyKeyReused Code = "IDEMPOTENCY_KEY_REUSED"
+	CodeBatchFailed          Code = "BATCH_FAILED"
+	CodeDatabaseBusy         Code = "DATABASE_BUSY"
+	CodeDatabaseUnavailable  Code = "DATABASE_UNAVAILABLE"
+	CodeTransactionFailed    Code = "TRANSACTION_FAILED"
+	CodeReadOnly             Code = "READ_ONLY"
+)
+
+// notFoundSuffix ends the codes of the NOT_FOUND family.
+const notFoundSuffix = "_NOT_FOUND"
+
+var families = map[Code]Code{
+	CodeConstraintViolation:  CodeConflict,
+	CodeUniqueViolation:      CodeConflict,
+	CodeForeignKeyViolation:  CodeConflict,
+	CodeNotNullViolation:     CodeConflict,
+	CodeCheckViolation:       CodeConflict,
+	CodeDuplicateItem:        CodeConflict,
+	CodeIdempotencyKeyReused: CodeValidationFailed,
+	CodeBatchFailed:          CodeValidationFailed,
+	CodeDatabaseBusy:         CodeUnavailable,
+	CodeDatabaseUnavailable:  CodeUnavailable,
+	CodeTransactionFailed:    CodeUnavailable,
+	CodeReadOnly:             CodeInternal,
+}
+
+// Family returns the generic code that c refines, or c itself when it is
+// generic. Unknown codes are INTERNAL.
+func (c Code) Family() Code {
+	switch {
+	case strings.HasSuffix(string(c), notFoundSuffix):
+		return CodeNotFound
+	case families[c] != "":
+		return families[c]
+	}
+	switch c {
+	case CodeBadRequest, CodeInvalidToken, CodePermissionDenied, CodeNotFound, CodeValidationFailed,
+		CodeConflict, CodeTimeout, CodeCanceled, CodeUnavailable:
+		return c
+	}
+	return CodeInternal
+}
+
+// EntityNotFoundCode returns the code reporting that an entity, named as
+// in RepositoryError, does not exist: ITEM_NOT_FOUND for "item".
+func EntityNotFoundCode(entity string) Code {
+	if entity == "" {
+		return CodeNotFound
+	}
+	return Code(strings.ToUpper(entity) + notFoundSuffix)
+}
+
+// sentinelCodes gives the code of the errors that carry none, in order of
+// precedence when a chain matches several.
+var sentinelCodes = []struct {
+	err  error
+	code Code
+}{
+	{ErrInvalidToken, CodeInvalidToken},
+	{ErrPermissionDenied, CodePermissionDenied},
+	{ErrEntityNotFound, CodeNotFound},
+	{ErrIdempotencyKeyReused, CodeIdempotencyKeyReused},
+	{ErrValidationFailed, CodeValidationFailed},
+	{ErrConstraintViolation, CodeConstraintViolation},
+	{ErrDuplicateItem, CodeDuplicateItem},
+	{ErrConflict, CodeConflict},
+	{context.DeadlineExceeded, CodeTimeout},
+	{ErrDatabaseBusy, CodeDatabaseBusy},
+	{ErrServiceUnavailable, CodeUnavailable},
+	{context.Canceled, CodeCanceled},
+	{ErrReadOnly, CodeReadOnly},
+}
+
+// CodeOf returns the most specific code of err, walking the errors it
+// wraps. The innermost error with an ErrorCode method returning a code
+// wins, such as the RepositoryError of a missing extension
+// (EXTENSION_NOT_FOUND) or a ConstraintError (UNIQUE_VIOLATION). Otherwise
+// the code comes from the sentinel errors err matches, then from the
+// DBError or UOWError it wraps, and is INTERNAL for any other error.
+func CodeOf(err error) Code {
+	if err == nil {
+		return ""
+	}
+	if code := innermostCode(err); code != "" {
+		return code
+	}
+	for _, sentinel := range sentinelCodes {
+		if errors.Is(err, sentinel.err) {
+			return sentinel.code
+		}
+	}
+
+	var dbErr *DBError
+	var uowErr *UOWError
+	switch {
+	case errors.As(err, &dbErr):
+		return CodeDatabaseUnavailable
+	case errors.As(err, &uowErr):
+		return CodeTransactionFailed
+	default:
+		return CodeInternal
+	}
+}
+
+// innermostCode returns the code of the innermost error of the chain of err
+// having an ErrorCode method, or "" when there is none.
+func innermostCode(err error) Code {
+	var code Code
+	for err != nil {
+		if coded, ok := err.(interface{ ErrorCode() Code }); ok {
+			if c := coded.ErrorCode(); c != "" {
+				code = c
+			}
+		}
+
+		switch wrapped := err.(type) {
+		case interface{ Unwrap() error }:
+			err = wrapped.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, e := range wrapped.Unwrap() {
+				if c := innermostCode(e); c != "" {
+					return c
+				}
+			}
+			return code
+		default:
+			err = nil
+		}
+	}
+	return code
+}
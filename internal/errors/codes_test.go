@@ -0,0 +1,70 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodeOf(t *testing.T) {
+	notFound := NewRepositoryError("find", "extension", "NOPE", ErrEntityNotFound)
+	violation := &ConstraintError{Kind: ConstraintForeignKey, Cause: errors.New("FOREIGN KEY constraint failed")}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected Code
+	}{
+		{"nil", nil, ""},
+		{"entity not found", notFound, "EXTENSION_NOT_FOUND"},
+		{"wrapped entity not found", NewServiceError("create_item", "item_service", "extension NOPE not found", notFound), "EXTENSION_NOT_FOUND"},
+		{"entity qualified with underscores", NewRepositoryError("find", "alert_rule", "1", ErrEntityNotFound), "ALERT_RULE_NOT_FOUND"},
+		{"not found without entity", NewServiceError("run_job", "scheduler", "job 'x' not found", ErrEntityNotFound), CodeNotFound},
+		{"constraint", NewServiceError("create_item", "item_service", "", NewRepositoryError("create", "item", "new", violation)), CodeForeignKeyViolation},
+		{"constraint of another kind", &ConstraintError{Cause: errors.New("raised by a trigger")}, CodeConstraintViolation},
+		{"validation", NewServiceError("issue_token", "token_service", "token name is required", ErrValidationFailed), CodeValidationFailed},
+		{"conflict", NewServiceError("undo", "audit_service", "", ErrConflict), CodeConflict},
+		{"idempotency key reused", NewServiceError("create_item", "item_service", "", ErrIdempotencyKeyReused), CodeIdempotencyKeyReused},
+		{"batch", NewServiceError("create_items", "item_service", "", &BatchError{Total: 2, Entries: []EntryError{{Index: 1, Err: notFound}}}), CodeBatchFailed},
+		{"invalid token", NewServiceError("authenticate", "token_service", "", ErrInvalidToken), CodeInvalidToken},
+		{"busy", NewUOWBusyError(3, errors.New("database is locked")), CodeDatabaseBusy},
+		{"read only", NewUOWError("begin", ErrReadOnly), CodeReadOnly},
+		{"timeout", fmt.Errorf("wrapped: %w", context.DeadlineExceeded), CodeTimeout},
+		{"database", NewDBError("open", errors.New("boom")), CodeDatabaseUnavailable},
+		{"unit of work", NewUOWError("commit", errors.New("boom")), CodeTransactionFailed},
+		{"joined", errors.Join(errors.New("boom"), notFound), "EXTENSION_NOT_FOUND"},
+		{"other", errors.New("boom"), CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CodeOf(tt.err))
+		})
+	}
+}
+
+func TestCode_Family(t *testing.T) {
+	tests := []struct {
+		code     Code
+		expected Code
+	}{
+		{"EXTENSION_NOT_FOUND", CodeNotFound},
+		{CodeNotFound, CodeNotFound},
+		{CodeUniqueViolation, CodeConflict},
+		{CodeDatabaseBusy, CodeUnavailable},
+		{CodeValidationFailed, CodeValidationFailed},
+		{CodeIdempotencyKeyReused, CodeValidationFailed},
+		{CodeBatchFailed, CodeValidationFailed},
+		{CodeReadOnly, CodeInternal},
+		{"SOMETHING_ELSE", CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.code), func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.code.Family())
+		})
+	}
+}
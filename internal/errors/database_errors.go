@@ -21,6 +21,9 @@ var (
 	ErrDBOpenFailed  = errors.New("database open failed")
 	ErrDBPingFailed  = errors.New("database ping failed")
 	ErrDBCloseFailed = errors.New("database close failed")
+	// ErrDBSettingsMismatch reports a database whose effective settings
+	// differ from the configured ones.
+	ErrDBSettingsMismatch = errors.New("database settings not in effect")
 )
 
 func NewDBError(op string, cause error, path ...string) *DBError {
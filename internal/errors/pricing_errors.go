@@ -0,0 +1,27 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+)
+
+type PricingError struct {
+	*BaseError
+	Provider string
+}
+
+func (e PricingError) Error() string {
+	return fmt.Sprintf("price provider %s failed: %s", e.Provider, e.BaseError.Error())
+}
+
+var (
+	ErrNoQuote         = errors.New("no price quote")
+	ErrUnknownProvider = errors.New("unknown price provider")
+)
+
+func NewPricingError(op, provider, message string, cause error) *PricingError {
+	return &PricingError{
+		BaseError: NewBaseError(op, "pricing", message, cause),
+		Provider:  provider,
+	}
+}
@@ -3,6 +3,7 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 type RepositoryError struct {
@@ -20,6 +21,15 @@ var (
 	ErrConstraintViolation = errors.New("constraint violation")
 )
 
+// ErrorCode returns the <ENTITY>_NOT_FOUND code of the entity when e
+// reports a missing one.
+func (e RepositoryError) ErrorCode() Code {
+	if errors.Is(e.Cause, ErrEntityNotFound) {
+		return EntityNotFoundCode(e.Entity)
+	}
+	return ""
+}
+
 func NewRepositoryError(op, entity, key string, cause error) *RepositoryError {
 	return &RepositoryError{
 		BaseError: NewBaseError(op, "repository", "", cause),
@@ -27,3 +37,93 @@ func NewRepositoryError(op, entity, key string, cause error) *RepositoryError {
 		Key:       key,
 	}
 }
+
+// ConstraintKind is the kind of database constraint a write violated.
+type ConstraintKind string
+
+const (
+	ConstraintUnique     ConstraintKind = "unique"
+	ConstraintForeignKey ConstraintKind = "foreign key"
+	ConstraintNotNull    ConstraintKind = "not null"
+	ConstraintCheck      ConstraintKind = "check"
+)
+
+// Errors matching the ConstraintError of each kind, besides
+// ErrConstraintViolation which matches them all.
+var (
+	ErrUniqueViolation     = errors.New("unique constraint violation")
+	ErrForeignKeyViolation = errors.New("foreign key constraint violation")
+	ErrNotNullViolation    = errors.New("not null constraint violation")
+	ErrCheckViolation      = errors.New("check constraint violation")
+)
+
+var constraintCodes = map[ConstraintKind]Code{
+	ConstraintUnique:     CodeUniqueViolation,
+	ConstraintForeignKey: CodeForeignKeyViolation,
+	ConstraintNotNull:    CodeNotNullViolation,
+	ConstraintCheck:      CodeCheckViolation,
+}
+
+var constraintSentinels = map[ConstraintKind]error{
+	ConstraintUnique:     ErrUniqueViolation,
+	ConstraintForeignKey: ErrForeignKeyViolation,
+	ConstraintNotNull:    ErrNotNullViolation,
+	ConstraintCheck:      ErrCheckViolation,
+}
+
+// ConstraintError reports a write rejected by a database constraint. Table
+// and Columns name what the constraint covers when the database tells,
+// which SQLite does not for foreign keys; Constraint is the name or the
+// expression of a check constraint. Kind is empty for other constraints,
+// such as those raised by triggers.
+type ConstraintError struct {
+	Kind       ConstraintKind
+	Table      string
+	Columns    []string
+	Constraint string
+	Cause      error
+}
+
+func (e *ConstraintError) Error() string {
+	kind := "constraint"
+	if e.Kind != "" {
+		kind = string(e.Kind) + " constraint"
+	}
+
+	switch {
+	case len(e.Columns) > 0:
+		columns := make([]string, len(e.Columns))
+		for i, column := range e.Columns {
+			columns[i] = e.Table + "." + column
+		}
+		return fmt.Sprintf("%s violated on %s", kind, strings.Join(columns, ", "))
+	case e.Constraint != "":
+		return fmt.Sprintf("%s violated: %s", kind, e.Constraint)
+	case e.Kind == "" && e.Cause != nil:
+		return fmt.Sprintf("%s violated: %v", kind, e.Cause)
+	default:
+		return kind + " violated"
+	}
+}
+
+// Is matches ErrConstraintViolation and the error of the kind of e.
+func (e *ConstraintError) Is(target error) bool {
+	if target == ErrConstraintViolation {
+		return true
+	}
+	sentinel, ok := constraintSentinels[e.Kind]
+	return ok && target == sentinel
+}
+
+// ErrorCode returns the code of the kind of e, CONSTRAINT_VIOLATION for
+// other constraints.
+func (e *ConstraintError) ErrorCode() Code {
+	if code, ok := constraintCodes[e.Kind]; ok {
+		return code
+	}
+	return CodeConstraintViolation
+}
+
+func (e *ConstraintError) Unwrap() error {
+	return e.Cause
+}
@@ -17,6 +17,13 @@ func (e ServiceError) Error() string {
 var (
 	ErrValidationFailed   = errors.New("service validation failed")
 	ErrServiceUnavailable = errors.New("service unavailable")
+	ErrConflict           = errors.New("conflicting change")
+	// ErrDuplicateItem refuses an item that looks like one already in the
+	// collection.
+	ErrDuplicateItem = errors.New("likely duplicate item")
+	// ErrIdempotencyKeyReused refuses a request made with the idempotency
+	// key of another request.
+	ErrIdempotencyKeyReused = errors.New("idempotency key reused for another request")
 )
 
 func NewServiceError(op, service, message string, cause error) *ServiceError {
@@ -5,8 +5,11 @@ import (
 	"fmt"
 )
 
+// UOWError reports a failed transaction. Attempts is the number of tries
+// of a transaction given up because the database stayed busy, 0 otherwise.
 type UOWError struct {
 	*BaseError
+	Attempts int
 }
 
 func (e UOWError) Error() string {
@@ -16,6 +19,12 @@ func (e UOWError) Error() string {
 var (
 	ErrUOWBeginFailed  = errors.New("unit of work begin failed")
 	ErrUOWCommitFailed = errors.New("unit of work commit failed")
+	// ErrDatabaseBusy is the cause of the UOWError of a transaction that
+	// kept finding the database locked by another connection.
+	ErrDatabaseBusy = errors.New("database is busy")
+	// ErrReadOnly is the cause of the errors of writes attempted in a
+	// read-only transaction.
+	ErrReadOnly = errors.New("write attempted in a read-only transaction")
 )
 
 func NewUOWError(op string, cause error) *UOWError {
@@ -23,3 +32,12 @@ func NewUOWError(op string, cause error) *UOWError {
 		BaseError: NewBaseError(op, "unit_of_work", "", cause),
 	}
 }
+
+// NewUOWBusyError reports a transaction given up after attempts tries that
+// all found the database locked; last is the error of the last one.
+func NewUOWBusyError(attempts int, last error) *UOWError {
+	return &UOWError{
+		BaseError: NewBaseError("retry", "unit_of_work", fmt.Sprintf("still busy after %d attempts", attempts), fmt.Errorf("%w: %w", ErrDatabaseBusy, last)),
+		Attempts:  attempts,
+	}
+}
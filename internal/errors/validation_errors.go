@@ -0,0 +1,45 @@
+package errors
+
+import "strings"
+
+// Rules broken by invalid fields, as reported in FieldError.
+const (
+	RuleRequired = "required"
+	RuleMaxLen   = "max_length"
+	RuleMin      = "min"
+	RuleMax      = "max"
+	RuleOneOf    = "one_of"
+	RuleFormat   = "format"
+	RuleExcludes = "excludes"
+)
+
+// FieldError reports an invalid field of a service input: Field is its
+// name as clients send it, such as extension_code, Rule the rule it breaks
+// and Message a sentence describing the problem.
+type FieldError struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Message
+}
+
+// ValidationError lists every invalid field of a service input. It matches
+// ErrValidationFailed.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidationFailed
+}
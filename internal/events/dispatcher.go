@@ -0,0 +1,242 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/R4yL-dev/pkmc/internal/repository"
+)
+
+const (
+	defaultPollInterval = 2 * time.Second
+	defaultBatchSize    = 100
+	defaultMaxAttempts  = 10
+	defaultBaseBackoff  = 5 * time.Second
+	defaultMaxBackoff   = time.Hour
+)
+
+// AllEvents subscribes a handler to every event type.
+const AllEvents = "*"
+
+// Handler reacts to an event. Returning an error, or panicking, makes the
+// dispatcher deliver the event again later.
+type Handler func(ctx context.Context, event Event) error
+
+type subscription struct {
+	name    string
+	handler Handler
+}
+
+// DispatcherOption configures a Dispatcher.
+type DispatcherOption func(*Dispatcher)
+
+// WithPollInterval sets how often Run looks for due events.
+func WithPollInterval(interval time.Duration) DispatcherOption {
+	return func(d *Dispatcher) {
+		if interval > 0 {
+			d.pollInterval = interval
+		}
+	}
+}
+
+// WithBatchSize sets how many events are loaded at once.
+func WithBatchSize(n int) DispatcherOption {
+	return func(d *Dispatcher) {
+		if n > 0 {
+			d.batchSize = n
+		}
+	}
+}
+
+// WithRetry sets how many times an event is attempted before it is given
+// up, and the delay before the first retry, doubled for each following
+// one up to max.
+func WithRetry(attempts int, base, max time.Duration) DispatcherOption {
+	return func(d *Dispatcher) {
+		if attempts > 0 {
+			d.maxAttempts = attempts
+		}
+		if base > 0 {
+			d.baseBackoff = base
+		}
+		if max >= d.baseBackoff {
+			d.maxBackoff = max
+		}
+	}
+}
+
+// WithLogOutput sets where delivery failures are reported.
+func WithLogOutput(w io.Writer) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.logger = log.New(w, "events: ", log.LstdFlags)
+	}
+}
+
+// Dispatcher delivers the events of the outbox to the subscribed handlers.
+// An event is marked dispatched once every handler of its type succeeded;
+// otherwise all of them see it again on the next attempt. After the last
+// attempt the event is marked failed and kept in the outbox with its last
+// error.
+type Dispatcher struct {
+	uow          repository.UnitOfWork
+	pollInterval time.Duration
+	batchSize    int
+	maxAttempts  int
+	baseBackoff  time.Duration
+	maxBackoff   time.Duration
+	logger       *log.Logger
+
+	mu            sync.RWMutex
+	subscriptions map[string][]subscription
+
+	// dispatching serialises DispatchPending so an event is not handed
+	// to the same handlers twice concurrently.
+	dispatching sync.Mutex
+}
+
+func NewDispatcher(uow repository.UnitOfWork, opts ...DispatcherOption) *Dispatcher {
+	d := &Dispatcher{
+		uow:           uow,
+		pollInterval:  defaultPollInterval,
+		batchSize:     defaultBatchSize,
+		maxAttempts:   defaultMaxAttempts,
+		baseBackoff:   defaultBaseBackoff,
+		maxBackoff:    defaultMaxBackoff,
+		logger:        log.New(io.Discard, "", 0),
+		subscriptions: make(map[string][]subscription),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Subscribe registers handler for one event type, or for AllEvents. The
+// name identifies the subscriber in error messages.
+func (d *Dispatcher) Subscribe(eventType, name string, handler Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.subscriptions[eventType] = append(d.subscriptions[eventType], subscription{name: name, handler: handler})
+}
+
+func (d *Dispatcher) subscribers(eventType string) []subscription {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	subs := make([]subscription, 0, len(d.subscriptions[eventType])+len(d.subscriptions[AllEvents]))
+	subs = append(subs, d.subscriptions[eventType]...)
+	return append(subs, d.subscriptions[AllEvents]...)
+}
+
+// Run delivers due events every poll interval until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.DispatchAll(ctx); err != nil && ctx.Err() == nil {
+			d.logger.Printf("dispatch: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchAll makes one delivery attempt of each due event, batch after
+// batch, so a backlog does not wait one poll interval per batch.
+func (d *Dispatcher) DispatchAll(ctx context.Context) error {
+	for {
+		n, err := d.DispatchPending(ctx)
+		if err != nil {
+			return err
+		}
+		if n < d.batchSize {
+			return nil
+		}
+	}
+}
+
+// DispatchPending makes one delivery attempt of each due event, up to the
+// batch size, and returns how many events it attempted.
+func (d *Dispatcher) DispatchPending(ctx context.Context) (int, error) {
+	d.dispatching.Lock()
+	defer d.dispatching.Unlock()
+
+	due, err := d.uow.Outbox().Due(ctx, time.Now(), d.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range due {
+		stored := &due[i]
+		event := fromOutbox(stored)
+
+		deliveryErr := d.deliver(ctx, event)
+		if ctx.Err() != nil {
+			// Interrupted deliveries are not counted as attempts.
+			return i, ctx.Err()
+		}
+
+		now := time.Now()
+		stored.Attempts++
+		if deliveryErr == nil {
+			stored.LastError = ""
+			stored.DispatchedAt = &now
+		} else {
+			stored.LastError = deliveryErr.Error()
+			if stored.Attempts >= d.maxAttempts {
+				stored.FailedAt = &now
+				d.logger.Printf("giving up %s event %d after %d attempts: %v", event.Type, event.ID, stored.Attempts, deliveryErr)
+			} else {
+				stored.NextAttemptAt = now.Add(Backoff(stored.Attempts, d.baseBackoff, d.maxBackoff))
+				d.logger.Printf("%s event %d, attempt %d: %v", event.Type, event.ID, stored.Attempts, deliveryErr)
+			}
+		}
+
+		if err := d.uow.Outbox().UpdateDelivery(ctx, stored); err != nil {
+			return i + 1, err
+		}
+	}
+	return len(due), nil
+}
+
+// deliver hands event to every subscriber of its type and returns the
+// first error.
+func (d *Dispatcher) deliver(ctx context.Context, event Event) error {
+	for _, sub := range d.subscribers(event.Type) {
+		if err := safeHandle(ctx, sub.handler, event); err != nil {
+			return fmt.Errorf("%s: %w", sub.name, err)
+		}
+	}
+	return nil
+}
+
+func safeHandle(ctx context.Context, handler Handler, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(ctx, event)
+}
+
+// Backoff returns the delay before the retry that follows the given
+// number of attempts: base, doubled after each attempt, up to max.
+func Backoff(attempts int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	return delay
+}
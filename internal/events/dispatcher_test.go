@@ -0,0 +1,212 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+	"github.com/R4yL-dev/pkmc/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// dispatcherFixture returns a unit of work over an empty outbox.
+func dispatcherFixture(t *testing.T) (*gorm.DB, repository.UnitOfWork) {
+	t.Helper()
+
+	db := testutil.SetupTestDB(t)
+	t.Cleanup(func() { testutil.CleanupTestDB(t, db) })
+	return db, repository.NewUnitOfWork(db)
+}
+
+// appendItemChange stores the events of a change the way the services do.
+func appendItemChange(t *testing.T, uow repository.UnitOfWork, before, after *Item) {
+	t.Helper()
+
+	err := uow.Do(context.Background(), func(uow repository.UnitOfWork) error {
+		changes, err := ForItemChange(before, after)
+		if err != nil {
+			return err
+		}
+		for _, event := range changes {
+			if err := uow.Outbox().Append(context.Background(), event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func storedEvent(t *testing.T, db *gorm.DB, id uint) models.OutboxEvent {
+	t.Helper()
+
+	var event models.OutboxEvent
+	require.NoError(t, db.First(&event, id).Error)
+	return event
+}
+
+func TestDispatcher_DeliversToSubscribers(t *testing.T) {
+	// Setup
+	db, uow := dispatcherFixture(t)
+	d := NewDispatcher(uow)
+
+	var priceChanges, all []string
+	d.Subscribe(ItemPriceChanged, "prices", func(ctx context.Context, event Event) error {
+		var payload ItemPriceChangedPayload
+		if err := event.Decode(&payload); err != nil {
+			return err
+		}
+		priceChanges = append(priceChanges, event.Type)
+		return nil
+	})
+	d.Subscribe(AllEvents, "log", func(ctx context.Context, event Event) error {
+		all = append(all, event.Type)
+		return nil
+	})
+
+	appendItemChange(t, uow, nil, &Item{ID: 1})
+	appendItemChange(t, uow, &Item{ID: 1}, &Item{ID: 1, Price: testutil.FloatPtr(10)})
+
+	// Execute
+	n, err := d.DispatchPending(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []string{ItemPriceChanged}, priceChanges)
+	assert.Equal(t, []string{ItemCreated, ItemUpdated, ItemPriceChanged}, all)
+
+	dispatched := storedEvent(t, db, 1)
+	assert.NotNil(t, dispatched.DispatchedAt)
+	assert.Equal(t, 1, dispatched.Attempts)
+
+	// Delivered events are not delivered again
+	n, err = d.DispatchPending(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	assert.Len(t, all, 3)
+}
+
+func TestDispatcher_RetriesFailedDeliveries(t *testing.T) {
+	// Setup
+	db, uow := dispatcherFixture(t)
+	d := NewDispatcher(uow, WithRetry(3, time.Millisecond, time.Millisecond))
+
+	calls := 0
+	d.Subscribe(ItemCreated, "flaky", func(ctx context.Context, event Event) error {
+		calls++
+		if calls == 1 {
+			return errors.New("connection refused")
+		}
+		if calls == 2 {
+			panic("nil map")
+		}
+		return nil
+	})
+	appendItemChange(t, uow, nil, &Item{ID: 1})
+
+	// Execute & Assert: the first attempt fails and is scheduled later
+	_, err := d.DispatchPending(context.Background())
+	require.NoError(t, err)
+
+	event := storedEvent(t, db, 1)
+	assert.True(t, event.Pending())
+	assert.Equal(t, 1, event.Attempts)
+	assert.Equal(t, "flaky: connection refused", event.LastError)
+	assert.True(t, event.NextAttemptAt.After(event.CreatedAt))
+
+	// A panicking handler is a failure too
+	time.Sleep(5 * time.Millisecond)
+	_, err = d.DispatchPending(context.Background())
+	require.NoError(t, err)
+	event = storedEvent(t, db, 1)
+	assert.Equal(t, 2, event.Attempts)
+	assert.Equal(t, "flaky: panic: nil map", event.LastError)
+
+	time.Sleep(5 * time.Millisecond)
+	_, err = d.DispatchPending(context.Background())
+	require.NoError(t, err)
+	event = storedEvent(t, db, 1)
+	assert.NotNil(t, event.DispatchedAt)
+	assert.Empty(t, event.LastError)
+	assert.Equal(t, 3, calls)
+}
+
+func TestDispatcher_GivesUpAfterMaxAttempts(t *testing.T) {
+	// Setup
+	db, uow := dispatcherFixture(t)
+	d := NewDispatcher(uow, WithRetry(2, time.Millisecond, time.Millisecond))
+	d.Subscribe(ItemCreated, "down", func(ctx context.Context, event Event) error {
+		return errors.New("unavailable")
+	})
+	appendItemChange(t, uow, nil, &Item{ID: 1})
+
+	// Execute
+	for i := 0; i < 3; i++ {
+		_, err := d.DispatchPending(context.Background())
+		require.NoError(t, err)
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Assert
+	event := storedEvent(t, db, 1)
+	assert.False(t, event.Pending())
+	assert.NotNil(t, event.FailedAt)
+	assert.Nil(t, event.DispatchedAt)
+	assert.Equal(t, 2, event.Attempts)
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, Backoff(1, time.Second, 5*time.Second))
+	assert.Equal(t, 2*time.Second, Backoff(2, time.Second, 5*time.Second))
+	assert.Equal(t, 4*time.Second, Backoff(3, time.Second, 5*time.Second))
+	assert.Equal(t, 5*time.Second, Backoff(4, time.Second, 5*time.Second))
+	assert.Equal(t, 5*time.Second, Backoff(9, time.Second, 5*time.Second))
+}
+
+func TestDispatcher_Run(t *testing.T) {
+	// Setup
+	_, uow := dispatcherFixture(t)
+	d := NewDispatcher(uow, WithPollInterval(5*time.Millisecond), WithBatchSize(1))
+
+	var mu sync.Mutex
+	var delivered []uint
+	d.Subscribe(AllEvents, "record", func(ctx context.Context, event Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		delivered = append(delivered, event.EntityID)
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		d.Run(ctx)
+		close(done)
+	}()
+
+	// Execute: events committed while running are picked up
+	appendItemChange(t, uow, nil, &Item{ID: 1})
+	appendItemChange(t, uow, nil, &Item{ID: 2})
+
+	// Assert
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(delivered) == 2
+	}, time.Second, 5*time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not stop after cancellation")
+	}
+	assert.Equal(t, []uint{1, 2}, delivered)
+}
@@ -0,0 +1,303 @@
+// Package events defines the domain events emitted by the services and
+// delivers them from the outbox to in-process subscribers.
+//
+// Services append events to the outbox inside UnitOfWork.Do, so an event
+// is stored if and only if the change it describes is committed. A
+// Dispatcher then delivers each stored event to the subscribers of its
+// type, at least once: an event whose delivery fails is retried, so
+// handlers must tolerate seeing the same event twice (Event.ID identifies
+// it).
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+)
+
+// Event types. The names are part of the contract with subscribers and
+// must stay stable.
+const (
+	ItemCreated      = "item.created"
+	ItemUpdated      = "item.updated"
+	ItemPriceChanged = "item.price_changed"
+	ItemSold         = "item.sold"
+	ItemDeleted      = "item.deleted"
+	AlertTriggered   = "alert.triggered"
+)
+
+// Types lists every event type.
+func Types() []string {
+	return []string{ItemCreated, ItemUpdated, ItemPriceChanged, ItemSold, ItemDeleted, AlertTriggered}
+}
+
+// Event is a delivered domain event.
+type Event struct {
+	ID          uint
+	Type        string
+	Entity      string
+	EntityID    uint
+	OperationID string
+	Actor       string
+	OccurredAt  time.Time
+	Payload     json.RawMessage
+}
+
+func fromOutbox(stored *models.OutboxEvent) Event {
+	return Event{
+		ID:          stored.ID,
+		Type:        stored.Type,
+		Entity:      stored.Entity,
+		EntityID:    stored.EntityID,
+		OperationID: stored.OperationID,
+		Actor:       stored.Actor,
+		OccurredAt:  stored.CreatedAt,
+		Payload:     json.RawMessage(stored.Payload),
+	}
+}
+
+// Decode unmarshals the payload into v, one of the payload types of this
+// package matching the event type.
+func (e Event) Decode(v interface{}) error {
+	if err := json.Unmarshal(e.Payload, v); err != nil {
+		return fmt.Errorf("decode %s event %d: %w", e.Type, e.ID, err)
+	}
+	return nil
+}
+
+// Item is the state of an item carried by item events.
+type Item struct {
+	ID            uint       `json:"id"`
+	ExtensionCode string     `json:"extension_code"`
+	BlockCode     string     `json:"block_code"`
+	Type          string     `json:"type"`
+	LanguageCode  string     `json:"language_code"`
+	Price         *float64   `json:"price"`
+	Quantity      int        `json:"quantity"`
+	PurchasedAt   *time.Time `json:"purchased_at"`
+	SoldAt        *time.Time `json:"sold_at"`
+	SoldPrice     *float64   `json:"sold_price"`
+}
+
+// ItemSnapshot copies the state of an item loaded with its associations.
+func ItemSnapshot(item *models.Item) Item {
+	snapshot := Item{
+		ID:            item.ID,
+		ExtensionCode: item.Extension.Code,
+		BlockCode:     item.Extension.Block.Code,
+		Type:          item.Type.Name,
+		LanguageCode:  item.Language.Code,
+		Quantity:      item.Quantity,
+	}
+	if item.Price != nil {
+		price := *item.Price
+		snapshot.Price = &price
+	}
+	if item.PurchasedAt != nil {
+		purchasedAt := *item.PurchasedAt
+		snapshot.PurchasedAt = &purchasedAt
+	}
+	if item.SoldAt != nil {
+		soldAt := *item.SoldAt
+		snapshot.SoldAt = &soldAt
+	}
+	if item.SoldPrice != nil {
+		soldPrice := *item.SoldPrice
+		snapshot.SoldPrice = &soldPrice
+	}
+	return snapshot
+}
+
+// ItemCreatedPayload is the payload of item.created.
+type ItemCreatedPayload struct {
+	Item Item `json:"item"`
+}
+
+// ItemUpdatedPayload is the payload of item.updated. Changed lists the
+// json names of the fields that differ between Before and After.
+type ItemUpdatedPayload struct {
+	Before  Item     `json:"before"`
+	After   Item     `json:"after"`
+	Changed []string `json:"changed"`
+}
+
+// ItemPriceChangedPayload is the payload of item.price_changed.
+type ItemPriceChangedPayload struct {
+	Item     Item     `json:"item"`
+	OldPrice *float64 `json:"old_price"`
+	NewPrice *float64 `json:"new_price"`
+}
+
+// ItemSoldPayload is the payload of item.sold. The item carries the sale
+// date and price.
+type ItemSoldPayload struct {
+	Item Item `json:"item"`
+}
+
+// ItemDeletedPayload is the payload of item.deleted.
+type ItemDeletedPayload struct {
+	Item Item `json:"item"`
+}
+
+// Alert is the rule carried by alert events. Window is empty for
+// conditions on the price itself.
+type Alert struct {
+	ID            uint    `json:"id"`
+	Name          string  `json:"name"`
+	ItemID        *uint   `json:"item_id"`
+	ExtensionCode string  `json:"extension_code,omitempty"`
+	Type          string  `json:"type,omitempty"`
+	LanguageCode  string  `json:"language_code,omitempty"`
+	Condition     string  `json:"condition"`
+	Threshold     float64 `json:"threshold"`
+	Window        string  `json:"window,omitempty"`
+}
+
+// AlertSnapshot copies an alert rule.
+func AlertSnapshot(rule *models.AlertRule) Alert {
+	alert := Alert{
+		ID:            rule.ID,
+		Name:          rule.Name,
+		ExtensionCode: rule.ExtensionCode,
+		Type:          rule.ItemType,
+		LanguageCode:  rule.LanguageCode,
+		Condition:     string(rule.Condition),
+		Threshold:     rule.Threshold,
+	}
+	if rule.ItemID != nil {
+		id := *rule.ItemID
+		alert.ItemID = &id
+	}
+	if rule.Condition.IsChange() {
+		alert.Window = models.FormatAlertWindow(rule.Window)
+	}
+	return alert
+}
+
+// AlertTriggeredPayload is the payload of alert.triggered. Price is the
+// latest recorded price; for changes over a window, ReferencePrice is the
+// oldest price of the window and ChangePercent the change between them.
+type AlertTriggeredPayload struct {
+	Alert          Alert     `json:"alert"`
+	Price          float64   `json:"price"`
+	Currency       string    `json:"currency"`
+	QuotedAt       time.Time `json:"quoted_at"`
+	ReferencePrice *float64  `json:"reference_price,omitempty"`
+	ChangePercent  *float64  `json:"change_percent,omitempty"`
+}
+
+// ForAlert returns the alert.triggered event of a rule, to be appended to
+// the outbox.
+func ForAlert(payload AlertTriggeredPayload) (*models.OutboxEvent, error) {
+	return newOutboxEvent(AlertTriggered, repository.AuditEntityAlert, payload.Alert.ID, payload)
+}
+
+// ForItemChange returns the events describing the change of an item from
+// before to after, to be appended to the outbox. A nil before is a
+// creation and a nil after a deletion. A change of price emits both
+// item.updated and item.price_changed, and a sale both item.updated and
+// item.sold; no change emits nothing.
+func ForItemChange(before, after *Item) ([]*models.OutboxEvent, error) {
+	switch {
+	case before == nil && after == nil:
+		return nil, nil
+	case before == nil:
+		return one(ItemCreated, after.ID, ItemCreatedPayload{Item: *after})
+	case after == nil:
+		return one(ItemDeleted, before.ID, ItemDeletedPayload{Item: *before})
+	}
+
+	changed := before.changes(after)
+	if len(changed) == 0 {
+		return nil, nil
+	}
+
+	updated, err := newOutboxEvent(ItemUpdated, repository.AuditEntityItem, after.ID, ItemUpdatedPayload{Before: *before, After: *after, Changed: changed})
+	if err != nil {
+		return nil, err
+	}
+	out := []*models.OutboxEvent{updated}
+
+	if !samePrice(before.Price, after.Price) {
+		priceChanged, err := newOutboxEvent(ItemPriceChanged, repository.AuditEntityItem, after.ID, ItemPriceChangedPayload{Item: *after, OldPrice: before.Price, NewPrice: after.Price})
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, priceChanged)
+	}
+	if before.SoldAt == nil && after.SoldAt != nil {
+		sold, err := newOutboxEvent(ItemSold, repository.AuditEntityItem, after.ID, ItemSoldPayload{Item: *after})
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, sold)
+	}
+	return out, nil
+}
+
+func (i *Item) changes(other *Item) []string {
+	var changed []string
+	if i.ExtensionCode != other.ExtensionCode {
+		changed = append(changed, "extension_code")
+	}
+	if i.Type != other.Type {
+		changed = append(changed, "type")
+	}
+	if i.LanguageCode != other.LanguageCode {
+		changed = append(changed, "language_code")
+	}
+	if !samePrice(i.Price, other.Price) {
+		changed = append(changed, "price")
+	}
+	if i.Quantity != other.Quantity {
+		changed = append(changed, "quantity")
+	}
+	if !sameTime(i.PurchasedAt, other.PurchasedAt) {
+		changed = append(changed, "purchased_at")
+	}
+	if !sameTime(i.SoldAt, other.SoldAt) {
+		changed = append(changed, "sold_at")
+	}
+	if !samePrice(i.SoldPrice, other.SoldPrice) {
+		changed = append(changed, "sold_price")
+	}
+	return changed
+}
+
+func samePrice(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func one(eventType string, itemID uint, payload interface{}) ([]*models.OutboxEvent, error) {
+	event, err := newOutboxEvent(eventType, repository.AuditEntityItem, itemID, payload)
+	if err != nil {
+		return nil, err
+	}
+	return []*models.OutboxEvent{event}, nil
+}
+
+func newOutboxEvent(eventType, entity string, entityID uint, payload interface{}) (*models.OutboxEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encode %s event: %w", eventType, err)
+	}
+	return &models.OutboxEvent{
+		Type:     eventType,
+		Entity:   entity,
+		EntityID: entityID,
+		Payload:  string(data),
+	}, nil
+}
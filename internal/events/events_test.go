@@ -0,0 +1,89 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestItemSnapshot(t *testing.T) {
+	item := &models.Item{
+		Model:     gorm.Model{ID: 3},
+		Extension: models.Extension{Code: "DRI", Block: models.Block{Code: "EV"}},
+		Type:      models.ItemType{Name: "Display"},
+		Language:  models.Language{Code: "fr"},
+		Price:     testutil.FloatPtr(180),
+	}
+
+	snapshot := ItemSnapshot(item)
+	*item.Price = 1
+
+	assert.Equal(t, Item{ID: 3, ExtensionCode: "DRI", BlockCode: "EV", Type: "Display", LanguageCode: "fr", Price: testutil.FloatPtr(180)}, snapshot)
+}
+
+func TestForItemChange(t *testing.T) {
+	item := func(lang string, price *float64) *Item {
+		return &Item{ID: 3, ExtensionCode: "DRI", BlockCode: "EV", Type: "Display", LanguageCode: lang, Price: price}
+	}
+	soldAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	sold := item("fr", testutil.FloatPtr(180))
+	sold.SoldAt, sold.SoldPrice = &soldAt, testutil.FloatPtr(240)
+
+	tests := []struct {
+		name     string
+		before   *Item
+		after    *Item
+		expected []string
+	}{
+		{"creation", nil, item("fr", nil), []string{ItemCreated}},
+		{"deletion", item("fr", nil), nil, []string{ItemDeleted}},
+		{"no change", item("fr", testutil.FloatPtr(180)), item("fr", testutil.FloatPtr(180)), nil},
+		{"language", item("fr", nil), item("en", nil), []string{ItemUpdated}},
+		{"price", item("fr", testutil.FloatPtr(180)), item("fr", testutil.FloatPtr(210)), []string{ItemUpdated, ItemPriceChanged}},
+		{"price removed", item("fr", testutil.FloatPtr(180)), item("fr", nil), []string{ItemUpdated, ItemPriceChanged}},
+		{"sale", item("fr", testutil.FloatPtr(180)), sold, []string{ItemUpdated, ItemSold}},
+		{"sale undone", sold, item("fr", testutil.FloatPtr(180)), []string{ItemUpdated}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Execute
+			changes, err := ForItemChange(tt.before, tt.after)
+
+			// Assert
+			require.NoError(t, err)
+			var types []string
+			for _, event := range changes {
+				types = append(types, event.Type)
+				assert.Equal(t, "item", event.Entity)
+				assert.Equal(t, uint(3), event.EntityID)
+				assert.True(t, json.Valid([]byte(event.Payload)))
+			}
+			assert.Equal(t, tt.expected, types)
+		})
+	}
+}
+
+func TestEvent_Decode(t *testing.T) {
+	changes, err := ForItemChange(&Item{ID: 3, Price: testutil.FloatPtr(180)}, &Item{ID: 3, Price: testutil.FloatPtr(210)})
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+
+	var updated ItemUpdatedPayload
+	require.NoError(t, fromOutbox(changes[0]).Decode(&updated))
+	assert.Equal(t, []string{"price"}, updated.Changed)
+	assert.Equal(t, 180.0, *updated.Before.Price)
+
+	var priceChanged ItemPriceChangedPayload
+	require.NoError(t, fromOutbox(changes[1]).Decode(&priceChanged))
+	assert.Equal(t, 180.0, *priceChanged.OldPrice)
+	assert.Equal(t, 210.0, *priceChanged.NewPrice)
+
+	assert.Error(t, Event{Type: ItemCreated, Payload: json.RawMessage("{")}.Decode(&updated))
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AlertCondition string
+
+const (
+	// AlertAbove holds when the price is at or above Threshold.
+	AlertAbove AlertCondition = "above"
+	// AlertBelow holds when the price is at or below Threshold.
+	AlertBelow AlertCondition = "below"
+	// AlertRise holds when the price rose by at least Threshold percent
+	// over Window.
+	AlertRise AlertCondition = "rise"
+	// AlertDrop holds when the price dropped by at least Threshold percent
+	// over Window.
+	AlertDrop AlertCondition = "drop"
+)
+
+// IsChange reports whether the condition compares prices over a window
+// rather than to a fixed price.
+func (c AlertCondition) IsChange() bool {
+	return c == AlertRise || c == AlertDrop
+}
+
+// AlertRule watches the recorded prices of one item, when ItemID is set,
+// or of every item of a product, identified by its extension code, item
+// type and language code.
+//
+// Triggered is the state of the rule: the rule fires when its condition
+// starts to hold and sets it, and only fires again once the condition
+// stopped holding and cleared it.
+type AlertRule struct {
+	ID            uint           `gorm:"primaryKey"`
+	Name          string         `gorm:"type:varchar(100);not null"`
+	ItemID        *uint          `gorm:"index"`
+	Item          *Item          `gorm:"foreignKey:ItemID;constraint:OnDelete:CASCADE"`
+	ExtensionCode string         `gorm:"type:varchar(10);not null;default:''"`
+	ItemType      string         `gorm:"type:varchar(50);not null;default:''"`
+	LanguageCode  string         `gorm:"type:varchar(5);not null;default:''"`
+	Condition     AlertCondition `gorm:"type:varchar(10);not null"`
+	Threshold     float64        `gorm:"type:decimal(10,2);not null"`
+	Window        time.Duration  `gorm:"not null;default:0"`
+	Triggered     bool           `gorm:"not null;default:false"`
+	TriggeredAt   *time.Time
+	CreatedAt     time.Time      `gorm:"not null"`
+	DeletedAt     gorm.DeletedAt `gorm:"index"`
+}
+
+// ParseAlertWindow parses the window of an alert rule: a number of days
+// such as "7d", or a Go duration such as "36h".
+func ParseAlertWindow(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	} else if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("invalid window '%s': expected a duration such as 7d or 36h", s)
+}
+
+// FormatAlertWindow formats a window the way ParseAlertWindow reads it, in
+// days when it is a whole number of days.
+func FormatAlertWindow(d time.Duration) string {
+	if d > 0 && d%(24*time.Hour) == 0 {
+		return strconv.Itoa(int(d/(24*time.Hour))) + "d"
+	}
+	return d.String()
+}
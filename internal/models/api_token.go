@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Role grants access to the HTTP API. Each role includes the permissions
+// of the roles before it.
+type Role string
+
+const (
+	RoleReadOnly Role = "readonly"
+	RoleEditor   Role = "editor"
+	RoleAdmin    Role = "admin"
+)
+
+var roleRanks = map[Role]int{
+	RoleReadOnly: 1,
+	RoleEditor:   2,
+	RoleAdmin:    3,
+}
+
+func Roles() []Role {
+	return []Role{RoleReadOnly, RoleEditor, RoleAdmin}
+}
+
+func (r Role) Valid() bool {
+	_, ok := roleRanks[r]
+	return ok
+}
+
+// Allows reports whether r grants the permissions of required.
+func (r Role) Allows(required Role) bool {
+	return r.Valid() && roleRanks[r] >= roleRanks[required]
+}
+
+// APIToken is a bearer token for the HTTP API. Only the SHA-256 hash of the
+// secret is stored; Prefix keeps its first characters so tokens can be told
+// apart in listings.
+type APIToken struct {
+	gorm.Model
+	Name       string `gorm:"type:varchar(100);not null"`
+	Prefix     string `gorm:"type:varchar(16);not null"`
+	TokenHash  string `gorm:"type:char(64);uniqueIndex;not null"`
+	Role       Role   `gorm:"type:varchar(20);not null"`
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+func (t *APIToken) Active() bool {
+	return t.RevokedAt == nil
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type AuditAction string
+
+const (
+	AuditCreate AuditAction = "create"
+	AuditUpdate AuditAction = "update"
+	AuditDelete AuditAction = "delete"
+)
+
+// AuditFields is an image of an entity's columns, keyed by column name,
+// stored as JSON. Numbers read back from the database are float64.
+type AuditFields map[string]interface{}
+
+func (f AuditFields) Value() (driver.Value, error) {
+	if f == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(map[string]interface{}(f))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (f *AuditFields) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*f = nil
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("cannot scan %T into AuditFields", value)
+	}
+	return json.Unmarshal(data, (*map[string]interface{})(f))
+}
+
+// Normalized returns f as it reads back from the database, so that an
+// image built in memory compares equal to a stored one.
+func (f AuditFields) Normalized() (AuditFields, error) {
+	value, err := f.Value()
+	if err != nil {
+		return nil, err
+	}
+	var out AuditFields
+	if err := out.Scan(value); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AuditEntry records one write to an audited entity. Before is nil for
+// creations and After is nil for deletions. Entries written in the same
+// UnitOfWork.Do share an OperationID; entries written by an undo name the
+// operation they revert in Reverts.
+type AuditEntry struct {
+	ID          uint        `gorm:"primaryKey"`
+	OperationID string      `gorm:"type:varchar(32);not null;index"`
+	Entity      string      `gorm:"type:varchar(50);not null;index:idx_audit_entity"`
+	EntityID    uint        `gorm:"not null;index:idx_audit_entity"`
+	Action      AuditAction `gorm:"type:varchar(20);not null"`
+	Actor       string      `gorm:"type:varchar(100);not null"`
+	Reverts     string      `gorm:"type:varchar(32);not null;default:'';index"`
+	Before      AuditFields `gorm:"type:text"`
+	After       AuditFields `gorm:"type:text"`
+	CreatedAt   time.Time   `gorm:"not null;index"`
+}
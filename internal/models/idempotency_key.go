@@ -0,0 +1,55 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// IDList is a list of IDs stored as JSON.
+type IDList []uint
+
+func (l IDList) Value() (driver.Value, error) {
+	if l == nil {
+		l = IDList{}
+	}
+	data, err := json.Marshal([]uint(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (l *IDList) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("cannot scan %T into IDList", value)
+	}
+	return json.Unmarshal(data, (*[]uint)(l))
+}
+
+// IdempotencyKey remembers the result of a write made with a key chosen by
+// the client, so that the write retried with the same key returns that
+// result instead of being made again. Keys are unique per actor and
+// operation, so clients cannot collide, and are forgotten once ExpiresAt
+// passes. RequestHash identifies the request the key was first used for:
+// the key cannot be reused for another one.
+type IdempotencyKey struct {
+	ID          uint      `gorm:"primaryKey"`
+	Actor       string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_idempotency_key"`
+	Operation   string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_idempotency_key"`
+	Key         string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_key"`
+	RequestHash string    `gorm:"type:char(64);not null"`
+	ItemIDs     IDList    `gorm:"type:text;not null"`
+	CreatedAt   time.Time `gorm:"not null"`
+	ExpiresAt   time.Time `gorm:"not null;index"`
+}
@@ -1,6 +1,10 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Item struct {
 	gorm.Model
@@ -11,4 +15,14 @@ type Item struct {
 	LanguageID  uint      `gorm:"not null;index"`
 	Language    Language  `gorm:"foreignKey:LanguageID;constraint:OnDelete:RESTRICT"`
 	Price       *float64  `gorm:"type:decimal(10,2)"`
+	// Quantity is the number of identical copies the item stands for.
+	Quantity    int `gorm:"not null;default:1"`
+	PurchasedAt *time.Time
+	// MergedIntoID is set on a deleted item that was merged into another
+	// one as a duplicate.
+	MergedIntoID *uint `gorm:"index"`
+	// SoldAt is set once the item is sold, SoldPrice to the amount it was
+	// sold for when known.
+	SoldAt    *time.Time
+	SoldPrice *float64 `gorm:"type:decimal(10,2)"`
 }
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type JobStatus string
+
+const (
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+)
+
+// JobState records the runs of a scheduled job, keyed by its name: when it
+// last ran, for how long and with which outcome, and when it runs next.
+// RunningSince is set while a run is in progress, so a run is not started
+// again before it ends, even by another process sharing the database.
+type JobState struct {
+	ID           uint   `gorm:"primaryKey"`
+	Name         string `gorm:"type:varchar(100);not null;uniqueIndex"`
+	Schedule     string `gorm:"type:varchar(100);not null;default:''"`
+	LastRunAt    *time.Time
+	LastDuration time.Duration `gorm:"not null;default:0"`
+	LastStatus   JobStatus     `gorm:"type:varchar(20);not null;default:''"`
+	LastError    string        `gorm:"type:text;not null;default:''"`
+	NextRunAt    *time.Time
+	RunningSince *time.Time
+	UpdatedAt    time.Time `gorm:"not null"`
+}
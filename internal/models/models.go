@@ -7,5 +7,14 @@ func GetModels() []interface{} {
 		&ItemType{},
 		&Item{},
 		&Language{},
+		&APIToken{},
+		&AuditEntry{},
+		&OutboxEvent{},
+		&Webhook{},
+		&WebhookDelivery{},
+		&PriceRecord{},
+		&AlertRule{},
+		&JobState{},
+		&IdempotencyKey{},
 	}
 }
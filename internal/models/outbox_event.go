@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// OutboxEvent is a domain event stored in the same transaction as the
+// change it describes, until it is delivered to the subscribers. Payload
+// is JSON whose shape depends on Type.
+type OutboxEvent struct {
+	ID            uint       `gorm:"primaryKey"`
+	Type          string     `gorm:"type:varchar(50);not null;index"`
+	Entity        string     `gorm:"type:varchar(50);not null"`
+	EntityID      uint       `gorm:"not null"`
+	OperationID   string     `gorm:"type:varchar(32);not null;default:'';index"`
+	Actor         string     `gorm:"type:varchar(100);not null"`
+	Payload       string     `gorm:"type:text;not null"`
+	Attempts      int        `gorm:"not null;default:0"`
+	LastError     string     `gorm:"type:text;not null;default:''"`
+	NextAttemptAt time.Time  `gorm:"not null;index"`
+	DispatchedAt  *time.Time `gorm:"index"`
+	FailedAt      *time.Time
+	CreatedAt     time.Time `gorm:"not null"`
+}
+
+// Pending reports whether the event still has to be delivered.
+func (e *OutboxEvent) Pending() bool {
+	return e.DispatchedAt == nil && e.FailedAt == nil
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// PriceRecord is a market value of an item quoted by a price provider. The
+// records of an item form its price history.
+type PriceRecord struct {
+	ID        uint      `gorm:"primaryKey"`
+	ItemID    uint      `gorm:"not null;index:idx_price_record_item,priority:1"`
+	Item      Item      `gorm:"foreignKey:ItemID;constraint:OnDelete:CASCADE"`
+	Price     float64   `gorm:"type:decimal(10,2);not null"`
+	Currency  string    `gorm:"type:varchar(3);not null;default:''"`
+	Source    string    `gorm:"type:varchar(50);not null"`
+	QuotedAt  time.Time `gorm:"not null;index:idx_price_record_item,priority:2"`
+	CreatedAt time.Time `gorm:"not null"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Webhook is a subscription of an external URL to domain events. Events is
+// a comma-separated list of event types, "*" for all of them. Secret signs
+// the deliveries and is stored as is, since it is needed to sign.
+// PriceThreshold, when set, only lets through the price changes that cross
+// it.
+type Webhook struct {
+	ID             uint           `gorm:"primaryKey"`
+	Name           string         `gorm:"type:varchar(100);not null"`
+	URL            string         `gorm:"type:varchar(2048);not null"`
+	Events         string         `gorm:"type:varchar(255);not null"`
+	Secret         string         `gorm:"type:varchar(100);not null"`
+	PriceThreshold *float64       `gorm:"type:decimal(10,2)"`
+	CreatedAt      time.Time      `gorm:"not null"`
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
+}
+
+// EventTypes returns the event types the webhook subscribes to.
+func (w *Webhook) EventTypes() []string {
+	var types []string
+	for _, t := range strings.Split(w.Events, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// Subscribes reports whether the webhook receives events of eventType.
+func (w *Webhook) Subscribes(eventType string) bool {
+	for _, t := range w.EventTypes() {
+		if t == "*" || t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// WebhookDelivery is the delivery of one event to one webhook and its log:
+// the number of attempts and the outcome of the last one. Payload is the
+// exact body sent on every attempt.
+type WebhookDelivery struct {
+	ID             uint           `gorm:"primaryKey"`
+	WebhookID      uint           `gorm:"not null;uniqueIndex:idx_delivery_event"`
+	Webhook        Webhook        `gorm:"foreignKey:WebhookID;constraint:OnDelete:CASCADE"`
+	EventID        uint           `gorm:"not null;uniqueIndex:idx_delivery_event"`
+	EventType      string         `gorm:"type:varchar(50);not null"`
+	Payload        string         `gorm:"type:text;not null"`
+	Status         DeliveryStatus `gorm:"type:varchar(20);not null;index"`
+	Attempts       int            `gorm:"not null;default:0"`
+	NextAttemptAt  time.Time      `gorm:"not null;index"`
+	ResponseStatus int            `gorm:"not null;default:0"`
+	LastError      string         `gorm:"type:text;not null;default:''"`
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time `gorm:"not null"`
+	UpdatedAt      time.Time `gorm:"not null"`
+}
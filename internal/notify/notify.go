@@ -0,0 +1,98 @@
+// Package notify tells the collector about triggered price alerts.
+//
+// A Notifier delivers a Message. LogNotifier writes it to a log, such as
+// the console, and SMTPNotifier sends it by email. AlertHandler subscribes
+// a notifier to the alert.triggered events of the dispatcher.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/R4yL-dev/pkmc/internal/events"
+	"github.com/R4yL-dev/pkmc/internal/models"
+)
+
+// Message is a notification.
+type Message struct {
+	Subject string
+	Body    string
+}
+
+// Notifier delivers messages. An error makes the dispatcher deliver the
+// event again later.
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// LogNotifier writes messages to a log.
+type LogNotifier struct {
+	logger *log.Logger
+}
+
+func NewLogNotifier(w io.Writer) *LogNotifier {
+	return &LogNotifier{logger: log.New(w, "alert: ", log.LstdFlags)}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, msg Message) error {
+	n.logger.Printf("%s\n%s", msg.Subject, msg.Body)
+	return nil
+}
+
+// AlertHandler returns the event handler that notifies alert.triggered
+// events.
+func AlertHandler(notifier Notifier) events.Handler {
+	return func(ctx context.Context, event events.Event) error {
+		var alert events.AlertTriggeredPayload
+		if err := event.Decode(&alert); err != nil {
+			return err
+		}
+		return notifier.Notify(ctx, AlertMessage(alert))
+	}
+}
+
+// AlertMessage describes a triggered alert.
+func AlertMessage(alert events.AlertTriggeredPayload) Message {
+	subject := "pkmc alert: " + alert.Alert.Name
+
+	var scope string
+	if alert.Alert.ItemID != nil {
+		scope = fmt.Sprintf("Item %d", *alert.Alert.ItemID)
+	} else {
+		scope = fmt.Sprintf("%s %s (%s)", alert.Alert.ExtensionCode, alert.Alert.Type, alert.Alert.LanguageCode)
+	}
+	price := formatPrice(alert.Price, alert.Currency)
+
+	var body strings.Builder
+	switch models.AlertCondition(alert.Alert.Condition) {
+	case models.AlertAbove:
+		fmt.Fprintf(&body, "%s is now at %s, at or above %s.\n", scope, price, formatPrice(alert.Alert.Threshold, alert.Currency))
+	case models.AlertBelow:
+		fmt.Fprintf(&body, "%s is now at %s, at or below %s.\n", scope, price, formatPrice(alert.Alert.Threshold, alert.Currency))
+	default:
+		verb := "rose"
+		if alert.Alert.Condition == string(models.AlertDrop) {
+			verb = "dropped"
+		}
+		var change, reference float64
+		if alert.ChangePercent != nil && alert.ReferencePrice != nil {
+			change, reference = *alert.ChangePercent, *alert.ReferencePrice
+		}
+		if change < 0 {
+			change = -change
+		}
+		fmt.Fprintf(&body, "%s %s %.1f%% within %s, from %s to %s.\n", scope, verb, change, alert.Alert.Window, formatPrice(reference, alert.Currency), price)
+	}
+	fmt.Fprintf(&body, "Quoted at %s.\n", alert.QuotedAt.Local().Format("2006-01-02 15:04"))
+	return Message{Subject: subject, Body: body.String()}
+}
+
+func formatPrice(price float64, currency string) string {
+	if currency == "" {
+		return fmt.Sprintf("%.2f", price)
+	}
+	return fmt.Sprintf("%.2f %s", price, currency)
+}
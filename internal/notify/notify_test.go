@@ -0,0 +1,240 @@
+package notify
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/pkmc/internal/events"
+	"github.com/R4yL-dev/pkmc/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTP is a local SMTP server that accepts every message and records
+// the envelope and data of each.
+type fakeSMTP struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	from     []string
+	to       [][]string
+	messages []string
+	// reject makes the server refuse recipients with this reply.
+	reject string
+}
+
+func startFakeSMTP(t *testing.T) *fakeSMTP {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &fakeSMTP{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (s *fakeSMTP) addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	var from string
+	var to []string
+	reply("220 localhost fake SMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		switch verb {
+		case "EHLO", "HELO":
+			reply("250-localhost")
+			reply("250 8BITMIME")
+		case "MAIL":
+			from = strings.Fields(strings.TrimPrefix(line, "MAIL FROM:"))[0]
+			reply("250 OK")
+		case "RCPT":
+			s.mu.Lock()
+			reject := s.reject
+			s.mu.Unlock()
+			if reject != "" {
+				reply(reject)
+				continue
+			}
+			to = append(to, strings.Fields(strings.TrimPrefix(line, "RCPT TO:"))[0])
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data bytes.Buffer
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			s.mu.Lock()
+			s.from = append(s.from, from)
+			s.to = append(s.to, to)
+			s.messages = append(s.messages, data.String())
+			s.mu.Unlock()
+			reply("250 OK queued")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func droppedDisplay() events.AlertTriggeredPayload {
+	return events.AlertTriggeredPayload{
+		Alert: events.Alert{
+			ID:            1,
+			Name:          "DRI Display (fr) drop 15% in 7d",
+			ExtensionCode: "DRI",
+			Type:          "Display",
+			LanguageCode:  "fr",
+			Condition:     "drop",
+			Threshold:     15,
+			Window:        "7d",
+		},
+		Price:          204,
+		Currency:       "EUR",
+		QuotedAt:       time.Date(2026, 10, 8, 12, 0, 0, 0, time.UTC),
+		ReferencePrice: testutil.FloatPtr(255),
+		ChangePercent:  testutil.FloatPtr(-20),
+	}
+}
+
+func TestAlertMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		alert    events.AlertTriggeredPayload
+		subject  string
+		contains string
+	}{
+		{
+			name:     "drop over a window",
+			alert:    droppedDisplay(),
+			subject:  "pkmc alert: DRI Display (fr) drop 15% in 7d",
+			contains: "DRI Display (fr) dropped 20.0% within 7d, from 255.00 EUR to 204.00 EUR.",
+		},
+		{
+			name: "above a price, item scope",
+			alert: events.AlertTriggeredPayload{
+				Alert: events.Alert{ID: 2, Name: "my display", ItemID: func() *uint { id := uint(3); return &id }(), Condition: "above", Threshold: 250},
+				Price: 255.5,
+			},
+			subject:  "pkmc alert: my display",
+			contains: "Item 3 is now at 255.50, at or above 250.00.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := AlertMessage(tt.alert)
+
+			assert.Equal(t, tt.subject, msg.Subject)
+			assert.Contains(t, msg.Body, tt.contains)
+		})
+	}
+}
+
+func TestLogNotifier(t *testing.T) {
+	var out bytes.Buffer
+	notifier := NewLogNotifier(&out)
+
+	require.NoError(t, notifier.Notify(context.Background(), Message{Subject: "pkmc alert: test", Body: "body"}))
+
+	assert.Contains(t, out.String(), "alert: ")
+	assert.Contains(t, out.String(), "pkmc alert: test\nbody")
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	server := startFakeSMTP(t)
+	notifier := NewSMTPNotifier(server.addr(), "pkmc@home.example", []string{"me@home.example", "partner@home.example"})
+
+	// Execute
+	err := notifier.Notify(context.Background(), Message{Subject: "pkmc alert: Display ≥ 250€", Body: "line one\nline two\n"})
+
+	// Assert
+	require.NoError(t, err)
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	require.Len(t, server.messages, 1)
+	assert.Equal(t, "<pkmc@home.example>", server.from[0])
+	assert.Equal(t, []string{"<me@home.example>", "<partner@home.example>"}, server.to[0])
+
+	data := server.messages[0]
+	assert.Contains(t, data, "From: pkmc@home.example\r\n")
+	assert.Contains(t, data, "To: me@home.example, partner@home.example\r\n")
+	assert.Contains(t, data, "Subject: =?utf-8?q?", "non-ASCII subjects are encoded")
+	assert.Contains(t, data, "\r\n\r\nline one\r\nline two\r\n")
+}
+
+func TestSMTPNotifier_Errors(t *testing.T) {
+	t.Run("rejected recipient", func(t *testing.T) {
+		server := startFakeSMTP(t)
+		server.reject = "550 No such user"
+		notifier := NewSMTPNotifier(server.addr(), "pkmc@home.example", []string{"nobody@home.example"})
+
+		err := notifier.Notify(context.Background(), Message{Subject: "s", Body: "b"})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "550")
+		assert.Contains(t, err.Error(), "No such user")
+	})
+
+	t.Run("server down", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := listener.Addr().String()
+		listener.Close()
+		notifier := NewSMTPNotifier(addr, "pkmc@home.example", []string{"me@home.example"}, WithSMTPTimeout(time.Second))
+
+		assert.Error(t, notifier.Notify(context.Background(), Message{Subject: "s", Body: "b"}))
+	})
+}
+
+func TestAlertHandler(t *testing.T) {
+	server := startFakeSMTP(t)
+	handler := AlertHandler(NewSMTPNotifier(server.addr(), "pkmc@home.example", []string{"me@home.example"}))
+
+	event, err := events.ForAlert(droppedDisplay())
+	require.NoError(t, err)
+
+	require.NoError(t, handler(context.Background(), events.Event{ID: 1, Type: events.AlertTriggered, Payload: []byte(event.Payload)}))
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	require.Len(t, server.messages, 1)
+	assert.Contains(t, server.messages[0], "dropped 20.0% within 7d")
+}
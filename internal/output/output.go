@@ -0,0 +1,414 @@
+// Package output renders command results in the formats supported by the
+// CLI. Values are plain structs (see internal/dto); column and key names are
+// taken from their json tags so every format uses the same field names.
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+type Format string
+
+const (
+	FormatTable  Format = "table"
+	FormatJSON   Format = "json"
+	FormatNDJSON Format = "ndjson"
+	FormatCSV    Format = "csv"
+	FormatText   Format = "text"
+)
+
+func Formats() []Format {
+	return []Format{FormatTable, FormatJSON, FormatNDJSON, FormatCSV, FormatText}
+}
+
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats() {
+		if string(f) == strings.ToLower(strings.TrimSpace(s)) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown output format '%s' (expected one of %s)", s, formatList())
+}
+
+func formatList() string {
+	names := make([]string, 0, len(Formats()))
+	for _, f := range Formats() {
+		names = append(names, string(f))
+	}
+	return strings.Join(names, ", ")
+}
+
+// Render writes v to w in the requested format. v is either a flat struct,
+// a slice of flat structs, or a struct whose fields are all flat structs or
+// slices of flat structs (rendered as named sections).
+func Render(w io.Writer, format Format, v interface{}) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case FormatNDJSON:
+		return renderNDJSON(w, v)
+	case FormatCSV:
+		return renderCSV(w, v)
+	case FormatText:
+		return renderText(w, v)
+	case FormatTable, "":
+		return renderTable(w, v)
+	default:
+		return fmt.Errorf("unknown output format '%s'", format)
+	}
+}
+
+// section is one homogeneous block of records.
+type section struct {
+	name    string
+	columns []string
+	rows    []reflect.Value
+	single  bool
+}
+
+// shape classifies v into sections. A flat value or slice yields a single
+// unnamed section.
+func shape(v interface{}) ([]section, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+
+	switch {
+	case isRecordSlice(rv.Type()):
+		return []section{recordsSection("", rv)}, nil
+	case isFlatStruct(rv.Type()):
+		return []section{{columns: columns(rv.Type()), rows: []reflect.Value{rv}, single: true}}, nil
+	case rv.Kind() == reflect.Struct:
+		var sections []section
+		for i := 0; i < rv.NumField(); i++ {
+			field := rv.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			value := rv.Field(i)
+			switch {
+			case isFlatStruct(field.Type):
+				sections = append(sections, section{name: jsonName(field), columns: columns(field.Type), rows: []reflect.Value{value}, single: true})
+			case isRecordSlice(field.Type):
+				sections = append(sections, recordsSection(jsonName(field), value))
+			default:
+				return nil, fmt.Errorf("output: field %s of %s cannot be rendered", field.Name, rv.Type())
+			}
+		}
+		return sections, nil
+	default:
+		return nil, fmt.Errorf("output: values of type %s cannot be rendered", rv.Type())
+	}
+}
+
+func recordsSection(name string, rv reflect.Value) section {
+	s := section{name: name, columns: columns(rv.Type().Elem())}
+	for i := 0; i < rv.Len(); i++ {
+		s.rows = append(s.rows, rv.Index(i))
+	}
+	return s
+}
+
+func isRecordSlice(t reflect.Type) bool {
+	return (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && isFlatStruct(t.Elem())
+}
+
+func isFlatStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t == timeType {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() && !isScalar(t.Field(i).Type) {
+			return false
+		}
+	}
+	return true
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func isScalar(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func columns(t reflect.Type) []string {
+	var cols []string
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			cols = append(cols, jsonName(t.Field(i)))
+		}
+	}
+	return cols
+}
+
+func jsonName(field reflect.StructField) string {
+	if tag, ok := field.Tag.Lookup("json"); ok {
+		if name := strings.Split(tag, ",")[0]; name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// fieldValues returns the exported field values of a flat struct in
+// declaration order.
+func fieldValues(rv reflect.Value) []reflect.Value {
+	var values []reflect.Value
+	for i := 0; i < rv.NumField(); i++ {
+		if rv.Type().Field(i).IsExported() {
+			values = append(values, rv.Field(i))
+		}
+	}
+	return values
+}
+
+// scalar formats a scalar value. nilValue is used for nil pointers and
+// human selects the compact representations used in tables.
+func scalar(v reflect.Value, nilValue string, human bool) string {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nilValue
+		}
+		v = v.Elem()
+	}
+
+	if v.Type() == timeType {
+		t := v.Interface().(time.Time)
+		if human {
+			// Dates such as release dates are stored at midnight.
+			if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+				return t.Format("2006-01-02")
+			}
+			return t.Local().Format("2006-01-02 15:04")
+		}
+		return t.Format(time.RFC3339)
+	}
+
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if human {
+			return strconv.FormatFloat(v.Float(), 'f', 2, 64)
+		}
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+func renderTable(w io.Writer, v interface{}) error {
+	sections, err := shape(v)
+	if err != nil {
+		return err
+	}
+
+	for i, s := range sections {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		if s.name != "" {
+			fmt.Fprintf(w, "%s\n", headerName(s.name))
+		}
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		if s.single && s.name == "" {
+			values := fieldValues(s.rows[0])
+			for j, col := range s.columns {
+				fmt.Fprintf(tw, "%s:\t%s\n", labelName(col), scalar(values[j], "-", true))
+			}
+		} else if len(s.rows) == 0 {
+			fmt.Fprintln(tw, "No results")
+		} else {
+			headers := make([]string, len(s.columns))
+			for j, col := range s.columns {
+				headers[j] = headerName(col)
+			}
+			fmt.Fprintln(tw, strings.Join(headers, "\t"))
+			for _, row := range s.rows {
+				cells := make([]string, 0, len(s.columns))
+				for _, value := range fieldValues(row) {
+					cells = append(cells, scalar(value, "-", true))
+				}
+				fmt.Fprintln(tw, strings.Join(cells, "\t"))
+			}
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func headerName(name string) string {
+	return strings.ToUpper(strings.ReplaceAll(name, "_", " "))
+}
+
+func labelName(name string) string {
+	words := strings.Split(name, "_")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func renderCSV(w io.Writer, v interface{}) error {
+	sections, err := shape(v)
+	if err != nil {
+		return err
+	}
+
+	grouped := len(sections) > 1 || sections[0].name != ""
+	header := sections[0].columns
+	for _, s := range sections[1:] {
+		if strings.Join(s.columns, ",") != strings.Join(header, ",") {
+			return fmt.Errorf("output: sections %s and %s have different columns", sections[0].name, s.name)
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	if grouped {
+		header = append([]string{"group"}, header...)
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, s := range sections {
+		for _, row := range s.rows {
+			var cells []string
+			if grouped {
+				cells = append(cells, s.name)
+			}
+			for _, value := range fieldValues(row) {
+				cells = append(cells, scalar(value, "", false))
+			}
+			if err := cw.Write(cells); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func renderNDJSON(w io.Writer, v interface{}) error {
+	sections, err := shape(v)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range sections {
+		for _, row := range s.rows {
+			line, err := json.Marshal(row.Interface())
+			if err != nil {
+				return err
+			}
+			if s.name != "" && len(line) > 2 {
+				group, _ := json.Marshal(s.name)
+				line = append([]byte(`{"group":`+string(group)+`,`), line[1:]...)
+			}
+			if _, err := fmt.Fprintf(w, "%s\n", line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// renderText writes a YAML-like plain text document.
+func renderText(w io.Writer, v interface{}) error {
+	if _, err := shape(v); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	writeTextValue(&b, reflect.Indirect(reflect.ValueOf(v)), 0)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeTextValue(b *strings.Builder, rv reflect.Value, indent int) {
+	pad := strings.Repeat("  ", indent)
+
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		if rv.Len() == 0 {
+			fmt.Fprintf(b, "%s[]\n", pad)
+			return
+		}
+		for i := 0; i < rv.Len(); i++ {
+			var item strings.Builder
+			writeTextValue(&item, rv.Index(i), indent+1)
+			fmt.Fprintf(b, "%s- %s", pad, strings.TrimPrefix(item.String(), pad+"  "))
+		}
+		return
+	}
+
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := jsonName(field)
+		value := rv.Field(i)
+
+		switch {
+		case isScalar(field.Type):
+			fmt.Fprintf(b, "%s%s: %s\n", pad, name, textScalar(value))
+		case (value.Kind() == reflect.Slice || value.Kind() == reflect.Array) && value.Len() == 0:
+			fmt.Fprintf(b, "%s%s: []\n", pad, name)
+		default:
+			fmt.Fprintf(b, "%s%s:\n", pad, name)
+			writeTextValue(b, value, indent+1)
+		}
+	}
+}
+
+func textScalar(v reflect.Value) string {
+	s := scalar(v, "null", false)
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return s
+	}
+	if v.Kind() == reflect.String || (v.Kind() == reflect.Ptr && v.Elem().Kind() == reflect.String) {
+		if needsQuoting(s) {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
+
+func needsQuoting(s string) bool {
+	if s == "" || s != strings.TrimSpace(s) {
+		return true
+	}
+	switch strings.ToLower(s) {
+	case "null", "true", "false", "yes", "no", "~":
+		return true
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return true
+	}
+	return strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") || strings.Contains(s, ": ") || strings.Contains(s, " #")
+}
@@ -0,0 +1,172 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type record struct {
+	ID    uint       `json:"id"`
+	Name  string     `json:"name"`
+	Price *float64   `json:"price"`
+	Date  *time.Time `json:"date"`
+}
+
+type report struct {
+	Summary record   `json:"summary"`
+	Rows    []record `json:"rows"`
+}
+
+func fixtures() []record {
+	price := 12.5
+	date := time.Date(2025, 3, 28, 0, 0, 0, 0, time.UTC)
+	return []record{
+		{ID: 1, Name: "Display", Price: &price, Date: &date},
+		{ID: 2, Name: "yes"},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Format
+		wantErr  bool
+	}{
+		{"table", FormatTable, false},
+		{"JSON", FormatJSON, false},
+		{" ndjson ", FormatNDJSON, false},
+		{"csv", FormatCSV, false},
+		{"text", FormatText, false},
+		{"yaml", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			format, err := ParseFormat(tt.input)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, format)
+		})
+	}
+}
+
+func TestRender_Records(t *testing.T) {
+	tests := []struct {
+		format   Format
+		expected string
+	}{
+		{
+			format: FormatTable,
+			expected: "ID  NAME     PRICE  DATE\n" +
+				"1   Display  12.50  2025-03-28\n" +
+				"2   yes      -      -\n",
+		},
+		{
+			format: FormatNDJSON,
+			expected: `{"id":1,"name":"Display","price":12.5,"date":"2025-03-28T00:00:00Z"}` + "\n" +
+				`{"id":2,"name":"yes","price":null,"date":null}` + "\n",
+		},
+		{
+			format: FormatCSV,
+			expected: "id,name,price,date\n" +
+				"1,Display,12.5,2025-03-28T00:00:00Z\n" +
+				"2,yes,,\n",
+		},
+		{
+			format: FormatText,
+			expected: "- id: 1\n  name: Display\n  price: 12.5\n  date: 2025-03-28T00:00:00Z\n" +
+				"- id: 2\n  name: \"yes\"\n  price: null\n  date: null\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := Render(&buf, tt.format, fixtures())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestRender_SingleRecordTable(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := Render(&buf, FormatTable, fixtures()[0])
+
+	require.NoError(t, err)
+	assert.Equal(t, "Id:     1\nName:   Display\nPrice:  12.50\nDate:   2025-03-28\n", buf.String())
+}
+
+func TestRender_TableShowsTimeOfDay(t *testing.T) {
+	var buf bytes.Buffer
+	at := time.Date(2025, 3, 28, 14, 5, 0, 0, time.Local)
+
+	err := Render(&buf, FormatTable, struct {
+		At time.Time `json:"at"`
+	}{At: at})
+
+	require.NoError(t, err)
+	assert.Equal(t, "At:  2025-03-28 14:05\n", buf.String())
+}
+
+func TestRender_EmptyTable(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := Render(&buf, FormatTable, []record{})
+
+	require.NoError(t, err)
+	assert.Equal(t, "No results\n", buf.String())
+}
+
+func TestRender_Sections(t *testing.T) {
+	value := report{Summary: fixtures()[0], Rows: fixtures()[1:]}
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := Render(&buf, FormatCSV, value)
+
+		require.NoError(t, err)
+		assert.Equal(t, "group,id,name,price,date\n"+
+			"summary,1,Display,12.5,2025-03-28T00:00:00Z\n"+
+			"rows,2,yes,,\n", buf.String())
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := Render(&buf, FormatNDJSON, value)
+
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), `{"group":"rows","id":2,`)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := Render(&buf, FormatJSON, value)
+
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), `"summary": {`)
+	})
+}
+
+func TestRender_Unsupported(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := Render(&buf, FormatTable, map[string]int{"a": 1})
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,169 @@
+package pricing
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+)
+
+// PriceEntry is one line of a price file.
+type PriceEntry struct {
+	ExtensionCode string  `json:"extension_code"`
+	Type          string  `json:"type"`
+	LanguageCode  string  `json:"language_code"`
+	Price         float64 `json:"price"`
+	Currency      string  `json:"currency,omitempty"`
+}
+
+// csvColumns are the required columns of a CSV price file. A currency
+// column may be added.
+var csvColumns = []string{"extension_code", "type", "language_code", "price"}
+
+// FileProvider quotes from a local price file: a JSON array of PriceEntry,
+// or a CSV file with a header naming the csvColumns, chosen by the file
+// extension. Products are matched case-insensitively. The file is read on
+// the first quote and again whenever it is modified, so it can be updated
+// while the application runs.
+type FileProvider struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	prices  map[Query]PriceEntry
+}
+
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{path: path}
+}
+
+func (p *FileProvider) Name() string { return "file" }
+
+func (p *FileProvider) Quote(ctx context.Context, query Query) (*Quote, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	prices, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+
+	entry, ok := prices[query.key()]
+	if !ok {
+		return nil, customErr.NewPricingError("quote", p.Name(), fmt.Sprintf("no price for %s in %s", query, p.path), customErr.ErrNoQuote)
+	}
+	return &Quote{
+		Price:    entry.Price,
+		Currency: entry.Currency,
+		Source:   p.Name(),
+		QuotedAt: time.Now(),
+	}, nil
+}
+
+// load returns the prices of the file, read again if it changed since the
+// last call.
+func (p *FileProvider) load() (map[Query]PriceEntry, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return nil, customErr.NewPricingError("load", p.Name(), p.path, err)
+	}
+	if p.prices != nil && info.ModTime().Equal(p.modTime) && info.Size() == p.size {
+		return p.prices, nil
+	}
+
+	f, err := os.Open(p.path)
+	if err != nil {
+		return nil, customErr.NewPricingError("load", p.Name(), p.path, err)
+	}
+	defer f.Close()
+
+	var entries []PriceEntry
+	switch ext := strings.ToLower(filepath.Ext(p.path)); ext {
+	case ".json":
+		err = json.NewDecoder(f).Decode(&entries)
+	case ".csv":
+		entries, err = readCSV(f)
+	default:
+		err = fmt.Errorf("unsupported price file extension '%s': expected .csv or .json", ext)
+	}
+	if err != nil {
+		return nil, customErr.NewPricingError("load", p.Name(), p.path, err)
+	}
+
+	prices := make(map[Query]PriceEntry, len(entries))
+	for i, entry := range entries {
+		if entry.ExtensionCode == "" || entry.Type == "" || entry.LanguageCode == "" {
+			return nil, customErr.NewPricingError("load", p.Name(), p.path, fmt.Errorf("entry %d: extension_code, type and language_code are required", i+1))
+		}
+		if entry.Price < 0 {
+			return nil, customErr.NewPricingError("load", p.Name(), p.path, fmt.Errorf("entry %d: negative price %v", i+1, entry.Price))
+		}
+		prices[Query{ExtensionCode: entry.ExtensionCode, ItemType: entry.Type, LanguageCode: entry.LanguageCode}.key()] = entry
+	}
+
+	p.prices, p.modTime, p.size = prices, info.ModTime(), info.Size()
+	return prices, nil
+}
+
+func readCSV(r io.Reader) ([]PriceEntry, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range csvColumns {
+		if _, ok := index[name]; !ok {
+			return nil, fmt.Errorf("missing column '%s'", name)
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := index[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var entries []PriceEntry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		price, err := strconv.ParseFloat(field(record, "price"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid price '%s'", line, field(record, "price"))
+		}
+		entries = append(entries, PriceEntry{
+			ExtensionCode: field(record, "extension_code"),
+			Type:          field(record, "type"),
+			LanguageCode:  field(record, "language_code"),
+			Price:         price,
+			Currency:      field(record, "currency"),
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+)
+
+const defaultRequestTimeout = 10 * time.Second
+
+// HTTPOption configures an HTTPProvider.
+type HTTPOption func(*HTTPProvider)
+
+// WithHTTPClient sets the client used to query the price API.
+func WithHTTPClient(client *http.Client) HTTPOption {
+	return func(p *HTTPProvider) {
+		if client != nil {
+			p.client = client
+		}
+	}
+}
+
+// WithToken sends token as a bearer token with every request.
+func WithToken(token string) HTTPOption {
+	return func(p *HTTPProvider) {
+		p.token = token
+	}
+}
+
+// WithName sets the provider name, "http" by default, recorded as the
+// source of its quotes.
+func WithName(name string) HTTPOption {
+	return func(p *HTTPProvider) {
+		if name != "" {
+			p.name = name
+		}
+	}
+}
+
+// quoteResponse is the body of a successful quote request. QuotedAt is
+// optional and defaults to the time of the request.
+type quoteResponse struct {
+	Price    *float64   `json:"price"`
+	Currency string     `json:"currency"`
+	QuotedAt *time.Time `json:"quoted_at"`
+}
+
+// HTTPProvider quotes from a price API, such as a gateway to a marketplace
+// or a local stand-in. A quote is requested with
+//
+//	GET {baseURL}/quote?extension=DRI&type=Display&language=fr
+//
+// answered by {"price": 189.95, "currency": "EUR", "quoted_at": "..."}, or
+// by 404 when the API has no price for the product.
+type HTTPProvider struct {
+	name    string
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+func NewHTTPProvider(baseURL string, opts ...HTTPOption) *HTTPProvider {
+	p := &HTTPProvider{
+		name:    "http",
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: defaultRequestTimeout},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func (p *HTTPProvider) Name() string { return p.name }
+
+func (p *HTTPProvider) Quote(ctx context.Context, query Query) (*Quote, error) {
+	params := url.Values{}
+	params.Set("extension", query.ExtensionCode)
+	params.Set("type", query.ItemType)
+	params.Set("language", query.LanguageCode)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/quote?"+params.Encode(), nil)
+	if err != nil {
+		return nil, customErr.NewPricingError("quote", p.name, query.String(), err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "pkmc")
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, customErr.NewPricingError("quote", p.name, query.String(), err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, customErr.NewPricingError("quote", p.name, fmt.Sprintf("no quote for %s", query), customErr.ErrNoQuote)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return nil, customErr.NewPricingError("quote", p.name, query.String(), fmt.Errorf("HTTP %d", resp.StatusCode))
+	}
+
+	var body quoteResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return nil, customErr.NewPricingError("quote", p.name, query.String(), fmt.Errorf("decode response: %w", err))
+	}
+	if body.Price == nil {
+		return nil, customErr.NewPricingError("quote", p.name, fmt.Sprintf("no quote for %s", query), customErr.ErrNoQuote)
+	}
+	if *body.Price < 0 {
+		return nil, customErr.NewPricingError("quote", p.name, query.String(), fmt.Errorf("negative price %v", *body.Price))
+	}
+
+	quote := &Quote{
+		Price:    *body.Price,
+		Currency: body.Currency,
+		Source:   p.name,
+		QuotedAt: time.Now(),
+	}
+	if body.QuotedAt != nil {
+		quote.QuotedAt = *body.QuotedAt
+	}
+	return quote, nil
+}
@@ -0,0 +1,130 @@
+// Package pricing quotes the market value of collection items from price
+// providers, such as a local price file or a price API.
+//
+// A PriceProvider quotes one product, identified by its extension code,
+// item type and language code. Providers are registered in a Registry,
+// which asks them in turn until one has a quote.
+package pricing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+)
+
+// Query identifies the product to quote.
+type Query struct {
+	ExtensionCode string
+	ItemType      string
+	LanguageCode  string
+}
+
+func (q Query) String() string {
+	return fmt.Sprintf("%s %s (%s)", q.ExtensionCode, q.ItemType, q.LanguageCode)
+}
+
+// key is the case-insensitive form of a query used to match price lists.
+func (q Query) key() Query {
+	return Query{
+		ExtensionCode: strings.ToLower(strings.TrimSpace(q.ExtensionCode)),
+		ItemType:      strings.ToLower(strings.TrimSpace(q.ItemType)),
+		LanguageCode:  strings.ToLower(strings.TrimSpace(q.LanguageCode)),
+	}
+}
+
+// Quote is the market value of a product. Currency is the ISO 4217 code
+// given by the provider, empty when it does not say.
+type Quote struct {
+	Price    float64
+	Currency string
+	Source   string
+	QuotedAt time.Time
+}
+
+// PriceProvider quotes products. Quote returns an error wrapping
+// errors.ErrNoQuote when the provider has no price for the product.
+type PriceProvider interface {
+	Name() string
+	Quote(ctx context.Context, query Query) (*Quote, error)
+}
+
+// Registry holds the configured price providers, in registration order.
+type Registry struct {
+	mu        sync.RWMutex
+	providers []PriceProvider
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds a provider. Names must be unique.
+func (r *Registry) Register(provider PriceProvider) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, p := range r.providers {
+		if p.Name() == provider.Name() {
+			return fmt.Errorf("price provider '%s' is already registered", provider.Name())
+		}
+	}
+	r.providers = append(r.providers, provider)
+	return nil
+}
+
+// Get returns the provider registered under name.
+func (r *Registry) Get(name string) (PriceProvider, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, p := range r.providers {
+		if p.Name() == name {
+			return p, nil
+		}
+	}
+	return nil, customErr.NewPricingError("get", name, "not registered", customErr.ErrUnknownProvider)
+}
+
+// Names lists the registered providers.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.providers))
+	for _, p := range r.providers {
+		names = append(names, p.Name())
+	}
+	return names
+}
+
+// Quote asks each provider in turn and returns the first quote. When none
+// has one it returns the first error other than ErrNoQuote, or
+// ErrNoQuote.
+func (r *Registry) Quote(ctx context.Context, query Query) (*Quote, error) {
+	r.mu.RLock()
+	providers := append([]PriceProvider(nil), r.providers...)
+	r.mu.RUnlock()
+
+	var firstErr error
+	for _, p := range providers {
+		quote, err := p.Quote(ctx, query)
+		if err == nil {
+			return quote, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if firstErr == nil && !errors.Is(err, customErr.ErrNoQuote) {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return nil, customErr.NewPricingError("quote", "registry", fmt.Sprintf("no quote for %s", query), customErr.ErrNoQuote)
+}
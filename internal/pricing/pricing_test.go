@@ -0,0 +1,235 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var display = Query{ExtensionCode: "DRI", ItemType: "Display", LanguageCode: "fr"}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestFileProvider_Quote(t *testing.T) {
+	tests := []struct {
+		name          string
+		file          string
+		content       string
+		query         Query
+		expectedPrice float64
+		expectedCurr  string
+		expectedError error
+		errorContains string
+	}{
+		{
+			name:          "csv",
+			file:          "prices.csv",
+			content:       "extension_code,type,language_code,price,currency\nDRI,Display,fr,189.95,EUR\nSVI,ETB,en,54.5,EUR\n",
+			query:         display,
+			expectedPrice: 189.95,
+			expectedCurr:  "EUR",
+		},
+		{
+			name:          "csv without currency, columns in any order and case",
+			file:          "prices.CSV",
+			content:       "price,language_code,type,extension_code\n54.5,EN,etb,svi\n",
+			query:         Query{ExtensionCode: "SVI", ItemType: "ETB", LanguageCode: "en"},
+			expectedPrice: 54.5,
+		},
+		{
+			name:          "json",
+			file:          "prices.json",
+			content:       `[{"extension_code":"DRI","type":"Display","language_code":"fr","price":189.95,"currency":"EUR"}]`,
+			query:         display,
+			expectedPrice: 189.95,
+			expectedCurr:  "EUR",
+		},
+		{
+			name:          "no quote",
+			file:          "prices.json",
+			content:       `[{"extension_code":"DRI","type":"Display","language_code":"en","price":120}]`,
+			query:         display,
+			expectedError: customErr.ErrNoQuote,
+		},
+		{
+			name:          "missing column",
+			file:          "prices.csv",
+			content:       "extension_code,type,price\nDRI,Display,189.95\n",
+			query:         display,
+			errorContains: "missing column 'language_code'",
+		},
+		{
+			name:          "invalid price",
+			file:          "prices.csv",
+			content:       "extension_code,type,language_code,price\nDRI,Display,fr,cheap\n",
+			query:         display,
+			errorContains: "line 2: invalid price 'cheap'",
+		},
+		{
+			name:          "unsupported format",
+			file:          "prices.txt",
+			content:       "DRI Display fr 189.95",
+			query:         display,
+			errorContains: "unsupported price file extension '.txt'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			provider := NewFileProvider(writeFile(t, tt.file, tt.content))
+
+			// Execute
+			quote, err := provider.Quote(context.Background(), tt.query)
+
+			// Assert
+			if tt.expectedError != nil || tt.errorContains != "" {
+				require.Error(t, err)
+				if tt.expectedError != nil {
+					assert.ErrorIs(t, err, tt.expectedError)
+				}
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedPrice, quote.Price)
+			assert.Equal(t, tt.expectedCurr, quote.Currency)
+			assert.Equal(t, "file", quote.Source)
+		})
+	}
+}
+
+func TestFileProvider_ReloadsModifiedFile(t *testing.T) {
+	// Setup
+	path := writeFile(t, "prices.csv", "extension_code,type,language_code,price\nDRI,Display,fr,180\n")
+	provider := NewFileProvider(path)
+	ctx := context.Background()
+
+	quote, err := provider.Quote(ctx, display)
+	require.NoError(t, err)
+	require.Equal(t, 180.0, quote.Price)
+
+	// Execute
+	require.NoError(t, os.WriteFile(path, []byte("extension_code,type,language_code,price\nDRI,Display,fr,210.5\n"), 0o644))
+	later := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(path, later, later))
+	quote, err = provider.Quote(ctx, display)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 210.5, quote.Price)
+}
+
+func TestHTTPProvider_Quote(t *testing.T) {
+	var gotQuery, gotAuth string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery, gotAuth = r.URL.RawQuery, r.Header.Get("Authorization")
+		switch r.URL.Query().Get("extension") {
+		case "DRI":
+			w.Write([]byte(`{"price":189.95,"currency":"EUR","quoted_at":"2026-10-01T12:00:00Z"}`))
+		case "SVI":
+			http.NotFound(w, r)
+		default:
+			http.Error(w, "rate limited", http.StatusTooManyRequests)
+		}
+	}))
+	defer api.Close()
+
+	provider := NewHTTPProvider(api.URL+"/", WithName("cardmarket"), WithToken("k3y"))
+	ctx := context.Background()
+
+	t.Run("quote", func(t *testing.T) {
+		quote, err := provider.Quote(ctx, display)
+
+		require.NoError(t, err)
+		assert.Equal(t, "extension=DRI&language=fr&type=Display", gotQuery)
+		assert.Equal(t, "Bearer k3y", gotAuth)
+		assert.Equal(t, 189.95, quote.Price)
+		assert.Equal(t, "EUR", quote.Currency)
+		assert.Equal(t, "cardmarket", quote.Source)
+		assert.Equal(t, time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC), quote.QuotedAt.UTC())
+	})
+
+	t.Run("not found is no quote", func(t *testing.T) {
+		_, err := provider.Quote(ctx, Query{ExtensionCode: "SVI", ItemType: "ETB", LanguageCode: "en"})
+
+		assert.ErrorIs(t, err, customErr.ErrNoQuote)
+	})
+
+	t.Run("other statuses fail", func(t *testing.T) {
+		_, err := provider.Quote(ctx, Query{ExtensionCode: "PAR", ItemType: "ETB", LanguageCode: "en"})
+
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, customErr.ErrNoQuote)
+		assert.Contains(t, err.Error(), "HTTP 429")
+	})
+}
+
+// stubProvider quotes a fixed price for one extension and fails or has no
+// quote for the others.
+type stubProvider struct {
+	name      string
+	extension string
+	price     float64
+	err       error
+}
+
+func (p stubProvider) Name() string { return p.name }
+
+func (p stubProvider) Quote(ctx context.Context, query Query) (*Quote, error) {
+	if query.ExtensionCode != p.extension {
+		if p.err != nil {
+			return nil, p.err
+		}
+		return nil, customErr.NewPricingError("quote", p.name, "", customErr.ErrNoQuote)
+	}
+	return &Quote{Price: p.price, Source: p.name}, nil
+}
+
+func TestRegistry(t *testing.T) {
+	// Setup
+	down := errors.New("connection refused")
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(stubProvider{name: "file", extension: "DRI", price: 180}))
+	require.NoError(t, registry.Register(stubProvider{name: "http", extension: "SVI", price: 50, err: down}))
+	ctx := context.Background()
+
+	// Assert
+	assert.Error(t, registry.Register(stubProvider{name: "file"}), "names are unique")
+	assert.Equal(t, []string{"file", "http"}, registry.Names())
+
+	provider, err := registry.Get("http")
+	require.NoError(t, err)
+	assert.Equal(t, "http", provider.Name())
+	_, err = registry.Get("tcgplayer")
+	assert.ErrorIs(t, err, customErr.ErrUnknownProvider)
+
+	quote, err := registry.Quote(ctx, Query{ExtensionCode: "DRI"})
+	require.NoError(t, err)
+	assert.Equal(t, "file", quote.Source, "the first provider with a quote wins")
+
+	quote, err = registry.Quote(ctx, Query{ExtensionCode: "SVI"})
+	require.NoError(t, err)
+	assert.Equal(t, "http", quote.Source, "later providers are asked when earlier ones have no quote")
+
+	_, err = registry.Quote(ctx, Query{ExtensionCode: "PAR"})
+	assert.ErrorIs(t, err, down, "failures are reported over missing quotes")
+
+	_, err = NewRegistry().Quote(ctx, display)
+	assert.ErrorIs(t, err, customErr.ErrNoQuote)
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"gorm.io/gorm"
+)
+
+type alertRuleRepository struct {
+	db    *gorm.DB
+	audit *auditor
+}
+
+func NewAlertRuleRepository(db *gorm.DB) AlertRuleRepository {
+	return newAlertRuleRepository(db, &auditor{db: db})
+}
+
+func newAlertRuleRepository(db *gorm.DB, audit *auditor) *alertRuleRepository {
+	return &alertRuleRepository{db: db, audit: audit}
+}
+
+// alertAuditFields is the image of an alert rule recorded in the audit
+// trail. Its state is left out.
+func alertAuditFields(rule *models.AlertRule) models.AuditFields {
+	return models.AuditFields{
+		"name":           rule.Name,
+		"item_id":        rule.ItemID,
+		"extension_code": rule.ExtensionCode,
+		"item_type":      rule.ItemType,
+		"language_code":  rule.LanguageCode,
+		"condition":      rule.Condition,
+		"threshold":      rule.Threshold,
+		"window":         models.FormatAlertWindow(rule.Window),
+	}
+}
+
+func (r *alertRuleRepository) Create(ctx context.Context, rule *models.AlertRule) error {
+	if err := r.db.WithContext(ctx).Omit("Item").Create(rule).Error; err != nil {
+		return newRepositoryError("create", "alert_rule", rule.Name, err)
+	}
+	return r.audit.record(ctx, AuditEntityAlert, rule.ID, models.AuditCreate, nil, alertAuditFields(rule))
+}
+
+func (r *alertRuleRepository) FindByID(ctx context.Context, id uint) (*models.AlertRule, error) {
+	var rule models.AlertRule
+
+	err := r.db.WithContext(ctx).First(&rule, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, newRepositoryError("find", "alert_rule", strconv.Itoa(int(id)), customErr.ErrEntityNotFound)
+		}
+		return nil, newRepositoryError("find", "alert_rule", strconv.Itoa(int(id)), err)
+	}
+	return &rule, nil
+}
+
+func (r *alertRuleRepository) FindAll(ctx context.Context) ([]models.AlertRule, error) {
+	var rules []models.AlertRule
+
+	if err := r.db.WithContext(ctx).Order("id").Find(&rules).Error; err != nil {
+		return nil, newRepositoryError("list", "alert_rule", "all", err)
+	}
+	return rules, nil
+}
+
+func (r *alertRuleRepository) Delete(ctx context.Context, id uint) error {
+	before, err := r.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	result := r.db.WithContext(ctx).Delete(&models.AlertRule{}, id)
+	if result.Error != nil {
+		return newRepositoryError("delete", "alert_rule", strconv.Itoa(int(id)), result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return newRepositoryError("delete", "alert_rule", strconv.Itoa(int(id)), customErr.ErrEntityNotFound)
+	}
+	return r.audit.record(ctx, AuditEntityAlert, id, models.AuditDelete, alertAuditFields(before), nil)
+}
+
+// Reassign moves deleted rules too, as Delete only marks them.
+func (r *alertRuleRepository) Reassign(ctx context.Context, id, from, to uint) error {
+	key := strconv.Itoa(int(id))
+
+	var rule models.AlertRule
+	err := r.db.WithContext(ctx).Unscoped().Where("id = ? AND item_id = ?", id, from).First(&rule).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return newRepositoryError("reassign", "alert_rule", key, customErr.ErrEntityNotFound)
+		}
+		return newRepositoryError("reassign", "alert_rule", key, err)
+	}
+	before := alertAuditFields(&rule)
+
+	if err := r.db.WithContext(ctx).Unscoped().Model(&models.AlertRule{}).Where("id = ?", id).Update("item_id", to).Error; err != nil {
+		return newRepositoryError("reassign", "alert_rule", key, err)
+	}
+	rule.ItemID = &to
+	return r.audit.record(ctx, AuditEntityAlert, id, models.AuditUpdate, before, alertAuditFields(&rule))
+}
+
+// DeleteByItems removes deleted rules for good too. Those were audited
+// when they were deleted, so only the others are.
+func (r *alertRuleRepository) DeleteByItems(ctx context.Context, itemIDs []uint) error {
+	var rules []models.AlertRule
+
+	if err := r.db.WithContext(ctx).Where("item_id IN ?", itemIDs).Order("id").Find(&rules).Error; err != nil {
+		return newRepositoryError("delete", "alert_rule", "", err)
+	}
+
+	if err := r.db.WithContext(ctx).Unscoped().Where("item_id IN ?", itemIDs).Delete(&models.AlertRule{}).Error; err != nil {
+		return newRepositoryError("delete", "alert_rule", "", err)
+	}
+	for i := range rules {
+		if err := r.audit.record(ctx, AuditEntityAlert, rules[i].ID, models.AuditDelete, alertAuditFields(&rules[i]), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *alertRuleRepository) UpdateState(ctx context.Context, rule *models.AlertRule) error {
+	key := strconv.Itoa(int(rule.ID))
+
+	result := r.db.WithContext(ctx).
+		Model(&models.AlertRule{}).
+		Where("id = ?", rule.ID).
+		Select("triggered", "triggered_at").
+		Updates(&models.AlertRule{Triggered: rule.Triggered, TriggeredAt: rule.TriggeredAt})
+	if result.Error != nil {
+		return newRepositoryError("update", "alert_rule", key, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return newRepositoryError("update", "alert_rule", key, customErr.ErrEntityNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"gorm.io/gorm"
+)
+
+type apiTokenRepository struct {
+	db    *gorm.DB
+	audit *auditor
+}
+
+func NewAPITokenRepository(db *gorm.DB) APITokenRepository {
+	return newAPITokenRepository(db, &auditor{db: db})
+}
+
+func newAPITokenRepository(db *gorm.DB, audit *auditor) *apiTokenRepository {
+	return &apiTokenRepository{db: db, audit: audit}
+}
+
+// apiTokenAuditFields is the image of a token recorded in the audit trail.
+// The secret hash is left out.
+func apiTokenAuditFields(token *models.APIToken) models.AuditFields {
+	return models.AuditFields{
+		"name":       token.Name,
+		"prefix":     token.Prefix,
+		"role":       token.Role,
+		"revoked_at": token.RevokedAt,
+	}
+}
+
+func (r *apiTokenRepository) Create(ctx context.Context, token *models.APIToken) error {
+	if err := r.db.WithContext(ctx).Create(token).Error; err != nil {
+		return newRepositoryError("create", "api_token", token.Name, err)
+	}
+	return r.audit.record(ctx, AuditEntityAPIToken, token.ID, models.AuditCreate, nil, apiTokenAuditFields(token))
+}
+
+func (r *apiTokenRepository) FindByID(ctx context.Context, id uint) (*models.APIToken, error) {
+	var token models.APIToken
+
+	err := r.db.WithContext(ctx).First(&token, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, newRepositoryError("find", "api_token", strconv.Itoa(int(id)), customErr.ErrEntityNotFound)
+		}
+		return nil, newRepositoryError("find", "api_token", strconv.Itoa(int(id)), err)
+	}
+	return &token, nil
+}
+
+// FindByHash returns the token with the given secret hash. The hash is
+// never used as the error key.
+func (r *apiTokenRepository) FindByHash(ctx context.Context, hash string) (*models.APIToken, error) {
+	var token models.APIToken
+
+	err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, newRepositoryError("find", "api_token", "hash", customErr.ErrEntityNotFound)
+		}
+		return nil, newRepositoryError("find", "api_token", "hash", err)
+	}
+	return &token, nil
+}
+
+func (r *apiTokenRepository) FindAll(ctx context.Context) ([]models.APIToken, error) {
+	var tokens []models.APIToken
+
+	if err := r.db.WithContext(ctx).Order("id").Find(&tokens).Error; err != nil {
+		return nil, newRepositoryError("list", "api_token", "all", err)
+	}
+	return tokens, nil
+}
+
+// Revoke marks an active token as revoked. Revoking an unknown or already
+// revoked token returns ErrEntityNotFound.
+func (r *apiTokenRepository) Revoke(ctx context.Context, id uint, at time.Time) error {
+	var token models.APIToken
+	err := r.db.WithContext(ctx).Where("id = ? AND revoked_at IS NULL", id).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return newRepositoryError("revoke", "api_token", strconv.Itoa(int(id)), customErr.ErrEntityNotFound)
+		}
+		return newRepositoryError("revoke", "api_token", strconv.Itoa(int(id)), err)
+	}
+	before := apiTokenAuditFields(&token)
+
+	result := r.db.WithContext(ctx).
+		Model(&models.APIToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at)
+
+	if result.Error != nil {
+		return newRepositoryError("revoke", "api_token", strconv.Itoa(int(id)), result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return newRepositoryError("revoke", "api_token", strconv.Itoa(int(id)), customErr.ErrEntityNotFound)
+	}
+
+	token.RevokedAt = &at
+	return r.audit.record(ctx, AuditEntityAPIToken, id, models.AuditUpdate, before, apiTokenAuditFields(&token))
+}
+
+// TouchLastUsed is bookkeeping for authentication and is not audited.
+func (r *apiTokenRepository) TouchLastUsed(ctx context.Context, id uint, at time.Time) error {
+	err := r.db.WithContext(ctx).
+		Model(&models.APIToken{}).
+		Where("id = ?", id).
+		UpdateColumn("last_used_at", at).Error
+
+	if err != nil {
+		return newRepositoryError("touch", "api_token", strconv.Itoa(int(id)), err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPITokenRepository_Lifecycle(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewAPITokenRepository(db)
+	ctx := context.Background()
+
+	token := &models.APIToken{Name: "laptop", Prefix: "pkmc_abcdefg", TokenHash: "hash-1", Role: models.RoleEditor}
+	require.NoError(t, repo.Create(ctx, token))
+	require.NotZero(t, token.ID)
+
+	// Execute & Assert: lookup by hash
+	found, err := repo.FindByHash(ctx, "hash-1")
+	require.NoError(t, err)
+	assert.Equal(t, token.ID, found.ID)
+	assert.True(t, found.Active())
+
+	_, err = repo.FindByHash(ctx, "hash-2")
+	assert.ErrorIs(t, err, customErr.ErrEntityNotFound)
+
+	// Duplicate hashes violate the unique index
+	err = repo.Create(ctx, &models.APIToken{Name: "copy", Prefix: "pkmc_abcdefg", TokenHash: "hash-1", Role: models.RoleAdmin})
+	assert.Error(t, err)
+
+	// Touch
+	now := time.Now()
+	require.NoError(t, repo.TouchLastUsed(ctx, token.ID, now))
+	found, err = repo.FindByID(ctx, token.ID)
+	require.NoError(t, err)
+	require.NotNil(t, found.LastUsedAt)
+	assert.WithinDuration(t, now, *found.LastUsedAt, time.Second)
+
+	// Revoke once, then the token is no longer active
+	require.NoError(t, repo.Revoke(ctx, token.ID, now))
+	err = repo.Revoke(ctx, token.ID, now)
+	assert.ErrorIs(t, err, customErr.ErrEntityNotFound)
+
+	found, err = repo.FindByID(ctx, token.ID)
+	require.NoError(t, err)
+	assert.False(t, found.Active())
+
+	tokens, err := repo.FindAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, tokens, 1)
+
+	// Creation and revocation are audited without the secret hash
+	entries, err := NewAuditRepository(db).List(ctx, AuditFilter{Entity: AuditEntityAPIToken})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, models.AuditCreate, entries[0].Action)
+	assert.Equal(t, "editor", entries[0].After["role"])
+	assert.NotContains(t, entries[0].After, "token_hash")
+	assert.Nil(t, entries[1].Before["revoked_at"])
+	assert.NotNil(t, entries[1].After["revoked_at"])
+}
+
+func TestAPITokenRepository_Revoke_NotFound(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	// Execute
+	err := NewAPITokenRepository(db).Revoke(context.Background(), 42, time.Now())
+
+	// Assert
+	assert.ErrorIs(t, err, customErr.ErrEntityNotFound)
+}
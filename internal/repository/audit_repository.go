@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"gorm.io/gorm"
+)
+
+// Audited entities. Outbox events, webhook deliveries, job states and
+// idempotency keys are bookkeeping of the writes above and are not audited.
+const (
+	AuditEntityItem        = "item"
+	AuditEntityAPIToken    = "api_token"
+	AuditEntityWebhook     = "webhook"
+	AuditEntityAlert       = "alert_rule"
+	AuditEntityPriceRecord = "price_record"
+)
+
+// SystemActor is recorded for writes whose context carries no actor.
+const SystemActor = "system"
+
+type actorKey struct{}
+
+// WithActor returns a context whose writes are attributed to actor in the
+// audit trail.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor set by WithActor, or SystemActor.
+func ActorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return SystemActor
+}
+
+type revertsKey struct{}
+
+// Reverting returns a context whose writes are recorded as reverting the
+// given operation.
+func Reverting(ctx context.Context, operationID string) context.Context {
+	return context.WithValue(ctx, revertsKey{}, operationID)
+}
+
+func revertsFrom(ctx context.Context) string {
+	operationID, _ := ctx.Value(revertsKey{}).(string)
+	return operationID
+}
+
+func newOperationID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		// crypto/rand does not fail on supported platforms; fall back to
+		// the clock rather than losing the entry.
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(buf)
+}
+
+// auditor writes audit entries next to the changes they describe. Inside
+// UnitOfWork.Do it shares the transaction, so an entry is committed or
+// rolled back with its change, and every entry gets the operation ID of
+// the Do call. Outside Do each entry gets its own operation ID.
+type auditor struct {
+	db          *gorm.DB
+	operationID string
+}
+
+func (a *auditor) record(ctx context.Context, entity string, id uint, action models.AuditAction, before, after models.AuditFields) error {
+	operationID := a.operationID
+	if operationID == "" {
+		operationID = newOperationID()
+	}
+
+	entry := &models.AuditEntry{
+		OperationID: operationID,
+		Entity:      entity,
+		EntityID:    id,
+		Action:      action,
+		Actor:       ActorFrom(ctx),
+		Reverts:     revertsFrom(ctx),
+		Before:      before,
+		After:       after,
+	}
+	if err := a.db.WithContext(ctx).Create(entry).Error; err != nil {
+		return newRepositoryError("record", "audit_entry", entity+" "+strconv.Itoa(int(id)), err)
+	}
+	return nil
+}
+
+// recordCreations records the creation of the entities ids, whose images
+// are afters, in batches of batchSize entries. The entries share one
+// operation ID, outside Do too.
+func (a *auditor) recordCreations(ctx context.Context, entity string, ids []uint, afters []models.AuditFields, batchSize int) error {
+	operationID := a.operationID
+	if operationID == "" {
+		operationID = newOperationID()
+	}
+
+	entries := make([]models.AuditEntry, len(ids))
+	for i, id := range ids {
+		entries[i] = models.AuditEntry{
+			OperationID: operationID,
+			Entity:      entity,
+			EntityID:    id,
+			Action:      models.AuditCreate,
+			Actor:       ActorFrom(ctx),
+			Reverts:     revertsFrom(ctx),
+			After:       afters[i],
+		}
+	}
+	if err := a.db.WithContext(ctx).CreateInBatches(entries, batchSize).Error; err != nil {
+		return newRepositoryError("record", "audit_entry", entity, err)
+	}
+	return nil
+}
+
+// sameFields reports whether two images hold the same values once stored.
+func sameFields(a, b models.AuditFields) bool {
+	av, errA := a.Value()
+	bv, errB := b.Value()
+	return errA == nil && errB == nil && av == bv
+}
+
+type AuditFilter struct {
+	Entity   string
+	EntityID uint
+	// EntityIDs selects the entries of any of several entities, along
+	// with EntityID when it is set.
+	EntityIDs   []uint
+	OperationID string
+	// Reverts selects the entries written by the undo of an operation.
+	Reverts string
+	Since   *time.Time
+	Until   *time.Time
+	Limit   int
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+// List returns the matching entries, oldest first. Since is inclusive and
+// Until exclusive.
+func (r *auditRepository) List(ctx context.Context, filter AuditFilter) ([]models.AuditEntry, error) {
+	var entries []models.AuditEntry
+
+	query := r.db.WithContext(ctx)
+	if filter.Entity != "" {
+		query = query.Where("entity = ?", filter.Entity)
+	}
+	switch ids := filter.EntityIDs; {
+	case len(ids) > 0 && filter.EntityID != 0:
+		query = query.Where("entity_id IN ?", append([]uint{filter.EntityID}, ids...))
+	case len(ids) > 0:
+		query = query.Where("entity_id IN ?", ids)
+	case filter.EntityID != 0:
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.OperationID != "" {
+		query = query.Where("operation_id = ?", filter.OperationID)
+	}
+	if filter.Reverts != "" {
+		query = query.Where("reverts = ?", filter.Reverts)
+	}
+	if filter.Since != nil {
+		query = query.Where("created_at >= ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		query = query.Where("created_at < ?", *filter.Until)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	if err := query.Order("id").Find(&entries).Error; err != nil {
+		return nil, newRepositoryError("list", "audit_entry", "filter", err)
+	}
+	return entries, nil
+}
+
+// UndoableOperations returns the IDs of the most recent operations that
+// only changed items, along with the records moved by a merge, were not
+// undone and are not undos themselves, newest first.
+func (r *auditRepository) UndoableOperations(ctx context.Context, limit int) ([]string, error) {
+	var ids []string
+
+	reverted := r.db.Model(&models.AuditEntry{}).Select("reverts").Where("reverts <> ''")
+	err := r.db.WithContext(ctx).
+		Model(&models.AuditEntry{}).
+		Select("operation_id").
+		Where("reverts = '' AND operation_id NOT IN (?)", reverted).
+		Group("operation_id").
+		Having("SUM(CASE WHEN entity = ? OR (entity IN ? AND action = ?) THEN 0 ELSE 1 END) = 0",
+			AuditEntityItem, []string{AuditEntityPriceRecord, AuditEntityAlert}, models.AuditUpdate).
+		Order("MAX(id) DESC").
+		Limit(limit).
+		Pluck("operation_id", &ids).Error
+
+	if err != nil {
+		return nil, newRepositoryError("list", "audit_entry", "undoable", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudit_RecordsItemWrites(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	uow := NewUnitOfWork(db)
+	ctx := WithActor(context.Background(), "cli:ash")
+
+	// Execute: create and reprice in one operation, then delete in another
+	var itemID uint
+	err := uow.Do(ctx, func(uow UnitOfWork) error {
+		item := &models.Item{ExtensionID: 1, TypeID: 1, LanguageID: 1, Price: testutil.FloatPtr(10)}
+		if err := uow.Items().Create(ctx, item); err != nil {
+			return err
+		}
+		itemID = item.ID
+
+		item.Price = testutil.FloatPtr(12.5)
+		if err := uow.Items().Update(ctx, item); err != nil {
+			return err
+		}
+		// Saving unchanged values is not a change
+		return uow.Items().Update(ctx, item)
+	})
+	require.NoError(t, err)
+
+	err = uow.Do(context.Background(), func(uow UnitOfWork) error {
+		return uow.Items().Delete(context.Background(), itemID)
+	})
+	require.NoError(t, err)
+
+	// Assert
+	entries, err := NewAuditRepository(db).List(context.Background(), AuditFilter{Entity: AuditEntityItem, EntityID: itemID})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	created, updated, deleted := entries[0], entries[1], entries[2]
+	assert.Equal(t, models.AuditCreate, created.Action)
+	assert.Nil(t, created.Before)
+	assert.Equal(t, 10.0, created.After["price"])
+	assert.Equal(t, "cli:ash", created.Actor)
+
+	assert.Equal(t, models.AuditUpdate, updated.Action)
+	assert.Equal(t, 10.0, updated.Before["price"])
+	assert.Equal(t, 12.5, updated.After["price"])
+	assert.Equal(t, created.OperationID, updated.OperationID, "writes of one Do share an operation")
+
+	assert.Equal(t, models.AuditDelete, deleted.Action)
+	assert.Equal(t, 12.5, deleted.Before["price"])
+	assert.Nil(t, deleted.After)
+	assert.Equal(t, SystemActor, deleted.Actor)
+	assert.NotEqual(t, created.OperationID, deleted.OperationID)
+}
+
+func TestAudit_RecordsPriceRecordWrites(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	uow := NewUnitOfWork(db)
+	ctx := context.Background()
+	quotedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	// Execute
+	var record *models.PriceRecord
+	err := uow.Do(ctx, func(uow UnitOfWork) error {
+		item := &models.Item{ExtensionID: 1, TypeID: 1, LanguageID: 1}
+		if err := uow.Items().Create(ctx, item); err != nil {
+			return err
+		}
+		record = &models.PriceRecord{ItemID: item.ID, Price: 195.5, Currency: "EUR", Source: "file", QuotedAt: quotedAt}
+		return uow.PriceRecords().Create(ctx, record)
+	})
+	require.NoError(t, err)
+
+	// Assert
+	entries, err := NewAuditRepository(db).List(ctx, AuditFilter{Entity: AuditEntityPriceRecord})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, record.ID, entries[0].EntityID)
+	assert.Equal(t, models.AuditCreate, entries[0].Action)
+	assert.Equal(t, 195.5, entries[0].After["price"])
+	assert.Equal(t, "file", entries[0].After["source"])
+
+	var bookkeeping int64
+	require.NoError(t, db.Model(&models.AuditEntry{}).Where("entity NOT IN ?", []string{AuditEntityItem, AuditEntityPriceRecord}).Count(&bookkeeping).Error)
+	assert.Zero(t, bookkeeping)
+}
+
+func TestAudit_RolledBackWithTheChange(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	uow := NewUnitOfWork(db)
+	ctx := context.Background()
+
+	// Execute
+	err := uow.Do(ctx, func(uow UnitOfWork) error {
+		if err := uow.Items().Create(ctx, &models.Item{ExtensionID: 1, TypeID: 1, LanguageID: 1}); err != nil {
+			return err
+		}
+		return errors.New("simulated error")
+	})
+
+	// Assert
+	require.Error(t, err)
+	var count int64
+	require.NoError(t, db.Model(&models.AuditEntry{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestAuditRepository_List(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := []models.AuditEntry{
+		{OperationID: "op1", Entity: AuditEntityItem, EntityID: 1, Action: models.AuditCreate, Actor: "a", CreatedAt: base},
+		{OperationID: "op2", Entity: AuditEntityItem, EntityID: 2, Action: models.AuditCreate, Actor: "a", CreatedAt: base.Add(time.Hour)},
+		{OperationID: "op2", Entity: AuditEntityAPIToken, EntityID: 1, Action: models.AuditCreate, Actor: "a", CreatedAt: base.Add(time.Hour)},
+		{OperationID: "op3", Entity: AuditEntityItem, EntityID: 1, Action: models.AuditDelete, Actor: "b", CreatedAt: base.Add(2 * time.Hour)},
+	}
+	require.NoError(t, db.Create(&entries).Error)
+
+	since, until := base.Add(time.Hour), base.Add(2*time.Hour)
+
+	tests := []struct {
+		name     string
+		filter   AuditFilter
+		expected []string
+	}{
+		{"all", AuditFilter{}, []string{"op1", "op2", "op2", "op3"}},
+		{"one item", AuditFilter{Entity: AuditEntityItem, EntityID: 1}, []string{"op1", "op3"}},
+		{"entity", AuditFilter{Entity: AuditEntityAPIToken}, []string{"op2"}},
+		{"operation", AuditFilter{OperationID: "op2"}, []string{"op2", "op2"}},
+		{"since is inclusive", AuditFilter{Since: &since}, []string{"op2", "op2", "op3"}},
+		{"until is exclusive", AuditFilter{Until: &until}, []string{"op1", "op2", "op2"}},
+		{"limit", AuditFilter{Limit: 1}, []string{"op1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Execute
+			found, err := NewAuditRepository(db).List(context.Background(), tt.filter)
+
+			// Assert
+			require.NoError(t, err)
+			var ops []string
+			for _, entry := range found {
+				ops = append(ops, entry.OperationID)
+			}
+			assert.Equal(t, tt.expected, ops)
+		})
+	}
+}
+
+func TestAuditRepository_UndoableOperations(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	entries := []models.AuditEntry{
+		{OperationID: "op1", Entity: AuditEntityItem, EntityID: 1, Action: models.AuditCreate, Actor: "a"},
+		{OperationID: "op2", Entity: AuditEntityAPIToken, EntityID: 1, Action: models.AuditCreate, Actor: "a"},
+		{OperationID: "op3", Entity: AuditEntityItem, EntityID: 2, Action: models.AuditCreate, Actor: "a"},
+		{OperationID: "op4", Entity: AuditEntityItem, EntityID: 1, Action: models.AuditUpdate, Actor: "a"},
+		{OperationID: "op5", Entity: AuditEntityItem, EntityID: 1, Action: models.AuditUpdate, Actor: "a", Reverts: "op4"},
+		{OperationID: "op6", Entity: AuditEntityItem, EntityID: 2, Action: models.AuditDelete, Actor: "a"},
+		{OperationID: "op7", Entity: AuditEntityItem, EntityID: 1, Action: models.AuditUpdate, Actor: "a"},
+		{OperationID: "op7", Entity: AuditEntityPriceRecord, EntityID: 1, Action: models.AuditUpdate, Actor: "a"},
+		{OperationID: "op7", Entity: AuditEntityItem, EntityID: 3, Action: models.AuditDelete, Actor: "a"},
+		{OperationID: "op8", Entity: AuditEntityPriceRecord, EntityID: 2, Action: models.AuditDelete, Actor: "a"},
+	}
+	require.NoError(t, db.Create(&entries).Error)
+
+	// Execute
+	ops, err := NewAuditRepository(db).UndoableOperations(context.Background(), 10)
+
+	// Assert: merges moving price records are kept; token operations,
+	// price record deletions, undos and undone operations are skipped
+	require.NoError(t, err)
+	assert.Equal(t, []string{"op7", "op6", "op3", "op1"}, ops)
+
+	ops, err = NewAuditRepository(db).UndoableOperations(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"op7"}, ops)
+}
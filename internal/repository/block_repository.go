@@ -23,9 +23,18 @@ func (r *blockRepository) FindByCode(ctx context.Context, code string) (*models.
 	err := r.db.WithContext(ctx).Where("code = ?", code).First(&block).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, customErr.NewRepositoryError("find", "block", code, customErr.ErrEntityNotFound)
+			return nil, newRepositoryError("find", "block", code, customErr.ErrEntityNotFound)
 		}
-		return nil, customErr.NewRepositoryError("find", "block", code, err)
+		return nil, newRepositoryError("find", "block", code, err)
 	}
 	return &block, nil
 }
+
+func (r *blockRepository) FindAll(ctx context.Context) ([]models.Block, error) {
+	var blocks []models.Block
+
+	if err := r.db.WithContext(ctx).Order("release_date, code").Find(&blocks).Error; err != nil {
+		return nil, newRepositoryError("list", "block", "all", err)
+	}
+	return blocks, nil
+}
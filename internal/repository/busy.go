@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// busyRetry is the retry policy of transactions that find the database
+// locked.
+type busyRetry struct {
+	budget time.Duration
+	base   time.Duration
+	max    time.Duration
+}
+
+var defaultBusyRetry = busyRetry{
+	budget: 5 * time.Second,
+	base:   20 * time.Millisecond,
+	max:    time.Second,
+}
+
+// delay returns the wait before the retry that follows the given number of
+// attempts: base doubled after each attempt up to max, of which a random
+// half is kept so that competing writers do not retry in step.
+func (r busyRetry) delay(attempts int) time.Duration {
+	delay := r.base
+	for i := 1; i < attempts && delay < r.max; i++ {
+		delay *= 2
+	}
+	delay = min(delay, r.max)
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// allows reports whether a retry after delay still fits in the budget of a
+// transaction started at start and in the deadline of ctx.
+func (r busyRetry) allows(ctx context.Context, start time.Time, delay time.Duration) bool {
+	at := time.Now().Add(delay)
+	if at.Sub(start) > r.budget {
+		return false
+	}
+	if deadline, ok := ctx.Deadline(); ok && !at.Before(deadline) {
+		return false
+	}
+	return true
+}
+
+// isBusy reports whether err comes from SQLite finding the database, or a
+// table of it, locked by another connection.
+func isBusy(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+	}
+	// Some paths only keep the message of the driver error.
+	msg := err.Error()
+	return strings.Contains(msg, "database is locked") || strings.Contains(msg, "database table is locked")
+}
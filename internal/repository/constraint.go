@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/mattn/go-sqlite3"
+)
+
+// newRepositoryError is customErr.NewRepositoryError with the constraint
+// failures of SQLite translated by translateError, so that every
+// repository reports them the same way.
+func newRepositoryError(op, entity, key string, err error) *customErr.RepositoryError {
+	return customErr.NewRepositoryError(op, entity, key, translateError(err))
+}
+
+// constraintMessages maps the messages SQLite gives constraint failures to
+// their kind, for the errors that only keep the message of the driver
+// error.
+var constraintMessages = []struct {
+	prefix string
+	kind   customErr.ConstraintKind
+}{
+	{"UNIQUE constraint failed", customErr.ConstraintUnique},
+	{"PRIMARY KEY constraint failed", customErr.ConstraintUnique},
+	{"FOREIGN KEY constraint failed", customErr.ConstraintForeignKey},
+	{"NOT NULL constraint failed", customErr.ConstraintNotNull},
+	{"CHECK constraint failed", customErr.ConstraintCheck},
+}
+
+// translateError turns a constraint failure reported by SQLite into a
+// ConstraintError naming the table and columns, or the check constraint,
+// from the SQLite message. Other errors are returned unchanged.
+func translateError(err error) error {
+	var constraintErr *customErr.ConstraintError
+	if err == nil || errors.As(err, &constraintErr) {
+		return err
+	}
+
+	kind, msg, ok := constraintKind(err)
+	if !ok {
+		return err
+	}
+	constraintErr = &customErr.ConstraintError{Kind: kind, Cause: err}
+
+	// SQLite says "UNIQUE constraint failed: items.a, items.b" or "CHECK
+	// constraint failed: name", and nothing more for foreign keys.
+	_, detail, ok := strings.Cut(msg, "constraint failed: ")
+	if !ok {
+		return constraintErr
+	}
+	switch constraintErr.Kind {
+	case customErr.ConstraintUnique, customErr.ConstraintNotNull:
+		for _, column := range strings.Split(detail, ", ") {
+			table, name, _ := strings.Cut(column, ".")
+			constraintErr.Table = table
+			constraintErr.Columns = append(constraintErr.Columns, name)
+		}
+	default:
+		constraintErr.Constraint = detail
+	}
+	return constraintErr
+}
+
+// constraintKind reports whether err is a constraint failure, of which
+// kind, and the message describing it. It goes by the SQLite code of the
+// driver error, and by the message when only that was kept.
+func constraintKind(err error) (customErr.ConstraintKind, string, bool) {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		if sqliteErr.Code != sqlite3.ErrConstraint {
+			return "", "", false
+		}
+		var kind customErr.ConstraintKind
+		switch sqliteErr.ExtendedCode {
+		case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+			kind = customErr.ConstraintUnique
+		case sqlite3.ErrConstraintForeignKey:
+			kind = customErr.ConstraintForeignKey
+		case sqlite3.ErrConstraintNotNull:
+			kind = customErr.ConstraintNotNull
+		case sqlite3.ErrConstraintCheck:
+			kind = customErr.ConstraintCheck
+		}
+		return kind, sqliteErr.Error(), true
+	}
+
+	msg := err.Error()
+	for _, c := range constraintMessages {
+		if strings.Contains(msg, c.prefix) {
+			return c.kind, msg, true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestTranslateError(t *testing.T) {
+	tests := []struct {
+		name     string
+		write    func(db *gorm.DB) error
+		kind     error
+		expected customErr.ConstraintError
+		message  string
+	}{
+		{
+			name: "unique",
+			write: func(db *gorm.DB) error {
+				return db.Create(&models.Language{Code: "fr", Name: "Français bis"}).Error
+			},
+			kind:     customErr.ErrUniqueViolation,
+			expected: customErr.ConstraintError{Kind: customErr.ConstraintUnique, Table: "languages", Columns: []string{"code"}},
+			message:  "unique constraint violated on languages.code",
+		},
+		{
+			name: "primary key",
+			write: func(db *gorm.DB) error {
+				return db.Exec("INSERT INTO item_types (id, name) SELECT id, 'Other' FROM item_types LIMIT 1").Error
+			},
+			kind:     customErr.ErrUniqueViolation,
+			expected: customErr.ConstraintError{Kind: customErr.ConstraintUnique, Table: "item_types", Columns: []string{"id"}},
+			message:  "unique constraint violated on item_types.id",
+		},
+		{
+			name: "foreign key",
+			write: func(db *gorm.DB) error {
+				return db.Create(testutil.CreateTestItem(9999, 9999, 9999)).Error
+			},
+			kind:     customErr.ErrForeignKeyViolation,
+			expected: customErr.ConstraintError{Kind: customErr.ConstraintForeignKey},
+			message:  "foreign key constraint violated",
+		},
+		{
+			name: "not null",
+			write: func(db *gorm.DB) error {
+				return db.Exec("INSERT INTO item_types (name) VALUES (NULL)").Error
+			},
+			kind:     customErr.ErrNotNullViolation,
+			expected: customErr.ConstraintError{Kind: customErr.ConstraintNotNull, Table: "item_types", Columns: []string{"name"}},
+			message:  "not null constraint violated on item_types.name",
+		},
+		{
+			name: "check",
+			write: func(db *gorm.DB) error {
+				if err := db.Exec("CREATE TABLE checked (n INTEGER CONSTRAINT positive CHECK (n > 0))").Error; err != nil {
+					return err
+				}
+				return db.Exec("INSERT INTO checked (n) VALUES (-1)").Error
+			},
+			kind:     customErr.ErrCheckViolation,
+			expected: customErr.ConstraintError{Kind: customErr.ConstraintCheck, Constraint: "positive"},
+			message:  "check constraint violated: positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			db := testutil.SetupTestDB(t)
+			defer testutil.CleanupTestDB(t, db)
+
+			// Execute
+			err := translateError(tt.write(db))
+
+			// Assert
+			var constraintErr *customErr.ConstraintError
+			require.ErrorAs(t, err, &constraintErr)
+			assert.ErrorIs(t, err, customErr.ErrConstraintViolation)
+			assert.ErrorIs(t, err, tt.kind)
+			assert.Equal(t, tt.expected.Kind, constraintErr.Kind)
+			assert.Equal(t, tt.expected.Table, constraintErr.Table)
+			assert.Equal(t, tt.expected.Columns, constraintErr.Columns)
+			assert.Equal(t, tt.expected.Constraint, constraintErr.Constraint)
+			assert.Equal(t, tt.message, err.Error())
+			assert.NotNil(t, constraintErr.Cause, "the driver error is kept")
+		})
+	}
+}
+
+func TestTranslateError_MessageOnly(t *testing.T) {
+	// Execute: an error that only kept the message of the driver error
+	err := translateError(errors.New("insert failed: UNIQUE constraint failed: languages.code"))
+
+	// Assert
+	var constraintErr *customErr.ConstraintError
+	require.ErrorAs(t, err, &constraintErr)
+	assert.Equal(t, customErr.ConstraintUnique, constraintErr.Kind)
+	assert.Equal(t, "languages", constraintErr.Table)
+	assert.Equal(t, []string{"code"}, constraintErr.Columns)
+}
+
+func TestTranslateError_OtherErrors(t *testing.T) {
+	plain := errors.New("boom")
+	translated := &customErr.ConstraintError{Kind: customErr.ConstraintUnique}
+
+	assert.Same(t, plain, translateError(plain))
+	assert.Nil(t, translateError(nil))
+	assert.Same(t, translated, translateError(customErr.NewRepositoryError("create", "item", "new", translated)).(*customErr.RepositoryError).Cause)
+}
+
+func TestRepositories_ReportConstraintErrors(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	uow := NewUnitOfWork(db)
+	ctx := context.Background()
+	token := func() *models.APIToken {
+		return &models.APIToken{Name: "ci", TokenHash: "same-hash", Prefix: "pkmc_abc", Role: models.RoleReadOnly}
+	}
+
+	// Execute
+	err := uow.Do(ctx, func(uow UnitOfWork) error {
+		if err := uow.APITokens().Create(ctx, token()); err != nil {
+			return err
+		}
+		return uow.APITokens().Create(ctx, token())
+	})
+
+	// Assert
+	var repoErr *customErr.RepositoryError
+	require.ErrorAs(t, err, &repoErr)
+	assert.Equal(t, "api_token", repoErr.Entity)
+	var constraintErr *customErr.ConstraintError
+	require.ErrorAs(t, err, &constraintErr)
+	assert.Equal(t, customErr.ConstraintUnique, constraintErr.Kind)
+	assert.Equal(t, []string{"token_hash"}, constraintErr.Columns)
+}
@@ -22,9 +22,33 @@ func (r *extensionRepository) FindByCode(ctx context.Context, code string) (*mod
 	err := r.db.WithContext(ctx).Preload("Block").Where("code = ?", code).First(&ext).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, customErr.NewRepositoryError("find", "extension", code, customErr.ErrEntityNotFound)
+			return nil, newRepositoryError("find", "extension", code, customErr.ErrEntityNotFound)
 		}
-		return nil, customErr.NewRepositoryError("find", "extension", code, err)
+		return nil, newRepositoryError("find", "extension", code, err)
 	}
 	return &ext, nil
 }
+
+func (r *extensionRepository) FindAll(ctx context.Context) ([]models.Extension, error) {
+	var exts []models.Extension
+
+	if err := r.db.WithContext(ctx).Preload("Block").Order("release_date, code").Find(&exts).Error; err != nil {
+		return nil, newRepositoryError("list", "extension", "all", err)
+	}
+	return exts, nil
+}
+
+func (r *extensionRepository) FindByBlockCode(ctx context.Context, blockCode string) ([]models.Extension, error) {
+	var exts []models.Extension
+
+	err := r.db.WithContext(ctx).
+		Preload("Block").
+		Joins("JOIN blocks ON blocks.id = extensions.block_id").
+		Where("blocks.code = ?", blockCode).
+		Order("extensions.release_date, extensions.code").
+		Find(&exts).Error
+	if err != nil {
+		return nil, newRepositoryError("list", "extension", blockCode, err)
+	}
+	return exts, nil
+}
@@ -145,3 +145,49 @@ func TestExtensionRepository_CustomExtension(t *testing.T) {
 	assert.NotNil(t, found)
 	testutil.AssertExtensionEqual(t, customExt, found)
 }
+
+func TestExtensionRepository_FindAll(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewExtensionRepository(db)
+	ctx := context.Background()
+
+	// Execute
+	exts, err := repo.FindAll(ctx)
+
+	// Assert - every seeded extension, oldest first, with its block
+	assert.NoError(t, err)
+	var count int64
+	db.Model(&models.Extension{}).Count(&count)
+	assert.Len(t, exts, int(count))
+	assert.Equal(t, "SSH", exts[0].Code)
+	for _, ext := range exts {
+		assert.NotEmpty(t, ext.Block.Code, "Block should be preloaded")
+	}
+}
+
+func TestExtensionRepository_FindByBlockCode(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewExtensionRepository(db)
+	ctx := context.Background()
+
+	// Execute
+	exts, err := repo.FindByBlockCode(ctx, "ME")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, exts)
+	for _, ext := range exts {
+		assert.Equal(t, "ME", ext.Block.Code)
+	}
+
+	// Execute - unknown block yields an empty list
+	exts, err = repo.FindByBlockCode(ctx, "XX")
+	assert.NoError(t, err)
+	assert.Empty(t, exts)
+}
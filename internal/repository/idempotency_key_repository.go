@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"gorm.io/gorm"
+)
+
+type idempotencyKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyKeyRepository(db *gorm.DB) IdempotencyKeyRepository {
+	return &idempotencyKeyRepository{db: db}
+}
+
+func (r *idempotencyKeyRepository) Create(ctx context.Context, key *models.IdempotencyKey) error {
+	key.Actor = ActorFrom(ctx)
+	if err := r.db.WithContext(ctx).Create(key).Error; err != nil {
+		return newRepositoryError("create", "idempotency_key", key.Key, err)
+	}
+	return nil
+}
+
+func (r *idempotencyKeyRepository) Find(ctx context.Context, operation, key string, now time.Time) (*models.IdempotencyKey, error) {
+	var keys []models.IdempotencyKey
+
+	err := r.db.WithContext(ctx).
+		Where("actor = ? AND operation = ? AND key = ? AND expires_at > ?", ActorFrom(ctx), operation, key, now).
+		Limit(1).
+		Find(&keys).Error
+	if err != nil {
+		return nil, newRepositoryError("find", "idempotency_key", key, err)
+	}
+	if len(keys) == 0 {
+		return nil, newRepositoryError("find", "idempotency_key", key, customErr.ErrEntityNotFound)
+	}
+	return &keys[0], nil
+}
+
+func (r *idempotencyKeyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&models.IdempotencyKey{})
+	if result.Error != nil {
+		return 0, newRepositoryError("delete_expired", "idempotency_key", "", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyKeyRepository_Lifecycle(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewIdempotencyKeyRepository(db)
+	phone := WithActor(context.Background(), "token:phone")
+	laptop := WithActor(context.Background(), "token:laptop")
+	now := time.Now()
+
+	key := &models.IdempotencyKey{Operation: "create_item", Key: "k1", RequestHash: "hash", ItemIDs: models.IDList{4, 7}, ExpiresAt: now.Add(time.Hour)}
+	require.NoError(t, repo.Create(phone, key))
+	assert.Equal(t, "token:phone", key.Actor)
+
+	// Execute & Assert: found by its actor and operation only
+	found, err := repo.Find(phone, "create_item", "k1", now)
+	require.NoError(t, err)
+	assert.Equal(t, models.IDList{4, 7}, found.ItemIDs)
+	assert.Equal(t, "hash", found.RequestHash)
+
+	_, err = repo.Find(laptop, "create_item", "k1", now)
+	assert.ErrorIs(t, err, customErr.ErrEntityNotFound)
+	_, err = repo.Find(phone, "create_items", "k1", now)
+	assert.ErrorIs(t, err, customErr.ErrEntityNotFound)
+
+	// The unique index refuses the key twice, even expired
+	err = repo.Create(phone, &models.IdempotencyKey{Operation: "create_item", Key: "k1", RequestHash: "other", ExpiresAt: now.Add(time.Hour)})
+	assert.ErrorIs(t, err, customErr.ErrUniqueViolation)
+	require.NoError(t, repo.Create(laptop, &models.IdempotencyKey{Operation: "create_item", Key: "k1", RequestHash: "hash", ExpiresAt: now.Add(time.Hour)}))
+
+	later := now.Add(2 * time.Hour)
+	_, err = repo.Find(phone, "create_item", "k1", later)
+	assert.ErrorIs(t, err, customErr.ErrEntityNotFound, "expired keys are not found")
+
+	// Delete expired
+	deleted, err := repo.DeleteExpired(phone, later)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+	require.NoError(t, repo.Create(phone, &models.IdempotencyKey{Operation: "create_item", Key: "k1", RequestHash: "other", ExpiresAt: later.Add(time.Hour)}))
+}
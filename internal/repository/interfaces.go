@@ -2,36 +2,233 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/R4yL-dev/pkmc/internal/models"
 )
 
+type ItemFilter struct {
+	ExtensionCode string
+	BlockCode     string
+	LanguageCode  string
+	TypeName      string
+	MinPrice      *float64
+	MaxPrice      *float64
+	Limit         int
+	Offset        int
+}
+
+type ItemGroupBy string
+
+const (
+	GroupByNone      ItemGroupBy = ""
+	GroupByBlock     ItemGroupBy = "block"
+	GroupByExtension ItemGroupBy = "extension"
+	GroupByLanguage  ItemGroupBy = "language"
+	GroupByType      ItemGroupBy = "type"
+)
+
+type ItemAggregate struct {
+	Key         string
+	Label       string
+	Count       int64
+	PricedCount int64
+	TotalPrice  float64
+}
+
 type ItemRepository interface {
 	Create(ctx context.Context, item *models.Item) error
+	// CreateInBatches inserts items, setting their IDs, with one statement
+	// per batchSize items, and audits their creation as one operation.
+	CreateInBatches(ctx context.Context, items []models.Item, batchSize int) error
 	FindByID(ctx context.Context, id uint) (*models.Item, error)
+	List(ctx context.Context, filter ItemFilter) ([]models.Item, error)
+	Update(ctx context.Context, item *models.Item) error
+	Delete(ctx context.Context, id uint) error
+	// FindStored loads the columns of an item, deleted or not, without its
+	// associations.
+	FindStored(ctx context.Context, id uint) (*models.Item, error)
+	// Restore brings back a deleted item.
+	Restore(ctx context.Context, id uint) error
+	// Merge saves keep and folds the item dropID into it, moving its price
+	// history and alert rules, then deleting it.
+	Merge(ctx context.Context, keep *models.Item, dropID uint) error
+	// MergedInto returns the IDs of the items merged into the item id,
+	// directly or not.
+	MergedInto(ctx context.Context, id uint) ([]uint, error)
+	// Purge removes for good the items deleted before before and returns
+	// how many it removed.
+	Purge(ctx context.Context, before time.Time) (int64, error)
+	Aggregate(ctx context.Context, groupBy ItemGroupBy) ([]ItemAggregate, error)
 }
 
 type ExtensionRepository interface {
 	FindByCode(ctx context.Context, code string) (*models.Extension, error)
+	FindAll(ctx context.Context) ([]models.Extension, error)
+	FindByBlockCode(ctx context.Context, blockCode string) ([]models.Extension, error)
 }
 
 type LanguageRepository interface {
 	FindByCode(ctx context.Context, code string) (*models.Language, error)
+	FindAll(ctx context.Context) ([]models.Language, error)
 }
 
 type ItemTypeRepository interface {
 	FindByName(ctx context.Context, name string) (*models.ItemType, error)
+	FindAll(ctx context.Context) ([]models.ItemType, error)
 }
 
 type BlockRepository interface {
 	FindByCode(ctx context.Context, code string) (*models.Block, error)
+	FindAll(ctx context.Context) ([]models.Block, error)
+}
+
+type APITokenRepository interface {
+	Create(ctx context.Context, token *models.APIToken) error
+	FindByID(ctx context.Context, id uint) (*models.APIToken, error)
+	FindByHash(ctx context.Context, hash string) (*models.APIToken, error)
+	FindAll(ctx context.Context) ([]models.APIToken, error)
+	Revoke(ctx context.Context, id uint, at time.Time) error
+	TouchLastUsed(ctx context.Context, id uint, at time.Time) error
+}
+
+type AuditRepository interface {
+	List(ctx context.Context, filter AuditFilter) ([]models.AuditEntry, error)
+	UndoableOperations(ctx context.Context, limit int) ([]string, error)
+}
+
+type OutboxRepository interface {
+	// Append stores an event with the operation and actor of ctx.
+	Append(ctx context.Context, event *models.OutboxEvent) error
+	// Due returns the pending events whose next attempt is not after now,
+	// oldest first.
+	Due(ctx context.Context, now time.Time, limit int) ([]models.OutboxEvent, error)
+	// UpdateDelivery saves the delivery state of an event: its attempts,
+	// last error, next attempt and dispatched or failed time.
+	UpdateDelivery(ctx context.Context, event *models.OutboxEvent) error
+}
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *models.Webhook) error
+	FindByID(ctx context.Context, id uint) (*models.Webhook, error)
+	FindAll(ctx context.Context) ([]models.Webhook, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+type WebhookDeliveryFilter struct {
+	WebhookID uint
+	Status    models.DeliveryStatus
+	Limit     int
+}
+
+type WebhookDeliveryRepository interface {
+	// Enqueue stores a pending delivery. A delivery of the same event to
+	// the same webhook already stored is kept and delivery.ID is left
+	// zero, so an event handed over twice is only sent once.
+	Enqueue(ctx context.Context, delivery *models.WebhookDelivery) error
+	// Due returns the pending deliveries of existing webhooks whose next
+	// attempt is not after now, oldest first, with their webhook.
+	Due(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error)
+	// UpdateAttempt saves the outcome of an attempt: status, attempts,
+	// response status, last error, next attempt and delivery time.
+	UpdateAttempt(ctx context.Context, delivery *models.WebhookDelivery) error
+	// List returns deliveries, newest first.
+	List(ctx context.Context, filter WebhookDeliveryFilter) ([]models.WebhookDelivery, error)
+}
+
+// PriceScope selects the price records of one item, when ItemID is set, or
+// of every item of a product.
+type PriceScope struct {
+	ItemID        uint
+	ExtensionCode string
+	ItemType      string
+	LanguageCode  string
+}
+
+func (s PriceScope) String() string {
+	if s.ItemID != 0 {
+		return fmt.Sprintf("item %d", s.ItemID)
+	}
+	return fmt.Sprintf("%s %s (%s)", s.ExtensionCode, s.ItemType, s.LanguageCode)
+}
+
+type PriceRecordRepository interface {
+	Create(ctx context.Context, record *models.PriceRecord) error
+	// ListByItem returns the price history of an item, newest first.
+	ListByItem(ctx context.Context, itemID uint, limit int) ([]models.PriceRecord, error)
+	// Latest returns the newest record of scope, or ErrEntityNotFound.
+	Latest(ctx context.Context, scope PriceScope) (*models.PriceRecord, error)
+	// OldestSince returns the oldest record of scope quoted at or after
+	// since, or ErrEntityNotFound.
+	OldestSince(ctx context.Context, scope PriceScope, since time.Time) (*models.PriceRecord, error)
+	// Reassign moves the record id from the item from to the item to, or
+	// fails with ErrEntityNotFound when it is not a record of from.
+	Reassign(ctx context.Context, id, from, to uint) error
+	// DeleteByItems removes the price history of the items itemIDs.
+	DeleteByItems(ctx context.Context, itemIDs []uint) error
+}
+
+type AlertRuleRepository interface {
+	Create(ctx context.Context, rule *models.AlertRule) error
+	FindByID(ctx context.Context, id uint) (*models.AlertRule, error)
+	FindAll(ctx context.Context) ([]models.AlertRule, error)
+	Delete(ctx context.Context, id uint) error
+	// UpdateState saves whether a rule is triggered and since when.
+	UpdateState(ctx context.Context, rule *models.AlertRule) error
+	// Reassign moves the rule id from the item from to the item to, or
+	// fails with ErrEntityNotFound when it is not a rule of from.
+	Reassign(ctx context.Context, id, from, to uint) error
+	// DeleteByItems removes the rules of the items itemIDs for good.
+	DeleteByItems(ctx context.Context, itemIDs []uint) error
+}
+
+type JobStateRepository interface {
+	// Ensure returns the state of a job, creating it when it is missing.
+	Ensure(ctx context.Context, name string) (*models.JobState, error)
+	FindByName(ctx context.Context, name string) (*models.JobState, error)
+	// FindAll returns the states of every job that ever ran or was
+	// scheduled, by name.
+	FindAll(ctx context.Context) ([]models.JobState, error)
+	// SetNextRun saves the schedule of a job and when it runs next.
+	SetNextRun(ctx context.Context, name, schedule string, next time.Time) error
+	// Claim marks a job running at now and reports whether it did, which
+	// it does not while a run started at or after staleBefore is in
+	// progress.
+	Claim(ctx context.Context, name string, now, staleBefore time.Time) (bool, error)
+	// Finish saves the outcome of a run and the next run, and clears the
+	// running mark.
+	Finish(ctx context.Context, state *models.JobState) error
+}
+
+type IdempotencyKeyRepository interface {
+	// Create stores a key for the actor of ctx. A key already stored for
+	// the actor and operation fails with ErrUniqueViolation, even when it
+	// expired: DeleteExpired makes room.
+	Create(ctx context.Context, key *models.IdempotencyKey) error
+	// Find returns the key of the actor of ctx for operation, or
+	// ErrEntityNotFound when it is missing or expired at now.
+	Find(ctx context.Context, operation, key string, now time.Time) (*models.IdempotencyKey, error)
+	// DeleteExpired removes the keys expired at now and returns how many
+	// it removed.
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
 }
 
 type UnitOfWork interface {
 	Do(ctx context.Context, fn func(uow UnitOfWork) error) error
+	DoRead(ctx context.Context, fn func(uow UnitOfWork) error) error
 	Items() ItemRepository
 	Extensions() ExtensionRepository
 	Languages() LanguageRepository
 	ItemTypes() ItemTypeRepository
 	Blocks() BlockRepository
+	APITokens() APITokenRepository
+	Audit() AuditRepository
+	Outbox() OutboxRepository
+	Webhooks() WebhookRepository
+	WebhookDeliveries() WebhookDeliveryRepository
+	PriceRecords() PriceRecordRepository
+	AlertRules() AlertRuleRepository
+	JobStates() JobStateRepository
+	IdempotencyKeys() IdempotencyKeyRepository
 }
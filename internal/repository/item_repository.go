@@ -3,19 +3,109 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strconv"
+	"time"
 
 	customErr "github.com/R4yL-dev/pkmc/internal/errors"
 	"github.com/R4yL-dev/pkmc/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type itemRepository struct {
-	db *gorm.DB
+	db    *gorm.DB
+	audit *auditor
 }
 
 func NewItemRepository(db *gorm.DB) ItemRepository {
-	return &itemRepository{db: db}
+	return newItemRepository(db, &auditor{db: db})
+}
+
+func newItemRepository(db *gorm.DB, audit *auditor) *itemRepository {
+	return &itemRepository{db: db, audit: audit}
+}
+
+// ItemAuditFields is the image of an item recorded in the audit trail.
+func ItemAuditFields(item *models.Item) models.AuditFields {
+	return models.AuditFields{
+		"extension_id": item.ExtensionID,
+		"type_id":      item.TypeID,
+		"language_id":  item.LanguageID,
+		"price":        item.Price,
+		"quantity":     item.Quantity,
+		"purchased_at": item.PurchasedAt,
+		"sold_at":      item.SoldAt,
+		"sold_price":   item.SoldPrice,
+	}
+}
+
+// ApplyItemAuditFields sets the fields of item found in an image read back
+// from the audit trail.
+func ApplyItemAuditFields(item *models.Item, fields models.AuditFields) error {
+	for name, value := range fields {
+		var err error
+		switch name {
+		case "extension_id":
+			item.ExtensionID, err = auditUint(name, value)
+		case "type_id":
+			item.TypeID, err = auditUint(name, value)
+		case "language_id":
+			item.LanguageID, err = auditUint(name, value)
+		case "price":
+			item.Price, err = auditFloat(name, value)
+		case "quantity":
+			var quantity uint
+			quantity, err = auditUint(name, value)
+			item.Quantity = int(quantity)
+		case "purchased_at":
+			item.PurchasedAt, err = auditTime(name, value)
+		case "sold_at":
+			item.SoldAt, err = auditTime(name, value)
+		case "sold_price":
+			item.SoldPrice, err = auditFloat(name, value)
+		default:
+			err = fmt.Errorf("unknown item field '%s'", name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func auditUint(name string, value interface{}) (uint, error) {
+	f, ok := value.(float64)
+	if !ok || f < 0 || f != float64(uint(f)) {
+		return 0, fmt.Errorf("invalid value %v for %s", value, name)
+	}
+	return uint(f), nil
+}
+
+func auditFloat(name string, value interface{}) (*float64, error) {
+	if value == nil {
+		return nil, nil
+	}
+	f, ok := value.(float64)
+	if !ok {
+		return nil, fmt.Errorf("invalid value %v for %s", value, name)
+	}
+	return &f, nil
+}
+
+func auditTime(name string, value interface{}) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid value %v for %s", value, name)
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %v for %s", value, name)
+	}
+	return &t, nil
 }
 
 func (r *itemRepository) Create(ctx context.Context, item *models.Item) error {
@@ -25,9 +115,42 @@ func (r *itemRepository) Create(ctx context.Context, item *models.Item) error {
 		if item.ID != 0 {
 			key = strconv.Itoa(int(item.ID))
 		}
-		return customErr.NewRepositoryError("create", "item", key, err)
+		return newRepositoryError("create", "item", key, err)
 	}
-	return nil
+	return r.audit.record(ctx, AuditEntityItem, item.ID, models.AuditCreate, nil, ItemAuditFields(item))
+}
+
+func (r *itemRepository) CreateInBatches(ctx context.Context, items []models.Item, batchSize int) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	if err := r.db.WithContext(ctx).Omit(clause.Associations).CreateInBatches(items, batchSize).Error; err != nil {
+		return newRepositoryError("create", "item", "batch", err)
+	}
+
+	ids := make([]uint, len(items))
+	afters := make([]models.AuditFields, len(items))
+	for i := range items {
+		ids[i] = items[i].ID
+		afters[i] = ItemAuditFields(&items[i])
+	}
+	return r.audit.recordCreations(ctx, AuditEntityItem, ids, afters, batchSize)
+}
+
+// current loads the stored columns of an item, without its associations,
+// for the before-image of a change.
+func (r *itemRepository) current(ctx context.Context, op string, id uint) (*models.Item, error) {
+	var item models.Item
+
+	err := r.db.WithContext(ctx).First(&item, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, newRepositoryError(op, "item", strconv.Itoa(int(id)), customErr.ErrEntityNotFound)
+		}
+		return nil, newRepositoryError(op, "item", strconv.Itoa(int(id)), err)
+	}
+	return &item, nil
 }
 
 func (r *itemRepository) FindByID(ctx context.Context, id uint) (*models.Item, error) {
@@ -41,9 +164,273 @@ func (r *itemRepository) FindByID(ctx context.Context, id uint) (*models.Item, e
 
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, customErr.NewRepositoryError("find", "item", strconv.Itoa(int(id)), customErr.ErrEntityNotFound)
+			return nil, newRepositoryError("find", "item", strconv.Itoa(int(id)), customErr.ErrEntityNotFound)
 		}
-		return nil, customErr.NewRepositoryError("find", "item", strconv.Itoa(int(id)), err)
+		return nil, newRepositoryError("find", "item", strconv.Itoa(int(id)), err)
 	}
 	return &item, nil
 }
+
+func (r *itemRepository) List(ctx context.Context, filter ItemFilter) ([]models.Item, error) {
+	var items []models.Item
+
+	query := r.db.WithContext(ctx).
+		Preload("Extension.Block").
+		Preload("Type").
+		Preload("Language")
+
+	if filter.ExtensionCode != "" || filter.BlockCode != "" {
+		query = query.Joins("JOIN extensions ON extensions.id = items.extension_id")
+	}
+	if filter.ExtensionCode != "" {
+		query = query.Where("extensions.code = ?", filter.ExtensionCode)
+	}
+	if filter.BlockCode != "" {
+		query = query.Joins("JOIN blocks ON blocks.id = extensions.block_id").Where("blocks.code = ?", filter.BlockCode)
+	}
+	if filter.LanguageCode != "" {
+		query = query.Joins("JOIN languages ON languages.id = items.language_id").Where("languages.code = ?", filter.LanguageCode)
+	}
+	if filter.TypeName != "" {
+		query = query.Joins("JOIN item_types ON item_types.id = items.type_id").Where("item_types.name = ?", filter.TypeName)
+	}
+	if filter.MinPrice != nil {
+		query = query.Where("items.price >= ?", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		query = query.Where("items.price <= ?", *filter.MaxPrice)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	if err := query.Order("items.id").Find(&items).Error; err != nil {
+		return nil, newRepositoryError("list", "item", "filter", err)
+	}
+	return items, nil
+}
+
+func (r *itemRepository) Update(ctx context.Context, item *models.Item) error {
+	key := strconv.Itoa(int(item.ID))
+
+	before, err := r.current(ctx, "update", item.ID)
+	if err != nil {
+		return err
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(item).
+		Select("ExtensionID", "TypeID", "LanguageID", "Price", "Quantity", "PurchasedAt", "SoldAt", "SoldPrice").
+		Updates(item)
+	if result.Error != nil {
+		return newRepositoryError("update", "item", key, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return newRepositoryError("update", "item", key, customErr.ErrEntityNotFound)
+	}
+
+	beforeFields, afterFields := ItemAuditFields(before), ItemAuditFields(item)
+	if sameFields(beforeFields, afterFields) {
+		return nil
+	}
+	return r.audit.record(ctx, AuditEntityItem, item.ID, models.AuditUpdate, beforeFields, afterFields)
+}
+
+func (r *itemRepository) Delete(ctx context.Context, id uint) error {
+	key := strconv.Itoa(int(id))
+
+	before, err := r.current(ctx, "delete", id)
+	if err != nil {
+		return err
+	}
+
+	result := r.db.WithContext(ctx).Delete(&models.Item{}, id)
+	if result.Error != nil {
+		return newRepositoryError("delete", "item", key, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return newRepositoryError("delete", "item", key, customErr.ErrEntityNotFound)
+	}
+	return r.audit.record(ctx, AuditEntityItem, id, models.AuditDelete, ItemAuditFields(before), nil)
+}
+
+func (r *itemRepository) FindStored(ctx context.Context, id uint) (*models.Item, error) {
+	var item models.Item
+
+	err := r.db.WithContext(ctx).Unscoped().First(&item, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, newRepositoryError("find", "item", strconv.Itoa(int(id)), customErr.ErrEntityNotFound)
+		}
+		return nil, newRepositoryError("find", "item", strconv.Itoa(int(id)), err)
+	}
+	return &item, nil
+}
+
+// Restore clears the deletion mark of an item, and the item it was merged
+// into if any. Restoring an item that is not deleted returns
+// ErrEntityNotFound. It is audited as a creation.
+func (r *itemRepository) Restore(ctx context.Context, id uint) error {
+	key := strconv.Itoa(int(id))
+
+	result := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&models.Item{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{"deleted_at": nil, "merged_into_id": nil})
+	if result.Error != nil {
+		return newRepositoryError("restore", "item", key, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return newRepositoryError("restore", "item", key, customErr.ErrEntityNotFound)
+	}
+
+	item, err := r.current(ctx, "restore", id)
+	if err != nil {
+		return err
+	}
+	return r.audit.record(ctx, AuditEntityItem, id, models.AuditCreate, nil, ItemAuditFields(item))
+}
+
+// Merge saves keep and folds the item dropID into it: the price history and
+// alert rules of dropID move to keep, then dropID is deleted and marked as
+// merged into keep. The update of keep, each move and the deletion are
+// audited, so the history of both items stays and undoing the merge moves
+// the records back.
+func (r *itemRepository) Merge(ctx context.Context, keep *models.Item, dropID uint) error {
+	key := strconv.Itoa(int(dropID))
+
+	if err := r.Update(ctx, keep); err != nil {
+		return err
+	}
+
+	var recordIDs, ruleIDs []uint
+	if err := r.db.WithContext(ctx).Model(&models.PriceRecord{}).Where("item_id = ?", dropID).Order("id").Pluck("id", &recordIDs).Error; err != nil {
+		return newRepositoryError("merge", "price_record", key, err)
+	}
+	if err := r.db.WithContext(ctx).Unscoped().Model(&models.AlertRule{}).Where("item_id = ?", dropID).Order("id").Pluck("id", &ruleIDs).Error; err != nil {
+		return newRepositoryError("merge", "alert_rule", key, err)
+	}
+	records, rules := newPriceRecordRepository(r.db, r.audit), newAlertRuleRepository(r.db, r.audit)
+	for _, id := range recordIDs {
+		if err := records.Reassign(ctx, id, dropID, keep.ID); err != nil {
+			return err
+		}
+	}
+	for _, id := range ruleIDs {
+		if err := rules.Reassign(ctx, id, dropID, keep.ID); err != nil {
+			return err
+		}
+	}
+
+	result := r.db.WithContext(ctx).Model(&models.Item{}).Where("id = ?", dropID).Update("merged_into_id", keep.ID)
+	if result.Error != nil {
+		return newRepositoryError("merge", "item", key, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return newRepositoryError("merge", "item", key, customErr.ErrEntityNotFound)
+	}
+	return r.Delete(ctx, dropID)
+}
+
+// MergedInto returns the IDs of the items merged into the item id, directly
+// or through other merged items.
+func (r *itemRepository) MergedInto(ctx context.Context, id uint) ([]uint, error) {
+	var ids []uint
+
+	for pending := []uint{id}; len(pending) > 0; {
+		var merged []uint
+		err := r.db.WithContext(ctx).
+			Unscoped().
+			Model(&models.Item{}).
+			Where("merged_into_id IN ?", pending).
+			Order("id").
+			Pluck("id", &merged).Error
+		if err != nil {
+			return nil, newRepositoryError("find_merged", "item", strconv.Itoa(int(id)), err)
+		}
+		ids = append(ids, merged...)
+		pending = merged
+	}
+	return ids, nil
+}
+
+// Purge removes for good the items deleted before before, along with their
+// price history and alert rules. The deletion of the items was audited
+// already, so only that of their records is. Items merged into another are
+// kept, as the history of that item lists their changes.
+func (r *itemRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	var ids []uint
+
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&models.Item{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ? AND merged_into_id IS NULL", before).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return 0, newRepositoryError("purge", "item", "", err)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	if err := newPriceRecordRepository(r.db, r.audit).DeleteByItems(ctx, ids); err != nil {
+		return 0, err
+	}
+	if err := newAlertRuleRepository(r.db, r.audit).DeleteByItems(ctx, ids); err != nil {
+		return 0, err
+	}
+	result := r.db.WithContext(ctx).Unscoped().Where("id IN ?", ids).Delete(&models.Item{})
+	if result.Error != nil {
+		return 0, newRepositoryError("purge", "item", "", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *itemRepository) Aggregate(ctx context.Context, groupBy ItemGroupBy) ([]ItemAggregate, error) {
+	var aggregates []ItemAggregate
+
+	query := r.db.WithContext(ctx).Model(&models.Item{})
+
+	switch groupBy {
+	case GroupByNone:
+		query = query.Select("'' AS key, '' AS label, " + aggregateColumns)
+	case GroupByBlock:
+		query = query.
+			Select("blocks.code AS key, blocks.name AS label, " + aggregateColumns).
+			Joins("JOIN extensions ON extensions.id = items.extension_id").
+			Joins("JOIN blocks ON blocks.id = extensions.block_id").
+			Group("blocks.code, blocks.name")
+	case GroupByExtension:
+		query = query.
+			Select("extensions.code AS key, extensions.name AS label, " + aggregateColumns).
+			Joins("JOIN extensions ON extensions.id = items.extension_id").
+			Group("extensions.code, extensions.name")
+	case GroupByLanguage:
+		query = query.
+			Select("languages.code AS key, languages.name AS label, " + aggregateColumns).
+			Joins("JOIN languages ON languages.id = items.language_id").
+			Group("languages.code, languages.name")
+	case GroupByType:
+		query = query.
+			Select("item_types.name AS key, item_types.name AS label, " + aggregateColumns).
+			Joins("JOIN item_types ON item_types.id = items.type_id").
+			Group("item_types.name")
+	default:
+		return nil, newRepositoryError("aggregate", "item", string(groupBy), fmt.Errorf("unsupported grouping '%s'", groupBy))
+	}
+
+	if groupBy != GroupByNone {
+		query = query.Order("count DESC, key")
+	}
+
+	if err := query.Scan(&aggregates).Error; err != nil {
+		return nil, newRepositoryError("aggregate", "item", string(groupBy), err)
+	}
+	return aggregates, nil
+}
+
+const aggregateColumns = "COUNT(items.id) AS count, COUNT(items.price) AS priced_count, COALESCE(SUM(items.price), 0) AS total_price"
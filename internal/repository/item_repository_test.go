@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
 	"github.com/R4yL-dev/pkmc/internal/models"
 	"github.com/R4yL-dev/pkmc/internal/testutil"
 	"github.com/stretchr/testify/assert"
@@ -48,7 +49,7 @@ func TestItemRepository_Create(t *testing.T) {
 				Price:       testutil.FloatPtr(99.99),
 			},
 			expectedError: true,
-			errorContains: "FOREIGN KEY constraint failed",
+			errorContains: "foreign key constraint violated",
 		},
 		{
 			name: "error - invalid type id (FK constraint)",
@@ -59,7 +60,7 @@ func TestItemRepository_Create(t *testing.T) {
 				Price:       testutil.FloatPtr(99.99),
 			},
 			expectedError: true,
-			errorContains: "FOREIGN KEY constraint failed",
+			errorContains: "foreign key constraint violated",
 		},
 		{
 			name: "error - invalid language id (FK constraint)",
@@ -70,7 +71,7 @@ func TestItemRepository_Create(t *testing.T) {
 				Price:       testutil.FloatPtr(99.99),
 			},
 			expectedError: true,
-			errorContains: "FOREIGN KEY constraint failed",
+			errorContains: "foreign key constraint violated",
 		},
 		{
 			name: "error - missing extension id",
@@ -81,7 +82,7 @@ func TestItemRepository_Create(t *testing.T) {
 				Price:       testutil.FloatPtr(99.99),
 			},
 			expectedError: true,
-			errorContains: "FOREIGN KEY constraint failed",
+			errorContains: "foreign key constraint violated",
 		},
 		{
 			name: "error - missing type id",
@@ -92,7 +93,7 @@ func TestItemRepository_Create(t *testing.T) {
 				Price:       testutil.FloatPtr(99.99),
 			},
 			expectedError: true,
-			errorContains: "FOREIGN KEY constraint failed",
+			errorContains: "foreign key constraint violated",
 		},
 		{
 			name: "error - missing language id",
@@ -103,7 +104,7 @@ func TestItemRepository_Create(t *testing.T) {
 				Price:       testutil.FloatPtr(99.99),
 			},
 			expectedError: true,
-			errorContains: "FOREIGN KEY constraint failed",
+			errorContains: "foreign key constraint violated",
 		},
 	}
 
@@ -124,6 +125,7 @@ func TestItemRepository_Create(t *testing.T) {
 				assert.Error(t, err)
 				if tt.errorContains != "" {
 					assert.Contains(t, err.Error(), tt.errorContains)
+					assert.ErrorIs(t, err, customErr.ErrForeignKeyViolation)
 				}
 			} else {
 				assert.NoError(t, err)
@@ -164,6 +166,42 @@ func TestItemRepository_Create_WithTimeout(t *testing.T) {
 	assert.Contains(t, err.Error(), "context deadline exceeded")
 }
 
+func TestItemRepository_CreateInBatches(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewItemRepository(db)
+	ctx := context.Background()
+
+	items := make([]models.Item, 5)
+	for i := range items {
+		items[i] = *testutil.CreateTestItem(1, 1, 1)
+	}
+
+	// Execute
+	err := repo.CreateInBatches(ctx, items, 2)
+
+	// Assert
+	require.NoError(t, err)
+	for _, item := range items {
+		assert.NotZero(t, item.ID)
+		_, err := repo.FindByID(ctx, item.ID)
+		assert.NoError(t, err)
+	}
+
+	var entries []models.AuditEntry
+	require.NoError(t, db.Where("entity = ?", AuditEntityItem).Find(&entries).Error)
+	require.Len(t, entries, len(items))
+	for _, entry := range entries {
+		assert.Equal(t, models.AuditCreate, entry.Action)
+		assert.Equal(t, entries[0].OperationID, entry.OperationID, "the batch is one operation")
+	}
+
+	err = repo.CreateInBatches(ctx, []models.Item{*testutil.CreateTestItem(999, 1, 1)}, 2)
+	assert.ErrorIs(t, err, customErr.ErrForeignKeyViolation)
+}
+
 func TestItemRepository_FindByID(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -336,3 +374,288 @@ func TestItemRepository_MultipleItems(t *testing.T) {
 		testutil.AssertItemEqual(t, item, retrieved)
 	}
 }
+
+func TestItemRepository_List(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewItemRepository(db)
+	ctx := context.Background()
+
+	var dri, svi models.Extension
+	require.NoError(t, db.Where("code = ?", "DRI").First(&dri).Error)
+	require.NoError(t, db.Where("code = ?", "SSH").First(&svi).Error)
+	var fr, en models.Language
+	require.NoError(t, db.Where("code = ?", "fr").First(&fr).Error)
+	require.NoError(t, db.Where("code = ?", "en").First(&en).Error)
+	var display, etb models.ItemType
+	require.NoError(t, db.Where("name = ?", "Display").First(&display).Error)
+	require.NoError(t, db.Where("name = ?", "ETB").First(&etb).Error)
+
+	items := []*models.Item{
+		testutil.CreateTestItem(dri.ID, display.ID, fr.ID, func(i *models.Item) { i.Price = testutil.FloatPtr(180) }),
+		testutil.CreateTestItem(dri.ID, etb.ID, en.ID, func(i *models.Item) { i.Price = testutil.FloatPtr(55) }),
+		testutil.CreateTestItem(svi.ID, display.ID, fr.ID, func(i *models.Item) { i.Price = nil }),
+	}
+	for _, item := range items {
+		require.NoError(t, repo.Create(ctx, item))
+	}
+
+	tests := []struct {
+		name        string
+		filter      ItemFilter
+		expectedIDs []uint
+	}{
+		{"no filter", ItemFilter{}, []uint{items[0].ID, items[1].ID, items[2].ID}},
+		{"by extension", ItemFilter{ExtensionCode: "DRI"}, []uint{items[0].ID, items[1].ID}},
+		{"by block", ItemFilter{BlockCode: "EB"}, []uint{items[2].ID}},
+		{"by extension and block", ItemFilter{ExtensionCode: "DRI", BlockCode: "EV"}, []uint{items[0].ID, items[1].ID}},
+		{"by language", ItemFilter{LanguageCode: "en"}, []uint{items[1].ID}},
+		{"by type", ItemFilter{TypeName: "Display"}, []uint{items[0].ID, items[2].ID}},
+		{"by price range", ItemFilter{MinPrice: testutil.FloatPtr(50), MaxPrice: testutil.FloatPtr(100)}, []uint{items[1].ID}},
+		{"limit and offset", ItemFilter{Limit: 1, Offset: 1}, []uint{items[1].ID}},
+		{"no match", ItemFilter{LanguageCode: "de"}, []uint{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Execute
+			found, err := repo.List(ctx, tt.filter)
+
+			// Assert
+			require.NoError(t, err)
+			ids := make([]uint, 0, len(found))
+			for _, item := range found {
+				ids = append(ids, item.ID)
+				assert.NotEmpty(t, item.Extension.Code, "Extension should be preloaded")
+				assert.NotEmpty(t, item.Extension.Block.Code, "Block should be preloaded")
+				assert.NotEmpty(t, item.Type.Name, "Type should be preloaded")
+				assert.NotEmpty(t, item.Language.Code, "Language should be preloaded")
+			}
+			assert.Equal(t, tt.expectedIDs, ids)
+		})
+	}
+}
+
+func TestItemRepository_Update(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewItemRepository(db)
+	ctx := context.Background()
+
+	item := testutil.CreateTestItem(1, 1, 1)
+	require.NoError(t, repo.Create(ctx, item))
+
+	// Execute - change the language and clear the price
+	item.LanguageID = 2
+	item.Price = nil
+	err := repo.Update(ctx, item)
+
+	// Assert
+	require.NoError(t, err)
+	updated, err := repo.FindByID(ctx, item.ID)
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), updated.LanguageID)
+	assert.Nil(t, updated.Price)
+
+	// Execute - unknown item
+	err = repo.Update(ctx, &models.Item{Model: gorm.Model{ID: 9999}, ExtensionID: 1, TypeID: 1, LanguageID: 1})
+	assert.ErrorIs(t, err, customErr.ErrEntityNotFound)
+
+	// Execute - invalid foreign key
+	item.ExtensionID = 9999
+	err = repo.Update(ctx, item)
+	assert.ErrorIs(t, err, customErr.ErrForeignKeyViolation)
+}
+
+func TestItemRepository_Delete(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewItemRepository(db)
+	ctx := context.Background()
+
+	item := testutil.CreateTestItem(1, 1, 1)
+	require.NoError(t, repo.Create(ctx, item))
+
+	// Execute
+	err := repo.Delete(ctx, item.ID)
+
+	// Assert - soft deleted
+	require.NoError(t, err)
+	_, err = repo.FindByID(ctx, item.ID)
+	assert.ErrorIs(t, err, customErr.ErrEntityNotFound)
+
+	var count int64
+	db.Unscoped().Model(&models.Item{}).Where("id = ?", item.ID).Count(&count)
+	assert.Equal(t, int64(1), count, "row should be soft deleted")
+
+	// Execute - deleting twice reports not found
+	err = repo.Delete(ctx, item.ID)
+	assert.ErrorIs(t, err, customErr.ErrEntityNotFound)
+}
+
+func TestItemRepository_Purge(t *testing.T) {
+	// Setup: an item deleted long ago, one deleted recently and one kept
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewItemRepository(db)
+	ctx := context.Background()
+
+	var ids []uint
+	for i := 0; i < 3; i++ {
+		item := testutil.CreateTestItem(1, 1, 1)
+		require.NoError(t, repo.Create(ctx, item))
+		ids = append(ids, item.ID)
+	}
+	require.NoError(t, repo.Delete(ctx, ids[0]))
+	require.NoError(t, repo.Delete(ctx, ids[1]))
+	longAgo := time.Now().Add(-60 * 24 * time.Hour)
+	require.NoError(t, db.Unscoped().Model(&models.Item{}).Where("id = ?", ids[0]).Update("deleted_at", longAgo).Error)
+
+	require.NoError(t, NewPriceRecordRepository(db).Create(ctx, &models.PriceRecord{ItemID: ids[0], Price: 10, Currency: "EUR", Source: "file", QuotedAt: longAgo}))
+	require.NoError(t, NewAlertRuleRepository(db).Create(ctx, &models.AlertRule{Name: "old", ItemID: &ids[0], Condition: models.AlertAbove, Threshold: 20}))
+
+	// Execute
+	purged, err := repo.Purge(ctx, time.Now().Add(-30*24*time.Hour))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	var remaining []uint
+	require.NoError(t, db.Unscoped().Model(&models.Item{}).Order("id").Pluck("id", &remaining).Error)
+	assert.Equal(t, ids[1:], remaining, "recently deleted and live items are kept")
+
+	var records, rules int64
+	db.Model(&models.PriceRecord{}).Where("item_id = ?", ids[0]).Count(&records)
+	db.Unscoped().Model(&models.AlertRule{}).Where("item_id = ?", ids[0]).Count(&rules)
+	assert.Zero(t, records)
+	assert.Zero(t, rules)
+
+	var deletions []string
+	require.NoError(t, db.Model(&models.AuditEntry{}).Where("action = ? AND entity IN ?", models.AuditDelete, []string{AuditEntityPriceRecord, AuditEntityAlert}).Order("id").Pluck("entity", &deletions).Error)
+	assert.Equal(t, []string{AuditEntityPriceRecord, AuditEntityAlert}, deletions, "the removal of the records is audited")
+
+	purged, err = repo.Purge(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+}
+
+func TestItemRepository_Merge(t *testing.T) {
+	// Setup: the third item was already merged into the second
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewItemRepository(db)
+	ctx := context.Background()
+
+	var ids []uint
+	for i := 0; i < 3; i++ {
+		item := testutil.CreateTestItem(1, 1, 1)
+		require.NoError(t, repo.Create(ctx, item))
+		ids = append(ids, item.ID)
+	}
+	second, err := repo.FindByID(ctx, ids[1])
+	require.NoError(t, err)
+	require.NoError(t, repo.Merge(ctx, second, ids[2]))
+
+	require.NoError(t, NewPriceRecordRepository(db).Create(ctx, &models.PriceRecord{ItemID: ids[1], Price: 10, Currency: "EUR", Source: "file", QuotedAt: time.Now()}))
+	require.NoError(t, NewAlertRuleRepository(db).Create(ctx, &models.AlertRule{Name: "cheap", ItemID: &ids[1], Condition: models.AlertBelow, Threshold: 5}))
+
+	// Execute
+	keep, err := repo.FindByID(ctx, ids[0])
+	require.NoError(t, err)
+	keep.Quantity = 2
+	err = repo.Merge(ctx, keep, ids[1])
+
+	// Assert
+	require.NoError(t, err)
+	_, err = repo.FindByID(ctx, ids[1])
+	assert.ErrorIs(t, err, customErr.ErrEntityNotFound, "the duplicate is deleted")
+
+	kept, err := repo.FindByID(ctx, ids[0])
+	require.NoError(t, err)
+	assert.Equal(t, 2, kept.Quantity)
+
+	var records, rules int64
+	db.Model(&models.PriceRecord{}).Where("item_id = ?", ids[0]).Count(&records)
+	db.Model(&models.AlertRule{}).Where("item_id = ?", ids[0]).Count(&rules)
+	assert.Equal(t, int64(1), records, "price history moves to the kept item")
+	assert.Equal(t, int64(1), rules, "alert rules move to the kept item")
+
+	var moves []string
+	require.NoError(t, db.Model(&models.AuditEntry{}).Where("action = ? AND entity IN ?", models.AuditUpdate, []string{AuditEntityPriceRecord, AuditEntityAlert}).Order("id").Pluck("entity", &moves).Error)
+	assert.Equal(t, []string{AuditEntityPriceRecord, AuditEntityAlert}, moves, "the moves are audited")
+
+	merged, err := repo.MergedInto(ctx, ids[0])
+	require.NoError(t, err)
+	assert.Equal(t, ids[1:], merged, "items merged into a merged item are included")
+
+	purged, err := repo.Purge(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Zero(t, purged, "merged items are kept for the history")
+
+	err = repo.Merge(ctx, kept, ids[1])
+	assert.ErrorIs(t, err, customErr.ErrEntityNotFound)
+}
+
+func TestItemRepository_Aggregate(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewItemRepository(db)
+	ctx := context.Background()
+
+	var fr, en models.Language
+	require.NoError(t, db.Where("code = ?", "fr").First(&fr).Error)
+	require.NoError(t, db.Where("code = ?", "en").First(&en).Error)
+
+	items := []*models.Item{
+		testutil.CreateTestItem(1, 1, fr.ID, func(i *models.Item) { i.Price = testutil.FloatPtr(100) }),
+		testutil.CreateTestItem(1, 1, fr.ID, func(i *models.Item) { i.Price = testutil.FloatPtr(50) }),
+		testutil.CreateTestItem(1, 1, en.ID, func(i *models.Item) { i.Price = nil }),
+		testutil.CreateTestItem(1, 1, en.ID, func(i *models.Item) { i.Price = testutil.FloatPtr(999) }),
+	}
+	for _, item := range items {
+		require.NoError(t, repo.Create(ctx, item))
+	}
+	require.NoError(t, repo.Delete(ctx, items[3].ID))
+
+	// Execute - totals
+	totals, err := repo.Aggregate(ctx, GroupByNone)
+	require.NoError(t, err)
+	require.Len(t, totals, 1)
+	assert.Equal(t, int64(3), totals[0].Count, "soft-deleted items are excluded")
+	assert.Equal(t, int64(2), totals[0].PricedCount)
+	assert.InDelta(t, 150, totals[0].TotalPrice, 0.001)
+
+	// Execute - by language
+	byLang, err := repo.Aggregate(ctx, GroupByLanguage)
+	require.NoError(t, err)
+	require.Len(t, byLang, 2)
+	assert.Equal(t, "fr", byLang[0].Key)
+	assert.Equal(t, "Français", byLang[0].Label)
+	assert.Equal(t, int64(2), byLang[0].Count)
+	assert.Equal(t, "en", byLang[1].Key)
+	assert.Equal(t, int64(1), byLang[1].Count)
+	assert.Zero(t, byLang[1].PricedCount)
+
+	// Execute - other groupings
+	for _, groupBy := range []ItemGroupBy{GroupByBlock, GroupByExtension, GroupByType} {
+		aggregates, err := repo.Aggregate(ctx, groupBy)
+		require.NoError(t, err, groupBy)
+		require.Len(t, aggregates, 1, groupBy)
+		assert.Equal(t, int64(3), aggregates[0].Count, groupBy)
+	}
+
+	// Execute - unsupported grouping
+	_, err = repo.Aggregate(ctx, ItemGroupBy("price"))
+	assert.Error(t, err)
+}
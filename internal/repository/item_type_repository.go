@@ -23,9 +23,18 @@ func (r *itemTypeRepository) FindByName(ctx context.Context, name string) (*mode
 	err := r.db.WithContext(ctx).Where("name = ?", name).First(&itemType).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, customErr.NewRepositoryError("find", "item_type", name, customErr.ErrEntityNotFound)
+			return nil, newRepositoryError("find", "item_type", name, customErr.ErrEntityNotFound)
 		}
-		return nil, customErr.NewRepositoryError("find", "item_type", name, err)
+		return nil, newRepositoryError("find", "item_type", name, err)
 	}
 	return &itemType, nil
 }
+
+func (r *itemTypeRepository) FindAll(ctx context.Context) ([]models.ItemType, error) {
+	var itemTypes []models.ItemType
+
+	if err := r.db.WithContext(ctx).Order("name").Find(&itemTypes).Error; err != nil {
+		return nil, newRepositoryError("list", "item_type", "all", err)
+	}
+	return itemTypes, nil
+}
@@ -168,3 +168,23 @@ func TestItemTypeRepository_CustomItemType(t *testing.T) {
 	assert.NotNil(t, found)
 	testutil.AssertItemTypeEqual(t, customType, found)
 }
+
+func TestItemTypeRepository_FindAll(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewItemTypeRepository(db)
+	ctx := context.Background()
+
+	// Execute
+	itemTypes, err := repo.FindAll(ctx)
+
+	// Assert - seeded item types ordered by name
+	assert.NoError(t, err)
+	names := make([]string, 0, len(itemTypes))
+	for _, itemType := range itemTypes {
+		names = append(names, itemType.Name)
+	}
+	assert.Equal(t, []string{"Booster", "Bundle", "Display", "ETB", "Sleeve Booster"}, names)
+}
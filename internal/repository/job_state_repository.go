@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type jobStateRepository struct {
+	db *gorm.DB
+}
+
+func NewJobStateRepository(db *gorm.DB) JobStateRepository {
+	return &jobStateRepository{db: db}
+}
+
+func (r *jobStateRepository) Ensure(ctx context.Context, name string) (*models.JobState, error) {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.JobState{Name: name, UpdatedAt: time.Now()}).Error
+	if err != nil {
+		return nil, newRepositoryError("create", "job_state", name, err)
+	}
+	return r.FindByName(ctx, name)
+}
+
+func (r *jobStateRepository) FindByName(ctx context.Context, name string) (*models.JobState, error) {
+	var states []models.JobState
+
+	if err := r.db.WithContext(ctx).Where("name = ?", name).Limit(1).Find(&states).Error; err != nil {
+		return nil, newRepositoryError("find", "job_state", name, err)
+	}
+	if len(states) == 0 {
+		return nil, newRepositoryError("find", "job_state", name, customErr.ErrEntityNotFound)
+	}
+	return &states[0], nil
+}
+
+func (r *jobStateRepository) FindAll(ctx context.Context) ([]models.JobState, error) {
+	var states []models.JobState
+
+	if err := r.db.WithContext(ctx).Order("name").Find(&states).Error; err != nil {
+		return nil, newRepositoryError("list", "job_state", "", err)
+	}
+	return states, nil
+}
+
+func (r *jobStateRepository) SetNextRun(ctx context.Context, name, schedule string, next time.Time) error {
+	return r.update(ctx, "schedule", name, map[string]interface{}{
+		"schedule":    schedule,
+		"next_run_at": next,
+		"updated_at":  time.Now(),
+	})
+}
+
+func (r *jobStateRepository) Claim(ctx context.Context, name string, now, staleBefore time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.JobState{}).
+		Where("name = ? AND (running_since IS NULL OR running_since < ?)", name, staleBefore).
+		Updates(map[string]interface{}{"running_since": now, "updated_at": time.Now()})
+	if result.Error != nil {
+		return false, newRepositoryError("claim", "job_state", name, result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *jobStateRepository) Finish(ctx context.Context, state *models.JobState) error {
+	return r.update(ctx, "finish", state.Name, map[string]interface{}{
+		"last_run_at":   state.LastRunAt,
+		"last_duration": state.LastDuration,
+		"last_status":   state.LastStatus,
+		"last_error":    state.LastError,
+		"next_run_at":   state.NextRunAt,
+		"running_since": nil,
+		"updated_at":    time.Now(),
+	})
+}
+
+func (r *jobStateRepository) update(ctx context.Context, op, name string, values map[string]interface{}) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.JobState{}).
+		Where("name = ?", name).
+		Updates(values)
+	if result.Error != nil {
+		return newRepositoryError(op, "job_state", name, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return newRepositoryError(op, "job_state", name, customErr.ErrEntityNotFound)
+	}
+	return nil
+}
@@ -23,9 +23,18 @@ func (r *languageRepository) FindByCode(ctx context.Context, code string) (*mode
 	err := r.db.WithContext(ctx).Where("code = ?", code).First(&lang).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, customErr.NewRepositoryError("find", "language", code, customErr.ErrEntityNotFound)
+			return nil, newRepositoryError("find", "language", code, customErr.ErrEntityNotFound)
 		}
-		return nil, customErr.NewRepositoryError("find", "language", code, err)
+		return nil, newRepositoryError("find", "language", code, err)
 	}
 	return &lang, nil
 }
+
+func (r *languageRepository) FindAll(ctx context.Context) ([]models.Language, error) {
+	var langs []models.Language
+
+	if err := r.db.WithContext(ctx).Order("code").Find(&langs).Error; err != nil {
+		return nil, newRepositoryError("list", "language", "all", err)
+	}
+	return langs, nil
+}
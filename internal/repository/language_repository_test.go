@@ -163,3 +163,23 @@ func TestLanguageRepository_CustomLanguage(t *testing.T) {
 	assert.NotNil(t, found)
 	testutil.AssertLanguageEqual(t, customLang, found)
 }
+
+func TestLanguageRepository_FindAll(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewLanguageRepository(db)
+	ctx := context.Background()
+
+	// Execute
+	langs, err := repo.FindAll(ctx)
+
+	// Assert - seeded languages ordered by code
+	assert.NoError(t, err)
+	codes := make([]string, 0, len(langs))
+	for _, lang := range langs {
+		codes = append(codes, lang.Code)
+	}
+	assert.Equal(t, []string{"de", "en", "es", "fr"}, codes)
+}
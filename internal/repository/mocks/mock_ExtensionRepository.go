@@ -22,6 +22,123 @@ func (_m *MockExtensionRepository) EXPECT() *MockExtensionRepository_Expecter {
 	return &MockExtensionRepository_Expecter{mock: &_m.Mock}
 }
 
+// FindAll provides a mock function with given fields: ctx
+func (_m *MockExtensionRepository) FindAll(ctx context.Context) ([]models.Extension, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAll")
+	}
+
+	var r0 []models.Extension
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Extension, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Extension); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Extension)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockExtensionRepository_FindAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindAll'
+type MockExtensionRepository_FindAll_Call struct {
+	*mock.Call
+}
+
+// FindAll is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockExtensionRepository_Expecter) FindAll(ctx interface{}) *MockExtensionRepository_FindAll_Call {
+	return &MockExtensionRepository_FindAll_Call{Call: _e.mock.On("FindAll", ctx)}
+}
+
+func (_c *MockExtensionRepository_FindAll_Call) Run(run func(ctx context.Context)) *MockExtensionRepository_FindAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockExtensionRepository_FindAll_Call) Return(_a0 []models.Extension, _a1 error) *MockExtensionRepository_FindAll_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockExtensionRepository_FindAll_Call) RunAndReturn(run func(context.Context) ([]models.Extension, error)) *MockExtensionRepository_FindAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByBlockCode provides a mock function with given fields: ctx, blockCode
+func (_m *MockExtensionRepository) FindByBlockCode(ctx context.Context, blockCode string) ([]models.Extension, error) {
+	ret := _m.Called(ctx, blockCode)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByBlockCode")
+	}
+
+	var r0 []models.Extension
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]models.Extension, error)); ok {
+		return rf(ctx, blockCode)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []models.Extension); ok {
+		r0 = rf(ctx, blockCode)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Extension)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, blockCode)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockExtensionRepository_FindByBlockCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByBlockCode'
+type MockExtensionRepository_FindByBlockCode_Call struct {
+	*mock.Call
+}
+
+// FindByBlockCode is a helper method to define mock.On call
+//   - ctx context.Context
+//   - blockCode string
+func (_e *MockExtensionRepository_Expecter) FindByBlockCode(ctx interface{}, blockCode interface{}) *MockExtensionRepository_FindByBlockCode_Call {
+	return &MockExtensionRepository_FindByBlockCode_Call{Call: _e.mock.On("FindByBlockCode", ctx, blockCode)}
+}
+
+func (_c *MockExtensionRepository_FindByBlockCode_Call) Run(run func(ctx context.Context, blockCode string)) *MockExtensionRepository_FindByBlockCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockExtensionRepository_FindByBlockCode_Call) Return(_a0 []models.Extension, _a1 error) *MockExtensionRepository_FindByBlockCode_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockExtensionRepository_FindByBlockCode_Call) RunAndReturn(run func(context.Context, string) ([]models.Extension, error)) *MockExtensionRepository_FindByBlockCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // FindByCode provides a mock function with given fields: ctx, code
 func (_m *MockExtensionRepository) FindByCode(ctx context.Context, code string) (*models.Extension, error) {
 	ret := _m.Called(ctx, code)
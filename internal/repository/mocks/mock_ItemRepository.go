@@ -4,8 +4,10 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 
 	models "github.com/R4yL-dev/pkmc/internal/models"
+	repository "github.com/R4yL-dev/pkmc/internal/repository"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -22,6 +24,65 @@ func (_m *MockItemRepository) EXPECT() *MockItemRepository_Expecter {
 	return &MockItemRepository_Expecter{mock: &_m.Mock}
 }
 
+// Aggregate provides a mock function with given fields: ctx, groupBy
+func (_m *MockItemRepository) Aggregate(ctx context.Context, groupBy repository.ItemGroupBy) ([]repository.ItemAggregate, error) {
+	ret := _m.Called(ctx, groupBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Aggregate")
+	}
+
+	var r0 []repository.ItemAggregate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.ItemGroupBy) ([]repository.ItemAggregate, error)); ok {
+		return rf(ctx, groupBy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.ItemGroupBy) []repository.ItemAggregate); ok {
+		r0 = rf(ctx, groupBy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repository.ItemAggregate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.ItemGroupBy) error); ok {
+		r1 = rf(ctx, groupBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockItemRepository_Aggregate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Aggregate'
+type MockItemRepository_Aggregate_Call struct {
+	*mock.Call
+}
+
+// Aggregate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - groupBy repository.ItemGroupBy
+func (_e *MockItemRepository_Expecter) Aggregate(ctx interface{}, groupBy interface{}) *MockItemRepository_Aggregate_Call {
+	return &MockItemRepository_Aggregate_Call{Call: _e.mock.On("Aggregate", ctx, groupBy)}
+}
+
+func (_c *MockItemRepository_Aggregate_Call) Run(run func(ctx context.Context, groupBy repository.ItemGroupBy)) *MockItemRepository_Aggregate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(repository.ItemGroupBy))
+	})
+	return _c
+}
+
+func (_c *MockItemRepository_Aggregate_Call) Return(_a0 []repository.ItemAggregate, _a1 error) *MockItemRepository_Aggregate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockItemRepository_Aggregate_Call) RunAndReturn(run func(context.Context, repository.ItemGroupBy) ([]repository.ItemAggregate, error)) *MockItemRepository_Aggregate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Create provides a mock function with given fields: ctx, item
 func (_m *MockItemRepository) Create(ctx context.Context, item *models.Item) error {
 	ret := _m.Called(ctx, item)
@@ -69,6 +130,101 @@ func (_c *MockItemRepository_Create_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
+// CreateInBatches provides a mock function with given fields: ctx, items, batchSize
+func (_m *MockItemRepository) CreateInBatches(ctx context.Context, items []models.Item, batchSize int) error {
+	ret := _m.Called(ctx, items, batchSize)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateInBatches")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.Item, int) error); ok {
+		r0 = rf(ctx, items, batchSize)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockItemRepository_CreateInBatches_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateInBatches'
+type MockItemRepository_CreateInBatches_Call struct {
+	*mock.Call
+}
+
+// CreateInBatches is a helper method to define mock.On call
+//   - ctx context.Context
+//   - items []models.Item
+//   - batchSize int
+func (_e *MockItemRepository_Expecter) CreateInBatches(ctx interface{}, items interface{}, batchSize interface{}) *MockItemRepository_CreateInBatches_Call {
+	return &MockItemRepository_CreateInBatches_Call{Call: _e.mock.On("CreateInBatches", ctx, items, batchSize)}
+}
+
+func (_c *MockItemRepository_CreateInBatches_Call) Run(run func(ctx context.Context, items []models.Item, batchSize int)) *MockItemRepository_CreateInBatches_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]models.Item), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockItemRepository_CreateInBatches_Call) Return(_a0 error) *MockItemRepository_CreateInBatches_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockItemRepository_CreateInBatches_Call) RunAndReturn(run func(context.Context, []models.Item, int) error) *MockItemRepository_CreateInBatches_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *MockItemRepository) Delete(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockItemRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockItemRepository_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+func (_e *MockItemRepository_Expecter) Delete(ctx interface{}, id interface{}) *MockItemRepository_Delete_Call {
+	return &MockItemRepository_Delete_Call{Call: _e.mock.On("Delete", ctx, id)}
+}
+
+func (_c *MockItemRepository_Delete_Call) Run(run func(ctx context.Context, id uint)) *MockItemRepository_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockItemRepository_Delete_Call) Return(_a0 error) *MockItemRepository_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockItemRepository_Delete_Call) RunAndReturn(run func(context.Context, uint) error) *MockItemRepository_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // FindByID provides a mock function with given fields: ctx, id
 func (_m *MockItemRepository) FindByID(ctx context.Context, id uint) (*models.Item, error) {
 	ret := _m.Called(ctx, id)
@@ -128,6 +284,382 @@ func (_c *MockItemRepository_FindByID_Call) RunAndReturn(run func(context.Contex
 	return _c
 }
 
+// FindStored provides a mock function with given fields: ctx, id
+func (_m *MockItemRepository) FindStored(ctx context.Context, id uint) (*models.Item, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindStored")
+	}
+
+	var r0 *models.Item
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*models.Item, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *models.Item); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Item)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockItemRepository_FindStored_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindStored'
+type MockItemRepository_FindStored_Call struct {
+	*mock.Call
+}
+
+// FindStored is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+func (_e *MockItemRepository_Expecter) FindStored(ctx interface{}, id interface{}) *MockItemRepository_FindStored_Call {
+	return &MockItemRepository_FindStored_Call{Call: _e.mock.On("FindStored", ctx, id)}
+}
+
+func (_c *MockItemRepository_FindStored_Call) Run(run func(ctx context.Context, id uint)) *MockItemRepository_FindStored_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockItemRepository_FindStored_Call) Return(_a0 *models.Item, _a1 error) *MockItemRepository_FindStored_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockItemRepository_FindStored_Call) RunAndReturn(run func(context.Context, uint) (*models.Item, error)) *MockItemRepository_FindStored_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function with given fields: ctx, filter
+func (_m *MockItemRepository) List(ctx context.Context, filter repository.ItemFilter) ([]models.Item, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []models.Item
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.ItemFilter) ([]models.Item, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.ItemFilter) []models.Item); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Item)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.ItemFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockItemRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockItemRepository_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter repository.ItemFilter
+func (_e *MockItemRepository_Expecter) List(ctx interface{}, filter interface{}) *MockItemRepository_List_Call {
+	return &MockItemRepository_List_Call{Call: _e.mock.On("List", ctx, filter)}
+}
+
+func (_c *MockItemRepository_List_Call) Run(run func(ctx context.Context, filter repository.ItemFilter)) *MockItemRepository_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(repository.ItemFilter))
+	})
+	return _c
+}
+
+func (_c *MockItemRepository_List_Call) Return(_a0 []models.Item, _a1 error) *MockItemRepository_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockItemRepository_List_Call) RunAndReturn(run func(context.Context, repository.ItemFilter) ([]models.Item, error)) *MockItemRepository_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Merge provides a mock function with given fields: ctx, keep, dropID
+func (_m *MockItemRepository) Merge(ctx context.Context, keep *models.Item, dropID uint) error {
+	ret := _m.Called(ctx, keep, dropID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Merge")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Item, uint) error); ok {
+		r0 = rf(ctx, keep, dropID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockItemRepository_Merge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Merge'
+type MockItemRepository_Merge_Call struct {
+	*mock.Call
+}
+
+// Merge is a helper method to define mock.On call
+//   - ctx context.Context
+//   - keep *models.Item
+//   - dropID uint
+func (_e *MockItemRepository_Expecter) Merge(ctx interface{}, keep interface{}, dropID interface{}) *MockItemRepository_Merge_Call {
+	return &MockItemRepository_Merge_Call{Call: _e.mock.On("Merge", ctx, keep, dropID)}
+}
+
+func (_c *MockItemRepository_Merge_Call) Run(run func(ctx context.Context, keep *models.Item, dropID uint)) *MockItemRepository_Merge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.Item), args[2].(uint))
+	})
+	return _c
+}
+
+func (_c *MockItemRepository_Merge_Call) Return(_a0 error) *MockItemRepository_Merge_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockItemRepository_Merge_Call) RunAndReturn(run func(context.Context, *models.Item, uint) error) *MockItemRepository_Merge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MergedInto provides a mock function with given fields: ctx, id
+func (_m *MockItemRepository) MergedInto(ctx context.Context, id uint) ([]uint, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MergedInto")
+	}
+
+	var r0 []uint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]uint, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []uint); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockItemRepository_MergedInto_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MergedInto'
+type MockItemRepository_MergedInto_Call struct {
+	*mock.Call
+}
+
+// MergedInto is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+func (_e *MockItemRepository_Expecter) MergedInto(ctx interface{}, id interface{}) *MockItemRepository_MergedInto_Call {
+	return &MockItemRepository_MergedInto_Call{Call: _e.mock.On("MergedInto", ctx, id)}
+}
+
+func (_c *MockItemRepository_MergedInto_Call) Run(run func(ctx context.Context, id uint)) *MockItemRepository_MergedInto_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockItemRepository_MergedInto_Call) Return(_a0 []uint, _a1 error) *MockItemRepository_MergedInto_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockItemRepository_MergedInto_Call) RunAndReturn(run func(context.Context, uint) ([]uint, error)) *MockItemRepository_MergedInto_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Purge provides a mock function with given fields: ctx, before
+func (_m *MockItemRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Purge")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockItemRepository_Purge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Purge'
+type MockItemRepository_Purge_Call struct {
+	*mock.Call
+}
+
+// Purge is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+func (_e *MockItemRepository_Expecter) Purge(ctx interface{}, before interface{}) *MockItemRepository_Purge_Call {
+	return &MockItemRepository_Purge_Call{Call: _e.mock.On("Purge", ctx, before)}
+}
+
+func (_c *MockItemRepository_Purge_Call) Run(run func(ctx context.Context, before time.Time)) *MockItemRepository_Purge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockItemRepository_Purge_Call) Return(_a0 int64, _a1 error) *MockItemRepository_Purge_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockItemRepository_Purge_Call) RunAndReturn(run func(context.Context, time.Time) (int64, error)) *MockItemRepository_Purge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Restore provides a mock function with given fields: ctx, id
+func (_m *MockItemRepository) Restore(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockItemRepository_Restore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Restore'
+type MockItemRepository_Restore_Call struct {
+	*mock.Call
+}
+
+// Restore is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+func (_e *MockItemRepository_Expecter) Restore(ctx interface{}, id interface{}) *MockItemRepository_Restore_Call {
+	return &MockItemRepository_Restore_Call{Call: _e.mock.On("Restore", ctx, id)}
+}
+
+func (_c *MockItemRepository_Restore_Call) Run(run func(ctx context.Context, id uint)) *MockItemRepository_Restore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MockItemRepository_Restore_Call) Return(_a0 error) *MockItemRepository_Restore_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockItemRepository_Restore_Call) RunAndReturn(run func(context.Context, uint) error) *MockItemRepository_Restore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function with given fields: ctx, item
+func (_m *MockItemRepository) Update(ctx context.Context, item *models.Item) error {
+	ret := _m.Called(ctx, item)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Item) error); ok {
+		r0 = rf(ctx, item)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockItemRepository_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type MockItemRepository_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - ctx context.Context
+//   - item *models.Item
+func (_e *MockItemRepository_Expecter) Update(ctx interface{}, item interface{}) *MockItemRepository_Update_Call {
+	return &MockItemRepository_Update_Call{Call: _e.mock.On("Update", ctx, item)}
+}
+
+func (_c *MockItemRepository_Update_Call) Run(run func(ctx context.Context, item *models.Item)) *MockItemRepository_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.Item))
+	})
+	return _c
+}
+
+func (_c *MockItemRepository_Update_Call) Return(_a0 error) *MockItemRepository_Update_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockItemRepository_Update_Call) RunAndReturn(run func(context.Context, *models.Item) error) *MockItemRepository_Update_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockItemRepository creates a new instance of MockItemRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockItemRepository(t interface {
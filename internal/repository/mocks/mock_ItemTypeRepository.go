@@ -22,6 +22,64 @@ func (_m *MockItemTypeRepository) EXPECT() *MockItemTypeRepository_Expecter {
 	return &MockItemTypeRepository_Expecter{mock: &_m.Mock}
 }
 
+// FindAll provides a mock function with given fields: ctx
+func (_m *MockItemTypeRepository) FindAll(ctx context.Context) ([]models.ItemType, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAll")
+	}
+
+	var r0 []models.ItemType
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.ItemType, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.ItemType); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ItemType)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockItemTypeRepository_FindAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindAll'
+type MockItemTypeRepository_FindAll_Call struct {
+	*mock.Call
+}
+
+// FindAll is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockItemTypeRepository_Expecter) FindAll(ctx interface{}) *MockItemTypeRepository_FindAll_Call {
+	return &MockItemTypeRepository_FindAll_Call{Call: _e.mock.On("FindAll", ctx)}
+}
+
+func (_c *MockItemTypeRepository_FindAll_Call) Run(run func(ctx context.Context)) *MockItemTypeRepository_FindAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockItemTypeRepository_FindAll_Call) Return(_a0 []models.ItemType, _a1 error) *MockItemTypeRepository_FindAll_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockItemTypeRepository_FindAll_Call) RunAndReturn(run func(context.Context) ([]models.ItemType, error)) *MockItemTypeRepository_FindAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // FindByName provides a mock function with given fields: ctx, name
 func (_m *MockItemTypeRepository) FindByName(ctx context.Context, name string) (*models.ItemType, error) {
 	ret := _m.Called(ctx, name)
@@ -22,6 +22,64 @@ func (_m *MockLanguageRepository) EXPECT() *MockLanguageRepository_Expecter {
 	return &MockLanguageRepository_Expecter{mock: &_m.Mock}
 }
 
+// FindAll provides a mock function with given fields: ctx
+func (_m *MockLanguageRepository) FindAll(ctx context.Context) ([]models.Language, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAll")
+	}
+
+	var r0 []models.Language
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Language, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Language); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Language)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLanguageRepository_FindAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindAll'
+type MockLanguageRepository_FindAll_Call struct {
+	*mock.Call
+}
+
+// FindAll is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockLanguageRepository_Expecter) FindAll(ctx interface{}) *MockLanguageRepository_FindAll_Call {
+	return &MockLanguageRepository_FindAll_Call{Call: _e.mock.On("FindAll", ctx)}
+}
+
+func (_c *MockLanguageRepository_FindAll_Call) Run(run func(ctx context.Context)) *MockLanguageRepository_FindAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockLanguageRepository_FindAll_Call) Return(_a0 []models.Language, _a1 error) *MockLanguageRepository_FindAll_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLanguageRepository_FindAll_Call) RunAndReturn(run func(context.Context) ([]models.Language, error)) *MockLanguageRepository_FindAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // FindByCode provides a mock function with given fields: ctx, code
 func (_m *MockLanguageRepository) FindByCode(ctx context.Context, code string) (*models.Language, error) {
 	ret := _m.Called(ctx, code)
@@ -22,6 +22,147 @@ func (_m *MockUnitOfWork) EXPECT() *MockUnitOfWork_Expecter {
 	return &MockUnitOfWork_Expecter{mock: &_m.Mock}
 }
 
+// APITokens provides a mock function with no fields
+func (_m *MockUnitOfWork) APITokens() repository.APITokenRepository {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for APITokens")
+	}
+
+	var r0 repository.APITokenRepository
+	if rf, ok := ret.Get(0).(func() repository.APITokenRepository); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repository.APITokenRepository)
+		}
+	}
+
+	return r0
+}
+
+// MockUnitOfWork_APITokens_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'APITokens'
+type MockUnitOfWork_APITokens_Call struct {
+	*mock.Call
+}
+
+// APITokens is a helper method to define mock.On call
+func (_e *MockUnitOfWork_Expecter) APITokens() *MockUnitOfWork_APITokens_Call {
+	return &MockUnitOfWork_APITokens_Call{Call: _e.mock.On("APITokens")}
+}
+
+func (_c *MockUnitOfWork_APITokens_Call) Run(run func()) *MockUnitOfWork_APITokens_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockUnitOfWork_APITokens_Call) Return(_a0 repository.APITokenRepository) *MockUnitOfWork_APITokens_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUnitOfWork_APITokens_Call) RunAndReturn(run func() repository.APITokenRepository) *MockUnitOfWork_APITokens_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AlertRules provides a mock function with no fields
+func (_m *MockUnitOfWork) AlertRules() repository.AlertRuleRepository {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for AlertRules")
+	}
+
+	var r0 repository.AlertRuleRepository
+	if rf, ok := ret.Get(0).(func() repository.AlertRuleRepository); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repository.AlertRuleRepository)
+		}
+	}
+
+	return r0
+}
+
+// MockUnitOfWork_AlertRules_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AlertRules'
+type MockUnitOfWork_AlertRules_Call struct {
+	*mock.Call
+}
+
+// AlertRules is a helper method to define mock.On call
+func (_e *MockUnitOfWork_Expecter) AlertRules() *MockUnitOfWork_AlertRules_Call {
+	return &MockUnitOfWork_AlertRules_Call{Call: _e.mock.On("AlertRules")}
+}
+
+func (_c *MockUnitOfWork_AlertRules_Call) Run(run func()) *MockUnitOfWork_AlertRules_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockUnitOfWork_AlertRules_Call) Return(_a0 repository.AlertRuleRepository) *MockUnitOfWork_AlertRules_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUnitOfWork_AlertRules_Call) RunAndReturn(run func() repository.AlertRuleRepository) *MockUnitOfWork_AlertRules_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Audit provides a mock function with no fields
+func (_m *MockUnitOfWork) Audit() repository.AuditRepository {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Audit")
+	}
+
+	var r0 repository.AuditRepository
+	if rf, ok := ret.Get(0).(func() repository.AuditRepository); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repository.AuditRepository)
+		}
+	}
+
+	return r0
+}
+
+// MockUnitOfWork_Audit_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Audit'
+type MockUnitOfWork_Audit_Call struct {
+	*mock.Call
+}
+
+// Audit is a helper method to define mock.On call
+func (_e *MockUnitOfWork_Expecter) Audit() *MockUnitOfWork_Audit_Call {
+	return &MockUnitOfWork_Audit_Call{Call: _e.mock.On("Audit")}
+}
+
+func (_c *MockUnitOfWork_Audit_Call) Run(run func()) *MockUnitOfWork_Audit_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockUnitOfWork_Audit_Call) Return(_a0 repository.AuditRepository) *MockUnitOfWork_Audit_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUnitOfWork_Audit_Call) RunAndReturn(run func() repository.AuditRepository) *MockUnitOfWork_Audit_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Blocks provides a mock function with no fields
 func (_m *MockUnitOfWork) Blocks() repository.BlockRepository {
 	ret := _m.Called()
@@ -116,6 +257,53 @@ func (_c *MockUnitOfWork_Do_Call) RunAndReturn(run func(context.Context, func(re
 	return _c
 }
 
+// DoRead provides a mock function with given fields: ctx, fn
+func (_m *MockUnitOfWork) DoRead(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+	ret := _m.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DoRead")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(repository.UnitOfWork) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUnitOfWork_DoRead_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DoRead'
+type MockUnitOfWork_DoRead_Call struct {
+	*mock.Call
+}
+
+// DoRead is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fn func(repository.UnitOfWork) error
+func (_e *MockUnitOfWork_Expecter) DoRead(ctx interface{}, fn interface{}) *MockUnitOfWork_DoRead_Call {
+	return &MockUnitOfWork_DoRead_Call{Call: _e.mock.On("DoRead", ctx, fn)}
+}
+
+func (_c *MockUnitOfWork_DoRead_Call) Run(run func(ctx context.Context, fn func(repository.UnitOfWork) error)) *MockUnitOfWork_DoRead_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(func(repository.UnitOfWork) error))
+	})
+	return _c
+}
+
+func (_c *MockUnitOfWork_DoRead_Call) Return(_a0 error) *MockUnitOfWork_DoRead_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUnitOfWork_DoRead_Call) RunAndReturn(run func(context.Context, func(repository.UnitOfWork) error) error) *MockUnitOfWork_DoRead_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Extensions provides a mock function with no fields
 func (_m *MockUnitOfWork) Extensions() repository.ExtensionRepository {
 	ret := _m.Called()
@@ -163,6 +351,53 @@ func (_c *MockUnitOfWork_Extensions_Call) RunAndReturn(run func() repository.Ext
 	return _c
 }
 
+// IdempotencyKeys provides a mock function with no fields
+func (_m *MockUnitOfWork) IdempotencyKeys() repository.IdempotencyKeyRepository {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for IdempotencyKeys")
+	}
+
+	var r0 repository.IdempotencyKeyRepository
+	if rf, ok := ret.Get(0).(func() repository.IdempotencyKeyRepository); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repository.IdempotencyKeyRepository)
+		}
+	}
+
+	return r0
+}
+
+// MockUnitOfWork_IdempotencyKeys_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IdempotencyKeys'
+type MockUnitOfWork_IdempotencyKeys_Call struct {
+	*mock.Call
+}
+
+// IdempotencyKeys is a helper method to define mock.On call
+func (_e *MockUnitOfWork_Expecter) IdempotencyKeys() *MockUnitOfWork_IdempotencyKeys_Call {
+	return &MockUnitOfWork_IdempotencyKeys_Call{Call: _e.mock.On("IdempotencyKeys")}
+}
+
+func (_c *MockUnitOfWork_IdempotencyKeys_Call) Run(run func()) *MockUnitOfWork_IdempotencyKeys_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockUnitOfWork_IdempotencyKeys_Call) Return(_a0 repository.IdempotencyKeyRepository) *MockUnitOfWork_IdempotencyKeys_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUnitOfWork_IdempotencyKeys_Call) RunAndReturn(run func() repository.IdempotencyKeyRepository) *MockUnitOfWork_IdempotencyKeys_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ItemTypes provides a mock function with no fields
 func (_m *MockUnitOfWork) ItemTypes() repository.ItemTypeRepository {
 	ret := _m.Called()
@@ -257,6 +492,53 @@ func (_c *MockUnitOfWork_Items_Call) RunAndReturn(run func() repository.ItemRepo
 	return _c
 }
 
+// JobStates provides a mock function with no fields
+func (_m *MockUnitOfWork) JobStates() repository.JobStateRepository {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for JobStates")
+	}
+
+	var r0 repository.JobStateRepository
+	if rf, ok := ret.Get(0).(func() repository.JobStateRepository); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repository.JobStateRepository)
+		}
+	}
+
+	return r0
+}
+
+// MockUnitOfWork_JobStates_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'JobStates'
+type MockUnitOfWork_JobStates_Call struct {
+	*mock.Call
+}
+
+// JobStates is a helper method to define mock.On call
+func (_e *MockUnitOfWork_Expecter) JobStates() *MockUnitOfWork_JobStates_Call {
+	return &MockUnitOfWork_JobStates_Call{Call: _e.mock.On("JobStates")}
+}
+
+func (_c *MockUnitOfWork_JobStates_Call) Run(run func()) *MockUnitOfWork_JobStates_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockUnitOfWork_JobStates_Call) Return(_a0 repository.JobStateRepository) *MockUnitOfWork_JobStates_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUnitOfWork_JobStates_Call) RunAndReturn(run func() repository.JobStateRepository) *MockUnitOfWork_JobStates_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Languages provides a mock function with no fields
 func (_m *MockUnitOfWork) Languages() repository.LanguageRepository {
 	ret := _m.Called()
@@ -304,6 +586,194 @@ func (_c *MockUnitOfWork_Languages_Call) RunAndReturn(run func() repository.Lang
 	return _c
 }
 
+// Outbox provides a mock function with no fields
+func (_m *MockUnitOfWork) Outbox() repository.OutboxRepository {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Outbox")
+	}
+
+	var r0 repository.OutboxRepository
+	if rf, ok := ret.Get(0).(func() repository.OutboxRepository); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repository.OutboxRepository)
+		}
+	}
+
+	return r0
+}
+
+// MockUnitOfWork_Outbox_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Outbox'
+type MockUnitOfWork_Outbox_Call struct {
+	*mock.Call
+}
+
+// Outbox is a helper method to define mock.On call
+func (_e *MockUnitOfWork_Expecter) Outbox() *MockUnitOfWork_Outbox_Call {
+	return &MockUnitOfWork_Outbox_Call{Call: _e.mock.On("Outbox")}
+}
+
+func (_c *MockUnitOfWork_Outbox_Call) Run(run func()) *MockUnitOfWork_Outbox_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockUnitOfWork_Outbox_Call) Return(_a0 repository.OutboxRepository) *MockUnitOfWork_Outbox_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUnitOfWork_Outbox_Call) RunAndReturn(run func() repository.OutboxRepository) *MockUnitOfWork_Outbox_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PriceRecords provides a mock function with no fields
+func (_m *MockUnitOfWork) PriceRecords() repository.PriceRecordRepository {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for PriceRecords")
+	}
+
+	var r0 repository.PriceRecordRepository
+	if rf, ok := ret.Get(0).(func() repository.PriceRecordRepository); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repository.PriceRecordRepository)
+		}
+	}
+
+	return r0
+}
+
+// MockUnitOfWork_PriceRecords_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PriceRecords'
+type MockUnitOfWork_PriceRecords_Call struct {
+	*mock.Call
+}
+
+// PriceRecords is a helper method to define mock.On call
+func (_e *MockUnitOfWork_Expecter) PriceRecords() *MockUnitOfWork_PriceRecords_Call {
+	return &MockUnitOfWork_PriceRecords_Call{Call: _e.mock.On("PriceRecords")}
+}
+
+func (_c *MockUnitOfWork_PriceRecords_Call) Run(run func()) *MockUnitOfWork_PriceRecords_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockUnitOfWork_PriceRecords_Call) Return(_a0 repository.PriceRecordRepository) *MockUnitOfWork_PriceRecords_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUnitOfWork_PriceRecords_Call) RunAndReturn(run func() repository.PriceRecordRepository) *MockUnitOfWork_PriceRecords_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WebhookDeliveries provides a mock function with no fields
+func (_m *MockUnitOfWork) WebhookDeliveries() repository.WebhookDeliveryRepository {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for WebhookDeliveries")
+	}
+
+	var r0 repository.WebhookDeliveryRepository
+	if rf, ok := ret.Get(0).(func() repository.WebhookDeliveryRepository); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repository.WebhookDeliveryRepository)
+		}
+	}
+
+	return r0
+}
+
+// MockUnitOfWork_WebhookDeliveries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WebhookDeliveries'
+type MockUnitOfWork_WebhookDeliveries_Call struct {
+	*mock.Call
+}
+
+// WebhookDeliveries is a helper method to define mock.On call
+func (_e *MockUnitOfWork_Expecter) WebhookDeliveries() *MockUnitOfWork_WebhookDeliveries_Call {
+	return &MockUnitOfWork_WebhookDeliveries_Call{Call: _e.mock.On("WebhookDeliveries")}
+}
+
+func (_c *MockUnitOfWork_WebhookDeliveries_Call) Run(run func()) *MockUnitOfWork_WebhookDeliveries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockUnitOfWork_WebhookDeliveries_Call) Return(_a0 repository.WebhookDeliveryRepository) *MockUnitOfWork_WebhookDeliveries_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUnitOfWork_WebhookDeliveries_Call) RunAndReturn(run func() repository.WebhookDeliveryRepository) *MockUnitOfWork_WebhookDeliveries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Webhooks provides a mock function with no fields
+func (_m *MockUnitOfWork) Webhooks() repository.WebhookRepository {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Webhooks")
+	}
+
+	var r0 repository.WebhookRepository
+	if rf, ok := ret.Get(0).(func() repository.WebhookRepository); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repository.WebhookRepository)
+		}
+	}
+
+	return r0
+}
+
+// MockUnitOfWork_Webhooks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Webhooks'
+type MockUnitOfWork_Webhooks_Call struct {
+	*mock.Call
+}
+
+// Webhooks is a helper method to define mock.On call
+func (_e *MockUnitOfWork_Expecter) Webhooks() *MockUnitOfWork_Webhooks_Call {
+	return &MockUnitOfWork_Webhooks_Call{Call: _e.mock.On("Webhooks")}
+}
+
+func (_c *MockUnitOfWork_Webhooks_Call) Run(run func()) *MockUnitOfWork_Webhooks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockUnitOfWork_Webhooks_Call) Return(_a0 repository.WebhookRepository) *MockUnitOfWork_Webhooks_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUnitOfWork_Webhooks_Call) RunAndReturn(run func() repository.WebhookRepository) *MockUnitOfWork_Webhooks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockUnitOfWork creates a new instance of MockUnitOfWork. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockUnitOfWork(t interface {
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"gorm.io/gorm"
+)
+
+type outboxRepository struct {
+	db          *gorm.DB
+	operationID string
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return newOutboxRepository(db, "")
+}
+
+// newOutboxRepository returns a repository whose events are tagged with
+// operationID, the operation of the audit entries written alongside them.
+func newOutboxRepository(db *gorm.DB, operationID string) *outboxRepository {
+	return &outboxRepository{db: db, operationID: operationID}
+}
+
+func (r *outboxRepository) Append(ctx context.Context, event *models.OutboxEvent) error {
+	event.OperationID = r.operationID
+	event.Actor = ActorFrom(ctx)
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	if event.NextAttemptAt.IsZero() {
+		event.NextAttemptAt = event.CreatedAt
+	}
+
+	if err := r.db.WithContext(ctx).Create(event).Error; err != nil {
+		return newRepositoryError("create", "outbox_event", event.Type, err)
+	}
+	return nil
+}
+
+func (r *outboxRepository) Due(ctx context.Context, now time.Time, limit int) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+
+	query := r.db.WithContext(ctx).
+		Where("dispatched_at IS NULL AND failed_at IS NULL AND next_attempt_at <= ?", now).
+		Order("id")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Find(&events).Error; err != nil {
+		return nil, newRepositoryError("list", "outbox_event", "due", err)
+	}
+	return events, nil
+}
+
+func (r *outboxRepository) UpdateDelivery(ctx context.Context, event *models.OutboxEvent) error {
+	key := strconv.Itoa(int(event.ID))
+
+	result := r.db.WithContext(ctx).
+		Model(&models.OutboxEvent{}).
+		Where("id = ?", event.ID).
+		Select("attempts", "last_error", "next_attempt_at", "dispatched_at", "failed_at").
+		Updates(event)
+	if result.Error != nil {
+		return newRepositoryError("update", "outbox_event", key, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return newRepositoryError("update", "outbox_event", key, customErr.ErrEntityNotFound)
+	}
+	return nil
+}
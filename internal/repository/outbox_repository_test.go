@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxRepository_Lifecycle(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	uow := NewUnitOfWork(db)
+	ctx := WithActor(context.Background(), "token:phone")
+
+	// Execute: events appended in a Do carry its operation
+	err := uow.Do(ctx, func(uow UnitOfWork) error {
+		if err := uow.Items().Create(ctx, &models.Item{ExtensionID: 1, TypeID: 1, LanguageID: 1}); err != nil {
+			return err
+		}
+		for _, eventType := range []string{"item.created", "item.updated"} {
+			if err := uow.Outbox().Append(ctx, &models.OutboxEvent{Type: eventType, Entity: AuditEntityItem, EntityID: 1, Payload: "{}"}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	// Assert
+	repo := NewOutboxRepository(db)
+	due, err := repo.Due(context.Background(), time.Now(), 0)
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	assert.Equal(t, "item.created", due[0].Type)
+	assert.Equal(t, "token:phone", due[0].Actor)
+	assert.True(t, due[0].Pending())
+
+	entries, err := NewAuditRepository(db).List(context.Background(), AuditFilter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, entries[0].OperationID, due[0].OperationID)
+	assert.Equal(t, due[0].OperationID, due[1].OperationID)
+
+	// Events scheduled later and delivered events are not due
+	later := time.Now().Add(time.Hour)
+	due[0].Attempts = 1
+	due[0].NextAttemptAt = later
+	due[0].LastError = "boom"
+	require.NoError(t, repo.UpdateDelivery(context.Background(), &due[0]))
+
+	now := time.Now()
+	due[1].Attempts = 1
+	due[1].DispatchedAt = &now
+	require.NoError(t, repo.UpdateDelivery(context.Background(), &due[1]))
+
+	remaining, err := repo.Due(context.Background(), time.Now(), 0)
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+
+	remaining, err = repo.Due(context.Background(), later, 1)
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, "boom", remaining[0].LastError)
+
+	err = repo.UpdateDelivery(context.Background(), &models.OutboxEvent{ID: 42})
+	assert.ErrorIs(t, err, customErr.ErrEntityNotFound)
+}
+
+func TestOutbox_RolledBackWithTheChange(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	// Execute
+	err := NewUnitOfWork(db).Do(context.Background(), func(uow UnitOfWork) error {
+		if err := uow.Outbox().Append(context.Background(), &models.OutboxEvent{Type: "item.created", Entity: AuditEntityItem, EntityID: 1, Payload: "{}"}); err != nil {
+			return err
+		}
+		return customErr.ErrConstraintViolation
+	})
+
+	// Assert
+	require.Error(t, err)
+	due, err := NewOutboxRepository(db).Due(context.Background(), time.Now(), 0)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"gorm.io/gorm"
+)
+
+type priceRecordRepository struct {
+	db    *gorm.DB
+	audit *auditor
+}
+
+func NewPriceRecordRepository(db *gorm.DB) PriceRecordRepository {
+	return newPriceRecordRepository(db, &auditor{db: db})
+}
+
+func newPriceRecordRepository(db *gorm.DB, audit *auditor) *priceRecordRepository {
+	return &priceRecordRepository{db: db, audit: audit}
+}
+
+// priceRecordAuditFields is the image of a price record recorded in the
+// audit trail.
+func priceRecordAuditFields(record *models.PriceRecord) models.AuditFields {
+	return models.AuditFields{
+		"item_id":   record.ItemID,
+		"price":     record.Price,
+		"currency":  record.Currency,
+		"source":    record.Source,
+		"quoted_at": record.QuotedAt,
+	}
+}
+
+func (r *priceRecordRepository) Create(ctx context.Context, record *models.PriceRecord) error {
+	if err := r.db.WithContext(ctx).Omit("Item").Create(record).Error; err != nil {
+		return newRepositoryError("create", "price_record", strconv.Itoa(int(record.ItemID)), err)
+	}
+	return r.audit.record(ctx, AuditEntityPriceRecord, record.ID, models.AuditCreate, nil, priceRecordAuditFields(record))
+}
+
+func (r *priceRecordRepository) Reassign(ctx context.Context, id, from, to uint) error {
+	key := strconv.Itoa(int(id))
+
+	var record models.PriceRecord
+	err := r.db.WithContext(ctx).Where("id = ? AND item_id = ?", id, from).First(&record).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return newRepositoryError("reassign", "price_record", key, customErr.ErrEntityNotFound)
+		}
+		return newRepositoryError("reassign", "price_record", key, err)
+	}
+	before := priceRecordAuditFields(&record)
+
+	if err := r.db.WithContext(ctx).Model(&models.PriceRecord{}).Where("id = ?", id).Update("item_id", to).Error; err != nil {
+		return newRepositoryError("reassign", "price_record", key, err)
+	}
+	record.ItemID = to
+	return r.audit.record(ctx, AuditEntityPriceRecord, id, models.AuditUpdate, before, priceRecordAuditFields(&record))
+}
+
+func (r *priceRecordRepository) DeleteByItems(ctx context.Context, itemIDs []uint) error {
+	var records []models.PriceRecord
+
+	if err := r.db.WithContext(ctx).Where("item_id IN ?", itemIDs).Order("id").Find(&records).Error; err != nil {
+		return newRepositoryError("delete", "price_record", "", err)
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	if err := r.db.WithContext(ctx).Where("item_id IN ?", itemIDs).Delete(&models.PriceRecord{}).Error; err != nil {
+		return newRepositoryError("delete", "price_record", "", err)
+	}
+	for i := range records {
+		if err := r.audit.record(ctx, AuditEntityPriceRecord, records[i].ID, models.AuditDelete, priceRecordAuditFields(&records[i]), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *priceRecordRepository) ListByItem(ctx context.Context, itemID uint, limit int) ([]models.PriceRecord, error) {
+	var records []models.PriceRecord
+
+	query := r.db.WithContext(ctx).
+		Where("item_id = ?", itemID).
+		Order("quoted_at DESC, id DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Find(&records).Error; err != nil {
+		return nil, newRepositoryError("list", "price_record", strconv.Itoa(int(itemID)), err)
+	}
+	return records, nil
+}
+
+// scoped restricts a query of price records to scope.
+func (r *priceRecordRepository) scoped(ctx context.Context, scope PriceScope) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&models.PriceRecord{})
+	if scope.ItemID != 0 {
+		return query.Where("price_records.item_id = ?", scope.ItemID)
+	}
+	return query.
+		Joins("JOIN items ON items.id = price_records.item_id").
+		Joins("JOIN extensions ON extensions.id = items.extension_id").
+		Joins("JOIN item_types ON item_types.id = items.type_id").
+		Joins("JOIN languages ON languages.id = items.language_id").
+		Where("extensions.code = ? AND item_types.name = ? AND languages.code = ?", scope.ExtensionCode, scope.ItemType, scope.LanguageCode)
+}
+
+func (r *priceRecordRepository) first(query *gorm.DB, op string, scope PriceScope) (*models.PriceRecord, error) {
+	var records []models.PriceRecord
+
+	if err := query.Limit(1).Find(&records).Error; err != nil {
+		return nil, newRepositoryError(op, "price_record", scope.String(), err)
+	}
+	if len(records) == 0 {
+		return nil, newRepositoryError(op, "price_record", scope.String(), customErr.ErrEntityNotFound)
+	}
+	return &records[0], nil
+}
+
+func (r *priceRecordRepository) Latest(ctx context.Context, scope PriceScope) (*models.PriceRecord, error) {
+	query := r.scoped(ctx, scope).
+		Select("price_records.*").
+		Order("price_records.quoted_at DESC, price_records.id DESC")
+	return r.first(query, "latest", scope)
+}
+
+func (r *priceRecordRepository) OldestSince(ctx context.Context, scope PriceScope, since time.Time) (*models.PriceRecord, error) {
+	query := r.scoped(ctx, scope).
+		Select("price_records.*").
+		Where("price_records.quoted_at >= ?", since).
+		Order("price_records.quoted_at, price_records.id")
+	return r.first(query, "oldest", scope)
+}
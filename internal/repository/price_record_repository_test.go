@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceRecordRepository_ListByItem(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	ctx := context.Background()
+	items := NewItemRepository(db)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, items.Create(ctx, &models.Item{ExtensionID: 1, TypeID: 1, LanguageID: 1}))
+	}
+
+	repo := NewPriceRecordRepository(db)
+	start := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	quotes := []struct {
+		itemID uint
+		price  float64
+		at     time.Time
+	}{
+		{1, 180, start},
+		{1, 195.5, start.Add(48 * time.Hour)},
+		{1, 190, start.Add(24 * time.Hour)},
+		{2, 54.5, start},
+	}
+	for _, q := range quotes {
+		require.NoError(t, repo.Create(ctx, &models.PriceRecord{ItemID: q.itemID, Price: q.price, Currency: "EUR", Source: "file", QuotedAt: q.at}))
+	}
+
+	// Execute
+	history, err := repo.ListByItem(ctx, 1, 0)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Equal(t, []float64{195.5, 190, 180}, []float64{history[0].Price, history[1].Price, history[2].Price}, "newest quote first")
+
+	latest, err := repo.ListByItem(ctx, 1, 1)
+	require.NoError(t, err)
+	require.Len(t, latest, 1)
+	assert.Equal(t, 195.5, latest[0].Price)
+
+	none, err := repo.ListByItem(ctx, 3, 0)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
+func TestPriceRecordRepository_LatestAndOldestSince(t *testing.T) {
+	// Setup: two DRI Displays (fr) and one ETB of the same extension
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	ctx := context.Background()
+	ext, err := NewExtensionRepository(db).FindByCode(ctx, "DRI")
+	require.NoError(t, err)
+	lang, err := NewLanguageRepository(db).FindByCode(ctx, "fr")
+	require.NoError(t, err)
+	display, err := NewItemTypeRepository(db).FindByName(ctx, "Display")
+	require.NoError(t, err)
+	etb, err := NewItemTypeRepository(db).FindByName(ctx, "ETB")
+	require.NoError(t, err)
+
+	items := NewItemRepository(db)
+	for _, typeID := range []uint{display.ID, display.ID, etb.ID} {
+		require.NoError(t, items.Create(ctx, &models.Item{ExtensionID: ext.ID, TypeID: typeID, LanguageID: lang.ID}))
+	}
+
+	repo := NewPriceRecordRepository(db)
+	start := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	quotes := []struct {
+		itemID uint
+		price  float64
+		at     time.Time
+	}{
+		{1, 180, start},
+		{2, 185, start.Add(24 * time.Hour)},
+		{1, 190, start.Add(48 * time.Hour)},
+		{3, 54.5, start.Add(72 * time.Hour)},
+	}
+	for _, q := range quotes {
+		require.NoError(t, repo.Create(ctx, &models.PriceRecord{ItemID: q.itemID, Price: q.price, Currency: "EUR", Source: "file", QuotedAt: q.at}))
+	}
+	product := PriceScope{ExtensionCode: "DRI", ItemType: "Display", LanguageCode: "fr"}
+
+	// Execute & Assert
+	latest, err := repo.Latest(ctx, product)
+	require.NoError(t, err)
+	assert.Equal(t, 190.0, latest.Price, "records of other products are ignored")
+
+	latest, err = repo.Latest(ctx, PriceScope{ItemID: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 185.0, latest.Price)
+
+	oldest, err := repo.OldestSince(ctx, product, start.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 185.0, oldest.Price)
+
+	oldest, err = repo.OldestSince(ctx, PriceScope{ItemID: 1}, start)
+	require.NoError(t, err)
+	assert.Equal(t, 180.0, oldest.Price)
+
+	_, err = repo.OldestSince(ctx, product, start.Add(96*time.Hour))
+	assert.ErrorIs(t, err, customErr.ErrEntityNotFound)
+
+	_, err = repo.Latest(ctx, PriceScope{ExtensionCode: "SVI", ItemType: "Display", LanguageCode: "fr"})
+	assert.ErrorIs(t, err, customErr.ErrEntityNotFound)
+}
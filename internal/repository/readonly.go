@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"sync"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"gorm.io/gorm"
+)
+
+// readOnlyGuard names the callbacks that refuse writes in read-only
+// transactions.
+const readOnlyGuard = "pkmc:read_only"
+
+// readOnlyPool is the connection of a read-only transaction. Wrapping it
+// marks the statements run through it, so that the guard callbacks can
+// refuse the writes, which also prevents GORM from opening a transaction
+// of its own on it.
+type readOnlyPool struct {
+	gorm.ConnPool
+}
+
+var guardMu sync.Mutex
+
+// guardReadOnly installs the callbacks refusing writes on the read-only
+// connections of db, once.
+func guardReadOnly(db *gorm.DB) {
+	guardMu.Lock()
+	defer guardMu.Unlock()
+
+	callbacks := db.Callback()
+	if callbacks.Create().Get(readOnlyGuard) != nil {
+		return
+	}
+	_ = callbacks.Create().Before("*").Register(readOnlyGuard, refuseReadOnlyWrite)
+	_ = callbacks.Update().Before("*").Register(readOnlyGuard, refuseReadOnlyWrite)
+	_ = callbacks.Delete().Before("*").Register(readOnlyGuard, refuseReadOnlyWrite)
+	_ = callbacks.Raw().Before("*").Register(readOnlyGuard, refuseReadOnlyWrite)
+}
+
+func refuseReadOnlyWrite(db *gorm.DB) {
+	if _, ok := db.Statement.ConnPool.(readOnlyPool); ok {
+		db.AddError(customErr.ErrReadOnly)
+	}
+}
+
+// readOnly returns a session of db whose writes fail with ErrReadOnly.
+func readOnly(ctx context.Context, db *gorm.DB, pool gorm.ConnPool) *gorm.DB {
+	session := db.Session(&gorm.Session{Context: ctx})
+	session.Statement.ConnPool = readOnlyPool{ConnPool: pool}
+	return session
+}
+
+// ReadTransaction runs fn in a read-only transaction of db: every query of
+// fn sees the database as it was at the first one, and the writes made
+// through tx fail with ErrReadOnly, as do those that bypass GORM through
+// the connection's PRAGMA query_only. The transaction takes no write lock,
+// so under WAL it neither waits for writers nor makes them wait.
+func ReadTransaction(ctx context.Context, db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	guardReadOnly(db)
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return customErr.NewUOWError("begin", err)
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return customErr.NewUOWError("begin", err)
+	}
+
+	// The connection goes back to the pool as it was taken, or not at all.
+	clean := false
+	defer func() {
+		if !clean {
+			conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		conn.Close()
+	}()
+
+	if _, err := conn.ExecContext(ctx, "PRAGMA query_only = ON"); err != nil {
+		return customErr.NewUOWError("begin", err)
+	}
+	// A plain BEGIN is deferred whatever the _txlock of the connection
+	// string: the snapshot is taken by the first read.
+	if _, err := conn.ExecContext(ctx, "BEGIN"); err != nil {
+		clean = reset(ctx, conn, "PRAGMA query_only = OFF")
+		return customErr.NewUOWError("begin", err)
+	}
+
+	err = fn(readOnly(ctx, db, conn))
+	clean = reset(ctx, conn, "ROLLBACK", "PRAGMA query_only = OFF")
+	return err
+}
+
+// reset runs statements restoring conn, even when ctx is cancelled, and
+// reports whether they all succeeded.
+func reset(ctx context.Context, conn *sql.Conn, statements ...string) bool {
+	ctx = context.WithoutCancel(ctx)
+	for _, statement := range statements {
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -1,32 +1,161 @@
+// Package repository stores the domain models in the database, one
+// repository per model, grouped in transactions by UnitOfWork.
+//
+// Writes to items, API tokens, webhooks, alert rules and price records are
+// recorded in the audit trail in the same transaction, those of one Do
+// sharing an operation ID. Outbox events, webhook deliveries, job states and
+// idempotency keys are bookkeeping derived from those writes and are not
+// audited, nor are the audit entries themselves.
 package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	customErr "github.com/R4yL-dev/pkmc/internal/errors"
 	"gorm.io/gorm"
 )
 
 type unitOfWork struct {
-	db  *gorm.DB
-	tx  *gorm.DB
-	ctx context.Context
+	db    *gorm.DB
+	tx    *gorm.DB
+	ctx   context.Context
+	audit *auditor
+	// savepoints counts the savepoints of tx, to name them.
+	savepoints *int
+	retry      busyRetry
+	// readOnly is set in the transactions of DoRead.
+	readOnly bool
 }
 
-func NewUnitOfWork(db *gorm.DB) UnitOfWork {
-	return &unitOfWork{db: db}
+// UnitOfWorkOption configures a unit of work.
+type UnitOfWorkOption func(*unitOfWork)
+
+// WithBusyRetry sets how long Do keeps retrying a transaction after it
+// first found the database locked by another connection, 0 to never retry,
+// and the delay before the first retry, doubled for each following one up
+// to max.
+func WithBusyRetry(budget, base, max time.Duration) UnitOfWorkOption {
+	return func(u *unitOfWork) {
+		if budget >= 0 {
+			u.retry.budget = budget
+		}
+		if base > 0 {
+			u.retry.base = base
+		}
+		if max >= u.retry.base {
+			u.retry.max = max
+		}
+	}
+}
+
+func NewUnitOfWork(db *gorm.DB, opts ...UnitOfWorkOption) UnitOfWork {
+	u := &unitOfWork{db: db, audit: &auditor{db: db}, retry: defaultBusyRetry}
+	for _, opt := range opts {
+		opt(u)
+	}
+	guardReadOnly(db)
+	return u
 }
 
+// Do runs fn in a transaction, committed when fn returns nil and rolled
+// back otherwise. Called on the unit of work of a transaction, Do joins
+// it within a savepoint instead: an error of fn only rolls back the
+// changes made since the savepoint, and the outer transaction decides
+// whether everything is committed. The changes of a nested Do belong to
+// the operation of the outer one in the audit trail.
+//
+// A transaction that fails because another connection holds the database
+// lock is rolled back and run again, fn included, after a jittered
+// exponential backoff, for as long as the retry budget, counted from the
+// first failure, and the deadline of ctx allow. fn must therefore only
+// have effects through uow. When the retries run out, Do returns a
+// UOWError caused by ErrDatabaseBusy.
+//
+// Do fails with ErrReadOnly within DoRead.
 func (u *unitOfWork) Do(ctx context.Context, fn func(uow UnitOfWork) error) error {
+	if u.readOnly {
+		return customErr.NewUOWError("begin", customErr.ErrReadOnly)
+	}
+	if u.tx != nil {
+		return u.nested(ctx, fn)
+	}
+
+	// The budget starts once the first attempt has failed: that attempt
+	// already waited out the busy timeout of the driver, which would
+	// otherwise use up a budget no longer than it.
+	var start time.Time
+	for attempt := 1; ; attempt++ {
+		err := u.transaction(ctx, fn)
+		if err == nil || !isBusy(err) {
+			return err
+		}
+		if attempt == 1 {
+			start = time.Now()
+		}
+
+		delay := u.retry.delay(attempt)
+		if !u.retry.allows(ctx, start, delay) {
+			return customErr.NewUOWBusyError(attempt, err)
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(delay):
+		}
+		// The deadline may have passed while waiting: giving up on the
+		// busy database is then the outcome, not a failed begin.
+		if ctx.Err() != nil {
+			return customErr.NewUOWBusyError(attempt, err)
+		}
+	}
+}
+
+// DoRead runs fn in a read-only transaction, for queries and reports that
+// must see a consistent snapshot of the database without taking its write
+// lock: under WAL it runs alongside writers. Writes through the
+// repositories of the unit of work passed to fn fail with ErrReadOnly, as
+// does Do. Called within Do, DoRead runs fn in the outer transaction, whose
+// writes it sees, still refusing writes of its own.
+func (u *unitOfWork) DoRead(ctx context.Context, fn func(uow UnitOfWork) error) error {
+	switch {
+	case u.readOnly:
+		return fn(u)
+	case u.tx != nil:
+		return fn(u.readOnlyView(readOnly(ctx, u.tx, u.tx.Statement.ConnPool)))
+	}
+
+	return ReadTransaction(ctx, u.db, func(tx *gorm.DB) error {
+		return fn(u.readOnlyView(tx))
+	})
+}
+
+// readOnlyView returns a unit of work refusing writes on tx, a read-only
+// session.
+func (u *unitOfWork) readOnlyView(tx *gorm.DB) *unitOfWork {
+	return &unitOfWork{
+		db:       u.db,
+		tx:       tx,
+		ctx:      tx.Statement.Context,
+		audit:    &auditor{db: tx, operationID: u.audit.operationID},
+		readOnly: true,
+	}
+}
+
+// transaction makes one attempt of the transaction of Do.
+func (u *unitOfWork) transaction(ctx context.Context, fn func(uow UnitOfWork) error) error {
 	tx := u.db.WithContext(ctx).Begin()
 	if tx.Error != nil {
 		return customErr.NewUOWError("begin", tx.Error)
 	}
 
 	txUoW := &unitOfWork{
-		db:  u.db,
-		tx:  tx,
-		ctx: ctx,
+		db:         u.db,
+		tx:         tx,
+		ctx:        ctx,
+		audit:      &auditor{db: tx, operationID: newOperationID()},
+		savepoints: new(int),
 	}
 
 	if err := fn(txUoW); err != nil {
@@ -40,13 +169,47 @@ func (u *unitOfWork) Do(ctx context.Context, fn func(uow UnitOfWork) error) erro
 	return nil
 }
 
+// nested runs fn within a savepoint of the transaction of u.
+func (u *unitOfWork) nested(ctx context.Context, fn func(uow UnitOfWork) error) error {
+	*u.savepoints++
+	name := fmt.Sprintf("uow_%d", *u.savepoints)
+
+	tx := u.tx.WithContext(ctx)
+	if err := tx.Exec("SAVEPOINT " + name).Error; err != nil {
+		return customErr.NewUOWError("savepoint", err)
+	}
+
+	nestedUoW := &unitOfWork{
+		db:         u.db,
+		tx:         tx,
+		ctx:        ctx,
+		audit:      u.audit,
+		savepoints: u.savepoints,
+	}
+
+	if err := fn(nestedUoW); err != nil {
+		// Rolling back to a savepoint keeps it open; release it so that
+		// the outer transaction carries on as before the call.
+		if rbErr := tx.Exec("ROLLBACK TO SAVEPOINT " + name).Error; rbErr != nil {
+			return customErr.NewUOWError("rollback_savepoint", errors.Join(err, rbErr))
+		}
+		tx.Exec("RELEASE SAVEPOINT " + name)
+		return err
+	}
+
+	if err := tx.Exec("RELEASE SAVEPOINT " + name).Error; err != nil {
+		return customErr.NewUOWError("release_savepoint", err)
+	}
+	return nil
+}
+
 func (u *unitOfWork) Items() ItemRepository {
 	db := u.db
 
 	if u.tx != nil {
 		db = u.tx
 	}
-	return NewItemRepository(db)
+	return newItemRepository(db, u.audit)
 }
 
 func (u *unitOfWork) Extensions() ExtensionRepository {
@@ -84,3 +247,84 @@ func (u *unitOfWork) ItemTypes() ItemTypeRepository {
 	}
 	return NewItemTypeRepository(db)
 }
+
+func (u *unitOfWork) APITokens() APITokenRepository {
+	db := u.db
+
+	if u.tx != nil {
+		db = u.tx
+	}
+	return newAPITokenRepository(db, u.audit)
+}
+
+func (u *unitOfWork) Audit() AuditRepository {
+	db := u.db
+
+	if u.tx != nil {
+		db = u.tx
+	}
+	return NewAuditRepository(db)
+}
+
+func (u *unitOfWork) Outbox() OutboxRepository {
+	db := u.db
+
+	if u.tx != nil {
+		db = u.tx
+	}
+	return newOutboxRepository(db, u.audit.operationID)
+}
+
+func (u *unitOfWork) Webhooks() WebhookRepository {
+	db := u.db
+
+	if u.tx != nil {
+		db = u.tx
+	}
+	return newWebhookRepository(db, u.audit)
+}
+
+func (u *unitOfWork) WebhookDeliveries() WebhookDeliveryRepository {
+	db := u.db
+
+	if u.tx != nil {
+		db = u.tx
+	}
+	return NewWebhookDeliveryRepository(db)
+}
+
+func (u *unitOfWork) PriceRecords() PriceRecordRepository {
+	db := u.db
+
+	if u.tx != nil {
+		db = u.tx
+	}
+	return newPriceRecordRepository(db, u.audit)
+}
+
+func (u *unitOfWork) AlertRules() AlertRuleRepository {
+	db := u.db
+
+	if u.tx != nil {
+		db = u.tx
+	}
+	return newAlertRuleRepository(db, u.audit)
+}
+
+func (u *unitOfWork) JobStates() JobStateRepository {
+	db := u.db
+
+	if u.tx != nil {
+		db = u.tx
+	}
+	return NewJobStateRepository(db)
+}
+
+func (u *unitOfWork) IdempotencyKeys() IdempotencyKeyRepository {
+	db := u.db
+
+	if u.tx != nil {
+		db = u.tx
+	}
+	return NewIdempotencyKeyRepository(db)
+}
@@ -3,13 +3,22 @@ package repository
 import (
 	"context"
 	"errors"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/R4yL-dev/pkmc/internal/config"
+	"github.com/R4yL-dev/pkmc/internal/database"
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
 	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/seed"
 	"github.com/R4yL-dev/pkmc/internal/testutil"
+	"github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 func TestUnitOfWork_DoCommit(t *testing.T) {
@@ -292,11 +301,510 @@ func TestUnitOfWork_NestedTransactionBehavior(t *testing.T) {
 	})
 
 	// Assert
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "FOREIGN KEY constraint failed")
+	assert.ErrorIs(t, err, customErr.ErrForeignKeyViolation)
 
 	// Verify first item was also rolled back
 	var count int64
 	db.Model(&models.Item{}).Count(&count)
 	assert.Zero(t, count, "All operations should be rolled back")
 }
+
+func TestUnitOfWork_NestedDo(t *testing.T) {
+	errInner := errors.New("inner failed")
+	errOuter := errors.New("outer failed")
+
+	tests := []struct {
+		name          string
+		innerErr      error
+		outerErr      error
+		expectedError error
+		// expectedPrices lists the prices of the committed items.
+		expectedPrices []float64
+	}{
+		{
+			name:           "inner and outer succeed",
+			expectedPrices: []float64{1, 2, 3},
+		},
+		{
+			name:           "inner fails, outer goes on",
+			innerErr:       errInner,
+			expectedPrices: []float64{1, 3},
+		},
+		{
+			name:          "inner succeeds, outer fails",
+			outerErr:      errOuter,
+			expectedError: errOuter,
+		},
+		{
+			name:          "inner fails, outer fails with it",
+			innerErr:      errInner,
+			outerErr:      errInner,
+			expectedError: errInner,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			db := testutil.SetupTestDB(t)
+			defer testutil.CleanupTestDB(t, db)
+
+			uow := NewUnitOfWork(db)
+			ctx := context.Background()
+			create := func(uow UnitOfWork, price float64) error {
+				return uow.Items().Create(ctx, &models.Item{ExtensionID: 1, TypeID: 1, LanguageID: 1, Price: testutil.FloatPtr(price)})
+			}
+
+			// Execute
+			err := uow.Do(ctx, func(outer UnitOfWork) error {
+				if err := create(outer, 1); err != nil {
+					return err
+				}
+				innerErr := outer.Do(ctx, func(inner UnitOfWork) error {
+					if err := create(inner, 2); err != nil {
+						return err
+					}
+					return tt.innerErr
+				})
+				if !errors.Is(innerErr, tt.innerErr) {
+					return errors.New("unexpected inner error")
+				}
+				if err := create(outer, 3); err != nil {
+					return err
+				}
+				return tt.outerErr
+			})
+
+			// Assert
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+
+			var prices []float64
+			require.NoError(t, db.Model(&models.Item{}).Order("price").Pluck("price", &prices).Error)
+			if len(tt.expectedPrices) == 0 {
+				assert.Empty(t, prices)
+			} else {
+				assert.Equal(t, tt.expectedPrices, prices)
+			}
+		})
+	}
+}
+
+func TestUnitOfWork_NestedDo_Deep(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	uow := NewUnitOfWork(db)
+	ctx := context.Background()
+	create := func(uow UnitOfWork, price float64) error {
+		return uow.Items().Create(ctx, &models.Item{ExtensionID: 1, TypeID: 1, LanguageID: 1, Price: testutil.FloatPtr(price)})
+	}
+
+	// Execute: the second level fails after its own nested call succeeded,
+	// and a sibling call after it succeeds
+	err := uow.Do(ctx, func(level1 UnitOfWork) error {
+		require.NoError(t, create(level1, 1))
+
+		err := level1.Do(ctx, func(level2 UnitOfWork) error {
+			require.NoError(t, create(level2, 2))
+			require.NoError(t, level2.Do(ctx, func(level3 UnitOfWork) error {
+				return create(level3, 3)
+			}))
+			return errors.New("level 2 failed")
+		})
+		assert.EqualError(t, err, "level 2 failed")
+
+		return level1.Do(ctx, func(sibling UnitOfWork) error {
+			return create(sibling, 4)
+		})
+	})
+
+	// Assert
+	require.NoError(t, err)
+	var prices []float64
+	require.NoError(t, db.Model(&models.Item{}).Order("price").Pluck("price", &prices).Error)
+	assert.Equal(t, []float64{1, 4}, prices, "level 3 is rolled back with level 2")
+
+	entries, err := NewAuditRepository(db).List(ctx, AuditFilter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, entries[0].OperationID, entries[1].OperationID, "nested changes belong to the outer operation")
+}
+
+func TestUnitOfWork_Do_RetriesWhenBusy(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	uow := NewUnitOfWork(db, WithBusyRetry(time.Second, time.Millisecond, 5*time.Millisecond))
+	ctx := context.Background()
+
+	// Execute: the first two attempts find the database locked
+	attempts := 0
+	err := uow.Do(ctx, func(uow UnitOfWork) error {
+		attempts++
+		if err := uow.Items().Create(ctx, testutil.CreateTestItem(1, 1, 1)); err != nil {
+			return err
+		}
+		if attempts < 3 {
+			return customErr.NewRepositoryError("create", "item", "", sqlite3.Error{Code: sqlite3.ErrBusy})
+		}
+		return nil
+	})
+
+	// Assert: the failed attempts were rolled back
+	require.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+	var count int64
+	db.Model(&models.Item{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestUnitOfWork_Do_GivesUpWhenBusy(t *testing.T) {
+	busy := errors.New("database is locked")
+
+	tests := []struct {
+		name    string
+		budget  time.Duration
+		timeout time.Duration
+	}{
+		{name: "retry budget spent", budget: 50 * time.Millisecond, timeout: time.Minute},
+		{name: "context deadline", budget: time.Minute, timeout: 50 * time.Millisecond},
+		{name: "retries disabled", budget: 0, timeout: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			db := testutil.SetupTestDB(t)
+			defer testutil.CleanupTestDB(t, db)
+
+			uow := NewUnitOfWork(db, WithBusyRetry(tt.budget, time.Millisecond, 10*time.Millisecond))
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+
+			// Execute
+			attempts := 0
+			start := time.Now()
+			err := uow.Do(ctx, func(uow UnitOfWork) error {
+				attempts++
+				return busy
+			})
+
+			// Assert
+			assert.Less(t, time.Since(start), time.Second)
+			var uowErr *customErr.UOWError
+			require.ErrorAs(t, err, &uowErr)
+			assert.Equal(t, attempts, uowErr.Attempts)
+			assert.ErrorIs(t, err, customErr.ErrDatabaseBusy)
+			assert.ErrorIs(t, err, busy)
+			if tt.budget == 0 {
+				assert.Equal(t, 1, attempts)
+			} else {
+				assert.Greater(t, attempts, 1)
+			}
+		})
+	}
+}
+
+func TestUnitOfWork_Do_DoesNotRetryOtherErrors(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	uow := NewUnitOfWork(db)
+	errFailed := errors.New("failed")
+
+	// Execute
+	attempts := 0
+	err := uow.Do(context.Background(), func(uow UnitOfWork) error {
+		attempts++
+		return errFailed
+	})
+
+	// Assert
+	assert.Equal(t, errFailed, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestUnitOfWork_Do_WaitsForConcurrentWriter(t *testing.T) {
+	// Setup: two connection pools on one file, failing at once on a lock
+	path := filepath.Join(t.TempDir(), "pkmc.db") + "?_txlock=immediate&_busy_timeout=0"
+	holder, err := gorm.Open(sqlite.Open(path), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, holder.AutoMigrate(models.GetModels()...))
+	writer, err := gorm.Open(sqlite.Open(path), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	defer func() {
+		for _, db := range []*gorm.DB{holder, writer} {
+			sqlDB, _ := db.DB()
+			sqlDB.Close()
+		}
+	}()
+
+	tx := holder.Begin()
+	require.NoError(t, tx.Error)
+	require.NoError(t, tx.Create(&models.Block{Code: "EV", Name: "Écarlate et Violet"}).Error)
+	time.AfterFunc(100*time.Millisecond, func() { tx.Commit() })
+
+	// Execute
+	uow := NewUnitOfWork(writer, WithBusyRetry(5*time.Second, 5*time.Millisecond, 20*time.Millisecond))
+	start := time.Now()
+	err = uow.Do(context.Background(), func(uow UnitOfWork) error {
+		return uow.Items().Create(context.Background(), testutil.CreateTestItem(1, 1, 1))
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond, "the writer waited for the lock")
+	var blocks, items int64
+	writer.Model(&models.Block{}).Count(&blocks)
+	writer.Model(&models.Item{}).Count(&items)
+	assert.Equal(t, int64(1), blocks)
+	assert.Equal(t, int64(1), items)
+}
+
+func TestUnitOfWork_Do_RetriesPastBusyTimeout(t *testing.T) {
+	if testing.Short() {
+		t.Skip("waits out the default busy timeout")
+	}
+
+	// Setup: the database and retry settings of the application, with a
+	// lock held a little longer than the busy timeout
+	cfg := config.Get()
+	settings := database.DefaultSettings()
+	settings.BusyTimeout = cfg.GetDBBusyTimeout()
+
+	path := filepath.Join(t.TempDir(), "pkmc.db")
+	holder, err := database.InitDB(path, settings)
+	require.NoError(t, err)
+	defer database.CloseDB(holder)
+	require.NoError(t, holder.AutoMigrate(models.GetModels()...))
+	seed.Seed(holder)
+	writer, err := database.InitDB(path, settings)
+	require.NoError(t, err)
+	defer database.CloseDB(writer)
+
+	tx := holder.Begin()
+	require.NoError(t, tx.Error)
+	time.AfterFunc(settings.BusyTimeout+500*time.Millisecond, func() { tx.Commit() })
+
+	// Execute
+	uow := NewUnitOfWork(writer, WithBusyRetry(cfg.GetBusyRetry(), 0, 0))
+	start := time.Now()
+	err = uow.Do(context.Background(), func(uow UnitOfWork) error {
+		return uow.Items().Create(context.Background(), testutil.CreateTestItem(1, 1, 1))
+	})
+
+	// Assert: the first attempt waited out the busy timeout, a retry got the
+	// lock
+	require.NoError(t, err)
+	assert.Greater(t, time.Since(start), settings.BusyTimeout)
+	var items int64
+	writer.Model(&models.Item{}).Count(&items)
+	assert.Equal(t, int64(1), items)
+}
+
+func TestUnitOfWork_DoRead_RefusesWrites(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(ctx context.Context, uow UnitOfWork, item *models.Item) error
+	}{
+		{
+			name: "create",
+			write: func(ctx context.Context, uow UnitOfWork, item *models.Item) error {
+				return uow.Items().Create(ctx, testutil.CreateTestItem(item.ExtensionID, item.TypeID, item.LanguageID))
+			},
+		},
+		{
+			name: "update",
+			write: func(ctx context.Context, uow UnitOfWork, item *models.Item) error {
+				item.Price = testutil.FloatPtr(1)
+				return uow.Items().Update(ctx, item)
+			},
+		},
+		{
+			name: "delete",
+			write: func(ctx context.Context, uow UnitOfWork, item *models.Item) error {
+				return uow.Items().Delete(ctx, item.ID)
+			},
+		},
+		{
+			name: "upsert",
+			write: func(ctx context.Context, uow UnitOfWork, item *models.Item) error {
+				_, err := uow.JobStates().Ensure(ctx, "backup")
+				return err
+			},
+		},
+		{
+			name: "nested Do",
+			write: func(ctx context.Context, uow UnitOfWork, item *models.Item) error {
+				return uow.Do(ctx, func(uow UnitOfWork) error { return nil })
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			db := testutil.SetupTestDB(t)
+			defer testutil.CleanupTestDB(t, db)
+
+			uow := NewUnitOfWork(db)
+			ctx := context.Background()
+			item := testutil.CreateTestItem(1, 1, 1)
+			require.NoError(t, db.Create(item).Error)
+
+			// Execute
+			err := uow.DoRead(ctx, func(uow UnitOfWork) error {
+				stored, err := uow.Items().FindByID(ctx, item.ID)
+				if err != nil {
+					return err
+				}
+				return tt.write(ctx, uow, stored)
+			})
+
+			// Assert
+			assert.ErrorIs(t, err, customErr.ErrReadOnly)
+			var stored []models.Item
+			require.NoError(t, db.Find(&stored).Error)
+			require.Len(t, stored, 1)
+			assert.Equal(t, *item.Price, *stored[0].Price)
+			var states int64
+			db.Model(&models.JobState{}).Count(&states)
+			assert.Zero(t, states)
+		})
+	}
+}
+
+func TestUnitOfWork_DoRead_ReleasesConnection(t *testing.T) {
+	// Setup: one connection, so the writes below reuse the one of DoRead
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	uow := NewUnitOfWork(db)
+	ctx := context.Background()
+
+	// Execute
+	readErr := uow.DoRead(ctx, func(uow UnitOfWork) error {
+		return uow.Items().Create(ctx, testutil.CreateTestItem(1, 1, 1))
+	})
+	err = uow.Do(ctx, func(uow UnitOfWork) error {
+		return uow.Items().Create(ctx, testutil.CreateTestItem(1, 1, 1))
+	})
+
+	// Assert
+	assert.ErrorIs(t, readErr, customErr.ErrReadOnly)
+	assert.NoError(t, err)
+	assert.NoError(t, db.Exec("DELETE FROM items").Error, "the connection accepts raw writes again")
+}
+
+func TestUnitOfWork_DoRead_WithinDo(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	uow := NewUnitOfWork(db)
+	ctx := context.Background()
+
+	// Execute
+	var seen, seenDeeper []models.Item
+	var readErr error
+	err := uow.Do(ctx, func(outer UnitOfWork) error {
+		if err := outer.Items().Create(ctx, testutil.CreateTestItem(1, 1, 1)); err != nil {
+			return err
+		}
+		readErr = outer.DoRead(ctx, func(reader UnitOfWork) error {
+			var err error
+			if seen, err = reader.Items().List(ctx, ItemFilter{}); err != nil {
+				return err
+			}
+			err = reader.DoRead(ctx, func(reader UnitOfWork) error {
+				var err error
+				seenDeeper, err = reader.Items().List(ctx, ItemFilter{})
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			return reader.Items().Create(ctx, testutil.CreateTestItem(1, 1, 1))
+		})
+		return outer.Items().Create(ctx, testutil.CreateTestItem(1, 1, 1))
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.ErrorIs(t, readErr, customErr.ErrReadOnly)
+	assert.Len(t, seen, 1, "DoRead sees the writes of the outer transaction")
+	assert.Len(t, seenDeeper, 1)
+	var count int64
+	db.Model(&models.Item{}).Count(&count)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestUnitOfWork_DoRead_ReadsSnapshotAlongsideWriter(t *testing.T) {
+	// Setup: a WAL database failing at once on a lock
+	path := filepath.Join(t.TempDir(), "pkmc.db") + "?_journal_mode=WAL&_txlock=immediate&_busy_timeout=0"
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(models.GetModels()...))
+	defer func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}()
+
+	uow := NewUnitOfWork(db, WithBusyRetry(0, 0, 0))
+	ctx := context.Background()
+
+	// A writer holds the lock with an uncommitted item.
+	written := make(chan struct{})
+	commit := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- uow.Do(ctx, func(uow UnitOfWork) error {
+			if err := uow.Items().Create(ctx, testutil.CreateTestItem(1, 1, 1)); err != nil {
+				close(written)
+				return err
+			}
+			close(written)
+			<-commit
+			return nil
+		})
+	}()
+	<-written
+
+	// Execute
+	var before, after []models.Item
+	var writeErr error
+	err = uow.DoRead(ctx, func(uow UnitOfWork) error {
+		var err error
+		if before, err = uow.Items().List(ctx, ItemFilter{}); err != nil {
+			return err
+		}
+		close(commit)
+		if writeErr = <-done; writeErr != nil {
+			return writeErr
+		}
+		after, err = uow.Items().List(ctx, ItemFilter{})
+		return err
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.NoError(t, writeErr, "the writer committed while the reader was open")
+	assert.Empty(t, before, "the reader does not see uncommitted writes")
+	assert.Empty(t, after, "the reader keeps its snapshot")
+	err = uow.DoRead(ctx, func(uow UnitOfWork) error {
+		after, err = uow.Items().List(ctx, ItemFilter{})
+		return err
+	})
+	require.NoError(t, err)
+	assert.Len(t, after, 1)
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type webhookRepository struct {
+	db    *gorm.DB
+	audit *auditor
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return newWebhookRepository(db, &auditor{db: db})
+}
+
+func newWebhookRepository(db *gorm.DB, audit *auditor) *webhookRepository {
+	return &webhookRepository{db: db, audit: audit}
+}
+
+// webhookAuditFields is the image of a webhook recorded in the audit
+// trail. The signing secret is left out.
+func webhookAuditFields(webhook *models.Webhook) models.AuditFields {
+	return models.AuditFields{
+		"name":            webhook.Name,
+		"url":             webhook.URL,
+		"events":          webhook.Events,
+		"price_threshold": webhook.PriceThreshold,
+	}
+}
+
+func (r *webhookRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	if err := r.db.WithContext(ctx).Create(webhook).Error; err != nil {
+		return newRepositoryError("create", "webhook", webhook.Name, err)
+	}
+	return r.audit.record(ctx, AuditEntityWebhook, webhook.ID, models.AuditCreate, nil, webhookAuditFields(webhook))
+}
+
+func (r *webhookRepository) FindByID(ctx context.Context, id uint) (*models.Webhook, error) {
+	var webhook models.Webhook
+
+	err := r.db.WithContext(ctx).First(&webhook, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, newRepositoryError("find", "webhook", strconv.Itoa(int(id)), customErr.ErrEntityNotFound)
+		}
+		return nil, newRepositoryError("find", "webhook", strconv.Itoa(int(id)), err)
+	}
+	return &webhook, nil
+}
+
+func (r *webhookRepository) FindAll(ctx context.Context) ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+
+	if err := r.db.WithContext(ctx).Order("id").Find(&webhooks).Error; err != nil {
+		return nil, newRepositoryError("list", "webhook", "all", err)
+	}
+	return webhooks, nil
+}
+
+// Delete removes a webhook. Its delivery log is kept, and its pending
+// deliveries are no longer due.
+func (r *webhookRepository) Delete(ctx context.Context, id uint) error {
+	before, err := r.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	result := r.db.WithContext(ctx).Delete(&models.Webhook{}, id)
+	if result.Error != nil {
+		return newRepositoryError("delete", "webhook", strconv.Itoa(int(id)), result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return newRepositoryError("delete", "webhook", strconv.Itoa(int(id)), customErr.ErrEntityNotFound)
+	}
+	return r.audit.record(ctx, AuditEntityWebhook, id, models.AuditDelete, webhookAuditFields(before), nil)
+}
+
+type webhookDeliveryRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookDeliveryRepository(db *gorm.DB) WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{db: db}
+}
+
+func (r *webhookDeliveryRepository) Enqueue(ctx context.Context, delivery *models.WebhookDelivery) error {
+	if delivery.Status == "" {
+		delivery.Status = models.DeliveryPending
+	}
+	if delivery.NextAttemptAt.IsZero() {
+		delivery.NextAttemptAt = time.Now()
+	}
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(delivery).Error
+	if err != nil {
+		key := strconv.Itoa(int(delivery.WebhookID)) + "/" + strconv.Itoa(int(delivery.EventID))
+		return newRepositoryError("create", "webhook_delivery", key, err)
+	}
+	return nil
+}
+
+func (r *webhookDeliveryRepository) Due(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+
+	query := r.db.WithContext(ctx).
+		InnerJoins("Webhook").
+		Where("webhook_deliveries.status = ? AND webhook_deliveries.next_attempt_at <= ?", models.DeliveryPending, now).
+		Order("webhook_deliveries.id")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Find(&deliveries).Error; err != nil {
+		return nil, newRepositoryError("list", "webhook_delivery", "due", err)
+	}
+	return deliveries, nil
+}
+
+func (r *webhookDeliveryRepository) UpdateAttempt(ctx context.Context, delivery *models.WebhookDelivery) error {
+	key := strconv.Itoa(int(delivery.ID))
+
+	result := r.db.WithContext(ctx).
+		Model(&models.WebhookDelivery{}).
+		Where("id = ?", delivery.ID).
+		Select("status", "attempts", "response_status", "last_error", "next_attempt_at", "delivered_at", "updated_at").
+		Updates(&models.WebhookDelivery{
+			Status:         delivery.Status,
+			Attempts:       delivery.Attempts,
+			ResponseStatus: delivery.ResponseStatus,
+			LastError:      delivery.LastError,
+			NextAttemptAt:  delivery.NextAttemptAt,
+			DeliveredAt:    delivery.DeliveredAt,
+			UpdatedAt:      time.Now(),
+		})
+	if result.Error != nil {
+		return newRepositoryError("update", "webhook_delivery", key, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return newRepositoryError("update", "webhook_delivery", key, customErr.ErrEntityNotFound)
+	}
+	return nil
+}
+
+func (r *webhookDeliveryRepository) List(ctx context.Context, filter WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+
+	query := r.db.WithContext(ctx).Order("id DESC")
+	if filter.WebhookID != 0 {
+		query = query.Where("webhook_id = ?", filter.WebhookID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	if err := query.Find(&deliveries).Error; err != nil {
+		return nil, newRepositoryError("list", "webhook_delivery", "filter", err)
+	}
+	return deliveries, nil
+}
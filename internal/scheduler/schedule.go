@@ -0,0 +1,224 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule tells when a job runs.
+type Schedule interface {
+	// Next returns the first run time strictly after after, or the zero
+	// time when there is none.
+	Next(after time.Time) time.Time
+	// String returns the specification the schedule was parsed from.
+	String() string
+}
+
+// Parse reads a schedule specification:
+//
+//   - "@every DURATION" runs at a fixed interval, e.g. "@every 6h".
+//   - "@hourly", "@daily" (or "@midnight"), "@weekly" and "@monthly" are
+//     shorthands for the cron expressions of the same name.
+//   - Anything else is a cron expression of five fields, minute, hour, day
+//     of month, month and day of week (0 or 7 is Sunday), each made of
+//     comma-separated values, ranges ("1-5") and steps ("*/15", "0-30/10"),
+//     evaluated in the local time zone.
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+
+	if rest, ok := strings.CutPrefix(spec, "@every"); ok {
+		interval, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid schedule '%s': expected a positive duration such as @every 6h", spec)
+		}
+		return every{interval: interval, spec: spec}, nil
+	}
+
+	expr := spec
+	switch spec {
+	case "@hourly":
+		expr = "0 * * * *"
+	case "@daily", "@midnight":
+		expr = "0 0 * * *"
+	case "@weekly":
+		expr = "0 0 * * 0"
+	case "@monthly":
+		expr = "0 0 1 * *"
+	}
+
+	schedule, err := parseCron(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule '%s': %w", spec, err)
+	}
+	schedule.spec = spec
+	return schedule, nil
+}
+
+// Every returns a schedule running every interval.
+func Every(interval time.Duration) Schedule {
+	return every{interval: interval, spec: "@every " + interval.String()}
+}
+
+type every struct {
+	interval time.Duration
+	spec     string
+}
+
+func (e every) Next(after time.Time) time.Time {
+	return after.Add(e.interval)
+}
+
+func (e every) String() string {
+	return e.spec
+}
+
+// field describes one field of a cron expression.
+type field struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []field{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// cron is a parsed cron expression: one bit per allowed value of each
+// field.
+type cron struct {
+	minute, hour, dom, month, dow uint64
+	// anyDay is set when the day of month or the day of week starts with
+	// "*": a day then matches when both fields match, and otherwise when
+	// either does, as in crontab(5).
+	anyDay bool
+	spec   string
+}
+
+// maxSearch bounds the search of the next run, so an expression that
+// never matches, such as February 30th, does not loop forever.
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+func parseCron(expr string) (*cron, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("expected %d fields (minute hour day-of-month month day-of-week), got %d", len(cronFields), len(parts))
+	}
+
+	sets := make([]uint64, len(parts))
+	for i, part := range parts {
+		set, err := parseField(part, cronFields[i])
+		if err != nil {
+			return nil, err
+		}
+		sets[i] = set
+	}
+
+	c := &cron{
+		minute: sets[0],
+		hour:   sets[1],
+		dom:    sets[2],
+		month:  sets[3],
+		// Sunday is both 0 and 7.
+		dow:    (sets[4] | sets[4]>>7) & 0x7f,
+		anyDay: strings.HasPrefix(parts[2], "*") || strings.HasPrefix(parts[4], "*"),
+	}
+	return c, nil
+}
+
+func parseField(part string, f field) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(part, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step '%s' in %s field", stepPart, f.name)
+			}
+			step = n
+		}
+
+		low, high := f.min, f.max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			from, to, _ := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = fieldValue(from, f); err != nil {
+				return 0, err
+			}
+			if high, err = fieldValue(to, f); err != nil {
+				return 0, err
+			}
+			if low > high {
+				return 0, fmt.Errorf("invalid range '%s' in %s field", rangePart, f.name)
+			}
+		default:
+			value, err := fieldValue(rangePart, f)
+			if err != nil {
+				return 0, err
+			}
+			low = value
+			if !hasStep {
+				high = value
+			}
+		}
+
+		for v := low; v <= high; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+func fieldValue(s string, f field) (int, error) {
+	value, err := strconv.Atoi(s)
+	if err != nil || value < f.min || value > f.max {
+		return 0, fmt.Errorf("invalid value '%s' in %s field: expected %d to %d", s, f.name, f.min, f.max)
+	}
+	return value, nil
+}
+
+func (c *cron) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxSearch)
+
+	for t.Before(limit) {
+		switch {
+		case !has(c.month, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !has(c.hour, t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !has(c.minute, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (c *cron) matchesDay(t time.Time) bool {
+	dom := has(c.dom, t.Day())
+	dow := has(c.dow, int(t.Weekday()))
+	if c.anyDay {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+func (c *cron) String() string {
+	return c.spec
+}
+
+func has(set uint64, value int) bool {
+	return set&(1<<uint(value)) != 0
+}
@@ -0,0 +1,115 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Next(t *testing.T) {
+	// Monday 2026-10-19 10:17:30 UTC
+	from := time.Date(2026, 10, 19, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		spec     string
+		expected []time.Time
+	}{
+		{
+			name:     "interval",
+			spec:     "@every 6h",
+			expected: []time.Time{from.Add(6 * time.Hour), from.Add(12 * time.Hour)},
+		},
+		{
+			name:     "daily",
+			spec:     "@daily",
+			expected: []time.Time{time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC), time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:     "hourly",
+			spec:     "@hourly",
+			expected: []time.Time{time.Date(2026, 10, 19, 11, 0, 0, 0, time.UTC), time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:     "every quarter hour",
+			spec:     "*/15 * * * *",
+			expected: []time.Time{time.Date(2026, 10, 19, 10, 30, 0, 0, time.UTC), time.Date(2026, 10, 19, 10, 45, 0, 0, time.UTC)},
+		},
+		{
+			name:     "weekdays at 3:30",
+			spec:     "30 3 * * 1-5",
+			expected: []time.Time{time.Date(2026, 10, 20, 3, 30, 0, 0, time.UTC), time.Date(2026, 10, 21, 3, 30, 0, 0, time.UTC)},
+		},
+		{
+			name:     "sunday as 7",
+			spec:     "0 12 * * 7",
+			expected: []time.Time{time.Date(2026, 10, 25, 12, 0, 0, 0, time.UTC), time.Date(2026, 11, 1, 12, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:     "day of month or day of week",
+			spec:     "0 0 1 * 3",
+			expected: []time.Time{time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC), time.Date(2026, 10, 28, 0, 0, 0, 0, time.UTC), time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:     "list and month",
+			spec:     "0 8,20 29 2 *",
+			expected: []time.Time{time.Date(2028, 2, 29, 8, 0, 0, 0, time.UTC), time.Date(2028, 2, 29, 20, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:     "never",
+			spec:     "0 0 30 2 *",
+			expected: []time.Time{{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			schedule, err := Parse(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.spec, schedule.String())
+
+			// Execute
+			var got []time.Time
+			at := from
+			for range tt.expected {
+				at = schedule.Next(at)
+				got = append(got, at)
+			}
+
+			// Assert
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		spec          string
+		errorContains string
+	}{
+		{"@every", "expected a positive duration"},
+		{"@every -1h", "expected a positive duration"},
+		{"@yearly", "expected 5 fields"},
+		{"* * * *", "expected 5 fields"},
+		{"60 * * * *", "invalid value '60' in minute field"},
+		{"* * 0 * *", "invalid value '0' in day of month field"},
+		{"* 5-2 * * *", "invalid range '5-2' in hour field"},
+		{"*/0 * * * *", "invalid step '0' in minute field"},
+		{"* * * jan *", "invalid value 'jan' in month field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			// Execute
+			_, err := Parse(tt.spec)
+
+			// Assert
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid schedule '"+tt.spec+"'")
+			assert.Contains(t, err.Error(), tt.errorContains)
+		})
+	}
+}
@@ -0,0 +1,340 @@
+// Package scheduler runs periodic jobs, such as price refreshes and
+// backups, while the application is up.
+//
+// The state of every job (last run, outcome, next run) is kept in the
+// database, so a restart resumes the schedule where it was and a run that
+// was missed while the application was down happens once at startup. A job
+// is never run twice at the same time: a run that is due while the
+// previous one is still going is skipped, including when the previous run
+// belongs to another process sharing the database.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+)
+
+const (
+	defaultTick       = time.Second
+	defaultStaleAfter = time.Hour
+)
+
+// Job is a task run on a schedule. Run gets a context cancelled when the
+// scheduler stops; its actor in the audit trail is "job:" followed by the
+// job name.
+type Job struct {
+	Name     string
+	Schedule Schedule
+	Run      func(ctx context.Context) error
+}
+
+// Option configures a Scheduler.
+type Option func(*Scheduler)
+
+// WithTick sets how often Run looks for due jobs.
+func WithTick(tick time.Duration) Option {
+	return func(s *Scheduler) {
+		if tick > 0 {
+			s.tick = tick
+		}
+	}
+}
+
+// WithStaleAfter sets after how long a run still marked in progress is
+// presumed to have died with its process, and no longer blocks new runs.
+func WithStaleAfter(d time.Duration) Option {
+	return func(s *Scheduler) {
+		if d > 0 {
+			s.staleAfter = d
+		}
+	}
+}
+
+// WithLogOutput sets where runs and their failures are reported.
+func WithLogOutput(w io.Writer) Option {
+	return func(s *Scheduler) {
+		s.logger = log.New(w, "jobs: ", log.LstdFlags)
+	}
+}
+
+type entry struct {
+	job     Job
+	next    time.Time
+	running bool
+}
+
+// Scheduler runs the registered jobs when they are due.
+type Scheduler struct {
+	uow        repository.UnitOfWork
+	tick       time.Duration
+	staleAfter time.Duration
+	logger     *log.Logger
+
+	mu      sync.Mutex
+	entries []*entry
+	runs    sync.WaitGroup
+}
+
+func NewScheduler(uow repository.UnitOfWork, opts ...Option) *Scheduler {
+	s := &Scheduler{
+		uow:        uow,
+		tick:       defaultTick,
+		staleAfter: defaultStaleAfter,
+		logger:     log.New(io.Discard, "", 0),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Register adds a job. Names must be unique.
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" || job.Schedule == nil || job.Run == nil {
+		return fmt.Errorf("job %q needs a name, a schedule and a function", job.Name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.find(job.Name) != nil {
+		return fmt.Errorf("job %s is already registered", job.Name)
+	}
+	s.entries = append(s.entries, &entry{job: job})
+	return nil
+}
+
+// Jobs returns the registered jobs in registration order.
+func (s *Scheduler) Jobs() []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]Job, len(s.entries))
+	for i, e := range s.entries {
+		jobs[i] = e.job
+	}
+	return jobs
+}
+
+func (s *Scheduler) find(name string) *entry {
+	for _, e := range s.entries {
+		if e.job.Name == name {
+			return e
+		}
+	}
+	return nil
+}
+
+// States returns the stored state of every registered job, in
+// registration order. The state of a job that never ran is empty but for
+// its name and schedule.
+func (s *Scheduler) States(ctx context.Context) ([]models.JobState, error) {
+	stored, err := s.uow.JobStates().FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]models.JobState, len(stored))
+	for _, state := range stored {
+		byName[state.Name] = state
+	}
+
+	jobs := s.Jobs()
+	states := make([]models.JobState, len(jobs))
+	for i, job := range jobs {
+		state, ok := byName[job.Name]
+		if !ok {
+			state = models.JobState{Name: job.Name}
+		}
+		state.Schedule = job.Schedule.String()
+		states[i] = state
+	}
+	return states, nil
+}
+
+// Run starts the due jobs every tick until ctx is cancelled, then waits for
+// the runs in progress, which see ctx cancelled, to return.
+func (s *Scheduler) Run(ctx context.Context) {
+	defer s.runs.Wait()
+
+	if err := s.load(ctx); err != nil {
+		if ctx.Err() == nil {
+			s.logger.Printf("load: %v", err)
+		}
+		return
+	}
+
+	ticker := time.NewTicker(s.tick)
+	defer ticker.Stop()
+
+	for {
+		s.startDue(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// load reads when each job runs next. A job that never ran, or whose
+// schedule changed, is scheduled from now; a run missed while the
+// application was down is due at once.
+func (s *Scheduler) load(ctx context.Context) error {
+	now := time.Now()
+	for _, job := range s.Jobs() {
+		state, err := s.uow.JobStates().Ensure(ctx, job.Name)
+		if err != nil {
+			return err
+		}
+
+		spec := job.Schedule.String()
+		var next time.Time
+		if state.NextRunAt != nil && state.Schedule == spec {
+			next = *state.NextRunAt
+		} else {
+			next = job.Schedule.Next(now)
+			if err := s.uow.JobStates().SetNextRun(ctx, job.Name, spec, next); err != nil {
+				return err
+			}
+		}
+
+		s.mu.Lock()
+		s.find(job.Name).next = next
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+func (s *Scheduler) startDue(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.entries {
+		if e.next.IsZero() || e.next.After(now) {
+			continue
+		}
+		if e.running {
+			// The previous run is still going: skip this one.
+			s.logger.Printf("%s: previous run still in progress, skipping", e.job.Name)
+			e.next = e.job.Schedule.Next(now)
+			continue
+		}
+
+		e.running = true
+		s.runs.Add(1)
+		go func(e *entry) {
+			defer s.runs.Done()
+			if err := s.run(ctx, e); err != nil && ctx.Err() == nil {
+				s.logger.Printf("%s: %v", e.job.Name, err)
+			}
+		}(e)
+	}
+}
+
+// RunNow runs a registered job at once and waits for it to finish. It
+// returns ErrConflict when a run of the job is already in progress, and
+// the error of the job otherwise.
+func (s *Scheduler) RunNow(ctx context.Context, name string) error {
+	s.mu.Lock()
+	e := s.find(name)
+	switch {
+	case e == nil:
+		s.mu.Unlock()
+		return customErr.NewServiceError("run_job", "scheduler", fmt.Sprintf("job '%s' not found", name), customErr.ErrEntityNotFound)
+	case e.running:
+		s.mu.Unlock()
+		return customErr.NewServiceError("run_job", "scheduler", fmt.Sprintf("job '%s' is already running", name), customErr.ErrConflict)
+	}
+	e.running = true
+	s.mu.Unlock()
+
+	if _, err := s.uow.JobStates().Ensure(ctx, name); err != nil {
+		s.mu.Lock()
+		e.running = false
+		s.mu.Unlock()
+		return err
+	}
+	return s.run(ctx, e)
+}
+
+// run makes one run of a job and records its outcome. The run is skipped
+// with ErrConflict when the database shows another run in progress.
+func (s *Scheduler) run(ctx context.Context, e *entry) error {
+	name := e.job.Name
+
+	start := time.Now()
+	claimed, err := s.uow.JobStates().Claim(ctx, name, start, start.Add(-s.staleAfter))
+	if err != nil {
+		s.release(e, e.job.Schedule.Next(start))
+		return err
+	}
+	if !claimed {
+		s.release(e, e.job.Schedule.Next(start))
+		return customErr.NewServiceError("run_job", "scheduler", fmt.Sprintf("job '%s' is already running", name), customErr.ErrConflict)
+	}
+
+	runErr := safeRun(repository.WithActor(ctx, "job:"+name), e.job.Run)
+	end := time.Now()
+
+	// Runs are scheduled from their start, so the cadence does not drift,
+	// unless the run lasted past its next start.
+	next := e.job.Schedule.Next(start)
+	if !next.After(end) {
+		next = e.job.Schedule.Next(end)
+	}
+
+	state := &models.JobState{
+		Name:         name,
+		LastRunAt:    &start,
+		LastDuration: end.Sub(start),
+		LastStatus:   models.JobSucceeded,
+	}
+	if !next.IsZero() {
+		state.NextRunAt = &next
+	}
+	if runErr != nil {
+		state.LastStatus = models.JobFailed
+		state.LastError = runErr.Error()
+	}
+
+	// The outcome is recorded even when ctx was cancelled, so the running
+	// mark does not outlive the run.
+	err = s.uow.JobStates().Finish(context.WithoutCancel(ctx), state)
+	s.release(e, next)
+	if err != nil {
+		return err
+	}
+
+	if runErr == nil {
+		s.logger.Printf("%s: done in %s", name, state.LastDuration.Round(time.Millisecond))
+	}
+	return runErr
+}
+
+// release marks the in-process run of a job over and sets its next run,
+// zero when the schedule has none left.
+func (s *Scheduler) release(e *entry, next time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e.running = false
+	e.next = next
+}
+
+func safeRun(ctx context.Context, run func(context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return run(ctx)
+}
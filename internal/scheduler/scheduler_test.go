@@ -0,0 +1,185 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+	"github.com/R4yL-dev/pkmc/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestUoW(t *testing.T) repository.UnitOfWork {
+	t.Helper()
+
+	db := testutil.SetupTestDB(t)
+	t.Cleanup(func() { testutil.CleanupTestDB(t, db) })
+
+	// Every connection to ":memory:" is a separate database.
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	return repository.NewUnitOfWork(db)
+}
+
+func state(t *testing.T, uow repository.UnitOfWork, name string) *models.JobState {
+	t.Helper()
+
+	state, err := uow.JobStates().FindByName(context.Background(), name)
+	require.NoError(t, err)
+	return state
+}
+
+func TestScheduler_Register(t *testing.T) {
+	s := NewScheduler(newTestUoW(t))
+	run := func(context.Context) error { return nil }
+
+	require.NoError(t, s.Register(Job{Name: "backup", Schedule: Every(time.Hour), Run: run}))
+	assert.Error(t, s.Register(Job{Name: "backup", Schedule: Every(time.Minute), Run: run}), "names are unique")
+	assert.Error(t, s.Register(Job{Name: "purge", Run: run}), "a schedule is required")
+	assert.Len(t, s.Jobs(), 1)
+}
+
+func TestScheduler_RunNow(t *testing.T) {
+	// Setup
+	uow := newTestUoW(t)
+	s := NewScheduler(uow)
+	ctx := context.Background()
+
+	var actor string
+	require.NoError(t, s.Register(Job{Name: "ok", Schedule: Every(time.Hour), Run: func(ctx context.Context) error {
+		actor = repository.ActorFrom(ctx)
+		return nil
+	}}))
+	require.NoError(t, s.Register(Job{Name: "broken", Schedule: Every(time.Hour), Run: func(context.Context) error {
+		return errors.New("disk full")
+	}}))
+	require.NoError(t, s.Register(Job{Name: "panics", Schedule: Every(time.Hour), Run: func(context.Context) error {
+		panic("boom")
+	}}))
+
+	// Execute & Assert
+	before := time.Now()
+	require.NoError(t, s.RunNow(ctx, "ok"))
+	assert.Equal(t, "job:ok", actor)
+	ok := state(t, uow, "ok")
+	assert.Equal(t, models.JobSucceeded, ok.LastStatus)
+	require.NotNil(t, ok.LastRunAt)
+	assert.False(t, ok.LastRunAt.Before(before))
+	require.NotNil(t, ok.NextRunAt)
+	assert.WithinDuration(t, ok.LastRunAt.Add(time.Hour), *ok.NextRunAt, time.Second)
+	assert.Nil(t, ok.RunningSince)
+
+	err := s.RunNow(ctx, "broken")
+	assert.EqualError(t, err, "disk full")
+	broken := state(t, uow, "broken")
+	assert.Equal(t, models.JobFailed, broken.LastStatus)
+	assert.Equal(t, "disk full", broken.LastError)
+
+	err = s.RunNow(ctx, "panics")
+	assert.ErrorContains(t, err, "panic: boom")
+	assert.Equal(t, models.JobFailed, state(t, uow, "panics").LastStatus)
+
+	err = s.RunNow(ctx, "missing")
+	assert.ErrorIs(t, err, customErr.ErrEntityNotFound)
+
+	states, err := s.States(ctx)
+	require.NoError(t, err)
+	require.Len(t, states, 3)
+	assert.Equal(t, "@every 1h0m0s", states[0].Schedule)
+}
+
+func TestScheduler_NoOverlap(t *testing.T) {
+	// Setup
+	uow := newTestUoW(t)
+	s := NewScheduler(uow, WithStaleAfter(time.Minute))
+	ctx := context.Background()
+
+	started, release := make(chan struct{}), make(chan struct{})
+	require.NoError(t, s.Register(Job{Name: "slow", Schedule: Every(time.Hour), Run: func(context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}}))
+	require.NoError(t, s.Register(Job{Name: "shared", Schedule: Every(time.Hour), Run: func(context.Context) error {
+		return nil
+	}}))
+
+	// Execute & Assert: a run of this process is in progress
+	done := make(chan error)
+	go func() { done <- s.RunNow(ctx, "slow") }()
+	<-started
+	assert.ErrorIs(t, s.RunNow(ctx, "slow"), customErr.ErrConflict)
+	close(release)
+	require.NoError(t, <-done)
+
+	// Another process runs the job
+	_, err := uow.JobStates().Ensure(ctx, "shared")
+	require.NoError(t, err)
+	claimed, err := uow.JobStates().Claim(ctx, "shared", time.Now(), time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	require.True(t, claimed)
+	assert.ErrorIs(t, s.RunNow(ctx, "shared"), customErr.ErrConflict)
+
+	// ... and died in the middle of it long ago
+	claimed, err = uow.JobStates().Claim(ctx, "shared", time.Now().Add(-2*time.Minute), time.Now())
+	require.NoError(t, err)
+	require.True(t, claimed)
+	assert.NoError(t, s.RunNow(ctx, "shared"))
+}
+
+func TestScheduler_Run(t *testing.T) {
+	// Setup
+	uow := newTestUoW(t)
+	s := NewScheduler(uow, WithTick(10*time.Millisecond))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var frequent, missed, blocked atomic.Int32
+	require.NoError(t, s.Register(Job{Name: "frequent", Schedule: Every(30 * time.Millisecond), Run: func(context.Context) error {
+		frequent.Add(1)
+		return nil
+	}}))
+	require.NoError(t, s.Register(Job{Name: "missed", Schedule: Every(time.Hour), Run: func(context.Context) error {
+		missed.Add(1)
+		return nil
+	}}))
+	require.NoError(t, s.Register(Job{Name: "blocked", Schedule: Every(10 * time.Millisecond), Run: func(ctx context.Context) error {
+		blocked.Add(1)
+		<-ctx.Done()
+		return ctx.Err()
+	}}))
+
+	// The hourly job was due while the application was down
+	_, err := uow.JobStates().Ensure(ctx, "missed")
+	require.NoError(t, err)
+	require.NoError(t, uow.JobStates().SetNextRun(ctx, "missed", "@every 1h0m0s", time.Now().Add(-time.Minute)))
+
+	// Execute
+	stopped := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(stopped)
+	}()
+	time.Sleep(200 * time.Millisecond)
+	cancel()
+	<-stopped
+
+	// Assert
+	assert.GreaterOrEqual(t, frequent.Load(), int32(2))
+	assert.Equal(t, int32(1), missed.Load(), "a missed run happens once at startup")
+	assert.Equal(t, int32(1), blocked.Load(), "runs do not overlap")
+
+	assert.Equal(t, models.JobSucceeded, state(t, uow, "missed").LastStatus)
+	interrupted := state(t, uow, "blocked")
+	assert.Equal(t, models.JobFailed, interrupted.LastStatus)
+	assert.Equal(t, context.Canceled.Error(), interrupted.LastError)
+	assert.Nil(t, interrupted.RunningSince, "the running mark is cleared on shutdown")
+}
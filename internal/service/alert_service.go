@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/events"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+)
+
+type alertService struct {
+	uow repository.UnitOfWork
+}
+
+func NewAlertService(uow repository.UnitOfWork) AlertService {
+	return &alertService{uow: uow}
+}
+
+func (s *alertService) CreateRule(ctx context.Context, input AlertRuleInput) (*models.AlertRule, error) {
+	input.ExtensionCode = normalizeCode(input.ExtensionCode)
+	input.ItemType = normalizeName(input.ItemType)
+	input.LanguageCode = normalizeLanguageCode(input.LanguageCode)
+	input.Name = strings.TrimSpace(input.Name)
+	v := &validator{}
+
+	condition := models.AlertCondition(strings.ToLower(strings.TrimSpace(input.Condition)))
+	validCondition := v.check(condition == models.AlertAbove || condition == models.AlertBelow || condition == models.AlertRise || condition == models.AlertDrop,
+		"condition", customErr.RuleOneOf, fmt.Sprintf("unknown alert condition '%s': expected above, below, rise or drop", input.Condition))
+	v.check(input.Threshold > 0, "threshold", customErr.RuleMin, "threshold must be positive")
+
+	var window time.Duration
+	switch {
+	case !validCondition:
+	case condition.IsChange() && strings.TrimSpace(input.Window) == "":
+		v.add("window", customErr.RuleRequired, fmt.Sprintf("a window is required for %s alerts, e.g. 7d", condition))
+	case condition.IsChange():
+		var err error
+		if window, err = models.ParseAlertWindow(input.Window); err != nil {
+			v.add("window", customErr.RuleFormat, err.Error())
+		}
+		if condition == models.AlertDrop {
+			v.check(input.Threshold < 100, "threshold", customErr.RuleMax, "a drop must be below 100%")
+		}
+	case strings.TrimSpace(input.Window) != "":
+		v.add("window", customErr.RuleExcludes, "a window only applies to rise and drop alerts")
+	}
+
+	hasProduct := input.ExtensionCode != "" || input.ItemType != "" || input.LanguageCode != ""
+	switch {
+	case input.ItemID != nil && hasProduct:
+		v.add("item_id", customErr.RuleExcludes, "an alert watches either an item or a product, not both")
+	case input.ItemID == nil && (input.ExtensionCode == "" || input.ItemType == "" || input.LanguageCode == ""):
+		v.add("item_id", customErr.RuleRequired, "an alert needs an item, or the extension code, item type and language code of a product")
+	case input.ItemID == nil:
+		v.maxLength("extension_code", input.ExtensionCode, maxCodeLength)
+		v.maxLength("item_type", input.ItemType, maxNameLength)
+		v.maxLength("language_code", input.LanguageCode, maxCodeLength)
+	}
+	v.maxLength("name", input.Name, maxNameLength)
+	if err := v.err("create_alert", "alert_service"); err != nil {
+		return nil, err
+	}
+
+	rule := &models.AlertRule{
+		Condition: condition,
+		Threshold: input.Threshold,
+		Window:    window,
+	}
+
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		if input.ItemID != nil {
+			item, err := uow.Items().FindByID(ctx, *input.ItemID)
+			if err != nil {
+				return customErr.NewServiceError("create_alert", "alert_service", fmt.Sprintf("item %d not found", *input.ItemID), err)
+			}
+			rule.ItemID = &item.ID
+		} else {
+			ext, err := uow.Extensions().FindByCode(ctx, input.ExtensionCode)
+			if err != nil {
+				return customErr.NewServiceError("create_alert", "alert_service", fmt.Sprintf("extension '%s' not found", input.ExtensionCode), err)
+			}
+			itemType, err := findItemType(ctx, uow, input.ItemType)
+			if err != nil {
+				return customErr.NewServiceError("create_alert", "alert_service", fmt.Sprintf("item type '%s' not found", input.ItemType), err)
+			}
+			lang, err := uow.Languages().FindByCode(ctx, input.LanguageCode)
+			if err != nil {
+				return customErr.NewServiceError("create_alert", "alert_service", fmt.Sprintf("language '%s' not found", input.LanguageCode), err)
+			}
+			rule.ExtensionCode, rule.ItemType, rule.LanguageCode = ext.Code, itemType.Name, lang.Code
+		}
+
+		rule.Name = input.Name
+		if rule.Name == "" {
+			rule.Name = defaultAlertName(rule)
+		}
+
+		if err := uow.AlertRules().Create(ctx, rule); err != nil {
+			return customErr.NewServiceError("create_alert", "alert_service", "failed to store alert rule", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// defaultAlertName describes a rule, e.g. "DRI Display (fr) above 250" or
+// "item 3 drop 15% in 7d".
+func defaultAlertName(rule *models.AlertRule) string {
+	scope := priceScope(rule).String()
+	threshold := strconv.FormatFloat(rule.Threshold, 'f', -1, 64)
+	if rule.Condition.IsChange() {
+		return fmt.Sprintf("%s %s %s%% in %s", scope, rule.Condition, threshold, models.FormatAlertWindow(rule.Window))
+	}
+	return fmt.Sprintf("%s %s %s", scope, rule.Condition, threshold)
+}
+
+func priceScope(rule *models.AlertRule) repository.PriceScope {
+	if rule.ItemID != nil {
+		return repository.PriceScope{ItemID: *rule.ItemID}
+	}
+	return repository.PriceScope{
+		ExtensionCode: rule.ExtensionCode,
+		ItemType:      rule.ItemType,
+		LanguageCode:  rule.LanguageCode,
+	}
+}
+
+func (s *alertService) ListRules(ctx context.Context) ([]models.AlertRule, error) {
+	var rules []models.AlertRule
+
+	err := s.uow.DoRead(ctx, func(uow repository.UnitOfWork) error {
+		var err error
+		rules, err = uow.AlertRules().FindAll(ctx)
+		if err != nil {
+			return customErr.NewServiceError("list_alerts", "alert_service", "failed to list alert rules", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (s *alertService) DeleteRule(ctx context.Context, id uint) error {
+	return s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		if err := uow.AlertRules().Delete(ctx, id); err != nil {
+			if errors.Is(err, customErr.ErrEntityNotFound) {
+				return customErr.NewServiceError("delete_alert", "alert_service", fmt.Sprintf("alert rule %d not found", id), err)
+			}
+			return customErr.NewServiceError("delete_alert", "alert_service", fmt.Sprintf("failed to delete alert rule %d", id), err)
+		}
+		return nil
+	})
+}
+
+// evaluateAlerts checks every alert rule against the latest recorded
+// prices, in the transaction of uow. A rule whose condition starts to hold
+// appends an alert.triggered event and is marked triggered; a triggered
+// rule whose condition no longer holds is reset, so it can fire again.
+func evaluateAlerts(ctx context.Context, uow repository.UnitOfWork, op, service string) error {
+	rules, err := uow.AlertRules().FindAll(ctx)
+	if err != nil {
+		return customErr.NewServiceError(op, service, "failed to load alert rules", err)
+	}
+
+	for i := range rules {
+		rule := &rules[i]
+		scope := priceScope(rule)
+
+		latest, err := uow.PriceRecords().Latest(ctx, scope)
+		if errors.Is(err, customErr.ErrEntityNotFound) {
+			continue
+		}
+		if err != nil {
+			return customErr.NewServiceError(op, service, fmt.Sprintf("failed to load prices of %s", scope), err)
+		}
+
+		payload := events.AlertTriggeredPayload{
+			Alert:    events.AlertSnapshot(rule),
+			Price:    latest.Price,
+			Currency: latest.Currency,
+			QuotedAt: latest.QuotedAt,
+		}
+
+		var holds bool
+		switch rule.Condition {
+		case models.AlertAbove:
+			holds = latest.Price >= rule.Threshold
+		case models.AlertBelow:
+			holds = latest.Price <= rule.Threshold
+		case models.AlertRise, models.AlertDrop:
+			reference, err := uow.PriceRecords().OldestSince(ctx, scope, latest.QuotedAt.Add(-rule.Window))
+			if err != nil {
+				return customErr.NewServiceError(op, service, fmt.Sprintf("failed to load prices of %s", scope), err)
+			}
+			if reference.Price <= 0 {
+				break
+			}
+			change := math.Round((latest.Price-reference.Price)/reference.Price*10000) / 100
+			payload.ReferencePrice = &reference.Price
+			payload.ChangePercent = &change
+			if rule.Condition == models.AlertRise {
+				holds = change >= rule.Threshold
+			} else {
+				holds = -change >= rule.Threshold
+			}
+		}
+
+		if holds == rule.Triggered {
+			continue
+		}
+
+		rule.Triggered = holds
+		rule.TriggeredAt = nil
+		if holds {
+			now := time.Now()
+			rule.TriggeredAt = &now
+
+			event, err := events.ForAlert(payload)
+			if err != nil {
+				return customErr.NewServiceError(op, service, "failed to build alert event", err)
+			}
+			if err := uow.Outbox().Append(ctx, event); err != nil {
+				return customErr.NewServiceError(op, service, fmt.Sprintf("failed to record alert %d", rule.ID), err)
+			}
+		}
+		if err := uow.AlertRules().UpdateState(ctx, rule); err != nil {
+			return customErr.NewServiceError(op, service, fmt.Sprintf("failed to update alert %d", rule.ID), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/events"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+	"github.com/R4yL-dev/pkmc/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func uintPtr(v uint) *uint {
+	return &v
+}
+
+func TestAlertService_CreateRule(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         AlertRuleInput
+		expectedError error
+		errorContains string
+		validate      func(*testing.T, *models.AlertRule)
+	}{
+		{
+			name:  "success - product above a price",
+			input: AlertRuleInput{ExtensionCode: "DRI", ItemType: "Display", LanguageCode: "fr", Condition: "above", Threshold: 250},
+			validate: func(t *testing.T, rule *models.AlertRule) {
+				assert.Equal(t, "DRI Display (fr) above 250", rule.Name)
+				assert.Nil(t, rule.ItemID)
+				assert.Equal(t, models.AlertAbove, rule.Condition)
+				assert.Zero(t, rule.Window)
+				assert.False(t, rule.Triggered)
+			},
+		},
+		{
+			name:  "success - item drop over a week",
+			input: AlertRuleInput{Name: "my display", ItemID: uintPtr(1), Condition: "DROP", Threshold: 15, Window: "7d"},
+			validate: func(t *testing.T, rule *models.AlertRule) {
+				assert.Equal(t, "my display", rule.Name)
+				assert.Equal(t, uint(1), *rule.ItemID)
+				assert.Equal(t, models.AlertDrop, rule.Condition)
+				assert.Equal(t, 7*24*time.Hour, rule.Window)
+			},
+		},
+		{
+			name:  "success - default name of a change",
+			input: AlertRuleInput{ItemID: uintPtr(1), Condition: "rise", Threshold: 10, Window: "36h"},
+			validate: func(t *testing.T, rule *models.AlertRule) {
+				assert.Equal(t, "item 1 rise 10% in 36h0m0s", rule.Name)
+			},
+		},
+		{
+			name:          "error - unknown condition",
+			input:         AlertRuleInput{ItemID: uintPtr(1), Condition: "sold", Threshold: 1},
+			expectedError: customErr.ErrValidationFailed,
+			errorContains: "unknown alert condition 'sold'",
+		},
+		{
+			name:          "error - threshold not positive",
+			input:         AlertRuleInput{ItemID: uintPtr(1), Condition: "above", Threshold: 0},
+			expectedError: customErr.ErrValidationFailed,
+			errorContains: "threshold must be positive",
+		},
+		{
+			name:          "error - change without window",
+			input:         AlertRuleInput{ItemID: uintPtr(1), Condition: "drop", Threshold: 15},
+			expectedError: customErr.ErrValidationFailed,
+			errorContains: "a window is required for drop alerts",
+		},
+		{
+			name:          "error - invalid window",
+			input:         AlertRuleInput{ItemID: uintPtr(1), Condition: "drop", Threshold: 15, Window: "a week"},
+			expectedError: customErr.ErrValidationFailed,
+			errorContains: "invalid window 'a week'",
+		},
+		{
+			name:          "error - window on a price condition",
+			input:         AlertRuleInput{ItemID: uintPtr(1), Condition: "below", Threshold: 100, Window: "7d"},
+			expectedError: customErr.ErrValidationFailed,
+			errorContains: "a window only applies to rise and drop alerts",
+		},
+		{
+			name:          "error - drop of 100%",
+			input:         AlertRuleInput{ItemID: uintPtr(1), Condition: "drop", Threshold: 100, Window: "7d"},
+			expectedError: customErr.ErrValidationFailed,
+			errorContains: "a drop must be below 100%",
+		},
+		{
+			name:          "error - item and product",
+			input:         AlertRuleInput{ItemID: uintPtr(1), ExtensionCode: "DRI", Condition: "above", Threshold: 250},
+			expectedError: customErr.ErrValidationFailed,
+			errorContains: "either an item or a product",
+		},
+		{
+			name:          "error - incomplete product",
+			input:         AlertRuleInput{ExtensionCode: "DRI", ItemType: "Display", Condition: "above", Threshold: 250},
+			expectedError: customErr.ErrValidationFailed,
+			errorContains: "extension code, item type and language code",
+		},
+		{
+			name:          "error - unknown item",
+			input:         AlertRuleInput{ItemID: uintPtr(99), Condition: "above", Threshold: 250},
+			expectedError: customErr.ErrEntityNotFound,
+			errorContains: "item 99 not found",
+		},
+		{
+			name:          "error - unknown extension",
+			input:         AlertRuleInput{ExtensionCode: "XXX", ItemType: "Display", LanguageCode: "fr", Condition: "above", Threshold: 250},
+			expectedError: customErr.ErrEntityNotFound,
+			errorContains: "extension 'XXX' not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			db := testutil.SetupTestDB(t)
+			defer testutil.CleanupTestDB(t, db)
+
+			uow := repository.NewUnitOfWork(db)
+			items := NewItemService(uow)
+			alerts := NewAlertService(uow)
+			ctx := context.Background()
+			_, err := items.CreateItem(ctx, "DRI", "fr", "Display", nil)
+			require.NoError(t, err)
+
+			// Execute
+			rule, err := alerts.CreateRule(ctx, tt.input)
+
+			// Assert
+			if tt.expectedError != nil {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotZero(t, rule.ID)
+			tt.validate(t, rule)
+		})
+	}
+}
+
+func TestAlertService_ListAndDeleteRules(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	uow := repository.NewUnitOfWork(db)
+	alerts := NewAlertService(uow)
+	ctx := context.Background()
+	rule, err := alerts.CreateRule(ctx, AlertRuleInput{ExtensionCode: "DRI", ItemType: "Display", LanguageCode: "fr", Condition: "above", Threshold: 250})
+	require.NoError(t, err)
+
+	// Execute
+	require.NoError(t, alerts.DeleteRule(ctx, rule.ID))
+
+	// Assert
+	rules, err := alerts.ListRules(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+
+	err = alerts.DeleteRule(ctx, rule.ID)
+	assert.ErrorIs(t, err, customErr.ErrEntityNotFound)
+
+	entries, err := uow.Audit().List(ctx, repository.AuditFilter{Entity: repository.AuditEntityAlert})
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "creation and deletion are audited")
+}
+
+// alertEvents returns the payloads of the alert.triggered events in the
+// outbox.
+func alertEvents(t *testing.T, uow repository.UnitOfWork) []events.AlertTriggeredPayload {
+	t.Helper()
+
+	stored, err := uow.Outbox().Due(context.Background(), time.Now(), 0)
+	require.NoError(t, err)
+
+	var alerts []events.AlertTriggeredPayload
+	for _, event := range stored {
+		if event.Type != events.AlertTriggered {
+			continue
+		}
+		var payload events.AlertTriggeredPayload
+		require.NoError(t, events.Event{Type: event.Type, Payload: []byte(event.Payload)}.Decode(&payload))
+		alerts = append(alerts, payload)
+	}
+	return alerts
+}
+
+func TestEvaluateAlerts(t *testing.T) {
+	// Setup: two displays of the same product, watched for a 15% weekly
+	// drop and for going above 250
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	uow := repository.NewUnitOfWork(db)
+	items := NewItemService(uow)
+	alerts := NewAlertService(uow)
+	ctx := context.Background()
+	first, err := items.CreateItem(ctx, "DRI", "fr", "Display", nil)
+	require.NoError(t, err)
+	second, err := items.CreateItem(ctx, "DRI", "fr", "Display", nil)
+	require.NoError(t, err)
+
+	drop, err := alerts.CreateRule(ctx, AlertRuleInput{ExtensionCode: "DRI", ItemType: "Display", LanguageCode: "fr", Condition: "drop", Threshold: 15, Window: "7d"})
+	require.NoError(t, err)
+	above, err := alerts.CreateRule(ctx, AlertRuleInput{ItemID: &first.ID, Condition: "above", Threshold: 250})
+	require.NoError(t, err)
+
+	start := time.Now().Add(-30 * 24 * time.Hour)
+	record := func(day int, price float64) {
+		t.Helper()
+		err := uow.Do(ctx, func(uow repository.UnitOfWork) error {
+			for _, id := range []uint{first.ID, second.ID} {
+				if err := uow.PriceRecords().Create(ctx, &models.PriceRecord{ItemID: id, Price: price, Currency: "EUR", Source: "file", QuotedAt: start.Add(time.Duration(day) * 24 * time.Hour)}); err != nil {
+					return err
+				}
+			}
+			return evaluateAlerts(ctx, uow, "test", "test")
+		})
+		require.NoError(t, err)
+	}
+	state := func(id uint) bool {
+		t.Helper()
+		var rule *models.AlertRule
+		require.NoError(t, uow.Do(ctx, func(uow repository.UnitOfWork) error {
+			var err error
+			rule, err = uow.AlertRules().FindByID(ctx, id)
+			return err
+		}))
+		return rule.Triggered
+	}
+
+	// A slow decline does not drop 15% within a week
+	record(0, 240)
+	record(5, 230)
+	record(10, 220)
+	assert.Empty(t, alertEvents(t, uow))
+	assert.False(t, state(drop.ID))
+
+	// Above 250 fires once
+	record(12, 255)
+	record(13, 260)
+	fired := alertEvents(t, uow)
+	require.Len(t, fired, 1)
+	assert.Equal(t, above.ID, fired[0].Alert.ID)
+	assert.Equal(t, 255.0, fired[0].Price)
+	assert.True(t, state(above.ID))
+
+	// 260 to 215 in six days is a 17.31% drop; the price falling below 250
+	// resets the other rule
+	record(19, 215)
+	fired = alertEvents(t, uow)
+	require.Len(t, fired, 2)
+	assert.Equal(t, drop.ID, fired[1].Alert.ID)
+	assert.Equal(t, "7d", fired[1].Alert.Window)
+	assert.Equal(t, 255.0, *fired[1].ReferencePrice, "the oldest price of the window")
+	assert.Equal(t, -15.69, *fired[1].ChangePercent)
+	assert.True(t, state(drop.ID))
+	assert.False(t, state(above.ID))
+
+	// Still down: no repeat. Back up: reset, then above 250 fires again
+	record(20, 214)
+	assert.Len(t, alertEvents(t, uow), 2)
+	record(28, 251)
+	fired = alertEvents(t, uow)
+	require.Len(t, fired, 3)
+	assert.Equal(t, above.ID, fired[2].Alert.ID)
+	assert.False(t, state(drop.ID))
+}
+
+func TestPriceService_RefreshPrices_EvaluatesAlerts(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	uow := repository.NewUnitOfWork(db)
+	items := NewItemService(uow)
+	prices := NewPriceService(uow, filePrices(t, "extension_code,type,language_code,price,currency\nDRI,Display,fr,262,EUR\n"))
+	alerts := NewAlertService(uow)
+	ctx := context.Background()
+	_, err := items.CreateItem(ctx, "DRI", "fr", "Display", testutil.FloatPtr(180))
+	require.NoError(t, err)
+	_, err = alerts.CreateRule(ctx, AlertRuleInput{ExtensionCode: "DRI", ItemType: "Display", LanguageCode: "fr", Condition: "above", Threshold: 250})
+	require.NoError(t, err)
+
+	// Execute
+	for i := 0; i < 2; i++ {
+		_, err = prices.RefreshPrices(ctx, "")
+		require.NoError(t, err)
+	}
+
+	// Assert
+	fired := alertEvents(t, uow)
+	require.Len(t, fired, 1, "the rule fires once while the condition holds")
+	assert.Equal(t, 262.0, fired[0].Price)
+	assert.Equal(t, "EUR", fired[0].Currency)
+}
@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/events"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+)
+
+type auditService struct {
+	uow repository.UnitOfWork
+}
+
+func NewAuditService(uow repository.UnitOfWork) AuditService {
+	return &auditService{uow: uow}
+}
+
+// ItemHistory returns every change of an item, and of the duplicates merged
+// into it, oldest first. Deleted items keep their history, so an unknown ID
+// yields an empty list.
+func (s *auditService) ItemHistory(ctx context.Context, id uint) ([]models.AuditEntry, error) {
+	var entries []models.AuditEntry
+
+	err := s.uow.DoRead(ctx, func(uow repository.UnitOfWork) error {
+		merged, err := uow.Items().MergedInto(ctx, id)
+		if err != nil {
+			return customErr.NewServiceError("item_history", "audit_service", fmt.Sprintf("failed to find the items merged into item %d", id), err)
+		}
+		entries, err = uow.Audit().List(ctx, repository.AuditFilter{Entity: repository.AuditEntityItem, EntityID: id, EntityIDs: merged})
+		if err != nil {
+			return customErr.NewServiceError("item_history", "audit_service", fmt.Sprintf("failed to load history of item %d", id), err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (s *auditService) ListChanges(ctx context.Context, filter repository.AuditFilter) ([]models.AuditEntry, error) {
+	v := &validator{}
+	if filter.Since != nil && filter.Until != nil {
+		v.check(filter.Since.Before(*filter.Until), "until", customErr.RuleMin, "the start of the range must be before its end")
+	}
+	v.check(filter.Limit >= 0, "limit", customErr.RuleMin, "limit must not be negative")
+	if err := v.err("list_changes", "audit_service"); err != nil {
+		return nil, err
+	}
+
+	var entries []models.AuditEntry
+
+	err := s.uow.DoRead(ctx, func(uow repository.UnitOfWork) error {
+		var err error
+		entries, err = uow.Audit().List(ctx, filter)
+		if err != nil {
+			return customErr.NewServiceError("list_changes", "audit_service", "failed to list changes", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (s *auditService) UndoLast(ctx context.Context, n int) ([]models.AuditEntry, error) {
+	v := &validator{}
+	v.check(n >= 1, "count", customErr.RuleMin, "the number of operations to undo must be at least 1")
+	if err := v.err("undo", "audit_service"); err != nil {
+		return nil, err
+	}
+
+	var undone []models.AuditEntry
+
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		operations, err := uow.Audit().UndoableOperations(ctx, n)
+		if err != nil {
+			return customErr.NewServiceError("undo", "audit_service", "failed to find operations to undo", err)
+		}
+		if len(operations) == 0 {
+			return customErr.NewServiceError("undo", "audit_service", "nothing to undo", customErr.ErrEntityNotFound)
+		}
+
+		// Newest first, so each operation is reverted on top of the state
+		// it left behind.
+		for _, operationID := range operations {
+			entries, err := uow.Audit().List(ctx, repository.AuditFilter{OperationID: operationID})
+			if err != nil {
+				return customErr.NewServiceError("undo", "audit_service", fmt.Sprintf("failed to load operation %s", operationID), err)
+			}
+			written, err := revertOperation(ctx, uow, operationID, entries)
+			if err != nil {
+				return err
+			}
+			undone = append(undone, written...)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return undone, nil
+}
+
+func (s *auditService) UndoOperation(ctx context.Context, operationID string) ([]models.AuditEntry, error) {
+	var undone []models.AuditEntry
+
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		entries, err := uow.Audit().List(ctx, repository.AuditFilter{OperationID: operationID})
+		if err != nil {
+			return customErr.NewServiceError("undo", "audit_service", fmt.Sprintf("failed to load operation %s", operationID), err)
+		}
+		if len(entries) == 0 {
+			return customErr.NewServiceError("undo", "audit_service", fmt.Sprintf("operation %s not found", operationID), customErr.ErrEntityNotFound)
+		}
+		for _, entry := range entries {
+			if entry.Reverts != "" {
+				return customErr.NewServiceError("undo", "audit_service", fmt.Sprintf("operation %s is an undo and cannot be undone", operationID), customErr.ErrValidationFailed)
+			}
+			if entry.Entity != repository.AuditEntityItem && !isMove(&entry) {
+				return customErr.NewServiceError("undo", "audit_service", fmt.Sprintf("operation %s changed %s records, only item changes can be undone", operationID, entry.Entity), customErr.ErrValidationFailed)
+			}
+		}
+
+		reverts, err := uow.Audit().List(ctx, repository.AuditFilter{Reverts: operationID, Limit: 1})
+		if err != nil {
+			return customErr.NewServiceError("undo", "audit_service", fmt.Sprintf("failed to load operation %s", operationID), err)
+		}
+		if len(reverts) > 0 {
+			return customErr.NewServiceError("undo", "audit_service", fmt.Sprintf("operation %s was already undone by operation %s", operationID, reverts[0].OperationID), customErr.ErrValidationFailed)
+		}
+
+		undone, err = revertOperation(ctx, uow, operationID, entries)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return undone, nil
+}
+
+// revertOperation applies the inverse of entries, newest first, and returns
+// the audit entries it wrote. Each entry is only reverted if the fields it
+// changed still hold the values it wrote; otherwise a later change would be
+// lost and the undo fails with ErrConflict.
+func revertOperation(ctx context.Context, uow repository.UnitOfWork, operationID string, entries []models.AuditEntry) ([]models.AuditEntry, error) {
+	ctx = repository.Reverting(ctx, operationID)
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := &entries[i]
+		revert := revertItemEntry
+		if isMove(entry) {
+			revert = revertMoveEntry
+		}
+		if err := revert(ctx, uow, operationID, entry); err != nil {
+			return nil, err
+		}
+	}
+
+	written, err := uow.Audit().List(ctx, repository.AuditFilter{Reverts: operationID})
+	if err != nil {
+		return nil, customErr.NewServiceError("undo", "audit_service", fmt.Sprintf("failed to load the undo of operation %s", operationID), err)
+	}
+	return written, nil
+}
+
+func revertItemEntry(ctx context.Context, uow repository.UnitOfWork, operationID string, entry *models.AuditEntry) error {
+	item, err := uow.Items().FindStored(ctx, entry.EntityID)
+	if err != nil {
+		return customErr.NewServiceError("undo", "audit_service", fmt.Sprintf("item %d of operation %s not found", entry.EntityID, operationID), err)
+	}
+	deleted := item.DeletedAt.Valid
+
+	conflict := func(reason string) error {
+		return customErr.NewServiceError("undo", "audit_service", fmt.Sprintf("cannot undo operation %s: item %d %s", operationID, entry.EntityID, reason), customErr.ErrConflict)
+	}
+
+	switch entry.Action {
+	case models.AuditCreate:
+		if deleted {
+			return conflict("was deleted since")
+		}
+		if field, ok := firstDifference(item, entry.After, nil); ok {
+			return conflict(fmt.Sprintf("%s was changed since", field))
+		}
+		before, err := itemSnapshot(ctx, uow, item.ID)
+		if err != nil {
+			return err
+		}
+		if err := uow.Items().Delete(ctx, item.ID); err != nil {
+			return customErr.NewServiceError("undo", "audit_service", fmt.Sprintf("failed to delete item %d", item.ID), err)
+		}
+		return emitItemChange(ctx, uow, "undo", "audit_service", before, nil)
+
+	case models.AuditUpdate:
+		if deleted {
+			return conflict("was deleted since")
+		}
+		changed := changedFields(entry.Before, entry.After)
+		if field, ok := firstDifference(item, entry.After, changed); ok {
+			return conflict(fmt.Sprintf("%s was changed since", field))
+		}
+		restore := make(models.AuditFields, len(changed))
+		for _, name := range changed {
+			restore[name] = entry.Before[name]
+		}
+		before, err := itemSnapshot(ctx, uow, item.ID)
+		if err != nil {
+			return err
+		}
+		if err := repository.ApplyItemAuditFields(item, restore); err != nil {
+			return customErr.NewServiceError("undo", "audit_service", fmt.Sprintf("invalid audit entry %d", entry.ID), err)
+		}
+		if err := uow.Items().Update(ctx, item); err != nil {
+			return customErr.NewServiceError("undo", "audit_service", fmt.Sprintf("failed to update item %d", item.ID), err)
+		}
+		after, err := itemSnapshot(ctx, uow, item.ID)
+		if err != nil {
+			return err
+		}
+		return emitItemChange(ctx, uow, "undo", "audit_service", before, after)
+
+	case models.AuditDelete:
+		if !deleted {
+			return conflict("was restored since")
+		}
+		if err := uow.Items().Restore(ctx, item.ID); err != nil {
+			return customErr.NewServiceError("undo", "audit_service", fmt.Sprintf("failed to restore item %d", item.ID), err)
+		}
+		after, err := itemSnapshot(ctx, uow, item.ID)
+		if err != nil {
+			return err
+		}
+		return emitItemChange(ctx, uow, "undo", "audit_service", nil, after)
+
+	default:
+		return customErr.NewServiceError("undo", "audit_service", fmt.Sprintf("unknown action '%s' in audit entry %d", entry.Action, entry.ID), customErr.ErrValidationFailed)
+	}
+}
+
+// isMove reports whether entry is the move of a price record or an alert
+// rule to the item another was merged into.
+func isMove(entry *models.AuditEntry) bool {
+	return entry.Action == models.AuditUpdate &&
+		(entry.Entity == repository.AuditEntityPriceRecord || entry.Entity == repository.AuditEntityAlert)
+}
+
+// revertMoveEntry moves a price record or an alert rule back to the item it
+// was moved from, if it is still a record of the item it was moved to.
+func revertMoveEntry(ctx context.Context, uow repository.UnitOfWork, operationID string, entry *models.AuditEntry) error {
+	from, okFrom := auditID(entry.After["item_id"])
+	to, okTo := auditID(entry.Before["item_id"])
+	if !okFrom || !okTo {
+		return customErr.NewServiceError("undo", "audit_service", fmt.Sprintf("invalid audit entry %d", entry.ID), customErr.ErrValidationFailed)
+	}
+
+	var err error
+	if entry.Entity == repository.AuditEntityPriceRecord {
+		err = uow.PriceRecords().Reassign(ctx, entry.EntityID, from, to)
+	} else {
+		err = uow.AlertRules().Reassign(ctx, entry.EntityID, from, to)
+	}
+	if errors.Is(err, customErr.ErrEntityNotFound) {
+		return customErr.NewServiceError("undo", "audit_service", fmt.Sprintf("cannot undo operation %s: %s %d was moved or removed since", operationID, entry.Entity, entry.EntityID), customErr.ErrConflict)
+	}
+	if err != nil {
+		return customErr.NewServiceError("undo", "audit_service", fmt.Sprintf("failed to move %s %d back to item %d", entry.Entity, entry.EntityID, to), err)
+	}
+	return nil
+}
+
+// auditID reads an ID from an audit image, where JSON made it a float.
+func auditID(value any) (uint, bool) {
+	id, ok := value.(float64)
+	if !ok || id <= 0 || id != math.Trunc(id) {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// itemSnapshot loads the state of an item carried by its events.
+func itemSnapshot(ctx context.Context, uow repository.UnitOfWork, id uint) (*events.Item, error) {
+	item, err := uow.Items().FindByID(ctx, id)
+	if err != nil {
+		return nil, customErr.NewServiceError("undo", "audit_service", fmt.Sprintf("failed to load item %d", id), err)
+	}
+	snapshot := events.ItemSnapshot(item)
+	return &snapshot, nil
+}
+
+// changedFields lists the fields whose values differ between two images.
+func changedFields(before, after models.AuditFields) []string {
+	var names []string
+	for name, value := range after {
+		if !reflect.DeepEqual(before[name], value) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// firstDifference compares the stored item with an image, limited to names
+// when it is not nil, and returns the first field that differs.
+func firstDifference(item *models.Item, image models.AuditFields, names []string) (string, bool) {
+	current, err := repository.ItemAuditFields(item).Normalized()
+	if err != nil {
+		// An item that cannot be compared is never overwritten.
+		return "state", true
+	}
+	if names == nil {
+		for name := range image {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	for _, name := range names {
+		if !reflect.DeepEqual(current[name], image[name]) {
+			return name, true
+		}
+	}
+	return "", false
+}
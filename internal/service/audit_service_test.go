@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/events"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+	"github.com/R4yL-dev/pkmc/internal/repository/mocks"
+	"github.com/R4yL-dev/pkmc/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditService_ItemHistory(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockUoW := mocks.NewMockUnitOfWork(t)
+		mockAudit := mocks.NewMockAuditRepository(t)
+		mockItems := mocks.NewMockItemRepository(t)
+		readInUoW(mockUoW)
+		mockUoW.On("Items").Return(mockItems)
+		mockUoW.On("Audit").Return(mockAudit)
+
+		mockItems.On("MergedInto", mock.Anything, uint(3)).Return([]uint{5}, nil)
+		expected := []models.AuditEntry{{ID: 1, Entity: repository.AuditEntityItem, EntityID: 3}, {ID: 2, Entity: repository.AuditEntityItem, EntityID: 5}}
+		mockAudit.On("List", mock.Anything, repository.AuditFilter{Entity: repository.AuditEntityItem, EntityID: 3, EntityIDs: []uint{5}}).Return(expected, nil)
+
+		entries, err := NewAuditService(mockUoW).ItemHistory(context.Background(), 3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, entries)
+	})
+
+	t.Run("error - listing fails", func(t *testing.T) {
+		mockUoW := mocks.NewMockUnitOfWork(t)
+		mockAudit := mocks.NewMockAuditRepository(t)
+		mockItems := mocks.NewMockItemRepository(t)
+		readInUoW(mockUoW)
+		mockUoW.On("Items").Return(mockItems)
+		mockUoW.On("Audit").Return(mockAudit)
+
+		mockItems.On("MergedInto", mock.Anything, uint(3)).Return(nil, nil)
+		mockAudit.On("List", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+		entries, err := NewAuditService(mockUoW).ItemHistory(context.Background(), 3)
+
+		assert.Nil(t, entries)
+		assert.Contains(t, err.Error(), "failed to load history of item 3")
+	})
+}
+
+func TestAuditService_ListChanges_InvalidRange(t *testing.T) {
+	since := time.Now()
+	until := since.Add(-time.Hour)
+
+	entries, err := NewAuditService(mocks.NewMockUnitOfWork(t)).ListChanges(context.Background(), repository.AuditFilter{Since: &since, Until: &until})
+
+	assert.Nil(t, entries)
+	assert.ErrorIs(t, err, customErr.ErrValidationFailed)
+}
+
+func TestAuditService_UndoLast(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	uow := repository.NewUnitOfWork(db)
+	items := NewItemService(uow)
+	audit := NewAuditService(uow)
+	ctx := context.Background()
+
+	first, err := items.CreateItem(ctx, "DRI", "fr", "Display", testutil.FloatPtr(180))
+	require.NoError(t, err)
+	second, err := items.CreateItem(ctx, "SVI", "en", "ETB", nil)
+	require.NoError(t, err)
+	_, err = items.UpdateItem(ctx, first.ID, ItemUpdate{Price: testutil.FloatPtr(1800)})
+	require.NoError(t, err)
+	require.NoError(t, items.DeleteItem(ctx, second.ID))
+
+	// Execute: revert the deletion and the fat-fingered price
+	undone, err := audit.UndoLast(ctx, 2)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, undone, 2)
+	assert.Equal(t, models.AuditCreate, undone[0].Action, "the deletion is undone first")
+	assert.Equal(t, models.AuditUpdate, undone[1].Action)
+
+	restored, err := items.GetItem(ctx, second.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "ETB", restored.Type.Name)
+
+	repriced, err := items.GetItem(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 180.0, *repriced.Price)
+
+	// Execute: undo again walks further back instead of redoing
+	undone, err = audit.UndoLast(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, undone, 1)
+	assert.Equal(t, models.AuditDelete, undone[0].Action)
+	assert.Equal(t, second.ID, undone[0].EntityID)
+	_, err = items.GetItem(ctx, second.ID)
+	assert.ErrorIs(t, err, customErr.ErrEntityNotFound)
+
+	// Undos emit the events of the changes they make
+	stored, err := uow.Outbox().Due(ctx, time.Now(), 0)
+	require.NoError(t, err)
+	var types []string
+	for _, event := range stored[5:] {
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []string{events.ItemCreated, events.ItemUpdated, events.ItemPriceChanged, events.ItemDeleted}, types)
+}
+
+func TestAuditService_UndoOperation(t *testing.T) {
+	ctx := context.Background()
+
+	// operationOf returns the operation of the latest change of an item.
+	operationOf := func(t *testing.T, audit AuditService, id uint) string {
+		entries, err := audit.ItemHistory(ctx, id)
+		require.NoError(t, err)
+		require.NotEmpty(t, entries)
+		return entries[len(entries)-1].OperationID
+	}
+
+	t.Run("success - later changes to other fields are kept", func(t *testing.T) {
+		db := testutil.SetupTestDB(t)
+		defer testutil.CleanupTestDB(t, db)
+
+		uow := repository.NewUnitOfWork(db)
+		items := NewItemService(uow)
+		audit := NewAuditService(uow)
+		item, err := items.CreateItem(ctx, "DRI", "fr", "Display", testutil.FloatPtr(180))
+		require.NoError(t, err)
+		_, err = items.UpdateItem(ctx, item.ID, ItemUpdate{Price: testutil.FloatPtr(210)})
+		require.NoError(t, err)
+		priceOp := operationOf(t, audit, item.ID)
+		_, err = items.UpdateItem(ctx, item.ID, ItemUpdate{LanguageCode: testutil.StringPtr("en")})
+		require.NoError(t, err)
+
+		_, err = audit.UndoOperation(ctx, priceOp)
+
+		require.NoError(t, err)
+		got, err := items.GetItem(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 180.0, *got.Price)
+		assert.Equal(t, "en", got.Language.Code)
+	})
+
+	t.Run("error - conflicting later change", func(t *testing.T) {
+		db := testutil.SetupTestDB(t)
+		defer testutil.CleanupTestDB(t, db)
+
+		uow := repository.NewUnitOfWork(db)
+		items := NewItemService(uow)
+		audit := NewAuditService(uow)
+		item, err := items.CreateItem(ctx, "DRI", "fr", "Display", testutil.FloatPtr(180))
+		require.NoError(t, err)
+		_, err = items.UpdateItem(ctx, item.ID, ItemUpdate{Price: testutil.FloatPtr(1800)})
+		require.NoError(t, err)
+		priceOp := operationOf(t, audit, item.ID)
+		_, err = items.UpdateItem(ctx, item.ID, ItemUpdate{Price: testutil.FloatPtr(200)})
+		require.NoError(t, err)
+
+		_, err = audit.UndoOperation(ctx, priceOp)
+
+		assert.ErrorIs(t, err, customErr.ErrConflict)
+		assert.Contains(t, err.Error(), "price was changed since")
+		got, err := items.GetItem(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 200.0, *got.Price, "a refused undo changes nothing")
+	})
+
+	t.Run("error - already undone", func(t *testing.T) {
+		db := testutil.SetupTestDB(t)
+		defer testutil.CleanupTestDB(t, db)
+
+		uow := repository.NewUnitOfWork(db)
+		items := NewItemService(uow)
+		audit := NewAuditService(uow)
+		item, err := items.CreateItem(ctx, "DRI", "fr", "Display", nil)
+		require.NoError(t, err)
+		createOp := operationOf(t, audit, item.ID)
+		_, err = audit.UndoOperation(ctx, createOp)
+		require.NoError(t, err)
+
+		_, err = audit.UndoOperation(ctx, createOp)
+
+		assert.ErrorIs(t, err, customErr.ErrValidationFailed)
+		assert.Contains(t, err.Error(), "already undone")
+	})
+
+	t.Run("error - undo of an undo", func(t *testing.T) {
+		db := testutil.SetupTestDB(t)
+		defer testutil.CleanupTestDB(t, db)
+
+		uow := repository.NewUnitOfWork(db)
+		items := NewItemService(uow)
+		audit := NewAuditService(uow)
+		_, err := items.CreateItem(ctx, "DRI", "fr", "Display", nil)
+		require.NoError(t, err)
+		undone, err := audit.UndoLast(ctx, 1)
+		require.NoError(t, err)
+		require.NotEmpty(t, undone)
+
+		_, err = audit.UndoOperation(ctx, undone[0].OperationID)
+
+		assert.ErrorIs(t, err, customErr.ErrValidationFailed)
+		assert.Contains(t, err.Error(), "is an undo")
+	})
+
+	t.Run("error - unknown operation", func(t *testing.T) {
+		db := testutil.SetupTestDB(t)
+		defer testutil.CleanupTestDB(t, db)
+
+		uow := repository.NewUnitOfWork(db)
+		audit := NewAuditService(uow)
+
+		_, err := audit.UndoOperation(ctx, "nope")
+
+		assert.ErrorIs(t, err, customErr.ErrEntityNotFound)
+	})
+}
+
+func TestAuditService_UndoLast_Nothing(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	uow := repository.NewUnitOfWork(db)
+	audit := NewAuditService(uow)
+
+	_, err := audit.UndoLast(context.Background(), 1)
+
+	assert.ErrorIs(t, err, customErr.ErrEntityNotFound)
+	assert.Contains(t, err.Error(), "nothing to undo")
+}
@@ -0,0 +1,367 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/events"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+)
+
+// Sizes of the batches of CreateItems.
+const (
+	// maxBatchEntries bounds the items added by one call.
+	maxBatchEntries = 1000
+	// insertBatchSize is the number of items inserted per statement.
+	insertBatchSize = 100
+)
+
+// normalize returns spec as stored, its codes and type name normalized and
+// its quantity defaulted.
+func (spec ItemSpec) normalize() ItemSpec {
+	spec.ExtensionCode = normalizeCode(spec.ExtensionCode)
+	spec.LanguageCode = normalizeLanguageCode(spec.LanguageCode)
+	spec.TypeName = normalizeName(spec.TypeName)
+	if spec.Quantity == 0 {
+		spec.Quantity = 1
+	}
+	return spec
+}
+
+func (s *itemService) CreateItems(ctx context.Context, specs []ItemSpec, opts ...CreateOption) ([]models.Item, error) {
+	options := createOptions{policy: s.duplicatePolicy}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	v := &validator{}
+	v.check(len(specs) > 0, "items", customErr.RuleRequired, "items is required")
+	v.check(len(specs) <= maxBatchEntries, "items", customErr.RuleMax, fmt.Sprintf("items must hold at most %d entries", maxBatchEntries))
+	v.check(options.policy.Valid(), "duplicate_policy", customErr.RuleOneOf, fmt.Sprintf("unknown duplicate policy '%s': expected allow, warn, reject or increment", options.policy))
+	v.maxLength("idempotency_key", options.idempotencyKey, maxIdempotencyKeyLength)
+	if err := v.err("create_items", "item_service"); err != nil {
+		return nil, err
+	}
+
+	normalized := make([]ItemSpec, len(specs))
+	var failed []customErr.EntryError
+	for i, spec := range specs {
+		spec = spec.normalize()
+		normalized[i] = spec
+
+		v := &validator{}
+		v.code("extension_code", spec.ExtensionCode)
+		v.code("language_code", spec.LanguageCode)
+		v.name("type", spec.TypeName)
+		v.price("price", spec.Price)
+		v.quantity("quantity", spec.Quantity)
+		if err := v.err("create_items", "item_service"); err != nil {
+			failed = append(failed, customErr.EntryError{Index: i, Err: err})
+		}
+	}
+	if len(failed) > 0 {
+		return nil, batchError(len(specs), failed)
+	}
+
+	var hash string
+	if options.idempotencyKey != "" {
+		canonical := make([]ItemSpec, len(normalized))
+		for i, spec := range normalized {
+			spec.TypeName = strings.ToLower(spec.TypeName)
+			canonical[i] = spec
+		}
+		var err error
+		hash, err = requestHash(struct {
+			Items  []ItemSpec
+			Policy DuplicatePolicy
+		}{canonical, options.policy})
+		if err != nil {
+			return nil, customErr.NewServiceError("create_items", "item_service", "failed to hash the request", err)
+		}
+	}
+
+	var createdItems []models.Item
+	var reports []DuplicateReport
+
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		if options.idempotencyKey == "" {
+			var err error
+			createdItems, reports, err = createItems(ctx, uow, normalized, options.policy)
+			return err
+		}
+
+		ids, replayed, err := s.idempotent(ctx, uow, "create_items", options.idempotencyKey, hash, func() ([]uint, error) {
+			items, found, err := createItems(ctx, uow, normalized, options.policy)
+			if err != nil {
+				return nil, err
+			}
+			createdItems, reports = items, found
+			ids := make([]uint, len(items))
+			for i := range items {
+				ids[i] = items[i].ID
+			}
+			return ids, nil
+		})
+		if err != nil {
+			return err
+		}
+		if options.replayed != nil {
+			*options.replayed = replayed
+		}
+		if !replayed {
+			return nil
+		}
+
+		reports = make([]DuplicateReport, len(ids))
+		for i := range reports {
+			reports[i] = DuplicateReport{Policy: options.policy}
+		}
+		createdItems = make([]models.Item, 0, len(ids))
+		for _, id := range ids {
+			item, err := uow.Items().FindByID(ctx, id)
+			if err != nil {
+				return customErr.NewServiceError("create_items", "item_service", fmt.Sprintf("item %d created with idempotency key '%s' no longer exists", id, options.idempotencyKey), err)
+			}
+			createdItems = append(createdItems, *item)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if options.reports != nil {
+		*options.reports = reports
+	}
+	return createdItems, nil
+}
+
+// createItems adds the items of specs in uow, looking each extension,
+// language and item type up once, and follows policy for each entry that
+// looks like an item already in the collection. When an entry refers to a
+// missing reference or is refused as a duplicate, nothing is added and
+// the error lists every such entry. It returns the items of the entries,
+// in order, and what was found and done about their duplicates.
+func createItems(ctx context.Context, uow repository.UnitOfWork, specs []ItemSpec, policy DuplicatePolicy) ([]models.Item, []DuplicateReport, error) {
+	refs := newReferenceCache(uow)
+	items := make([]models.Item, len(specs))
+	var failed []customErr.EntryError
+
+	for i, spec := range specs {
+		ext, lang, itemType, err := refs.resolve(ctx, spec)
+		if err != nil {
+			failed = append(failed, customErr.EntryError{Index: i, Err: err})
+			continue
+		}
+		items[i] = models.Item{
+			ExtensionID: ext.ID,
+			TypeID:      itemType.ID,
+			LanguageID:  lang.ID,
+			Price:       spec.Price,
+			Quantity:    spec.Quantity,
+			PurchasedAt: spec.PurchasedAt,
+			Extension:   *ext,
+			Type:        *itemType,
+			Language:    *lang,
+		}
+	}
+	if len(failed) > 0 {
+		return nil, nil, batchError(len(specs), failed)
+	}
+
+	reports := make([]DuplicateReport, len(specs))
+	for i := range reports {
+		reports[i].Policy = policy
+	}
+	// incremented maps the entries that raise the quantity of an item
+	// already in the collection to that item.
+	incremented := make(map[int]*models.Item)
+	if policy != DuplicateAllow {
+		candidates := newDuplicateCandidates(uow)
+		for i := range items {
+			matches, err := candidates.matches(ctx, &items[i])
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, match := range matches {
+				reports[i].Matches = append(reports[i].Matches, *match)
+			}
+			if len(matches) == 0 {
+				continue
+			}
+			switch policy {
+			case DuplicateReject:
+				failed = append(failed, customErr.EntryError{Index: i, Err: customErr.NewServiceError("create_items", "item_service", fmt.Sprintf("the item looks like a duplicate of item %d", matches[0].ID), customErr.ErrDuplicateItem)})
+			case DuplicateIncrement:
+				incremented[i] = matches[0]
+				reports[i].Incremented = true
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return nil, nil, batchError(len(specs), failed)
+	}
+
+	created := make([]models.Item, 0, len(items))
+	for i := range items {
+		if incremented[i] == nil {
+			created = append(created, items[i])
+		}
+	}
+	if len(created) > 0 {
+		if err := uow.Items().CreateInBatches(ctx, created, insertBatchSize); err != nil {
+			if errors.Is(err, customErr.ErrForeignKeyViolation) {
+				return nil, nil, customErr.NewServiceError("create_items", "item_service", missingReferenceMessage, err)
+			}
+			return nil, nil, customErr.NewServiceError("create_items", "item_service", "failed to create items", err)
+		}
+	}
+
+	next := 0
+	for i := range items {
+		if match := incremented[i]; match != nil {
+			// match is shared by the entries that look like it, so that
+			// each one adds to the quantity the previous ones left.
+			updated, err := incrementQuantity(ctx, uow, match, items[i].Quantity)
+			if err != nil {
+				return nil, nil, err
+			}
+			items[i] = *updated
+			continue
+		}
+		items[i] = created[next]
+		next++
+		snapshot := events.ItemSnapshot(&items[i])
+		if err := emitItemChange(ctx, uow, "create_items", "item_service", nil, &snapshot); err != nil {
+			return nil, nil, err
+		}
+	}
+	return items, reports, nil
+}
+
+// duplicateCandidates lists the items of the collection that entries of a
+// batch may duplicate, once per product.
+type duplicateCandidates struct {
+	uow      repository.UnitOfWork
+	products map[[3]uint][]*models.Item
+}
+
+func newDuplicateCandidates(uow repository.UnitOfWork) *duplicateCandidates {
+	return &duplicateCandidates{uow: uow, products: make(map[[3]uint][]*models.Item)}
+}
+
+// matches returns the likely duplicates of item, oldest first.
+func (c *duplicateCandidates) matches(ctx context.Context, item *models.Item) ([]*models.Item, error) {
+	key := [3]uint{item.ExtensionID, item.TypeID, item.LanguageID}
+	candidates, ok := c.products[key]
+	if !ok {
+		listed, err := c.uow.Items().List(ctx, repository.ItemFilter{ExtensionCode: item.Extension.Code, LanguageCode: item.Language.Code, TypeName: item.Type.Name})
+		if err != nil {
+			return nil, customErr.NewServiceError("create_items", "item_service", "failed to look for duplicates", err)
+		}
+		candidates = make([]*models.Item, len(listed))
+		for i := range listed {
+			candidates[i] = &listed[i]
+		}
+		c.products[key] = candidates
+	}
+
+	var matches []*models.Item
+	for _, candidate := range candidates {
+		if likelyDuplicates(item, candidate) {
+			matches = append(matches, candidate)
+		}
+	}
+	return matches, nil
+}
+
+// referenceCache looks the extensions, languages and item types of a batch
+// up once per distinct code or name, remembering missing ones too.
+type referenceCache struct {
+	uow        repository.UnitOfWork
+	extensions map[string]*models.Extension
+	languages  map[string]*models.Language
+	itemTypes  map[string]*models.ItemType
+	errs       map[string]error
+}
+
+func newReferenceCache(uow repository.UnitOfWork) *referenceCache {
+	return &referenceCache{
+		uow:        uow,
+		extensions: make(map[string]*models.Extension),
+		languages:  make(map[string]*models.Language),
+		itemTypes:  make(map[string]*models.ItemType),
+		errs:       make(map[string]error),
+	}
+}
+
+// resolve returns the extension, language and item type of spec, or the
+// error of the first one missing.
+func (c *referenceCache) resolve(ctx context.Context, spec ItemSpec) (*models.Extension, *models.Language, *models.ItemType, error) {
+	ext, err := lookUp(c, c.extensions, "extension:"+spec.ExtensionCode, func() (*models.Extension, error) {
+		ext, err := c.uow.Extensions().FindByCode(ctx, spec.ExtensionCode)
+		if err != nil {
+			return nil, customErr.NewServiceError("create_items", "item_service", fmt.Sprintf("extension '%s' not found", spec.ExtensionCode), err)
+		}
+		return ext, nil
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	lang, err := lookUp(c, c.languages, "language:"+spec.LanguageCode, func() (*models.Language, error) {
+		lang, err := c.uow.Languages().FindByCode(ctx, spec.LanguageCode)
+		if err != nil {
+			return nil, customErr.NewServiceError("create_items", "item_service", fmt.Sprintf("language '%s' not found", spec.LanguageCode), err)
+		}
+		return lang, nil
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	itemType, err := lookUp(c, c.itemTypes, "type:"+strings.ToLower(spec.TypeName), func() (*models.ItemType, error) {
+		itemType, err := findItemType(ctx, c.uow, spec.TypeName)
+		if err != nil {
+			return nil, customErr.NewServiceError("create_items", "item_service", fmt.Sprintf("item type '%s' not found", spec.TypeName), err)
+		}
+		return itemType, nil
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return ext, lang, itemType, nil
+}
+
+// lookUp returns the value cached under key in found, or the error cached
+// in c, calling find on a miss.
+func lookUp[T any](c *referenceCache, found map[string]*T, key string, find func() (*T, error)) (*T, error) {
+	if v, ok := found[key]; ok {
+		return v, nil
+	}
+	if err, ok := c.errs[key]; ok {
+		return nil, err
+	}
+
+	v, err := find()
+	if err != nil {
+		c.errs[key] = err
+		return nil, err
+	}
+	found[key] = v
+	return v, nil
+}
+
+// batchError returns the ServiceError of CreateItems reporting the failed
+// entries of a batch of total entries.
+func batchError(total int, failed []customErr.EntryError) error {
+	batchErr := &customErr.BatchError{Total: total, Entries: failed}
+	message := fmt.Sprintf("%d of %d items failed, none was added", len(failed), total)
+	return customErr.NewServiceError("create_items", "item_service", message, batchErr)
+}
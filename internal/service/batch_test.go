@@ -0,0 +1,345 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/events"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+	"github.com/R4yL-dev/pkmc/internal/repository/mocks"
+	"github.com/R4yL-dev/pkmc/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestItemService_CreateItems(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	uow := repository.NewUnitOfWork(db)
+	svc := NewItemService(uow)
+	ctx := context.Background()
+
+	bought := time.Date(2024, 3, 28, 0, 0, 0, 0, time.UTC)
+	specs := []ItemSpec{
+		{ExtensionCode: "DRI", LanguageCode: "fr", TypeName: "Display", Price: testutil.FloatPtr(180)},
+		{ExtensionCode: "dri", LanguageCode: "FR", TypeName: "display", Price: testutil.FloatPtr(180)},
+		{ExtensionCode: "SVI", LanguageCode: "en", TypeName: "Display", Quantity: 3, PurchasedAt: &bought},
+	}
+
+	// Execute
+	items, err := svc.CreateItems(ctx, specs)
+
+	// Assert: copies are added as given, in order
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+	assert.Equal(t, "DRI", items[1].Extension.Code)
+	assert.Equal(t, "Display", items[1].Type.Name)
+	assert.Equal(t, 1, items[0].Quantity)
+	assert.Equal(t, "SVI", items[2].Extension.Code)
+	assert.Equal(t, 3, items[2].Quantity)
+
+	stored, err := svc.ListItems(ctx, repository.ItemFilter{})
+	require.NoError(t, err)
+	assert.Len(t, stored, 3)
+
+	created, err := uow.Outbox().Due(ctx, time.Now(), 0)
+	require.NoError(t, err)
+	assert.Len(t, created, 3)
+	assert.Equal(t, events.ItemCreated, created[0].Type)
+
+	var operations int64
+	db.Model(&models.AuditEntry{}).Distinct("operation_id").Count(&operations)
+	assert.Equal(t, int64(1), operations, "the batch is undone as one operation")
+}
+
+func TestItemService_CreateItems_ReportsEveryEntry(t *testing.T) {
+	tests := []struct {
+		name            string
+		specs           []ItemSpec
+		expectedError   error
+		expectedEntries []int
+	}{
+		{
+			name: "invalid entries",
+			specs: []ItemSpec{
+				{ExtensionCode: "DRI", LanguageCode: "fr", TypeName: "Display"},
+				{ExtensionCode: "", LanguageCode: "fr", TypeName: "Display"},
+				{ExtensionCode: "DRI", LanguageCode: "fr", TypeName: "Display", Quantity: -1},
+			},
+			expectedError:   customErr.ErrValidationFailed,
+			expectedEntries: []int{1, 2},
+		},
+		{
+			name: "missing references",
+			specs: []ItemSpec{
+				{ExtensionCode: "XXX", LanguageCode: "fr", TypeName: "Display"},
+				{ExtensionCode: "DRI", LanguageCode: "fr", TypeName: "Display"},
+				{ExtensionCode: "DRI", LanguageCode: "xx", TypeName: "Display"},
+				{ExtensionCode: "XXX", LanguageCode: "en", TypeName: "Display"},
+			},
+			expectedError:   customErr.ErrEntityNotFound,
+			expectedEntries: []int{0, 2, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			db := testutil.SetupTestDB(t)
+			defer testutil.CleanupTestDB(t, db)
+
+			svc := NewItemService(repository.NewUnitOfWork(db))
+			ctx := context.Background()
+
+			// Execute
+			items, err := svc.CreateItems(ctx, tt.specs)
+
+			// Assert: nothing is added
+			assert.Nil(t, items)
+			assert.ErrorIs(t, err, tt.expectedError)
+			assert.Equal(t, customErr.CodeBatchFailed, customErr.CodeOf(err))
+
+			var batchErr *customErr.BatchError
+			require.True(t, errors.As(err, &batchErr))
+			assert.Equal(t, len(tt.specs), batchErr.Total)
+			var indexes []int
+			for _, entry := range batchErr.Entries {
+				indexes = append(indexes, entry.Index)
+				assert.ErrorIs(t, entry.Err, tt.expectedError)
+			}
+			assert.Equal(t, tt.expectedEntries, indexes)
+
+			stored, err := svc.ListItems(ctx, repository.ItemFilter{})
+			require.NoError(t, err)
+			assert.Empty(t, stored)
+		})
+	}
+}
+
+func TestItemService_CreateItems_DuplicatePolicy(t *testing.T) {
+	// The first entry looks like the display already in the collection,
+	// the second is a copy of the first and the third another product.
+	specs := []ItemSpec{
+		{ExtensionCode: "DRI", LanguageCode: "fr", TypeName: "Display", Price: testutil.FloatPtr(175), Quantity: 2},
+		{ExtensionCode: "DRI", LanguageCode: "fr", TypeName: "Display", Price: testutil.FloatPtr(176)},
+		{ExtensionCode: "DRI", LanguageCode: "en", TypeName: "Display", Price: testutil.FloatPtr(180)},
+	}
+
+	tests := []struct {
+		name     string
+		policy   DuplicatePolicy
+		validate func(*testing.T, *models.Item, []models.Item, []DuplicateReport, error, []models.Item)
+	}{
+		{
+			name:   "allow",
+			policy: DuplicateAllow,
+			validate: func(t *testing.T, existing *models.Item, items []models.Item, reports []DuplicateReport, err error, stored []models.Item) {
+				require.NoError(t, err)
+				assert.Empty(t, reports[0].Matches, "duplicates are not looked for")
+				assert.Len(t, stored, 4)
+			},
+		},
+		{
+			name:   "warn",
+			policy: DuplicateWarn,
+			validate: func(t *testing.T, existing *models.Item, items []models.Item, reports []DuplicateReport, err error, stored []models.Item) {
+				require.NoError(t, err)
+				require.Len(t, reports, 3)
+				require.Len(t, reports[0].Matches, 1)
+				assert.Equal(t, existing.ID, reports[0].Matches[0].ID)
+				require.Len(t, reports[1].Matches, 1, "entries are not compared with each other")
+				assert.Empty(t, reports[2].Matches)
+				assert.Len(t, stored, 4)
+			},
+		},
+		{
+			name:   "reject",
+			policy: DuplicateReject,
+			validate: func(t *testing.T, existing *models.Item, items []models.Item, reports []DuplicateReport, err error, stored []models.Item) {
+				var batchErr *customErr.BatchError
+				require.ErrorAs(t, err, &batchErr)
+				require.Len(t, batchErr.Entries, 2)
+				assert.Equal(t, 0, batchErr.Entries[0].Index)
+				assert.Equal(t, 1, batchErr.Entries[1].Index)
+				assert.ErrorIs(t, batchErr.Entries[0].Err, customErr.ErrDuplicateItem)
+				assert.Nil(t, items)
+				assert.Len(t, stored, 1, "nothing is added")
+			},
+		},
+		{
+			name:   "increment",
+			policy: DuplicateIncrement,
+			validate: func(t *testing.T, existing *models.Item, items []models.Item, reports []DuplicateReport, err error, stored []models.Item) {
+				require.NoError(t, err)
+				require.Len(t, items, 3)
+				assert.Equal(t, existing.ID, items[0].ID)
+				assert.Equal(t, existing.ID, items[1].ID)
+				assert.Equal(t, 4, items[1].Quantity, "each entry adds to the quantity")
+				assert.True(t, reports[0].Incremented)
+				assert.False(t, reports[2].Incremented)
+				assert.Len(t, stored, 2)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			db := testutil.SetupTestDB(t)
+			defer testutil.CleanupTestDB(t, db)
+
+			svc := NewItemService(repository.NewUnitOfWork(db), WithDefaultDuplicatePolicy(DuplicateWarn))
+			ctx := context.Background()
+
+			existing, err := svc.CreateItem(ctx, "DRI", "fr", "Display", testutil.FloatPtr(180))
+			require.NoError(t, err)
+
+			// Execute
+			var reports []DuplicateReport
+			items, err := svc.CreateItems(ctx, specs, WithDuplicatePolicy(tt.policy), ReportBatchDuplicates(&reports))
+
+			// Assert
+			stored, listErr := svc.ListItems(ctx, repository.ItemFilter{})
+			require.NoError(t, listErr)
+			tt.validate(t, existing, items, reports, err, stored)
+		})
+	}
+}
+
+func TestItemService_CreateItems_DefaultDuplicatePolicy(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	svc := NewItemService(repository.NewUnitOfWork(db), WithDefaultDuplicatePolicy(DuplicateReject))
+	ctx := context.Background()
+
+	_, err := svc.CreateItem(ctx, "DRI", "fr", "Display", nil)
+	require.NoError(t, err)
+
+	// Execute
+	_, err = svc.CreateItems(ctx, []ItemSpec{{ExtensionCode: "DRI", LanguageCode: "fr", TypeName: "Display"}})
+
+	// Assert
+	assert.ErrorIs(t, err, customErr.ErrDuplicateItem)
+}
+
+func TestItemService_CreateItems_Invalid(t *testing.T) {
+	// Setup
+	service := NewItemService(mocks.NewMockUnitOfWork(t))
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		specs []ItemSpec
+		opts  []CreateOption
+	}{
+		{"no entries", nil, nil},
+		{"too many entries", make([]ItemSpec, maxBatchEntries+1), nil},
+		{"long idempotency key", []ItemSpec{{ExtensionCode: "DRI", LanguageCode: "fr", TypeName: "Display"}}, []CreateOption{WithIdempotencyKey(string(make([]byte, maxIdempotencyKeyLength+1)))}},
+		{"unknown duplicate policy", []ItemSpec{{ExtensionCode: "DRI", LanguageCode: "fr", TypeName: "Display"}}, []CreateOption{WithDuplicatePolicy("merge")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Execute
+			items, err := service.CreateItems(ctx, tt.specs, tt.opts...)
+
+			// Assert
+			assert.Nil(t, items)
+			assert.ErrorIs(t, err, customErr.ErrValidationFailed)
+			assert.Equal(t, customErr.CodeValidationFailed, customErr.CodeOf(err))
+		})
+	}
+}
+
+func TestItemService_CreateItems_LooksReferencesUpOnce(t *testing.T) {
+	// Setup: the mocks fail when called more than once
+	mockUoW := mocks.NewMockUnitOfWork(t)
+	mockItems := mocks.NewMockItemRepository(t)
+	mockExts := mocks.NewMockExtensionRepository(t)
+	mockLangs := mocks.NewMockLanguageRepository(t)
+	mockTypes := mocks.NewMockItemTypeRepository(t)
+	mockOutbox := mocks.NewMockOutboxRepository(t)
+
+	runInUoW(mockUoW)
+	mockUoW.On("Items").Return(mockItems)
+	mockUoW.On("Extensions").Return(mockExts)
+	mockUoW.On("Languages").Return(mockLangs)
+	mockUoW.On("ItemTypes").Return(mockTypes)
+	mockUoW.On("Outbox").Return(mockOutbox)
+
+	mockExts.On("FindByCode", mock.Anything, "DRI").Return(&models.Extension{Model: gorm.Model{ID: 1}, Code: "DRI"}, nil).Once()
+	mockLangs.On("FindByCode", mock.Anything, "fr").Return(&models.Language{Model: gorm.Model{ID: 1}, Code: "fr"}, nil).Once()
+	mockTypes.On("FindByName", mock.Anything, "Display").Return(&models.ItemType{Model: gorm.Model{ID: 1}, Name: "Display"}, nil).Once()
+	mockItems.On("CreateInBatches", mock.Anything, mock.MatchedBy(func(items []models.Item) bool {
+		return len(items) == 3
+	}), insertBatchSize).Run(func(args mock.Arguments) {
+		items := args.Get(1).([]models.Item)
+		for i := range items {
+			items[i].ID = uint(i + 1)
+		}
+	}).Return(nil).Once()
+	mockOutbox.On("Append", mock.Anything, mock.Anything).Return(nil).Times(3)
+
+	service := NewItemService(mockUoW)
+	spec := ItemSpec{ExtensionCode: "DRI", LanguageCode: "fr", TypeName: "Display"}
+
+	// Execute
+	items, err := service.CreateItems(context.Background(), []ItemSpec{spec, spec, spec})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+	assert.Equal(t, uint(3), items[2].ID)
+	assert.Equal(t, "DRI", items[2].Extension.Code)
+}
+
+func TestItemService_CreateItems_IdempotencyKey(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	svc := NewItemService(repository.NewUnitOfWork(db))
+	ctx := context.Background()
+	specs := []ItemSpec{
+		{ExtensionCode: "DRI", LanguageCode: "fr", TypeName: "Display"},
+		{ExtensionCode: "SVI", LanguageCode: "en", TypeName: "Display", Quantity: 2},
+	}
+
+	var replayed bool
+	first, err := svc.CreateItems(ctx, specs, WithIdempotencyKey("import-1"), ReportReplayed(&replayed))
+	require.NoError(t, err)
+	assert.False(t, replayed)
+
+	// Execute
+	retried, err := svc.CreateItems(ctx, specs, WithIdempotencyKey("import-1"), ReportReplayed(&replayed))
+
+	// Assert: the items of the first call are returned, in order
+	require.NoError(t, err)
+	assert.True(t, replayed)
+	require.Len(t, retried, 2)
+	assert.Equal(t, first[0].ID, retried[0].ID)
+	assert.Equal(t, first[1].ID, retried[1].ID)
+	assert.Equal(t, "SVI", retried[1].Extension.Code)
+
+	stored, err := svc.ListItems(ctx, repository.ItemFilter{})
+	require.NoError(t, err)
+	assert.Len(t, stored, 2)
+
+	// Execute & Assert: the key of another batch
+	_, err = svc.CreateItems(ctx, specs[:1], WithIdempotencyKey("import-1"))
+	assert.ErrorIs(t, err, customErr.ErrIdempotencyKeyReused)
+
+	// Execute & Assert: the key of a single item is not the key of a batch
+	_, err = svc.CreateItem(ctx, "DRI", "fr", "Display", nil, WithIdempotencyKey("import-1"))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+)
+
+type catalogService struct {
+	uow repository.UnitOfWork
+}
+
+func NewCatalogService(uow repository.UnitOfWork) CatalogService {
+	return &catalogService{uow: uow}
+}
+
+func (s *catalogService) ListBlocks(ctx context.Context) ([]models.Block, error) {
+	var blocks []models.Block
+
+	err := s.uow.DoRead(ctx, func(uow repository.UnitOfWork) error {
+		var err error
+		blocks, err = uow.Blocks().FindAll(ctx)
+		if err != nil {
+			return customErr.NewServiceError("list_blocks", "catalog_service", "failed to list blocks", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
+func (s *catalogService) ListExtensions(ctx context.Context, blockCode string) ([]models.Extension, error) {
+	blockCode = normalizeCode(blockCode)
+	var exts []models.Extension
+
+	err := s.uow.DoRead(ctx, func(uow repository.UnitOfWork) error {
+		if blockCode == "" {
+			var err error
+			exts, err = uow.Extensions().FindAll(ctx)
+			if err != nil {
+				return customErr.NewServiceError("list_extensions", "catalog_service", "failed to list extensions", err)
+			}
+			return nil
+		}
+
+		if _, err := uow.Blocks().FindByCode(ctx, blockCode); err != nil {
+			return customErr.NewServiceError("list_extensions", "catalog_service", fmt.Sprintf("block '%s' not found", blockCode), err)
+		}
+
+		var err error
+		exts, err = uow.Extensions().FindByBlockCode(ctx, blockCode)
+		if err != nil {
+			return customErr.NewServiceError("list_extensions", "catalog_service", fmt.Sprintf("failed to list extensions of block '%s'", blockCode), err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return exts, nil
+}
+
+func (s *catalogService) GetExtension(ctx context.Context, code string) (*models.Extension, error) {
+	code = normalizeCode(code)
+	v := &validator{}
+	v.code("code", code)
+	if err := v.err("get_extension", "catalog_service"); err != nil {
+		return nil, err
+	}
+
+	var ext *models.Extension
+
+	err := s.uow.DoRead(ctx, func(uow repository.UnitOfWork) error {
+		var err error
+		ext, err = uow.Extensions().FindByCode(ctx, code)
+		if err != nil {
+			return customErr.NewServiceError("get_extension", "catalog_service", fmt.Sprintf("extension '%s' not found", code), err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ext, nil
+}
+
+func (s *catalogService) ListLanguages(ctx context.Context) ([]models.Language, error) {
+	var langs []models.Language
+
+	err := s.uow.DoRead(ctx, func(uow repository.UnitOfWork) error {
+		var err error
+		langs, err = uow.Languages().FindAll(ctx)
+		if err != nil {
+			return customErr.NewServiceError("list_languages", "catalog_service", "failed to list languages", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return langs, nil
+}
+
+func (s *catalogService) ListItemTypes(ctx context.Context) ([]models.ItemType, error) {
+	var itemTypes []models.ItemType
+
+	err := s.uow.DoRead(ctx, func(uow repository.UnitOfWork) error {
+		var err error
+		itemTypes, err = uow.ItemTypes().FindAll(ctx)
+		if err != nil {
+			return customErr.NewServiceError("list_item_types", "catalog_service", "failed to list item types", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return itemTypes, nil
+}
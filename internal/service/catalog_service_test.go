@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+	"github.com/R4yL-dev/pkmc/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogService_ListExtensions(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	service := NewCatalogService(repository.NewUnitOfWork(db))
+	ctx := context.Background()
+
+	// Execute - every extension
+	all, err := service.ListExtensions(ctx, "")
+	require.NoError(t, err)
+
+	// Execute - one block
+	me, err := service.ListExtensions(ctx, "ME")
+	require.NoError(t, err)
+
+	// Assert
+	assert.Greater(t, len(all), len(me))
+	for _, ext := range me {
+		assert.Equal(t, "ME", ext.Block.Code)
+	}
+
+	// Execute - unknown block
+	_, err = service.ListExtensions(ctx, "XX")
+	assert.ErrorIs(t, err, customErr.ErrEntityNotFound)
+	assert.Contains(t, err.Error(), "block 'XX' not found")
+}
+
+func TestCatalogService_GetExtension(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	service := NewCatalogService(repository.NewUnitOfWork(db))
+	ctx := context.Background()
+
+	// Execute
+	ext, err := service.GetExtension(ctx, "DRI")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Rivalités Destinées", ext.Name)
+	assert.Equal(t, "EV", ext.Block.Code)
+
+	_, err = service.GetExtension(ctx, "NOPE")
+	assert.ErrorIs(t, err, customErr.ErrEntityNotFound)
+}
+
+func TestCatalogService_ReferenceLists(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	service := NewCatalogService(repository.NewUnitOfWork(db))
+	ctx := context.Background()
+
+	// Execute & Assert
+	blocks, err := service.ListBlocks(ctx)
+	require.NoError(t, err)
+	assert.Len(t, blocks, 3)
+	assert.Equal(t, "EB", blocks[0].Code, "blocks are ordered by release date")
+
+	langs, err := service.ListLanguages(ctx)
+	require.NoError(t, err)
+	assert.Len(t, langs, 4)
+
+	itemTypes, err := service.ListItemTypes(ctx)
+	require.NoError(t, err)
+	assert.Len(t, itemTypes, 5)
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/R4yL-dev/pkmc/internal/models"
+)
+
+// DuplicatePolicy tells CreateItem and CreateItems what to do with an item
+// that looks like one already in the collection: same extension, type and
+// language, a price within 5% and a purchase within a week, an unknown
+// price or date matching any.
+type DuplicatePolicy string
+
+const (
+	// DuplicateAllow creates the item without looking for duplicates.
+	DuplicateAllow DuplicatePolicy = "allow"
+	// DuplicateWarn creates the item and reports the likely duplicates.
+	DuplicateWarn DuplicatePolicy = "warn"
+	// DuplicateReject refuses the item with ErrDuplicateItem.
+	DuplicateReject DuplicatePolicy = "reject"
+	// DuplicateIncrement adds the quantity of the item to the oldest
+	// likely duplicate instead of creating it.
+	DuplicateIncrement DuplicatePolicy = "increment"
+)
+
+// DuplicatePolicies lists the policies, for help texts.
+var DuplicatePolicies = []DuplicatePolicy{DuplicateAllow, DuplicateWarn, DuplicateReject, DuplicateIncrement}
+
+// ParseDuplicatePolicy returns the policy named s, regardless of case.
+func ParseDuplicatePolicy(s string) (DuplicatePolicy, error) {
+	policy := DuplicatePolicy(strings.ToLower(strings.TrimSpace(s)))
+	if !policy.Valid() {
+		return "", fmt.Errorf("unknown duplicate policy '%s': expected allow, warn, reject or increment", s)
+	}
+	return policy, nil
+}
+
+func (p DuplicatePolicy) Valid() bool {
+	for _, policy := range DuplicatePolicies {
+		if p == policy {
+			return true
+		}
+	}
+	return false
+}
+
+// Tolerances within which two items of the same product are likely
+// duplicates.
+const (
+	duplicatePriceTolerance = 0.05
+	duplicateDateTolerance  = 7 * 24 * time.Hour
+)
+
+// DuplicateReport tells what CreateItem found and did about duplicates.
+type DuplicateReport struct {
+	Policy DuplicatePolicy
+	// Matches are the likely duplicates of the new item, oldest first.
+	Matches []models.Item
+	// Incremented is set when the quantity of Matches[0], which CreateItem
+	// returned, was raised instead of creating an item.
+	Incremented bool
+}
+
+// DuplicatePair is two items that look like duplicates. Duplicate is the
+// newer one, which merging into Item removes.
+type DuplicatePair struct {
+	Item      models.Item
+	Duplicate models.Item
+}
+
+// CreateOption sets the optional fields of an item created by CreateItem,
+// or how it handles duplicates.
+type CreateOption func(*createOptions)
+
+type createOptions struct {
+	quantity    int
+	purchasedAt *time.Time
+	policy      DuplicatePolicy
+	report      *DuplicateReport
+	reports     *[]DuplicateReport
+
+	idempotencyKey string
+	replayed       *bool
+}
+
+// WithQuantity sets the number of copies of the item, 1 by default.
+func WithQuantity(quantity int) CreateOption {
+	return func(o *createOptions) {
+		o.quantity = quantity
+	}
+}
+
+// WithPurchasedAt sets when the item was bought.
+func WithPurchasedAt(at time.Time) CreateOption {
+	return func(o *createOptions) {
+		o.purchasedAt = &at
+	}
+}
+
+// WithDuplicatePolicy overrides the duplicate policy of the service.
+func WithDuplicatePolicy(policy DuplicatePolicy) CreateOption {
+	return func(o *createOptions) {
+		o.policy = policy
+	}
+}
+
+// ReportDuplicates has CreateItem fill report with the likely duplicates it
+// found and what it did about them.
+func ReportDuplicates(report *DuplicateReport) CreateOption {
+	return func(o *createOptions) {
+		o.report = report
+	}
+}
+
+// ReportBatchDuplicates has CreateItems fill reports with the likely
+// duplicates it found for each entry and what it did about them, in the
+// order of the entries.
+func ReportBatchDuplicates(reports *[]DuplicateReport) CreateOption {
+	return func(o *createOptions) {
+		o.reports = reports
+	}
+}
+
+// ItemServiceOption configures the item service.
+type ItemServiceOption func(*itemService)
+
+// WithDefaultDuplicatePolicy sets the duplicate policy of CreateItem calls
+// that do not choose one, DuplicateAllow by default.
+func WithDefaultDuplicatePolicy(policy DuplicatePolicy) ItemServiceOption {
+	return func(s *itemService) {
+		s.duplicatePolicy = policy
+	}
+}
+
+// likelyDuplicates reports whether a and b look like the same item.
+func likelyDuplicates(a, b *models.Item) bool {
+	return a.ExtensionID == b.ExtensionID &&
+		a.TypeID == b.TypeID &&
+		a.LanguageID == b.LanguageID &&
+		similarPrices(a.Price, b.Price) &&
+		similarDates(a.PurchasedAt, b.PurchasedAt)
+}
+
+func similarPrices(a, b *float64) bool {
+	if a == nil || b == nil {
+		return true
+	}
+	return math.Abs(*a-*b) <= duplicatePriceTolerance*math.Max(*a, *b)
+}
+
+func similarDates(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return true
+	}
+	return a.Sub(*b).Abs() <= duplicateDateTolerance
+}
+
+// duplicatePairs returns the pairs of likely duplicates among items, which
+// are sorted by ID.
+func duplicatePairs(items []models.Item) []DuplicatePair {
+	type product struct{ extensionID, typeID, languageID uint }
+	groups := make(map[product][]int)
+	var order []product
+	for i := range items {
+		key := product{items[i].ExtensionID, items[i].TypeID, items[i].LanguageID}
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+
+	var pairs []DuplicatePair
+	for _, key := range order {
+		group := groups[key]
+		for i, a := range group {
+			for _, b := range group[i+1:] {
+				if likelyDuplicates(&items[a], &items[b]) {
+					pairs = append(pairs, DuplicatePair{Item: items[a], Duplicate: items[b]})
+				}
+			}
+		}
+	}
+	return pairs
+}
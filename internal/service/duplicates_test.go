@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+	"github.com/R4yL-dev/pkmc/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDuplicatePolicy(t *testing.T) {
+	policy, err := ParseDuplicatePolicy(" Increment ")
+	assert.NoError(t, err)
+	assert.Equal(t, DuplicateIncrement, policy)
+
+	_, err = ParseDuplicatePolicy("merge")
+	assert.Error(t, err)
+}
+
+func TestLikelyDuplicates(t *testing.T) {
+	bought := time.Date(2024, 3, 28, 0, 0, 0, 0, time.UTC)
+	item := models.Item{ExtensionID: 1, TypeID: 1, LanguageID: 1, Price: testutil.FloatPtr(100), PurchasedAt: &bought}
+
+	tests := []struct {
+		name     string
+		other    func(*models.Item)
+		expected bool
+	}{
+		{"same", func(i *models.Item) {}, true},
+		{"price within 5%", func(i *models.Item) { i.Price = testutil.FloatPtr(104) }, true},
+		{"price beyond 5%", func(i *models.Item) { i.Price = testutil.FloatPtr(110) }, false},
+		{"unknown price", func(i *models.Item) { i.Price = nil }, true},
+		{"bought within a week", func(i *models.Item) { d := bought.AddDate(0, 0, 6); i.PurchasedAt = &d }, true},
+		{"bought a month apart", func(i *models.Item) { d := bought.AddDate(0, 1, 0); i.PurchasedAt = &d }, false},
+		{"unknown purchase date", func(i *models.Item) { i.PurchasedAt = nil }, true},
+		{"other language", func(i *models.Item) { i.LanguageID = 2 }, false},
+		{"other type", func(i *models.Item) { i.TypeID = 2 }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			other := item
+			tt.other(&other)
+			assert.Equal(t, tt.expected, likelyDuplicates(&item, &other))
+		})
+	}
+}
+
+func TestItemService_CreateItem_DuplicatePolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      DuplicatePolicy
+		expectedErr error
+		validate    func(*testing.T, *models.Item, *models.Item, DuplicateReport, []models.Item)
+	}{
+		{
+			name:   "allow",
+			policy: DuplicateAllow,
+			validate: func(t *testing.T, existing, item *models.Item, report DuplicateReport, items []models.Item) {
+				assert.NotEqual(t, existing.ID, item.ID)
+				assert.Empty(t, report.Matches, "duplicates are not looked for")
+				assert.Len(t, items, 2)
+			},
+		},
+		{
+			name:   "warn",
+			policy: DuplicateWarn,
+			validate: func(t *testing.T, existing, item *models.Item, report DuplicateReport, items []models.Item) {
+				assert.NotEqual(t, existing.ID, item.ID)
+				require.Len(t, report.Matches, 1)
+				assert.Equal(t, existing.ID, report.Matches[0].ID)
+				assert.False(t, report.Incremented)
+				assert.Len(t, items, 2)
+			},
+		},
+		{
+			name:        "reject",
+			policy:      DuplicateReject,
+			expectedErr: customErr.ErrDuplicateItem,
+			validate: func(t *testing.T, existing, item *models.Item, report DuplicateReport, items []models.Item) {
+				assert.Len(t, items, 1)
+			},
+		},
+		{
+			name:   "increment",
+			policy: DuplicateIncrement,
+			validate: func(t *testing.T, existing, item *models.Item, report DuplicateReport, items []models.Item) {
+				assert.Equal(t, existing.ID, item.ID)
+				assert.Equal(t, 3, item.Quantity)
+				assert.True(t, report.Incremented)
+				assert.Len(t, items, 1)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			db := testutil.SetupTestDB(t)
+			defer testutil.CleanupTestDB(t, db)
+
+			svc := NewItemService(repository.NewUnitOfWork(db))
+			ctx := context.Background()
+
+			existing, err := svc.CreateItem(ctx, "DRI", "fr", "Display", testutil.FloatPtr(180))
+			require.NoError(t, err)
+
+			// Execute: a slightly cheaper copy, and an item of another language
+			var report DuplicateReport
+			item, err := svc.CreateItem(ctx, "dri", "FR", "display", testutil.FloatPtr(175),
+				WithQuantity(2), WithDuplicatePolicy(tt.policy), ReportDuplicates(&report))
+
+			// Assert
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Equal(t, customErr.CodeDuplicateItem, customErr.CodeOf(err))
+			} else {
+				require.NoError(t, err)
+			}
+			other, err := svc.CreateItem(ctx, "DRI", "en", "Display", testutil.FloatPtr(180), WithDuplicatePolicy(tt.policy))
+			require.NoError(t, err)
+
+			items, err := svc.ListItems(ctx, repository.ItemFilter{LanguageCode: "fr"})
+			require.NoError(t, err)
+			assert.NotEqual(t, existing.ID, other.ID)
+			tt.validate(t, existing, item, report, items)
+		})
+	}
+}
+
+func TestItemService_CreateItem_DefaultDuplicatePolicy(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	svc := NewItemService(repository.NewUnitOfWork(db), WithDefaultDuplicatePolicy(DuplicateReject))
+	ctx := context.Background()
+
+	_, err := svc.CreateItem(ctx, "DRI", "fr", "Display", nil)
+	require.NoError(t, err)
+
+	// Execute
+	_, rejected := svc.CreateItem(ctx, "DRI", "fr", "Display", nil)
+	_, allowed := svc.CreateItem(ctx, "DRI", "fr", "Display", nil, WithDuplicatePolicy(DuplicateAllow))
+	_, invalid := svc.CreateItem(ctx, "DRI", "fr", "Display", nil, WithDuplicatePolicy("merge"))
+
+	// Assert
+	assert.ErrorIs(t, rejected, customErr.ErrDuplicateItem)
+	assert.NoError(t, allowed)
+	assert.ErrorIs(t, invalid, customErr.ErrValidationFailed)
+}
+
+func TestItemService_FindAndMergeDuplicates(t *testing.T) {
+	// Setup: two copies of a display bought days apart, and one bought a
+	// year later
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	uow := repository.NewUnitOfWork(db)
+	svc := NewItemService(uow)
+	audit := NewAuditService(uow)
+	ctx := context.Background()
+
+	bought := time.Date(2024, 3, 28, 0, 0, 0, 0, time.UTC)
+	first, err := svc.CreateItem(ctx, "DRI", "fr", "Display", nil, WithPurchasedAt(bought.AddDate(0, 0, 3)))
+	require.NoError(t, err)
+	second, err := svc.CreateItem(ctx, "DRI", "fr", "Display", testutil.FloatPtr(180), WithPurchasedAt(bought), WithQuantity(2))
+	require.NoError(t, err)
+	_, err = svc.CreateItem(ctx, "DRI", "fr", "Display", nil, WithPurchasedAt(bought.AddDate(1, 0, 0)))
+	require.NoError(t, err)
+	_, err = svc.UpdateItem(ctx, second.ID, ItemUpdate{Price: testutil.FloatPtr(170)})
+	require.NoError(t, err)
+
+	// Execute
+	pairs, err := svc.FindDuplicates(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, pairs, 1)
+	assert.Equal(t, first.ID, pairs[0].Item.ID)
+	assert.Equal(t, second.ID, pairs[0].Duplicate.ID)
+
+	// Execute
+	merged, err := svc.MergeItems(ctx, first.ID, second.ID)
+
+	// Assert: the kept item gets the copies, the price and the earlier
+	// purchase date, and the history of both
+	require.NoError(t, err)
+	assert.Equal(t, 3, merged.Quantity)
+	require.NotNil(t, merged.Price)
+	assert.Equal(t, 170.0, *merged.Price)
+	require.NotNil(t, merged.PurchasedAt)
+	assert.True(t, merged.PurchasedAt.Equal(bought))
+
+	_, err = svc.GetItem(ctx, second.ID)
+	assert.ErrorIs(t, err, customErr.ErrEntityNotFound)
+
+	pairs, err = svc.FindDuplicates(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, pairs)
+
+	history, err := audit.ItemHistory(ctx, first.ID)
+	require.NoError(t, err)
+	entities := make(map[uint]bool)
+	for _, entry := range history {
+		entities[entry.EntityID] = true
+	}
+	assert.True(t, entities[second.ID], "the history of the duplicate is kept")
+}
+
+func TestItemService_MergeItems_Undo(t *testing.T) {
+	// Setup: the duplicate has a price history and an alert rule
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	uow := repository.NewUnitOfWork(db)
+	svc := NewItemService(uow)
+	audit := NewAuditService(uow)
+	ctx := context.Background()
+
+	first, err := svc.CreateItem(ctx, "DRI", "fr", "Display", nil)
+	require.NoError(t, err)
+	second, err := svc.CreateItem(ctx, "DRI", "fr", "Display", nil)
+	require.NoError(t, err)
+	err = uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		if err := uow.PriceRecords().Create(ctx, &models.PriceRecord{ItemID: second.ID, Price: 190, Currency: "EUR", Source: "file", QuotedAt: time.Now()}); err != nil {
+			return err
+		}
+		return uow.AlertRules().Create(ctx, &models.AlertRule{Name: "cheap", ItemID: &second.ID, Condition: models.AlertBelow, Threshold: 150})
+	})
+	require.NoError(t, err)
+
+	_, err = svc.MergeItems(ctx, first.ID, second.ID)
+	require.NoError(t, err)
+
+	// Execute
+	_, err = audit.UndoLast(ctx, 1)
+
+	// Assert: the duplicate is back with its records
+	require.NoError(t, err)
+	_, err = svc.GetItem(ctx, second.ID)
+	require.NoError(t, err)
+
+	var records, rules []uint
+	require.NoError(t, db.Model(&models.PriceRecord{}).Order("id").Pluck("item_id", &records).Error)
+	require.NoError(t, db.Model(&models.AlertRule{}).Order("id").Pluck("item_id", &rules).Error)
+	assert.Equal(t, []uint{second.ID}, records)
+	assert.Equal(t, []uint{second.ID}, rules)
+}
+
+func TestItemService_MergeItems_Invalid(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	svc := NewItemService(repository.NewUnitOfWork(db))
+	ctx := context.Background()
+
+	fr, err := svc.CreateItem(ctx, "DRI", "fr", "Display", nil)
+	require.NoError(t, err)
+	en, err := svc.CreateItem(ctx, "DRI", "en", "Display", nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		keepID        uint
+		dropID        uint
+		expectedError error
+	}{
+		{"same item", fr.ID, fr.ID, customErr.ErrValidationFailed},
+		{"other product", fr.ID, en.ID, customErr.ErrValidationFailed},
+		{"unknown item", fr.ID, 999, customErr.ErrEntityNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Execute
+			item, err := svc.MergeItems(ctx, tt.keepID, tt.dropID)
+
+			// Assert
+			assert.Nil(t, item)
+			assert.ErrorIs(t, err, tt.expectedError)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+)
+
+// maxIdempotencyKeyLength bounds the keys chosen by clients, which are
+// usually UUIDs.
+const maxIdempotencyKeyLength = 255
+
+// defaultIdempotencyRetention is how long an idempotency key is remembered
+// unless WithIdempotencyRetention says otherwise.
+const defaultIdempotencyRetention = 24 * time.Hour
+
+// WithIdempotencyKey makes CreateItem and CreateItems idempotent: called
+// again with the same key and the same items by the same actor, within the
+// retention of the service, they return the items created by the first call
+// instead of adding others. Reusing the key for other items fails with
+// ErrIdempotencyKeyReused.
+func WithIdempotencyKey(key string) CreateOption {
+	return func(o *createOptions) {
+		o.idempotencyKey = key
+	}
+}
+
+// ReportReplayed has CreateItem and CreateItems set replayed when they
+// returned the result of an earlier call made with the same idempotency
+// key.
+func ReportReplayed(replayed *bool) CreateOption {
+	return func(o *createOptions) {
+		o.replayed = replayed
+	}
+}
+
+// WithIdempotencyRetention sets how long idempotency keys are remembered,
+// 24 hours by default.
+func WithIdempotencyRetention(retention time.Duration) ItemServiceOption {
+	return func(s *itemService) {
+		if retention > 0 {
+			s.idempotencyRetention = retention
+		}
+	}
+}
+
+// requestHash identifies the request made with an idempotency key, from a
+// value holding its normalized input.
+func requestHash(request interface{}) (string, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// idempotent runs create in uow, which stores the returned item IDs under
+// key, unless the actor of ctx already used key for op. It then returns the
+// IDs stored by that call instead, and reports the replay. The key is
+// stored in the transaction of the items, which Do begins by taking the
+// write lock: a concurrent call with the same key waits for this one and
+// then finds the key.
+func (s *itemService) idempotent(ctx context.Context, uow repository.UnitOfWork, op, key, hash string, create func() ([]uint, error)) ([]uint, bool, error) {
+	now := time.Now()
+	keys := uow.IdempotencyKeys()
+
+	if _, err := keys.DeleteExpired(ctx, now); err != nil {
+		return nil, false, customErr.NewServiceError(op, "item_service", "failed to forget expired idempotency keys", err)
+	}
+
+	stored, err := keys.Find(ctx, op, key, now)
+	switch {
+	case err == nil:
+		if stored.RequestHash != hash {
+			return nil, false, customErr.NewServiceError(op, "item_service", fmt.Sprintf("idempotency key '%s' was already used for another request", key), customErr.ErrIdempotencyKeyReused)
+		}
+		return stored.ItemIDs, true, nil
+	case !errors.Is(err, customErr.ErrEntityNotFound):
+		return nil, false, customErr.NewServiceError(op, "item_service", fmt.Sprintf("failed to look up idempotency key '%s'", key), err)
+	}
+
+	ids, err := create()
+	if err != nil {
+		return nil, false, err
+	}
+
+	record := &models.IdempotencyKey{
+		Operation:   op,
+		Key:         key,
+		RequestHash: hash,
+		ItemIDs:     ids,
+		ExpiresAt:   now.Add(s.idempotencyRetention),
+	}
+	if err := keys.Create(ctx, record); err != nil {
+		return nil, false, customErr.NewServiceError(op, "item_service", fmt.Sprintf("failed to store idempotency key '%s'", key), err)
+	}
+	return ids, false, nil
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/pkmc/internal/database"
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+	"github.com/R4yL-dev/pkmc/internal/seed"
+	"github.com/R4yL-dev/pkmc/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemService_CreateItem_IdempotencyKey(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	uow := repository.NewUnitOfWork(db)
+	svc := NewItemService(uow)
+	phone := repository.WithActor(context.Background(), "token:phone")
+
+	var replayed bool
+	first, err := svc.CreateItem(phone, "DRI", "fr", "Display", testutil.FloatPtr(180), WithIdempotencyKey("k1"), ReportReplayed(&replayed))
+	require.NoError(t, err)
+	assert.False(t, replayed)
+
+	// Execute: the retry, spelled differently
+	retried, err := svc.CreateItem(phone, "dri", "FR", "display", testutil.FloatPtr(180), WithIdempotencyKey("k1"), ReportReplayed(&replayed))
+
+	// Assert: the first item is returned, and nothing else is written
+	require.NoError(t, err)
+	assert.True(t, replayed)
+	assert.Equal(t, first.ID, retried.ID)
+	assert.Equal(t, "DRI", retried.Extension.Code)
+
+	items, err := svc.ListItems(phone, repository.ItemFilter{})
+	require.NoError(t, err)
+	assert.Len(t, items, 1)
+
+	stored, err := uow.Outbox().Due(phone, time.Now(), 0)
+	require.NoError(t, err)
+	assert.Len(t, stored, 1, "the replay emits no event")
+
+	// Execute & Assert: the key of another request
+	_, err = svc.CreateItem(phone, "DRI", "fr", "Display", testutil.FloatPtr(200), WithIdempotencyKey("k1"))
+	assert.ErrorIs(t, err, customErr.ErrIdempotencyKeyReused)
+	assert.Equal(t, customErr.CodeIdempotencyKeyReused, customErr.CodeOf(err))
+
+	// Execute & Assert: the same key of another actor
+	laptop := repository.WithActor(context.Background(), "token:laptop")
+	other, err := svc.CreateItem(laptop, "DRI", "fr", "Display", testutil.FloatPtr(180), WithIdempotencyKey("k1"))
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, other.ID)
+
+	// Execute & Assert: an expired key is forgotten
+	require.NoError(t, db.Model(&models.IdempotencyKey{}).Where("1 = 1").Update("expires_at", time.Now().Add(-time.Minute)).Error)
+	again, err := svc.CreateItem(phone, "DRI", "fr", "Display", testutil.FloatPtr(180), WithIdempotencyKey("k1"), ReportReplayed(&replayed))
+	require.NoError(t, err)
+	assert.False(t, replayed)
+	assert.NotEqual(t, first.ID, again.ID)
+
+	var keys int64
+	db.Model(&models.IdempotencyKey{}).Count(&keys)
+	assert.Equal(t, int64(1), keys, "expired keys are deleted")
+}
+
+func TestItemService_CreateItem_IdempotencyKeyRolledBack(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	svc := NewItemService(repository.NewUnitOfWork(db), WithDefaultDuplicatePolicy(DuplicateReject))
+	ctx := context.Background()
+
+	_, err := svc.CreateItem(ctx, "DRI", "fr", "Display", nil)
+	require.NoError(t, err)
+
+	// Execute: a rejected creation does not keep its key
+	_, err = svc.CreateItem(ctx, "DRI", "fr", "Display", nil, WithIdempotencyKey("k1"))
+	require.ErrorIs(t, err, customErr.ErrDuplicateItem)
+	item, err := svc.CreateItem(ctx, "DRI", "fr", "Display", nil, WithIdempotencyKey("k1"), WithDuplicatePolicy(DuplicateAllow))
+
+	// Assert
+	require.NoError(t, err)
+	assert.NotZero(t, item.ID)
+
+	var keys int64
+	db.Model(&models.IdempotencyKey{}).Count(&keys)
+	assert.Equal(t, int64(1), keys)
+}
+
+func TestItemService_CreateItem_ConcurrentIdempotencyKey(t *testing.T) {
+	// Setup: a file database shared by several connections, opened like
+	// the application does
+	db, err := database.InitDB(filepath.Join(t.TempDir(), "pkmc.db"), database.DefaultSettings())
+	require.NoError(t, err)
+	defer database.CloseDB(db)
+	require.NoError(t, db.AutoMigrate(models.GetModels()...))
+	seed.Seed(db)
+
+	svc := NewItemService(repository.NewUnitOfWork(db))
+	ctx := repository.WithActor(context.Background(), "token:phone")
+
+	// Execute: the same request sent several times at once
+	const calls = 8
+	ids := make([]uint, calls)
+	errs := make([]error, calls)
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			item, err := svc.CreateItem(ctx, "DRI", "fr", "Display", nil, WithIdempotencyKey("k1"))
+			if err == nil {
+				ids[i] = item.ID
+			}
+			errs[i] = err
+		}(i)
+	}
+	wg.Wait()
+
+	// Assert: one item, returned to every call
+	for i := 0; i < calls; i++ {
+		require.NoError(t, errs[i])
+		assert.Equal(t, ids[0], ids[i])
+	}
+	var count int64
+	db.Model(&models.Item{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
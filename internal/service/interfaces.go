@@ -2,10 +2,182 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository"
 )
 
+type ItemUpdate struct {
+	ExtensionCode *string
+	LanguageCode  *string
+	TypeName      *string
+	Price         *float64
+	ClearPrice    bool
+	Quantity      *int
+	PurchasedAt   *time.Time
+}
+
+// ItemSpec describes an item added by CreateItems. A zero Quantity is 1.
+type ItemSpec struct {
+	ExtensionCode string
+	LanguageCode  string
+	TypeName      string
+	Price         *float64
+	Quantity      int
+	PurchasedAt   *time.Time
+}
+
+type CollectionStats struct {
+	Totals      repository.ItemAggregate
+	ByBlock     []repository.ItemAggregate
+	ByExtension []repository.ItemAggregate
+	ByLanguage  []repository.ItemAggregate
+	ByType      []repository.ItemAggregate
+}
+
 type ItemService interface {
-	CreateItem(ctx context.Context, extCode, langCode, typeName string, price *float64) (*models.Item, error)
+	// CreateItem adds an item, handling likely duplicates of it as the
+	// duplicate policy says. With WithIdempotencyKey, a repeated call
+	// returns the item of the first one.
+	CreateItem(ctx context.Context, extCode, langCode, typeName string, price *float64, opts ...CreateOption) (*models.Item, error)
+	// CreateItems adds the items of specs in one transaction and returns
+	// them. When any entry is invalid, refers to a missing extension,
+	// language or item type or is refused by the duplicate policy, nothing
+	// is added and the error is a BatchError reporting each such entry.
+	// The policy applies to each entry as in CreateItem, against the items
+	// already in the collection: the entries of a batch are not compared
+	// with each other. Of the options, WithDuplicatePolicy,
+	// ReportBatchDuplicates, WithIdempotencyKey and ReportReplayed apply.
+	CreateItems(ctx context.Context, specs []ItemSpec, opts ...CreateOption) ([]models.Item, error)
+	GetItem(ctx context.Context, id uint) (*models.Item, error)
+	ListItems(ctx context.Context, filter repository.ItemFilter) ([]models.Item, error)
+	UpdateItem(ctx context.Context, id uint, update ItemUpdate) (*models.Item, error)
+	DeleteItem(ctx context.Context, id uint) error
+	// SellItem marks an item as sold on soldAt, now when nil, for price
+	// when known. The item stays in the collection; selling it twice fails
+	// with ErrConflict.
+	SellItem(ctx context.Context, id uint, price *float64, soldAt *time.Time) (*models.Item, error)
+	// PurgeDeleted removes for good the items deleted before before, which
+	// can then no longer be undeleted, and returns how many it removed.
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+	// FindDuplicates returns the pairs of items that look like duplicates.
+	FindDuplicates(ctx context.Context) ([]DuplicatePair, error)
+	// MergeItems folds the item dropID into the item keepID, which gets
+	// its copies, its price history and alert rules, and its price and
+	// purchase date when it has none, then deletes it.
+	MergeItems(ctx context.Context, keepID, dropID uint) (*models.Item, error)
+}
+
+type CatalogService interface {
+	ListBlocks(ctx context.Context) ([]models.Block, error)
+	ListExtensions(ctx context.Context, blockCode string) ([]models.Extension, error)
+	GetExtension(ctx context.Context, code string) (*models.Extension, error)
+	ListLanguages(ctx context.Context) ([]models.Language, error)
+	ListItemTypes(ctx context.Context) ([]models.ItemType, error)
+}
+
+type StatsService interface {
+	CollectionStats(ctx context.Context) (*CollectionStats, error)
+}
+
+type TokenService interface {
+	// IssueToken creates a token and returns it with its secret, which is
+	// not stored and cannot be retrieved later.
+	IssueToken(ctx context.Context, name string, role models.Role) (*models.APIToken, string, error)
+	ListTokens(ctx context.Context) ([]models.APIToken, error)
+	RevokeToken(ctx context.Context, id uint) error
+	Authenticate(ctx context.Context, secret string) (*models.APIToken, error)
+}
+
+type AuditService interface {
+	ItemHistory(ctx context.Context, id uint) ([]models.AuditEntry, error)
+	ListChanges(ctx context.Context, filter repository.AuditFilter) ([]models.AuditEntry, error)
+	// UndoLast reverts the n most recent item operations that were not
+	// undone yet and returns the audit entries written by the undo.
+	UndoLast(ctx context.Context, n int) ([]models.AuditEntry, error)
+	// UndoOperation reverts one operation and returns the audit entries
+	// written by the undo.
+	UndoOperation(ctx context.Context, operationID string) ([]models.AuditEntry, error)
+}
+
+// WebhookInput describes a webhook to create. An empty Secret is generated
+// and an empty Name defaults to the host of the URL.
+type WebhookInput struct {
+	Name           string
+	URL            string
+	Events         []string
+	Secret         string
+	PriceThreshold *float64
+}
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, input WebhookInput) (*models.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id uint) error
+	ListDeliveries(ctx context.Context, filter repository.WebhookDeliveryFilter) ([]models.WebhookDelivery, error)
+}
+
+// PriceRefreshStatus is the outcome of refreshing the price of one item.
+type PriceRefreshStatus string
+
+const (
+	// PriceUpdated: the quote was recorded and differs from the previous
+	// one, if any.
+	PriceUpdated PriceRefreshStatus = "updated"
+	// PriceUnchanged: the quote was recorded and equals the previous one.
+	PriceUnchanged PriceRefreshStatus = "unchanged"
+	// PriceMissing: no provider has a quote for the item.
+	PriceMissing PriceRefreshStatus = "missing"
+	// PriceFailed: the item could not be quoted, see Error.
+	PriceFailed PriceRefreshStatus = "failed"
+)
+
+// PriceRefresh reports the refresh of the current value of one item.
+// OldPrice is the previous quote of the item and NewPrice the new one; the
+// price paid for the item is left alone.
+type PriceRefresh struct {
+	ItemID   uint
+	Status   PriceRefreshStatus
+	OldPrice *float64
+	NewPrice *float64
+	Currency string
+	Source   string
+	Error    string
+}
+
+type PriceService interface {
+	// Providers lists the configured price providers.
+	Providers() []string
+	// RefreshPrices quotes every item not sold with the named provider, or
+	// with the configured providers in turn when provider is empty, records
+	// the quotes in the price history as the current values of the items,
+	// then evaluates the alert rules. The items themselves, whose price is
+	// the price paid, are not changed. Items that cannot be quoted are
+	// reported.
+	RefreshPrices(ctx context.Context, provider string) ([]PriceRefresh, error)
+	// PriceHistory returns the recorded quotes of an item, newest first.
+	PriceHistory(ctx context.Context, itemID uint, limit int) ([]models.PriceRecord, error)
+}
+
+// AlertRuleInput describes an alert rule to create. It watches the item
+// ItemID, or the product given by ExtensionCode, ItemType and
+// LanguageCode. Threshold is a price for the above and below conditions
+// and a percentage for rise and drop, which also need a Window such as
+// "7d". An empty Name describes the rule.
+type AlertRuleInput struct {
+	Name          string
+	ItemID        *uint
+	ExtensionCode string
+	ItemType      string
+	LanguageCode  string
+	Condition     string
+	Threshold     float64
+	Window        string
+}
+
+type AlertService interface {
+	CreateRule(ctx context.Context, input AlertRuleInput) (*models.AlertRule, error)
+	ListRules(ctx context.Context) ([]models.AlertRule, error)
+	DeleteRule(ctx context.Context, id uint) error
 }
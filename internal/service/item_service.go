@@ -2,56 +2,254 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/events"
 	"github.com/R4yL-dev/pkmc/internal/models"
 	"github.com/R4yL-dev/pkmc/internal/repository"
 )
 
+// missingReferenceMessage explains a foreign key violation on an item, which
+// happens when a catalog entry it refers to is removed meanwhile.
+const missingReferenceMessage = "the extension, language or item type of the item no longer exists"
+
 type itemService struct {
-	uow repository.UnitOfWork
+	uow                  repository.UnitOfWork
+	duplicatePolicy      DuplicatePolicy
+	idempotencyRetention time.Duration
 }
 
-func NewItemService(uow repository.UnitOfWork) ItemService {
-	return &itemService{uow: uow}
+func NewItemService(uow repository.UnitOfWork, opts ...ItemServiceOption) ItemService {
+	s := &itemService{uow: uow, duplicatePolicy: DuplicateAllow, idempotencyRetention: defaultIdempotencyRetention}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-func (s *itemService) CreateItem(ctx context.Context, extCode, langCode, typeName string, price *float64) (*models.Item, error) {
+func (s *itemService) CreateItem(ctx context.Context, extCode, langCode, typeName string, price *float64, opts ...CreateOption) (*models.Item, error) {
+	extCode, langCode, typeName = normalizeCode(extCode), normalizeLanguageCode(langCode), normalizeName(typeName)
+	options := createOptions{quantity: 1, policy: s.duplicatePolicy}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	v := &validator{}
+	v.code("extension_code", extCode)
+	v.code("language_code", langCode)
+	v.name("type", typeName)
+	v.price("price", price)
+	v.quantity("quantity", options.quantity)
+	v.check(options.policy.Valid(), "duplicate_policy", customErr.RuleOneOf, fmt.Sprintf("unknown duplicate policy '%s': expected allow, warn, reject or increment", options.policy))
+	v.maxLength("idempotency_key", options.idempotencyKey, maxIdempotencyKeyLength)
+	if err := v.err("create_item", "item_service"); err != nil {
+		return nil, err
+	}
+
+	var hash string
+	if options.idempotencyKey != "" {
+		// Item types are matched regardless of case, so their case does not
+		// tell requests apart.
+		var err error
+		hash, err = requestHash(struct {
+			ExtensionCode, LanguageCode, Type string
+			Price                             *float64
+			Quantity                          int
+			PurchasedAt                       *time.Time
+			Policy                            DuplicatePolicy
+		}{extCode, langCode, strings.ToLower(typeName), price, options.quantity, options.purchasedAt, options.policy})
+		if err != nil {
+			return nil, customErr.NewServiceError("create_item", "item_service", "failed to hash the request", err)
+		}
+	}
+
 	var createdItem *models.Item
 
 	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
-		ext, err := uow.Extensions().FindByCode(ctx, extCode)
+		if options.idempotencyKey == "" {
+			var err error
+			createdItem, err = createItem(ctx, uow, extCode, langCode, typeName, price, options)
+			return err
+		}
+
+		ids, replayed, err := s.idempotent(ctx, uow, "create_item", options.idempotencyKey, hash, func() ([]uint, error) {
+			item, err := createItem(ctx, uow, extCode, langCode, typeName, price, options)
+			if err != nil {
+				return nil, err
+			}
+			createdItem = item
+			return []uint{item.ID}, nil
+		})
 		if err != nil {
-			return customErr.NewServiceError("create_item", "item_service", fmt.Sprintf("extension '%s' not found", extCode), err)
+			return err
+		}
+		if options.replayed != nil {
+			*options.replayed = replayed
+		}
+		if !replayed {
+			return nil
 		}
 
-		lang, err := uow.Languages().FindByCode(ctx, langCode)
+		if options.report != nil {
+			*options.report = DuplicateReport{Policy: options.policy}
+		}
+		createdItem, err = uow.Items().FindByID(ctx, ids[0])
 		if err != nil {
-			return customErr.NewServiceError("create_item", "item_service", fmt.Sprintf("language '%s' not found", langCode), err)
+			return customErr.NewServiceError("create_item", "item_service", fmt.Sprintf("item %d created with idempotency key '%s' no longer exists", ids[0], options.idempotencyKey), err)
 		}
+		return nil
+	})
 
-		itemType, err := uow.ItemTypes().FindByName(ctx, typeName)
+	if err != nil {
+		return nil, err
+	}
+
+	return createdItem, nil
+}
+
+// createItem adds an item in uow, following the duplicate policy of
+// options.
+func createItem(ctx context.Context, uow repository.UnitOfWork, extCode, langCode, typeName string, price *float64, options createOptions) (*models.Item, error) {
+	ext, err := uow.Extensions().FindByCode(ctx, extCode)
+	if err != nil {
+		return nil, customErr.NewServiceError("create_item", "item_service", fmt.Sprintf("extension '%s' not found", extCode), err)
+	}
+
+	lang, err := uow.Languages().FindByCode(ctx, langCode)
+	if err != nil {
+		return nil, customErr.NewServiceError("create_item", "item_service", fmt.Sprintf("language '%s' not found", langCode), err)
+	}
+
+	itemType, err := findItemType(ctx, uow, typeName)
+	if err != nil {
+		return nil, customErr.NewServiceError("create_item", "item_service", fmt.Sprintf("item type '%s' not found", typeName), err)
+	}
+
+	item := &models.Item{
+		ExtensionID: ext.ID,
+		TypeID:      itemType.ID,
+		LanguageID:  lang.ID,
+		Price:       price,
+		Quantity:    options.quantity,
+		PurchasedAt: options.purchasedAt,
+	}
+
+	var matches []models.Item
+	if options.policy != DuplicateAllow {
+		candidates, err := uow.Items().List(ctx, repository.ItemFilter{ExtensionCode: ext.Code, LanguageCode: lang.Code, TypeName: itemType.Name})
 		if err != nil {
-			return customErr.NewServiceError("create_item", "item_service", fmt.Sprintf("item type '%s' not found", typeName), err)
+			return nil, customErr.NewServiceError("create_item", "item_service", "failed to look for duplicates", err)
 		}
+		for i := range candidates {
+			if likelyDuplicates(item, &candidates[i]) {
+				matches = append(matches, candidates[i])
+			}
+		}
+	}
+	if options.report != nil {
+		*options.report = DuplicateReport{Policy: options.policy, Matches: matches}
+	}
 
-		item := &models.Item{
-			ExtensionID: ext.ID,
-			TypeID:      itemType.ID,
-			LanguageID:  lang.ID,
-			Price:       price,
+	if len(matches) > 0 {
+		switch options.policy {
+		case DuplicateReject:
+			return nil, customErr.NewServiceError("create_item", "item_service", fmt.Sprintf("the item looks like a duplicate of item %d", matches[0].ID), customErr.ErrDuplicateItem)
+		case DuplicateIncrement:
+			incremented, err := incrementQuantity(ctx, uow, &matches[0], options.quantity)
+			if err == nil && options.report != nil {
+				options.report.Incremented = true
+			}
+			return incremented, err
 		}
+	}
 
-		if err := uow.Items().Create(ctx, item); err != nil {
-			return customErr.NewServiceError("create_item", "item_service", "failed to create item", err)
+	if err := uow.Items().Create(ctx, item); err != nil {
+		if errors.Is(err, customErr.ErrForeignKeyViolation) {
+			return nil, customErr.NewServiceError("create_item", "item_service", missingReferenceMessage, err)
 		}
+		return nil, customErr.NewServiceError("create_item", "item_service", "failed to create item", err)
+	}
+
+	createdItem, err := uow.Items().FindByID(ctx, item.ID)
+	if err != nil {
+		return nil, customErr.NewServiceError("create_item", "item_service", "failed to load created item", err)
+	}
+
+	created := events.ItemSnapshot(createdItem)
+	if err := emitItemChange(ctx, uow, "create_item", "item_service", nil, &created); err != nil {
+		return nil, err
+	}
+	return createdItem, nil
+}
+
+// incrementQuantity adds quantity copies to item, in place of creating a
+// duplicate of it, and returns the updated item.
+func incrementQuantity(ctx context.Context, uow repository.UnitOfWork, item *models.Item, quantity int) (*models.Item, error) {
+	before := events.ItemSnapshot(item)
+
+	item.Quantity += quantity
+	if err := uow.Items().Update(ctx, item); err != nil {
+		return nil, customErr.NewServiceError("create_item", "item_service", fmt.Sprintf("failed to increment the quantity of item %d", item.ID), err)
+	}
+
+	updated, err := uow.Items().FindByID(ctx, item.ID)
+	if err != nil {
+		return nil, customErr.NewServiceError("create_item", "item_service", "failed to load updated item", err)
+	}
+
+	after := events.ItemSnapshot(updated)
+	return updated, emitItemChange(ctx, uow, "create_item", "item_service", &before, &after)
+}
+
+func (s *itemService) GetItem(ctx context.Context, id uint) (*models.Item, error) {
+	var item *models.Item
 
-		createdItem, err = uow.Items().FindByID(ctx, item.ID)
+	err := s.uow.DoRead(ctx, func(uow repository.UnitOfWork) error {
+		var err error
+		item, err = uow.Items().FindByID(ctx, id)
 		if err != nil {
-			return customErr.NewServiceError("create_item", "item_service", "failed to load created item", err)
+			return customErr.NewServiceError("get_item", "item_service", fmt.Sprintf("item %d not found", id), err)
 		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
 
+	return item, nil
+}
+
+func (s *itemService) ListItems(ctx context.Context, filter repository.ItemFilter) ([]models.Item, error) {
+	filter.ExtensionCode = normalizeCode(filter.ExtensionCode)
+	filter.BlockCode = normalizeCode(filter.BlockCode)
+	filter.LanguageCode = normalizeLanguageCode(filter.LanguageCode)
+	filter.TypeName = normalizeName(filter.TypeName)
+
+	v := &validator{}
+	v.price("min_price", filter.MinPrice)
+	v.price("max_price", filter.MaxPrice)
+	if filter.MinPrice != nil && filter.MaxPrice != nil {
+		v.check(*filter.MaxPrice >= *filter.MinPrice, "max_price", customErr.RuleMin, "max_price must not be below min_price")
+	}
+	v.check(filter.Limit >= 0, "limit", customErr.RuleMin, "limit must not be negative")
+	v.check(filter.Offset >= 0, "offset", customErr.RuleMin, "offset must not be negative")
+	if err := v.err("list_items", "item_service"); err != nil {
+		return nil, err
+	}
+
+	var items []models.Item
+
+	err := s.uow.DoRead(ctx, func(uow repository.UnitOfWork) error {
+		var err error
+		items, err = uow.Items().List(ctx, filter)
+		if err != nil {
+			return customErr.NewServiceError("list_items", "item_service", "failed to list items", err)
+		}
 		return nil
 	})
 
@@ -59,5 +257,287 @@ func (s *itemService) CreateItem(ctx context.Context, extCode, langCode, typeNam
 		return nil, err
 	}
 
-	return createdItem, nil
+	return items, nil
+}
+
+func (s *itemService) UpdateItem(ctx context.Context, id uint, update ItemUpdate) (*models.Item, error) {
+	v := &validator{}
+	if update.ExtensionCode != nil {
+		code := normalizeCode(*update.ExtensionCode)
+		update.ExtensionCode = &code
+		v.code("extension_code", code)
+	}
+	if update.LanguageCode != nil {
+		code := normalizeLanguageCode(*update.LanguageCode)
+		update.LanguageCode = &code
+		v.code("language_code", code)
+	}
+	if update.TypeName != nil {
+		name := normalizeName(*update.TypeName)
+		update.TypeName = &name
+		v.name("type", name)
+	}
+	v.price("price", update.Price)
+	if update.Quantity != nil {
+		v.quantity("quantity", *update.Quantity)
+	}
+	v.check(!update.ClearPrice || update.Price == nil, "price", customErr.RuleExcludes, "price cannot be both set and cleared")
+	if err := v.err("update_item", "item_service"); err != nil {
+		return nil, err
+	}
+
+	var updatedItem *models.Item
+
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		item, err := uow.Items().FindByID(ctx, id)
+		if err != nil {
+			return customErr.NewServiceError("update_item", "item_service", fmt.Sprintf("item %d not found", id), err)
+		}
+		before := events.ItemSnapshot(item)
+
+		if update.ExtensionCode != nil {
+			ext, err := uow.Extensions().FindByCode(ctx, *update.ExtensionCode)
+			if err != nil {
+				return customErr.NewServiceError("update_item", "item_service", fmt.Sprintf("extension '%s' not found", *update.ExtensionCode), err)
+			}
+			item.ExtensionID = ext.ID
+		}
+
+		if update.LanguageCode != nil {
+			lang, err := uow.Languages().FindByCode(ctx, *update.LanguageCode)
+			if err != nil {
+				return customErr.NewServiceError("update_item", "item_service", fmt.Sprintf("language '%s' not found", *update.LanguageCode), err)
+			}
+			item.LanguageID = lang.ID
+		}
+
+		if update.TypeName != nil {
+			itemType, err := findItemType(ctx, uow, *update.TypeName)
+			if err != nil {
+				return customErr.NewServiceError("update_item", "item_service", fmt.Sprintf("item type '%s' not found", *update.TypeName), err)
+			}
+			item.TypeID = itemType.ID
+		}
+
+		if update.ClearPrice {
+			item.Price = nil
+		} else if update.Price != nil {
+			item.Price = update.Price
+		}
+		if update.Quantity != nil {
+			item.Quantity = *update.Quantity
+		}
+		if update.PurchasedAt != nil {
+			item.PurchasedAt = update.PurchasedAt
+		}
+
+		if err := uow.Items().Update(ctx, item); err != nil {
+			if errors.Is(err, customErr.ErrForeignKeyViolation) {
+				return customErr.NewServiceError("update_item", "item_service", missingReferenceMessage, err)
+			}
+			return customErr.NewServiceError("update_item", "item_service", "failed to update item", err)
+		}
+
+		updatedItem, err = uow.Items().FindByID(ctx, id)
+		if err != nil {
+			return customErr.NewServiceError("update_item", "item_service", "failed to load updated item", err)
+		}
+
+		after := events.ItemSnapshot(updatedItem)
+		return emitItemChange(ctx, uow, "update_item", "item_service", &before, &after)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedItem, nil
+}
+
+func (s *itemService) DeleteItem(ctx context.Context, id uint) error {
+	return s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		item, err := uow.Items().FindByID(ctx, id)
+		if err != nil {
+			return customErr.NewServiceError("delete_item", "item_service", fmt.Sprintf("item %d not found", id), err)
+		}
+		before := events.ItemSnapshot(item)
+
+		if err := uow.Items().Delete(ctx, id); err != nil {
+			return customErr.NewServiceError("delete_item", "item_service", fmt.Sprintf("failed to delete item %d", id), err)
+		}
+		return emitItemChange(ctx, uow, "delete_item", "item_service", &before, nil)
+	})
+}
+
+func (s *itemService) SellItem(ctx context.Context, id uint, price *float64, soldAt *time.Time) (*models.Item, error) {
+	v := &validator{}
+	v.price("price", price)
+	if err := v.err("sell_item", "item_service"); err != nil {
+		return nil, err
+	}
+	if soldAt == nil {
+		now := time.Now()
+		soldAt = &now
+	}
+
+	var soldItem *models.Item
+
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		item, err := uow.Items().FindByID(ctx, id)
+		if err != nil {
+			return customErr.NewServiceError("sell_item", "item_service", fmt.Sprintf("item %d not found", id), err)
+		}
+		if item.SoldAt != nil {
+			return customErr.NewServiceError("sell_item", "item_service",
+				fmt.Sprintf("item %d was already sold on %s", id, item.SoldAt.Format(time.DateOnly)), customErr.ErrConflict)
+		}
+		before := events.ItemSnapshot(item)
+
+		item.SoldAt = soldAt
+		item.SoldPrice = price
+		if err := uow.Items().Update(ctx, item); err != nil {
+			return customErr.NewServiceError("sell_item", "item_service", fmt.Sprintf("failed to sell item %d", id), err)
+		}
+
+		soldItem, err = uow.Items().FindByID(ctx, id)
+		if err != nil {
+			return customErr.NewServiceError("sell_item", "item_service", "failed to load sold item", err)
+		}
+
+		after := events.ItemSnapshot(soldItem)
+		return emitItemChange(ctx, uow, "sell_item", "item_service", &before, &after)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return soldItem, nil
+}
+
+func (s *itemService) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		var err error
+		purged, err = uow.Items().Purge(ctx, before)
+		if err != nil {
+			return customErr.NewServiceError("purge_deleted", "item_service", "failed to purge deleted items", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
+
+func (s *itemService) FindDuplicates(ctx context.Context) ([]DuplicatePair, error) {
+	var pairs []DuplicatePair
+
+	err := s.uow.DoRead(ctx, func(uow repository.UnitOfWork) error {
+		items, err := uow.Items().List(ctx, repository.ItemFilter{})
+		if err != nil {
+			return customErr.NewServiceError("find_duplicates", "item_service", "failed to list items", err)
+		}
+		pairs = duplicatePairs(items)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pairs, nil
+}
+
+func (s *itemService) MergeItems(ctx context.Context, keepID, dropID uint) (*models.Item, error) {
+	v := &validator{}
+	v.check(keepID != dropID, "drop_id", customErr.RuleExcludes, "an item cannot be merged into itself")
+	if err := v.err("merge_items", "item_service"); err != nil {
+		return nil, err
+	}
+
+	var mergedItem *models.Item
+
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		keep, err := uow.Items().FindByID(ctx, keepID)
+		if err != nil {
+			return customErr.NewServiceError("merge_items", "item_service", fmt.Sprintf("item %d not found", keepID), err)
+		}
+		drop, err := uow.Items().FindByID(ctx, dropID)
+		if err != nil {
+			return customErr.NewServiceError("merge_items", "item_service", fmt.Sprintf("item %d not found", dropID), err)
+		}
+		if keep.ExtensionID != drop.ExtensionID || keep.TypeID != drop.TypeID || keep.LanguageID != drop.LanguageID {
+			return customErr.NewServiceError("merge_items", "item_service",
+				fmt.Sprintf("items %d and %d are not the same extension, type and language", keepID, dropID), customErr.ErrValidationFailed)
+		}
+
+		before, dropped := events.ItemSnapshot(keep), events.ItemSnapshot(drop)
+		keep.Quantity += drop.Quantity
+		if keep.Price == nil {
+			keep.Price = drop.Price
+		}
+		if keep.PurchasedAt == nil || (drop.PurchasedAt != nil && drop.PurchasedAt.Before(*keep.PurchasedAt)) {
+			keep.PurchasedAt = drop.PurchasedAt
+		}
+
+		if err := uow.Items().Merge(ctx, keep, drop.ID); err != nil {
+			return customErr.NewServiceError("merge_items", "item_service", fmt.Sprintf("failed to merge item %d into item %d", dropID, keepID), err)
+		}
+
+		mergedItem, err = uow.Items().FindByID(ctx, keepID)
+		if err != nil {
+			return customErr.NewServiceError("merge_items", "item_service", "failed to load merged item", err)
+		}
+
+		after := events.ItemSnapshot(mergedItem)
+		if err := emitItemChange(ctx, uow, "merge_items", "item_service", &before, &after); err != nil {
+			return err
+		}
+		return emitItemChange(ctx, uow, "merge_items", "item_service", &dropped, nil)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return mergedItem, nil
+}
+
+// findItemType returns the item type named name, regardless of case: the
+// item type gives the canonical name.
+func findItemType(ctx context.Context, uow repository.UnitOfWork, name string) (*models.ItemType, error) {
+	itemType, err := uow.ItemTypes().FindByName(ctx, name)
+	if !errors.Is(err, customErr.ErrEntityNotFound) {
+		return itemType, err
+	}
+
+	itemTypes, listErr := uow.ItemTypes().FindAll(ctx)
+	if listErr != nil {
+		return nil, err
+	}
+	for i := range itemTypes {
+		if strings.EqualFold(itemTypes[i].Name, name) {
+			return &itemTypes[i], nil
+		}
+	}
+	return nil, err
+}
+
+// emitItemChange appends to the outbox the events describing the change of
+// an item, in the transaction of uow. A nil before is a creation and a nil
+// after a deletion.
+func emitItemChange(ctx context.Context, uow repository.UnitOfWork, op, service string, before, after *events.Item) error {
+	changes, err := events.ForItemChange(before, after)
+	if err != nil {
+		return customErr.NewServiceError(op, service, "failed to build events", err)
+	}
+	for _, event := range changes {
+		if err := uow.Outbox().Append(ctx, event); err != nil {
+			return customErr.NewServiceError(op, service, fmt.Sprintf("failed to record %s event", event.Type), err)
+		}
+	}
+	return nil
 }
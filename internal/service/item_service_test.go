@@ -2,16 +2,22 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"math"
+	"strings"
 	"testing"
 	"time"
 
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/events"
 	"github.com/R4yL-dev/pkmc/internal/models"
 	"github.com/R4yL-dev/pkmc/internal/repository"
 	"github.com/R4yL-dev/pkmc/internal/repository/mocks"
 	"github.com/R4yL-dev/pkmc/internal/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 )
 
@@ -257,64 +263,89 @@ func TestItemService_CreateItem(t *testing.T) {
 			expectedError: "failed to load created item",
 		},
 		{
-			name:     "edge case - empty extension code",
-			extCode:  "",
-			langCode: "fr",
-			typeName: "Display",
-			price:    testutil.FloatPtr(99.99),
-			setupMocks: func(uow *mocks.MockUnitOfWork, items *mocks.MockItemRepository, exts *mocks.MockExtensionRepository, langs *mocks.MockLanguageRepository, types *mocks.MockItemTypeRepository) {
-				uow.On("Do", mock.Anything, mock.AnythingOfType("func(repository.UnitOfWork) error")).Run(func(args mock.Arguments) {
-					fn := args.Get(1).(func(repository.UnitOfWork) error)
-					fn(uow)
-				}).Return(errors.New("extension '' not found: record not found"))
-
-				uow.On("Extensions").Return(exts)
-
-				exts.On("FindByCode", mock.Anything, "").Return(nil, errors.New("record not found"))
-			},
-			expectedError: "extension '' not found",
+			name:          "validation - empty extension code",
+			extCode:       "",
+			langCode:      "fr",
+			typeName:      "Display",
+			price:         testutil.FloatPtr(99.99),
+			expectedError: "extension_code is required",
 		},
 		{
-			name:     "edge case - empty language code",
-			extCode:  "DRI",
-			langCode: "",
-			typeName: "Display",
-			price:    testutil.FloatPtr(99.99),
-			setupMocks: func(uow *mocks.MockUnitOfWork, items *mocks.MockItemRepository, exts *mocks.MockExtensionRepository, langs *mocks.MockLanguageRepository, types *mocks.MockItemTypeRepository) {
-				uow.On("Do", mock.Anything, mock.AnythingOfType("func(repository.UnitOfWork) error")).Run(func(args mock.Arguments) {
-					fn := args.Get(1).(func(repository.UnitOfWork) error)
-					fn(uow)
-				}).Return(errors.New("language '' not found: record not found"))
-
-				uow.On("Extensions").Return(exts)
-				uow.On("Languages").Return(langs)
-
-				exts.On("FindByCode", mock.Anything, "DRI").Return(&models.Extension{Model: gorm.Model{ID: 1}}, nil)
-				langs.On("FindByCode", mock.Anything, "").Return(nil, errors.New("record not found"))
-			},
-			expectedError: "language '' not found",
+			name:          "validation - blank language code",
+			extCode:       "DRI",
+			langCode:      "  ",
+			typeName:      "Display",
+			price:         testutil.FloatPtr(99.99),
+			expectedError: "language_code is required",
 		},
 		{
-			name:     "edge case - empty type name",
-			extCode:  "DRI",
-			langCode: "fr",
-			typeName: "",
-			price:    testutil.FloatPtr(99.99),
+			name:          "validation - empty type name",
+			extCode:       "DRI",
+			langCode:      "fr",
+			typeName:      "",
+			price:         testutil.FloatPtr(99.99),
+			expectedError: "type is required",
+		},
+		{
+			name:          "validation - negative price",
+			extCode:       "DRI",
+			langCode:      "fr",
+			typeName:      "Display",
+			price:         testutil.FloatPtr(-1),
+			expectedError: "price must not be negative",
+		},
+		{
+			name:          "validation - NaN price",
+			extCode:       "DRI",
+			langCode:      "fr",
+			typeName:      "Display",
+			price:         testutil.FloatPtr(math.NaN()),
+			expectedError: "price must be a number",
+		},
+		{
+			name:          "validation - absurd price",
+			extCode:       "DRI",
+			langCode:      "fr",
+			typeName:      "Display",
+			price:         testutil.FloatPtr(1e12),
+			expectedError: "price must be at most 1000000",
+		},
+		{
+			name:          "validation - overlong code",
+			extCode:       "DRIDRIDRIDRIDRIDRI",
+			langCode:      "fr",
+			typeName:      "Display",
+			expectedError: "extension_code must be at most 16 characters",
+		},
+		{
+			name:     "success - normalizes codes and type name",
+			extCode:  " dri ",
+			langCode: "FR",
+			typeName: "sleeve   booster",
 			setupMocks: func(uow *mocks.MockUnitOfWork, items *mocks.MockItemRepository, exts *mocks.MockExtensionRepository, langs *mocks.MockLanguageRepository, types *mocks.MockItemTypeRepository) {
-				uow.On("Do", mock.Anything, mock.AnythingOfType("func(repository.UnitOfWork) error")).Run(func(args mock.Arguments) {
-					fn := args.Get(1).(func(repository.UnitOfWork) error)
-					fn(uow)
-				}).Return(errors.New("item type '' not found: record not found"))
-
+				runInUoW(uow)
 				uow.On("Extensions").Return(exts)
 				uow.On("Languages").Return(langs)
 				uow.On("ItemTypes").Return(types)
+				uow.On("Items").Return(items)
 
-				exts.On("FindByCode", mock.Anything, "DRI").Return(&models.Extension{Model: gorm.Model{ID: 1}}, nil)
-				langs.On("FindByCode", mock.Anything, "fr").Return(&models.Language{Model: gorm.Model{ID: 1}}, nil)
-				types.On("FindByName", mock.Anything, "").Return(nil, errors.New("record not found"))
+				exts.On("FindByCode", mock.Anything, "DRI").Return(&models.Extension{Model: gorm.Model{ID: 1}, Code: "DRI"}, nil)
+				langs.On("FindByCode", mock.Anything, "fr").Return(&models.Language{Model: gorm.Model{ID: 1}, Code: "fr"}, nil)
+				types.On("FindByName", mock.Anything, "sleeve booster").Return(nil, customErr.NewRepositoryError("find", "item_type", "sleeve booster", customErr.ErrEntityNotFound))
+				types.On("FindAll", mock.Anything).Return([]models.ItemType{
+					{Model: gorm.Model{ID: 4}, Name: "Booster"},
+					{Model: gorm.Model{ID: 5}, Name: "Sleeve Booster"},
+				}, nil)
+				items.On("Create", mock.Anything, mock.MatchedBy(func(item *models.Item) bool {
+					return item.ExtensionID == 1 && item.LanguageID == 1 && item.TypeID == 5
+				})).Run(func(args mock.Arguments) {
+					args.Get(1).(*models.Item).ID = 7
+				}).Return(nil)
+				items.On("FindByID", mock.Anything, uint(7)).Return(&models.Item{Model: gorm.Model{ID: 7}, TypeID: 5}, nil)
+			},
+			validateItem: func(t *testing.T, item *models.Item) {
+				assert.Equal(t, uint(5), item.TypeID)
 			},
-			expectedError: "item type '' not found",
 		},
 	}
 
@@ -326,8 +357,15 @@ func TestItemService_CreateItem(t *testing.T) {
 			mockExts := mocks.NewMockExtensionRepository(t)
 			mockLangs := mocks.NewMockLanguageRepository(t)
 			mockTypes := mocks.NewMockItemTypeRepository(t)
+			mockOutbox := mocks.NewMockOutboxRepository(t)
 
-			tt.setupMocks(mockUoW, mockItems, mockExts, mockLangs, mockTypes)
+			if tt.setupMocks != nil {
+				tt.setupMocks(mockUoW, mockItems, mockExts, mockLangs, mockTypes)
+			}
+			mockUoW.On("Outbox").Return(mockOutbox).Maybe()
+			mockOutbox.On("Append", mock.Anything, mock.MatchedBy(func(event *models.OutboxEvent) bool {
+				return event.Type == events.ItemCreated
+			})).Return(nil).Maybe()
 
 			// Create service
 			service := NewItemService(mockUoW)
@@ -378,3 +416,449 @@ func TestItemService_CreateItem_WithTimeout(t *testing.T) {
 	assert.Contains(t, err.Error(), "context deadline exceeded")
 	assert.Nil(t, item)
 }
+
+// runInUoW makes the mocked UnitOfWork execute the callback it receives and
+// return the callback's error, like the real implementation.
+func runInUoW(uow *mocks.MockUnitOfWork) {
+	uow.On("Do", mock.Anything, mock.AnythingOfType("func(repository.UnitOfWork) error")).
+		Return(func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+			return fn(uow)
+		})
+}
+
+// readInUoW is runInUoW for the read-only transactions of DoRead.
+func readInUoW(uow *mocks.MockUnitOfWork) {
+	uow.On("DoRead", mock.Anything, mock.AnythingOfType("func(repository.UnitOfWork) error")).
+		Return(func(ctx context.Context, fn func(repository.UnitOfWork) error) error {
+			return fn(uow)
+		})
+}
+
+func TestItemService_CreateItem_ReportsEveryInvalidField(t *testing.T) {
+	// Setup
+	service := NewItemService(mocks.NewMockUnitOfWork(t))
+
+	// Execute
+	_, err := service.CreateItem(context.Background(), "", "fr", " ", testutil.FloatPtr(-5))
+
+	// Assert
+	require.ErrorIs(t, err, customErr.ErrValidationFailed)
+	var validationErr *customErr.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []customErr.FieldError{
+		{Field: "extension_code", Rule: customErr.RuleRequired, Message: "extension_code is required"},
+		{Field: "type", Rule: customErr.RuleRequired, Message: "type is required"},
+		{Field: "price", Rule: customErr.RuleMin, Message: "price must not be negative"},
+	}, validationErr.Fields)
+	assert.Equal(t, customErr.CodeValidationFailed, customErr.CodeOf(err))
+	assert.Equal(t, 1, strings.Count(err.Error(), "price must not be negative"), "the fields are listed once")
+}
+
+func TestItemService_CreateItem_MissingReference(t *testing.T) {
+	// Setup: the extension is removed between its lookup and the insert
+	mockUoW := mocks.NewMockUnitOfWork(t)
+	mockItems := mocks.NewMockItemRepository(t)
+	mockExts := mocks.NewMockExtensionRepository(t)
+	mockLangs := mocks.NewMockLanguageRepository(t)
+	mockTypes := mocks.NewMockItemTypeRepository(t)
+	runInUoW(mockUoW)
+	mockUoW.On("Extensions").Return(mockExts)
+	mockUoW.On("Languages").Return(mockLangs)
+	mockUoW.On("ItemTypes").Return(mockTypes)
+	mockUoW.On("Items").Return(mockItems)
+	mockExts.On("FindByCode", mock.Anything, "DRI").Return(&models.Extension{Model: gorm.Model{ID: 1}}, nil)
+	mockLangs.On("FindByCode", mock.Anything, "fr").Return(&models.Language{Model: gorm.Model{ID: 1}}, nil)
+	mockTypes.On("FindByName", mock.Anything, "Display").Return(&models.ItemType{Model: gorm.Model{ID: 1}}, nil)
+	violation := &customErr.ConstraintError{Kind: customErr.ConstraintForeignKey}
+	mockItems.On("Create", mock.Anything, mock.Anything).Return(customErr.NewRepositoryError("create", "item", "new", violation))
+
+	service := NewItemService(mockUoW)
+
+	// Execute
+	item, err := service.CreateItem(context.Background(), "DRI", "fr", "Display", nil)
+
+	// Assert
+	assert.Nil(t, item)
+	assert.ErrorIs(t, err, customErr.ErrForeignKeyViolation)
+	var svcErr *customErr.ServiceError
+	require.ErrorAs(t, err, &svcErr)
+	assert.Equal(t, missingReferenceMessage, svcErr.Message)
+}
+
+func TestItemService_GetItem(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockUoW := mocks.NewMockUnitOfWork(t)
+		mockItems := mocks.NewMockItemRepository(t)
+		readInUoW(mockUoW)
+		mockUoW.On("Items").Return(mockItems)
+		mockItems.On("FindByID", mock.Anything, uint(7)).Return(&models.Item{Model: gorm.Model{ID: 7}}, nil)
+
+		item, err := NewItemService(mockUoW).GetItem(context.Background(), 7)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(7), item.ID)
+	})
+
+	t.Run("error - not found", func(t *testing.T) {
+		mockUoW := mocks.NewMockUnitOfWork(t)
+		mockItems := mocks.NewMockItemRepository(t)
+		readInUoW(mockUoW)
+		mockUoW.On("Items").Return(mockItems)
+		mockItems.On("FindByID", mock.Anything, uint(7)).Return(nil, customErr.NewRepositoryError("find", "item", "7", customErr.ErrEntityNotFound))
+
+		item, err := NewItemService(mockUoW).GetItem(context.Background(), 7)
+
+		assert.Nil(t, item)
+		assert.ErrorIs(t, err, customErr.ErrEntityNotFound)
+		assert.Contains(t, err.Error(), "item 7 not found")
+	})
+}
+
+func TestItemService_ListItems(t *testing.T) {
+	mockUoW := mocks.NewMockUnitOfWork(t)
+	mockItems := mocks.NewMockItemRepository(t)
+	readInUoW(mockUoW)
+	mockUoW.On("Items").Return(mockItems)
+
+	filter := repository.ItemFilter{ExtensionCode: "DRI", Limit: 10}
+	mockItems.On("List", mock.Anything, filter).Return([]models.Item{{Model: gorm.Model{ID: 1}}, {Model: gorm.Model{ID: 2}}}, nil)
+
+	items, err := NewItemService(mockUoW).ListItems(context.Background(), filter)
+
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+}
+
+func TestItemService_UpdateItem(t *testing.T) {
+	existing := func() *models.Item {
+		return &models.Item{Model: gorm.Model{ID: 3}, ExtensionID: 1, TypeID: 1, LanguageID: 1, Price: testutil.FloatPtr(100)}
+	}
+
+	tests := []struct {
+		name          string
+		update        ItemUpdate
+		setupMocks    func(*mocks.MockItemRepository, *mocks.MockExtensionRepository, *mocks.MockLanguageRepository, *mocks.MockItemTypeRepository)
+		expectedError string
+	}{
+		{
+			name:   "success - change price and language",
+			update: ItemUpdate{LanguageCode: testutil.StringPtr("en"), Price: testutil.FloatPtr(120)},
+			setupMocks: func(items *mocks.MockItemRepository, exts *mocks.MockExtensionRepository, langs *mocks.MockLanguageRepository, types *mocks.MockItemTypeRepository) {
+				items.On("FindByID", mock.Anything, uint(3)).Return(existing(), nil)
+				langs.On("FindByCode", mock.Anything, "en").Return(&models.Language{Model: gorm.Model{ID: 2}, Code: "en"}, nil)
+				items.On("Update", mock.Anything, mock.MatchedBy(func(item *models.Item) bool {
+					return item.LanguageID == 2 && item.ExtensionID == 1 && item.Price != nil && *item.Price == 120
+				})).Return(nil)
+			},
+		},
+		{
+			name:   "success - clear price and change extension and type",
+			update: ItemUpdate{ExtensionCode: testutil.StringPtr("SVI"), TypeName: testutil.StringPtr("ETB"), ClearPrice: true},
+			setupMocks: func(items *mocks.MockItemRepository, exts *mocks.MockExtensionRepository, langs *mocks.MockLanguageRepository, types *mocks.MockItemTypeRepository) {
+				items.On("FindByID", mock.Anything, uint(3)).Return(existing(), nil)
+				exts.On("FindByCode", mock.Anything, "SVI").Return(&models.Extension{Model: gorm.Model{ID: 5}}, nil)
+				types.On("FindByName", mock.Anything, "ETB").Return(&models.ItemType{Model: gorm.Model{ID: 4}}, nil)
+				items.On("Update", mock.Anything, mock.MatchedBy(func(item *models.Item) bool {
+					return item.ExtensionID == 5 && item.TypeID == 4 && item.Price == nil
+				})).Return(nil)
+			},
+		},
+		{
+			name:   "error - item not found",
+			update: ItemUpdate{Price: testutil.FloatPtr(1)},
+			setupMocks: func(items *mocks.MockItemRepository, exts *mocks.MockExtensionRepository, langs *mocks.MockLanguageRepository, types *mocks.MockItemTypeRepository) {
+				items.On("FindByID", mock.Anything, uint(3)).Return(nil, customErr.NewRepositoryError("find", "item", "3", customErr.ErrEntityNotFound))
+			},
+			expectedError: "item 3 not found",
+		},
+		{
+			name:   "error - unknown language",
+			update: ItemUpdate{LanguageCode: testutil.StringPtr("xx")},
+			setupMocks: func(items *mocks.MockItemRepository, exts *mocks.MockExtensionRepository, langs *mocks.MockLanguageRepository, types *mocks.MockItemTypeRepository) {
+				items.On("FindByID", mock.Anything, uint(3)).Return(existing(), nil)
+				langs.On("FindByCode", mock.Anything, "xx").Return(nil, errors.New("record not found"))
+			},
+			expectedError: "language 'xx' not found",
+		},
+		{
+			name:   "error - update fails",
+			update: ItemUpdate{Price: testutil.FloatPtr(1)},
+			setupMocks: func(items *mocks.MockItemRepository, exts *mocks.MockExtensionRepository, langs *mocks.MockLanguageRepository, types *mocks.MockItemTypeRepository) {
+				items.On("FindByID", mock.Anything, uint(3)).Return(existing(), nil)
+				items.On("Update", mock.Anything, mock.Anything).Return(errors.New("database error"))
+			},
+			expectedError: "failed to update item",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUoW := mocks.NewMockUnitOfWork(t)
+			mockItems := mocks.NewMockItemRepository(t)
+			mockExts := mocks.NewMockExtensionRepository(t)
+			mockLangs := mocks.NewMockLanguageRepository(t)
+			mockTypes := mocks.NewMockItemTypeRepository(t)
+			mockOutbox := mocks.NewMockOutboxRepository(t)
+
+			runInUoW(mockUoW)
+			mockUoW.On("Items").Return(mockItems).Maybe()
+			mockUoW.On("Outbox").Return(mockOutbox).Maybe()
+			mockOutbox.On("Append", mock.Anything, mock.Anything).Return(nil).Maybe()
+			mockUoW.On("Extensions").Return(mockExts).Maybe()
+			mockUoW.On("Languages").Return(mockLangs).Maybe()
+			mockUoW.On("ItemTypes").Return(mockTypes).Maybe()
+			tt.setupMocks(mockItems, mockExts, mockLangs, mockTypes)
+
+			item, err := NewItemService(mockUoW).UpdateItem(context.Background(), 3, tt.update)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				assert.Nil(t, item)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, item)
+			}
+		})
+	}
+}
+
+func TestItemService_RejectsInvalidInput(t *testing.T) {
+	tests := []struct {
+		name     string
+		call     func(ItemService) error
+		expected []string
+	}{
+		{
+			name: "update with blank codes",
+			call: func(s ItemService) error {
+				_, err := s.UpdateItem(context.Background(), 1, ItemUpdate{ExtensionCode: testutil.StringPtr(" "), TypeName: testutil.StringPtr("")})
+				return err
+			},
+			expected: []string{"extension_code", "type"},
+		},
+		{
+			name: "update setting and clearing the price",
+			call: func(s ItemService) error {
+				_, err := s.UpdateItem(context.Background(), 1, ItemUpdate{Price: testutil.FloatPtr(5), ClearPrice: true})
+				return err
+			},
+			expected: []string{"price"},
+		},
+		{
+			name: "list with an inverted price range",
+			call: func(s ItemService) error {
+				_, err := s.ListItems(context.Background(), repository.ItemFilter{MinPrice: testutil.FloatPtr(50), MaxPrice: testutil.FloatPtr(10), Offset: -1})
+				return err
+			},
+			expected: []string{"max_price", "offset"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup: invalid input never reaches the unit of work.
+			service := NewItemService(mocks.NewMockUnitOfWork(t))
+
+			// Execute
+			err := tt.call(service)
+
+			// Assert
+			var validationErr *customErr.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			var fields []string
+			for _, field := range validationErr.Fields {
+				fields = append(fields, field.Field)
+			}
+			assert.Equal(t, tt.expected, fields)
+		})
+	}
+}
+
+func TestItemService_DeleteItem(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockUoW := mocks.NewMockUnitOfWork(t)
+		mockItems := mocks.NewMockItemRepository(t)
+		mockOutbox := mocks.NewMockOutboxRepository(t)
+		runInUoW(mockUoW)
+		mockUoW.On("Items").Return(mockItems)
+		mockUoW.On("Outbox").Return(mockOutbox)
+		mockItems.On("FindByID", mock.Anything, uint(4)).Return(&models.Item{Model: gorm.Model{ID: 4}}, nil)
+		mockItems.On("Delete", mock.Anything, uint(4)).Return(nil)
+		mockOutbox.On("Append", mock.Anything, mock.MatchedBy(func(event *models.OutboxEvent) bool {
+			return event.Type == events.ItemDeleted && event.EntityID == 4
+		})).Return(nil)
+
+		err := NewItemService(mockUoW).DeleteItem(context.Background(), 4)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("error - not found", func(t *testing.T) {
+		mockUoW := mocks.NewMockUnitOfWork(t)
+		mockItems := mocks.NewMockItemRepository(t)
+		runInUoW(mockUoW)
+		mockUoW.On("Items").Return(mockItems)
+		mockItems.On("FindByID", mock.Anything, uint(4)).Return(nil, customErr.NewRepositoryError("find", "item", "4", customErr.ErrEntityNotFound))
+
+		err := NewItemService(mockUoW).DeleteItem(context.Background(), 4)
+
+		assert.ErrorIs(t, err, customErr.ErrEntityNotFound)
+	})
+}
+
+func TestItemService_SellItem(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	uow := repository.NewUnitOfWork(db)
+	items := NewItemService(uow)
+	ctx := context.Background()
+
+	item, err := items.CreateItem(ctx, "DRI", "fr", "Display", testutil.FloatPtr(180))
+	require.NoError(t, err)
+	soldAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	// Execute
+	sold, err := items.SellItem(ctx, item.ID, testutil.FloatPtr(240), &soldAt)
+
+	// Assert: the sale is kept apart from the purchase price
+	require.NoError(t, err)
+	require.NotNil(t, sold.SoldAt)
+	assert.True(t, soldAt.Equal(*sold.SoldAt))
+	assert.Equal(t, 240.0, *sold.SoldPrice)
+	assert.Equal(t, 180.0, *sold.Price)
+
+	stored, err := uow.Outbox().Due(ctx, time.Now(), 0)
+	require.NoError(t, err)
+	require.Len(t, stored, 3)
+	assert.Equal(t, events.ItemUpdated, stored[1].Type)
+	assert.Equal(t, events.ItemSold, stored[2].Type)
+
+	var payload events.ItemSoldPayload
+	require.NoError(t, json.Unmarshal([]byte(stored[2].Payload), &payload))
+	assert.Equal(t, 240.0, *payload.Item.SoldPrice)
+	assert.Equal(t, "DRI", payload.Item.ExtensionCode)
+
+	// Execute & Assert: an item is sold once
+	_, err = items.SellItem(ctx, item.ID, nil, nil)
+	assert.ErrorIs(t, err, customErr.ErrConflict)
+
+	// Execute & Assert: the sale is undone like any change
+	_, err = NewAuditService(uow).UndoLast(ctx, 1)
+	require.NoError(t, err)
+	restored, err := items.GetItem(ctx, item.ID)
+	require.NoError(t, err)
+	assert.Nil(t, restored.SoldAt)
+	assert.Nil(t, restored.SoldPrice)
+
+	resold, err := items.SellItem(ctx, item.ID, nil, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, resold.SoldAt)
+	assert.Nil(t, resold.SoldPrice)
+}
+
+func TestItemService_SellItem_Invalid(t *testing.T) {
+	// Setup
+	service := NewItemService(mocks.NewMockUnitOfWork(t))
+
+	// Execute
+	item, err := service.SellItem(context.Background(), 4, testutil.FloatPtr(-1), nil)
+
+	// Assert
+	assert.Nil(t, item)
+	assert.ErrorIs(t, err, customErr.ErrValidationFailed)
+}
+
+func TestItemService_EmitsEvents(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	uow := repository.NewUnitOfWork(db)
+	svc := NewItemService(uow)
+	ctx := repository.WithActor(context.Background(), "cli:ash")
+
+	// Execute
+	item, err := svc.CreateItem(ctx, "DRI", "fr", "Display", testutil.FloatPtr(180))
+	require.NoError(t, err)
+	_, err = svc.UpdateItem(ctx, item.ID, ItemUpdate{Price: testutil.FloatPtr(210)})
+	require.NoError(t, err)
+	_, err = svc.UpdateItem(ctx, item.ID, ItemUpdate{Price: testutil.FloatPtr(210)})
+	require.NoError(t, err)
+	_, err = svc.UpdateItem(ctx, item.ID, ItemUpdate{LanguageCode: testutil.StringPtr("en")})
+	require.NoError(t, err)
+	require.NoError(t, svc.DeleteItem(ctx, item.ID))
+	_, err = svc.CreateItem(ctx, "NOPE", "fr", "Display", nil)
+	require.Error(t, err)
+
+	// Assert: no event for the unchanged update or the failed creation
+	stored, err := uow.Outbox().Due(context.Background(), time.Now(), 0)
+	require.NoError(t, err)
+
+	var types []string
+	for _, event := range stored {
+		types = append(types, event.Type)
+		assert.Equal(t, item.ID, event.EntityID)
+		assert.Equal(t, "cli:ash", event.Actor)
+		assert.NotEmpty(t, event.OperationID)
+	}
+	assert.Equal(t, []string{
+		events.ItemCreated,
+		events.ItemUpdated, events.ItemPriceChanged,
+		events.ItemUpdated,
+		events.ItemDeleted,
+	}, types)
+	assert.Equal(t, stored[1].OperationID, stored[2].OperationID, "events of one change share its operation")
+
+	var priceChanged events.ItemPriceChangedPayload
+	require.NoError(t, json.Unmarshal([]byte(stored[2].Payload), &priceChanged))
+	assert.Equal(t, 180.0, *priceChanged.OldPrice)
+	assert.Equal(t, 210.0, *priceChanged.NewPrice)
+	assert.Equal(t, "DRI", priceChanged.Item.ExtensionCode)
+
+	var updated events.ItemUpdatedPayload
+	require.NoError(t, json.Unmarshal([]byte(stored[3].Payload), &updated))
+	assert.Equal(t, []string{"language_code"}, updated.Changed)
+	assert.Equal(t, "fr", updated.Before.LanguageCode)
+	assert.Equal(t, "en", updated.After.LanguageCode)
+}
+
+func TestItemService_ComposedInTransaction(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	uow := repository.NewUnitOfWork(db)
+	ctx := context.Background()
+
+	// Execute: a trade gives away one item and receives another, then
+	// fails, so neither change is kept
+	given, err := NewItemService(uow).CreateItem(ctx, "DRI", "fr", "Display", nil)
+	require.NoError(t, err)
+
+	errTrade := errors.New("trade cancelled")
+	err = uow.Do(ctx, func(tx repository.UnitOfWork) error {
+		items := NewItemService(tx)
+		if err := items.DeleteItem(ctx, given.ID); err != nil {
+			return err
+		}
+		if _, err := items.CreateItem(ctx, "SVI", "en", "ETB", nil); err != nil {
+			return err
+		}
+		return errTrade
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, errTrade)
+
+	remaining, err := NewItemService(uow).ListItems(ctx, repository.ItemFilter{})
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, given.ID, remaining[0].ID)
+
+	stored, err := uow.Outbox().Due(ctx, time.Now(), 0)
+	require.NoError(t, err)
+	assert.Len(t, stored, 1, "only the event of the first creation is kept")
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/pricing"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+)
+
+type priceService struct {
+	uow       repository.UnitOfWork
+	providers *pricing.Registry
+}
+
+func NewPriceService(uow repository.UnitOfWork, providers *pricing.Registry) PriceService {
+	return &priceService{uow: uow, providers: providers}
+}
+
+func (s *priceService) Providers() []string {
+	return s.providers.Names()
+}
+
+// provider returns the named provider, or the whole registry when name is
+// empty.
+func (s *priceService) provider(name string) (pricing.PriceProvider, error) {
+	if len(s.providers.Names()) == 0 {
+		return nil, customErr.NewServiceError("refresh_prices", "price_service", "no price provider is configured", customErr.ErrValidationFailed)
+	}
+	if name == "" {
+		return registryProvider{s.providers}, nil
+	}
+	provider, err := s.providers.Get(name)
+	if err != nil {
+		return nil, customErr.NewServiceError("refresh_prices", "price_service", fmt.Sprintf("unknown price provider '%s'", name), customErr.ErrValidationFailed)
+	}
+	return provider, nil
+}
+
+// registryProvider asks every provider of a registry in turn.
+type registryProvider struct {
+	*pricing.Registry
+}
+
+func (registryProvider) Name() string { return "registry" }
+
+func (s *priceService) RefreshPrices(ctx context.Context, providerName string) ([]PriceRefresh, error) {
+	provider, err := s.provider(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []models.Item
+	err = s.uow.DoRead(ctx, func(uow repository.UnitOfWork) error {
+		listed, err := uow.Items().List(ctx, repository.ItemFilter{})
+		if err != nil {
+			return customErr.NewServiceError("refresh_prices", "price_service", "failed to list items", err)
+		}
+		// Sold items are no longer owned and have no current value.
+		for _, item := range listed {
+			if item.SoldAt == nil {
+				items = append(items, item)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Quotes are fetched before the transaction so that a slow provider
+	// does not hold the database, and once per product.
+	type result struct {
+		quote *pricing.Quote
+		err   error
+	}
+	cache := make(map[pricing.Query]result)
+	quotes := make([]*pricing.Quote, len(items))
+	refreshes := make([]PriceRefresh, len(items))
+	for i := range items {
+		item := &items[i]
+		query := pricing.Query{
+			ExtensionCode: item.Extension.Code,
+			ItemType:      item.Type.Name,
+			LanguageCode:  item.Language.Code,
+		}
+
+		r, ok := cache[query]
+		if !ok {
+			r.quote, r.err = provider.Quote(ctx, query)
+			if ctx.Err() != nil {
+				return nil, customErr.NewServiceError("refresh_prices", "price_service", "refresh interrupted", ctx.Err())
+			}
+			cache[query] = r
+		}
+
+		refreshes[i] = PriceRefresh{ItemID: item.ID}
+		switch {
+		case errors.Is(r.err, customErr.ErrNoQuote):
+			refreshes[i].Status = PriceMissing
+		case r.err != nil:
+			refreshes[i].Status = PriceFailed
+			refreshes[i].Error = r.err.Error()
+		default:
+			quotes[i] = r.quote
+		}
+	}
+
+	err = s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		for i, quote := range quotes {
+			if quote == nil {
+				continue
+			}
+			refresh := &refreshes[i]
+
+			item, err := uow.Items().FindByID(ctx, refresh.ItemID)
+			if errors.Is(err, customErr.ErrEntityNotFound) {
+				refresh.Status = PriceFailed
+				refresh.Error = "item deleted during the refresh"
+				continue
+			}
+			if err != nil {
+				return customErr.NewServiceError("refresh_prices", "price_service", fmt.Sprintf("failed to load item %d", refresh.ItemID), err)
+			}
+
+			// The quote is the current value of the item, kept in its price
+			// history; the item price stays the price paid.
+			previous, err := uow.PriceRecords().Latest(ctx, repository.PriceScope{ItemID: item.ID})
+			if err != nil && !errors.Is(err, customErr.ErrEntityNotFound) {
+				return customErr.NewServiceError("refresh_prices", "price_service", fmt.Sprintf("failed to load price history of item %d", item.ID), err)
+			}
+
+			price := math.Round(quote.Price*100) / 100
+			record := &models.PriceRecord{
+				ItemID:   item.ID,
+				Price:    price,
+				Currency: quote.Currency,
+				Source:   quote.Source,
+				QuotedAt: quote.QuotedAt,
+			}
+			if err := uow.PriceRecords().Create(ctx, record); err != nil {
+				return customErr.NewServiceError("refresh_prices", "price_service", fmt.Sprintf("failed to record price of item %d", item.ID), err)
+			}
+
+			refresh.NewPrice = &price
+			refresh.Currency = quote.Currency
+			refresh.Source = quote.Source
+			refresh.Status = PriceUpdated
+			if previous != nil {
+				refresh.OldPrice = &previous.Price
+				if previous.Price == price && previous.Currency == quote.Currency {
+					refresh.Status = PriceUnchanged
+				}
+			}
+		}
+		return evaluateAlerts(ctx, uow, "refresh_prices", "price_service")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return refreshes, nil
+}
+
+func (s *priceService) PriceHistory(ctx context.Context, itemID uint, limit int) ([]models.PriceRecord, error) {
+	var records []models.PriceRecord
+
+	err := s.uow.DoRead(ctx, func(uow repository.UnitOfWork) error {
+		// Deleted items keep their history.
+		if _, err := uow.Items().FindStored(ctx, itemID); err != nil {
+			return customErr.NewServiceError("price_history", "price_service", fmt.Sprintf("item %d not found", itemID), err)
+		}
+
+		var err error
+		records, err = uow.PriceRecords().ListByItem(ctx, itemID, limit)
+		if err != nil {
+			return customErr.NewServiceError("price_history", "price_service", "failed to list price history", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/pricing"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+	"github.com/R4yL-dev/pkmc/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// filePrices returns a registry quoting the prices of a CSV price file, or
+// no provider when prices is empty.
+func filePrices(t *testing.T, prices string) *pricing.Registry {
+	t.Helper()
+
+	registry := pricing.NewRegistry()
+	if prices != "" {
+		path := filepath.Join(t.TempDir(), "prices.csv")
+		require.NoError(t, os.WriteFile(path, []byte(prices), 0o644))
+		require.NoError(t, registry.Register(pricing.NewFileProvider(path)))
+	}
+	return registry
+}
+
+func TestPriceService_RefreshPrices(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	uow := repository.NewUnitOfWork(db)
+	items := NewItemService(uow)
+	prices := NewPriceService(uow, filePrices(t, "extension_code,type,language_code,price,currency\n"+
+		"DRI,Display,fr,199.999,EUR\n"+
+		"SVI,ETB,en,54.5,EUR\n"))
+	ctx := context.Background()
+
+	display, err := items.CreateItem(ctx, "DRI", "fr", "Display", testutil.FloatPtr(180))
+	require.NoError(t, err)
+	etb, err := items.CreateItem(ctx, "SVI", "en", "ETB", testutil.FloatPtr(54.5))
+	require.NoError(t, err)
+	unquoted, err := items.CreateItem(ctx, "DRI", "en", "Display", nil)
+	require.NoError(t, err)
+	sold, err := items.CreateItem(ctx, "DRI", "fr", "Display", testutil.FloatPtr(150))
+	require.NoError(t, err)
+	_, err = items.SellItem(ctx, sold.ID, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, uow.PriceRecords().Create(ctx, &models.PriceRecord{ItemID: etb.ID, Price: 54.5, Currency: "EUR", Source: "file", QuotedAt: time.Now().Add(-time.Hour)}))
+	pending, err := uow.Outbox().Due(ctx, time.Now(), 0)
+	require.NoError(t, err)
+
+	// Execute
+	refreshes, err := prices.RefreshPrices(ctx, "")
+
+	// Assert: sold items are not quoted
+	require.NoError(t, err)
+	require.Len(t, refreshes, 3)
+
+	assert.Equal(t, display.ID, refreshes[0].ItemID)
+	assert.Equal(t, PriceUpdated, refreshes[0].Status)
+	assert.Nil(t, refreshes[0].OldPrice, "first quote")
+	assert.Equal(t, 200.0, *refreshes[0].NewPrice, "quotes are rounded to cents")
+	assert.Equal(t, "EUR", refreshes[0].Currency)
+	assert.Equal(t, "file", refreshes[0].Source)
+
+	assert.Equal(t, etb.ID, refreshes[1].ItemID)
+	assert.Equal(t, PriceUnchanged, refreshes[1].Status)
+	assert.Equal(t, 54.5, *refreshes[1].OldPrice)
+
+	assert.Equal(t, unquoted.ID, refreshes[2].ItemID)
+	assert.Equal(t, PriceMissing, refreshes[2].Status)
+	assert.Nil(t, refreshes[2].NewPrice)
+
+	// The price paid is left alone
+	paid, err := items.GetItem(ctx, display.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 180.0, *paid.Price)
+
+	history, err := prices.PriceHistory(ctx, display.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, 200.0, history[0].Price)
+	assert.Equal(t, "EUR", history[0].Currency)
+	assert.Equal(t, "file", history[0].Source)
+
+	history, err = prices.PriceHistory(ctx, etb.ID, 0)
+	require.NoError(t, err)
+	assert.Len(t, history, 2, "unchanged quotes are recorded too")
+
+	history, err = prices.PriceHistory(ctx, unquoted.ID, 0)
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	history, err = prices.PriceHistory(ctx, sold.ID, 0)
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	// Items are not changed: no item event or audit entry
+	stored, err := uow.Outbox().Due(ctx, time.Now(), 0)
+	require.NoError(t, err)
+	assert.Len(t, stored, len(pending))
+
+	changes, err := uow.Audit().List(ctx, repository.AuditFilter{Entity: repository.AuditEntityItem, EntityID: display.ID})
+	require.NoError(t, err)
+	assert.Len(t, changes, 1)
+
+	quotes, err := uow.Audit().List(ctx, repository.AuditFilter{Entity: repository.AuditEntityPriceRecord})
+	require.NoError(t, err)
+	assert.Len(t, quotes, 3, "the recorded quotes are audited")
+}
+
+func TestPriceService_RefreshPrices_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		prices        string
+		provider      string
+		expectedError string
+	}{
+		{
+			name:          "no provider configured",
+			expectedError: "no price provider is configured",
+		},
+		{
+			name:          "unknown provider",
+			prices:        "extension_code,type,language_code,price\n",
+			provider:      "tcgplayer",
+			expectedError: "unknown price provider 'tcgplayer'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			db := testutil.SetupTestDB(t)
+			defer testutil.CleanupTestDB(t, db)
+
+			uow := repository.NewUnitOfWork(db)
+			prices := NewPriceService(uow, filePrices(t, tt.prices))
+
+			// Execute
+			_, err := prices.RefreshPrices(context.Background(), tt.provider)
+
+			// Assert
+			require.Error(t, err)
+			assert.ErrorIs(t, err, customErr.ErrValidationFailed)
+			assert.Contains(t, err.Error(), tt.expectedError)
+		})
+	}
+}
+
+func TestPriceService_RefreshPrices_ReportsProviderFailures(t *testing.T) {
+	// Setup: the price file is unreadable
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	uow := repository.NewUnitOfWork(db)
+	items := NewItemService(uow)
+	prices := NewPriceService(uow, filePrices(t, "extension_code,type,price\n"))
+	ctx := context.Background()
+	item, err := items.CreateItem(ctx, "DRI", "fr", "Display", testutil.FloatPtr(180))
+	require.NoError(t, err)
+
+	// Execute
+	refreshes, err := prices.RefreshPrices(ctx, "file")
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, refreshes, 1)
+	assert.Equal(t, PriceFailed, refreshes[0].Status)
+	assert.Contains(t, refreshes[0].Error, "missing column")
+
+	unchanged, err := items.GetItem(ctx, item.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 180.0, *unchanged.Price)
+}
+
+func TestPriceService_PriceHistory(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	uow := repository.NewUnitOfWork(db)
+	items := NewItemService(uow)
+	prices := NewPriceService(uow, filePrices(t, "extension_code,type,language_code,price\nDRI,Display,fr,200\n"))
+	ctx := context.Background()
+	item, err := items.CreateItem(ctx, "DRI", "fr", "Display", nil)
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		_, err := prices.RefreshPrices(ctx, "")
+		require.NoError(t, err)
+	}
+	require.NoError(t, items.DeleteItem(ctx, item.ID))
+
+	// Execute
+	history, err := prices.PriceHistory(ctx, item.ID, 2)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Len(t, history, 2, "deleted items keep their history")
+	assert.True(t, history[0].ID > history[1].ID, "newest first")
+
+	_, err = prices.PriceHistory(ctx, 999, 0)
+	assert.ErrorIs(t, err, customErr.ErrEntityNotFound)
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+)
+
+type statsService struct {
+	uow repository.UnitOfWork
+}
+
+func NewStatsService(uow repository.UnitOfWork) StatsService {
+	return &statsService{uow: uow}
+}
+
+func (s *statsService) CollectionStats(ctx context.Context) (*CollectionStats, error) {
+	stats := &CollectionStats{}
+
+	err := s.uow.DoRead(ctx, func(uow repository.UnitOfWork) error {
+		totals, err := uow.Items().Aggregate(ctx, repository.GroupByNone)
+		if err != nil {
+			return customErr.NewServiceError("collection_stats", "stats_service", "failed to compute totals", err)
+		}
+		if len(totals) > 0 {
+			stats.Totals = totals[0]
+		}
+
+		groups := []struct {
+			groupBy repository.ItemGroupBy
+			dest    *[]repository.ItemAggregate
+		}{
+			{repository.GroupByBlock, &stats.ByBlock},
+			{repository.GroupByExtension, &stats.ByExtension},
+			{repository.GroupByLanguage, &stats.ByLanguage},
+			{repository.GroupByType, &stats.ByType},
+		}
+
+		for _, group := range groups {
+			aggregates, err := uow.Items().Aggregate(ctx, group.groupBy)
+			if err != nil {
+				return customErr.NewServiceError("collection_stats", "stats_service", "failed to compute stats by "+string(group.groupBy), err)
+			}
+			*group.dest = aggregates
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/R4yL-dev/pkmc/internal/repository"
+	"github.com/R4yL-dev/pkmc/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStatsService_CollectionStats(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockUoW := mocks.NewMockUnitOfWork(t)
+		mockItems := mocks.NewMockItemRepository(t)
+		readInUoW(mockUoW)
+		mockUoW.On("Items").Return(mockItems)
+
+		mockItems.On("Aggregate", mock.Anything, repository.GroupByNone).Return([]repository.ItemAggregate{{Count: 3, PricedCount: 2, TotalPrice: 250}}, nil)
+		mockItems.On("Aggregate", mock.Anything, repository.GroupByBlock).Return([]repository.ItemAggregate{{Key: "EV", Count: 3}}, nil)
+		mockItems.On("Aggregate", mock.Anything, repository.GroupByExtension).Return([]repository.ItemAggregate{{Key: "DRI", Count: 2}, {Key: "SVI", Count: 1}}, nil)
+		mockItems.On("Aggregate", mock.Anything, repository.GroupByLanguage).Return([]repository.ItemAggregate{{Key: "fr", Count: 3}}, nil)
+		mockItems.On("Aggregate", mock.Anything, repository.GroupByType).Return([]repository.ItemAggregate{{Key: "Display", Count: 3}}, nil)
+
+		stats, err := NewStatsService(mockUoW).CollectionStats(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), stats.Totals.Count)
+		assert.InDelta(t, 250, stats.Totals.TotalPrice, 0.001)
+		assert.Len(t, stats.ByBlock, 1)
+		assert.Len(t, stats.ByExtension, 2)
+		assert.Len(t, stats.ByLanguage, 1)
+		assert.Len(t, stats.ByType, 1)
+	})
+
+	t.Run("error - aggregation fails", func(t *testing.T) {
+		mockUoW := mocks.NewMockUnitOfWork(t)
+		mockItems := mocks.NewMockItemRepository(t)
+		readInUoW(mockUoW)
+		mockUoW.On("Items").Return(mockItems)
+
+		mockItems.On("Aggregate", mock.Anything, repository.GroupByNone).Return(nil, errors.New("database error"))
+
+		stats, err := NewStatsService(mockUoW).CollectionStats(context.Background())
+
+		assert.Nil(t, stats)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to compute totals")
+	})
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+)
+
+const (
+	// TokenPrefix starts every API token so leaked tokens are easy to spot.
+	TokenPrefix = "pkmc_"
+
+	tokenBytes        = 32
+	tokenDisplayChars = 12
+	// lastUsedResolution limits how often authentication writes LastUsedAt.
+	lastUsedResolution = time.Minute
+)
+
+type tokenService struct {
+	uow repository.UnitOfWork
+}
+
+func NewTokenService(uow repository.UnitOfWork) TokenService {
+	return &tokenService{uow: uow}
+}
+
+// HashToken returns the stored form of a token secret.
+func HashToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func generateTokenSecret() (string, error) {
+	buf := make([]byte, tokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return TokenPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func (s *tokenService) IssueToken(ctx context.Context, name string, role models.Role) (*models.APIToken, string, error) {
+	name = strings.TrimSpace(name)
+	v := &validator{}
+	if v.check(name != "", "name", customErr.RuleRequired, "token name is required") {
+		v.maxLength("name", name, maxNameLength)
+	}
+	v.check(role.Valid(), "role", customErr.RuleOneOf, fmt.Sprintf("unknown role '%s'", role))
+	if err := v.err("issue_token", "token_service"); err != nil {
+		return nil, "", err
+	}
+
+	secret, err := generateTokenSecret()
+	if err != nil {
+		return nil, "", customErr.NewServiceError("issue_token", "token_service", "failed to generate token", err)
+	}
+
+	token := &models.APIToken{
+		Name:      name,
+		Prefix:    secret[:tokenDisplayChars],
+		TokenHash: HashToken(secret),
+		Role:      role,
+	}
+
+	err = s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		if err := uow.APITokens().Create(ctx, token); err != nil {
+			return customErr.NewServiceError("issue_token", "token_service", "failed to store token", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	return token, secret, nil
+}
+
+func (s *tokenService) ListTokens(ctx context.Context) ([]models.APIToken, error) {
+	var tokens []models.APIToken
+
+	err := s.uow.DoRead(ctx, func(uow repository.UnitOfWork) error {
+		var err error
+		tokens, err = uow.APITokens().FindAll(ctx)
+		if err != nil {
+			return customErr.NewServiceError("list_tokens", "token_service", "failed to list tokens", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (s *tokenService) RevokeToken(ctx context.Context, id uint) error {
+	return s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		if err := uow.APITokens().Revoke(ctx, id, time.Now()); err != nil {
+			if errors.Is(err, customErr.ErrEntityNotFound) {
+				return customErr.NewServiceError("revoke_token", "token_service", fmt.Sprintf("active token %d not found", id), err)
+			}
+			return customErr.NewServiceError("revoke_token", "token_service", fmt.Sprintf("failed to revoke token %d", id), err)
+		}
+		return nil
+	})
+}
+
+// Authenticate returns the active token matching secret. Unknown and
+// revoked tokens both fail with ErrInvalidToken.
+func (s *tokenService) Authenticate(ctx context.Context, secret string) (*models.APIToken, error) {
+	var token *models.APIToken
+
+	err := s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		var err error
+		token, err = uow.APITokens().FindByHash(ctx, HashToken(secret))
+		if err != nil {
+			if errors.Is(err, customErr.ErrEntityNotFound) {
+				return customErr.NewServiceError("authenticate", "token_service", "invalid or revoked token", customErr.ErrInvalidToken)
+			}
+			return customErr.NewServiceError("authenticate", "token_service", "failed to look up token", err)
+		}
+		if !token.Active() {
+			return customErr.NewServiceError("authenticate", "token_service", "invalid or revoked token", customErr.ErrInvalidToken)
+		}
+
+		now := time.Now()
+		if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedResolution {
+			if err := uow.APITokens().TouchLastUsed(ctx, token.ID, now); err != nil {
+				return customErr.NewServiceError("authenticate", "token_service", "failed to record token use", err)
+			}
+			token.LastUsedAt = &now
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenService_IssueToken(t *testing.T) {
+	t.Run("success - only the hash is stored", func(t *testing.T) {
+		mockUoW := mocks.NewMockUnitOfWork(t)
+		mockTokens := mocks.NewMockAPITokenRepository(t)
+		runInUoW(mockUoW)
+		mockUoW.On("APITokens").Return(mockTokens)
+
+		var stored *models.APIToken
+		mockTokens.On("Create", mock.Anything, mock.AnythingOfType("*models.APIToken")).
+			Run(func(args mock.Arguments) { stored = args.Get(1).(*models.APIToken) }).
+			Return(nil)
+
+		token, secret, err := NewTokenService(mockUoW).IssueToken(context.Background(), " phone ", models.RoleEditor)
+
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(secret, TokenPrefix))
+		assert.Equal(t, "phone", token.Name)
+		assert.Equal(t, models.RoleEditor, token.Role)
+		assert.Equal(t, HashToken(secret), stored.TokenHash)
+		assert.NotContains(t, stored.TokenHash, secret)
+		assert.True(t, strings.HasPrefix(secret, stored.Prefix))
+	})
+
+	tests := []struct {
+		name     string
+		tokName  string
+		role     models.Role
+		expected string
+	}{
+		{"error - empty name", "  ", models.RoleAdmin, "token name is required"},
+		{"error - unknown role", "phone", models.Role("owner"), "unknown role 'owner'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUoW := mocks.NewMockUnitOfWork(t)
+
+			token, secret, err := NewTokenService(mockUoW).IssueToken(context.Background(), tt.tokName, tt.role)
+
+			assert.Nil(t, token)
+			assert.Empty(t, secret)
+			assert.ErrorIs(t, err, customErr.ErrValidationFailed)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func TestTokenService_Authenticate(t *testing.T) {
+	revokedAt := time.Now()
+	recent := time.Now()
+
+	tests := []struct {
+		name      string
+		setupMock func(*mocks.MockAPITokenRepository)
+		errIs     error
+		wantErr   bool
+	}{
+		{
+			name: "success - records first use",
+			setupMock: func(m *mocks.MockAPITokenRepository) {
+				m.On("FindByHash", mock.Anything, HashToken("secret")).Return(&models.APIToken{Role: models.RoleReadOnly}, nil)
+				m.On("TouchLastUsed", mock.Anything, uint(0), mock.AnythingOfType("time.Time")).Return(nil)
+			},
+		},
+		{
+			name: "success - recent use is not written again",
+			setupMock: func(m *mocks.MockAPITokenRepository) {
+				m.On("FindByHash", mock.Anything, HashToken("secret")).Return(&models.APIToken{Role: models.RoleReadOnly, LastUsedAt: &recent}, nil)
+			},
+		},
+		{
+			name: "error - unknown token",
+			setupMock: func(m *mocks.MockAPITokenRepository) {
+				m.On("FindByHash", mock.Anything, HashToken("secret")).Return(nil, customErr.NewRepositoryError("find", "api_token", "hash", customErr.ErrEntityNotFound))
+			},
+			errIs:   customErr.ErrInvalidToken,
+			wantErr: true,
+		},
+		{
+			name: "error - revoked token",
+			setupMock: func(m *mocks.MockAPITokenRepository) {
+				m.On("FindByHash", mock.Anything, HashToken("secret")).Return(&models.APIToken{Role: models.RoleAdmin, RevokedAt: &revokedAt}, nil)
+			},
+			errIs:   customErr.ErrInvalidToken,
+			wantErr: true,
+		},
+		{
+			name: "error - database failure",
+			setupMock: func(m *mocks.MockAPITokenRepository) {
+				m.On("FindByHash", mock.Anything, HashToken("secret")).Return(nil, errors.New("database error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			mockUoW := mocks.NewMockUnitOfWork(t)
+			mockTokens := mocks.NewMockAPITokenRepository(t)
+			runInUoW(mockUoW)
+			mockUoW.On("APITokens").Return(mockTokens)
+			tt.setupMock(mockTokens)
+
+			// Execute
+			token, err := NewTokenService(mockUoW).Authenticate(context.Background(), "secret")
+
+			// Assert
+			if tt.wantErr {
+				assert.Nil(t, token)
+				assert.Error(t, err)
+				if tt.errIs != nil {
+					assert.ErrorIs(t, err, tt.errIs)
+				} else {
+					assert.NotErrorIs(t, err, customErr.ErrInvalidToken)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, token.LastUsedAt)
+		})
+	}
+}
+
+func TestTokenService_RevokeToken_NotFound(t *testing.T) {
+	mockUoW := mocks.NewMockUnitOfWork(t)
+	mockTokens := mocks.NewMockAPITokenRepository(t)
+	runInUoW(mockUoW)
+	mockUoW.On("APITokens").Return(mockTokens)
+	mockTokens.On("Revoke", mock.Anything, uint(7), mock.AnythingOfType("time.Time")).
+		Return(customErr.NewRepositoryError("revoke", "api_token", "7", customErr.ErrEntityNotFound))
+
+	err := NewTokenService(mockUoW).RevokeToken(context.Background(), 7)
+
+	assert.ErrorIs(t, err, customErr.ErrEntityNotFound)
+	assert.Contains(t, err.Error(), "active token 7 not found")
+}
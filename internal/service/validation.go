@@ -0,0 +1,111 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+)
+
+// Limits of the values accepted from service inputs.
+const (
+	maxCodeLength = 16
+	maxNameLength = 100
+	maxURLLength  = 2048
+	maxQuantity   = 10_000
+	// maxPrice is well below what the decimal(10,2) price columns hold,
+	// and above any price a sealed product reaches.
+	maxPrice = 1_000_000
+)
+
+// validator collects the invalid fields of a service input, so that they
+// are all reported at once:
+//
+//	v := &validator{}
+//	v.code("extension_code", extCode)
+//	v.price("price", price)
+//	if err := v.err("create_item", "item_service"); err != nil {
+//		return nil, err
+//	}
+type validator struct {
+	fields []customErr.FieldError
+}
+
+// add reports field as breaking rule.
+func (v *validator) add(field, rule, message string) {
+	v.fields = append(v.fields, customErr.FieldError{Field: field, Rule: rule, Message: message})
+}
+
+// check reports field as breaking rule unless ok, and returns ok.
+func (v *validator) check(ok bool, field, rule, message string) bool {
+	if !ok {
+		v.add(field, rule, message)
+	}
+	return ok
+}
+
+// required checks that value is not empty.
+func (v *validator) required(field, value string) bool {
+	return v.check(value != "", field, customErr.RuleRequired, fmt.Sprintf("%s is required", field))
+}
+
+// maxLength checks that value has at most max characters.
+func (v *validator) maxLength(field, value string, max int) bool {
+	return v.check(len([]rune(value)) <= max, field, customErr.RuleMaxLen, fmt.Sprintf("%s must be at most %d characters", field, max))
+}
+
+// code checks a required code of the catalog, such as an extension code.
+func (v *validator) code(field, value string) bool {
+	return v.required(field, value) && v.maxLength(field, value, maxCodeLength)
+}
+
+// name checks a required name.
+func (v *validator) name(field, value string) bool {
+	return v.required(field, value) && v.maxLength(field, value, maxNameLength)
+}
+
+// price checks that a price, when given, is a number between 0 and
+// maxPrice.
+func (v *validator) price(field string, value *float64) bool {
+	if value == nil {
+		return true
+	}
+	return v.check(!math.IsNaN(*value), field, customErr.RuleFormat, fmt.Sprintf("%s must be a number", field)) &&
+		v.check(*value >= 0, field, customErr.RuleMin, fmt.Sprintf("%s must not be negative", field)) &&
+		v.check(*value <= maxPrice, field, customErr.RuleMax, fmt.Sprintf("%s must be at most %d", field, maxPrice))
+}
+
+// quantity checks a number of copies.
+func (v *validator) quantity(field string, value int) bool {
+	return v.check(value >= 1, field, customErr.RuleMin, fmt.Sprintf("%s must be at least 1", field)) &&
+		v.check(value <= maxQuantity, field, customErr.RuleMax, fmt.Sprintf("%s must be at most %d", field, maxQuantity))
+}
+
+// err returns the ServiceError of op wrapping a ValidationError that lists
+// the invalid fields, or nil when there is none.
+func (v *validator) err(op, service string) error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return customErr.NewServiceError(op, service, "invalid input", &customErr.ValidationError{Fields: v.fields})
+}
+
+// normalizeCode returns an extension or block code as stored: trimmed and
+// in upper case.
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// normalizeLanguageCode returns a language code as stored: trimmed and in
+// lower case.
+func normalizeLanguageCode(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
+
+// normalizeName trims name and collapses its inner runs of spaces. Item
+// type names are then matched regardless of case, and the item type gives
+// the canonical one.
+func normalizeName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
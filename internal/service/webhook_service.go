@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/events"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+)
+
+const (
+	// WebhookSecretPrefix starts every generated webhook secret.
+	WebhookSecretPrefix = "whsec_"
+
+	webhookSecretBytes = 24
+)
+
+type webhookService struct {
+	uow repository.UnitOfWork
+}
+
+func NewWebhookService(uow repository.UnitOfWork) WebhookService {
+	return &webhookService{uow: uow}
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return WebhookSecretPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// validEventType reports whether t can be subscribed to.
+func validEventType(t string) bool {
+	if t == events.AllEvents {
+		return true
+	}
+	for _, known := range events.Types() {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *webhookService) CreateWebhook(ctx context.Context, input WebhookInput) (*models.Webhook, error) {
+	v := &validator{}
+
+	target, err := url.Parse(strings.TrimSpace(input.URL))
+	if v.check(err == nil && (target.Scheme == "http" || target.Scheme == "https") && target.Host != "",
+		"url", customErr.RuleFormat, fmt.Sprintf("invalid webhook URL '%s': expected an http or https URL", input.URL)) {
+		v.maxLength("url", target.String(), maxURLLength)
+	}
+
+	var types []string
+	given := 0
+	for _, t := range input.Events {
+		if t = strings.TrimSpace(t); t == "" {
+			continue
+		}
+		given++
+		if v.check(validEventType(t), "events", customErr.RuleOneOf, fmt.Sprintf("unknown event type '%s'", t)) {
+			types = append(types, t)
+		}
+	}
+	if given == 0 {
+		v.add("events", customErr.RuleRequired, "at least one event type is required")
+	}
+
+	if input.PriceThreshold != nil {
+		v.check(*input.PriceThreshold >= 0, "price_threshold", customErr.RuleMin, "price threshold must not be negative")
+	}
+	v.maxLength("name", strings.TrimSpace(input.Name), maxNameLength)
+	if err := v.err("create_webhook", "webhook_service"); err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		name = target.Host
+	}
+
+	secret := input.Secret
+	if secret == "" {
+		if secret, err = generateWebhookSecret(); err != nil {
+			return nil, customErr.NewServiceError("create_webhook", "webhook_service", "failed to generate secret", err)
+		}
+	}
+
+	webhook := &models.Webhook{
+		Name:           name,
+		URL:            target.String(),
+		Events:         strings.Join(types, ","),
+		Secret:         secret,
+		PriceThreshold: input.PriceThreshold,
+	}
+
+	err = s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		if err := uow.Webhooks().Create(ctx, webhook); err != nil {
+			return customErr.NewServiceError("create_webhook", "webhook_service", "failed to store webhook", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func (s *webhookService) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+
+	err := s.uow.DoRead(ctx, func(uow repository.UnitOfWork) error {
+		var err error
+		webhooks, err = uow.Webhooks().FindAll(ctx)
+		if err != nil {
+			return customErr.NewServiceError("list_webhooks", "webhook_service", "failed to list webhooks", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (s *webhookService) DeleteWebhook(ctx context.Context, id uint) error {
+	return s.uow.Do(ctx, func(uow repository.UnitOfWork) error {
+		if err := uow.Webhooks().Delete(ctx, id); err != nil {
+			if errors.Is(err, customErr.ErrEntityNotFound) {
+				return customErr.NewServiceError("delete_webhook", "webhook_service", fmt.Sprintf("webhook %d not found", id), err)
+			}
+			return customErr.NewServiceError("delete_webhook", "webhook_service", fmt.Sprintf("failed to delete webhook %d", id), err)
+		}
+		return nil
+	})
+}
+
+func (s *webhookService) ListDeliveries(ctx context.Context, filter repository.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	v := &validator{}
+	switch filter.Status {
+	case "", models.DeliveryPending, models.DeliverySucceeded, models.DeliveryFailed:
+	default:
+		v.add("status", customErr.RuleOneOf, fmt.Sprintf("unknown delivery status '%s'", filter.Status))
+	}
+	if err := v.err("list_deliveries", "webhook_service"); err != nil {
+		return nil, err
+	}
+
+	var deliveries []models.WebhookDelivery
+
+	err := s.uow.DoRead(ctx, func(uow repository.UnitOfWork) error {
+		if filter.WebhookID != 0 {
+			if _, err := uow.Webhooks().FindByID(ctx, filter.WebhookID); err != nil {
+				return customErr.NewServiceError("list_deliveries", "webhook_service", fmt.Sprintf("webhook %d not found", filter.WebhookID), err)
+			}
+		}
+
+		var err error
+		deliveries, err = uow.WebhookDeliveries().List(ctx, filter)
+		if err != nil {
+			return customErr.NewServiceError("list_deliveries", "webhook_service", "failed to list deliveries", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	customErr "github.com/R4yL-dev/pkmc/internal/errors"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+	"github.com/R4yL-dev/pkmc/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookService_CreateWebhook(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         WebhookInput
+		expectedError string
+		validate      func(*testing.T, *models.Webhook)
+	}{
+		{
+			name:  "success - defaults",
+			input: WebhookInput{URL: "http://192.168.1.20:8123/api/webhook/pkmc", Events: []string{"item.created", " item.price_changed "}},
+			validate: func(t *testing.T, webhook *models.Webhook) {
+				assert.Equal(t, "192.168.1.20:8123", webhook.Name)
+				assert.Equal(t, "item.created,item.price_changed", webhook.Events)
+				assert.True(t, strings.HasPrefix(webhook.Secret, WebhookSecretPrefix))
+			},
+		},
+		{
+			name:  "success - given secret and threshold",
+			input: WebhookInput{Name: "home", URL: "https://home.example/hook", Events: []string{"*"}, Secret: "s3cret", PriceThreshold: testutil.FloatPtr(200)},
+			validate: func(t *testing.T, webhook *models.Webhook) {
+				assert.Equal(t, "home", webhook.Name)
+				assert.Equal(t, "s3cret", webhook.Secret)
+				assert.True(t, webhook.Subscribes("item.deleted"))
+			},
+		},
+		{
+			name:          "error - not http",
+			input:         WebhookInput{URL: "ftp://home.example/hook", Events: []string{"item.created"}},
+			expectedError: "invalid webhook URL 'ftp://home.example/hook'",
+		},
+		{
+			name:          "error - relative URL",
+			input:         WebhookInput{URL: "/hook", Events: []string{"item.created"}},
+			expectedError: "invalid webhook URL '/hook'",
+		},
+		{
+			name:          "error - unknown event",
+			input:         WebhookInput{URL: "http://home/hook", Events: []string{"item.shipped"}},
+			expectedError: "unknown event type 'item.shipped'",
+		},
+		{
+			name:          "error - no event",
+			input:         WebhookInput{URL: "http://home/hook", Events: []string{" "}},
+			expectedError: "at least one event type is required",
+		},
+		{
+			name:          "error - negative threshold",
+			input:         WebhookInput{URL: "http://home/hook", Events: []string{"item.price_changed"}, PriceThreshold: testutil.FloatPtr(-1)},
+			expectedError: "price threshold must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			db := testutil.SetupTestDB(t)
+			defer testutil.CleanupTestDB(t, db)
+			svc := NewWebhookService(repository.NewUnitOfWork(db))
+
+			// Execute
+			webhook, err := svc.CreateWebhook(context.Background(), tt.input)
+
+			// Assert
+			if tt.expectedError != "" {
+				assert.ErrorIs(t, err, customErr.ErrValidationFailed)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				assert.Nil(t, webhook)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotZero(t, webhook.ID)
+			tt.validate(t, webhook)
+		})
+	}
+}
+
+func TestWebhookService_Lifecycle(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	svc := NewWebhookService(repository.NewUnitOfWork(db))
+	ctx := context.Background()
+
+	webhook, err := svc.CreateWebhook(ctx, WebhookInput{URL: "http://home/hook", Events: []string{"item.created"}, Secret: "s3cret"})
+	require.NoError(t, err)
+
+	// Execute & Assert
+	webhooks, err := svc.ListWebhooks(ctx)
+	require.NoError(t, err)
+	assert.Len(t, webhooks, 1)
+
+	_, err = svc.ListDeliveries(ctx, repository.WebhookDeliveryFilter{Status: "lost"})
+	assert.ErrorIs(t, err, customErr.ErrValidationFailed)
+
+	require.NoError(t, svc.DeleteWebhook(ctx, webhook.ID))
+	err = svc.DeleteWebhook(ctx, webhook.ID)
+	assert.ErrorIs(t, err, customErr.ErrEntityNotFound)
+	assert.Contains(t, err.Error(), "webhook 1 not found")
+
+	_, err = svc.ListDeliveries(ctx, repository.WebhookDeliveryFilter{WebhookID: webhook.ID})
+	assert.ErrorIs(t, err, customErr.ErrEntityNotFound)
+
+	// Creation and deletion are audited without the secret
+	entries, err := repository.NewAuditRepository(db).List(ctx, repository.AuditFilter{Entity: repository.AuditEntityWebhook})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "http://home/hook", entries[0].After["url"])
+	assert.NotContains(t, entries[0].After, "secret")
+	assert.Equal(t, models.AuditDelete, entries[1].Action)
+}
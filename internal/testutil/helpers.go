@@ -12,6 +12,10 @@ func FloatPtr(f float64) *float64 {
 	return &f
 }
 
+func StringPtr(s string) *string {
+	return &s
+}
+
 func DatePtr(year int, month int, day int) *time.Time {
 	releaseDate := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
 	return &releaseDate
@@ -0,0 +1,293 @@
+"use strict";
+
+// The interface only talks to the REST API; every request carries the token
+// saved in this browser.
+const API = "/api/v1";
+const TOKEN_KEY = "pkmc.token";
+
+const $ = (selector) => document.querySelector(selector);
+
+function showMessage(text, isError) {
+  const el = $("#message");
+  el.textContent = text;
+  el.className = isError ? "error" : "";
+  el.hidden = !text;
+}
+
+async function api(method, path, body) {
+  const headers = {};
+  const token = localStorage.getItem(TOKEN_KEY);
+  if (token) {
+    headers["Authorization"] = "Bearer " + token;
+  }
+  if (body !== undefined) {
+    headers["Content-Type"] = "application/json";
+  }
+
+  const resp = await fetch(API + path, {
+    method,
+    headers,
+    body: body === undefined ? undefined : JSON.stringify(body),
+  });
+  if (resp.status === 204) {
+    return null;
+  }
+
+  const data = await resp.json();
+  if (!resp.ok) {
+    let text = data.error ? data.error.message : resp.statusText;
+    if (resp.status === 401) {
+      text += ": enter an API token above";
+    }
+    throw new Error(text);
+  }
+  return data;
+}
+
+function formatPrice(price) {
+  return price === null || price === undefined ? "" : price.toFixed(2);
+}
+
+function cell(text, className) {
+  const td = document.createElement("td");
+  td.textContent = text;
+  if (className) {
+    td.className = className;
+  }
+  return td;
+}
+
+function button(label, onClick) {
+  const b = document.createElement("button");
+  b.type = "button";
+  b.textContent = label;
+  b.addEventListener("click", onClick);
+  return b;
+}
+
+// Reference data
+
+const sources = {
+  blocks: (b) => [b.code, `${b.code} - ${b.name}`],
+  extensions: (e) => [e.code, `${e.code} - ${e.name}`],
+  languages: (l) => [l.code, `${l.code} - ${l.name}`],
+  "item-types": (t) => [t.name, t.name],
+};
+
+function fillSelect(select, records, toOption) {
+  const first = select.options[0];
+  select.replaceChildren(first);
+  for (const record of records) {
+    const [value, label] = toOption(record);
+    select.append(new Option(label, value));
+  }
+}
+
+async function loadReferenceData() {
+  for (const [source, toOption] of Object.entries(sources)) {
+    const records = await api("GET", "/" + source);
+    for (const select of document.querySelectorAll(`select[data-source="${source}"]`)) {
+      fillSelect(select, records, toOption);
+    }
+  }
+}
+
+// Narrows the extension filter to the extensions of block, or lists them
+// all when block is empty.
+async function narrowExtensions(block) {
+  const query = block ? "?block=" + encodeURIComponent(block) : "";
+  const exts = await api("GET", "/extensions" + query);
+  fillSelect($("#filters [name=ext]"), exts, sources.extensions);
+}
+
+// Items
+
+function filterQuery() {
+  const params = new URLSearchParams();
+  for (const [name, value] of new FormData($("#filters"))) {
+    if (value !== "") {
+      params.set(name, value);
+    }
+  }
+  const query = params.toString();
+  return query ? "?" + query : "";
+}
+
+async function loadItems() {
+  const items = await api("GET", "/items" + filterQuery());
+  const rows = items.map((item) => {
+    const tr = document.createElement("tr");
+    const actions = cell("", "actions");
+    actions.append(button("Price", () => editPrice(item)), " ", button("Delete", () => deleteItem(item)));
+    tr.append(
+      cell(item.id),
+      cell(`${item.extension_code} - ${item.extension_name}`),
+      cell(item.block_code),
+      cell(item.type),
+      cell(item.language_name),
+      cell(formatPrice(item.price), "num"),
+      actions,
+    );
+    return tr;
+  });
+  $("#items").replaceChildren(...rows);
+  $("#items-empty").hidden = items.length > 0;
+}
+
+async function editPrice(item) {
+  const input = prompt(`New price for item ${item.id} (empty to remove it)`, formatPrice(item.price));
+  if (input === null) {
+    return;
+  }
+  const price = input.trim() === "" ? null : Number(input);
+  if (Number.isNaN(price)) {
+    showMessage(`invalid price '${input}'`, true);
+    return;
+  }
+  await run(async () => {
+    await api("PATCH", `/items/${item.id}`, { price });
+    await loadItems();
+    showMessage(`Item ${item.id} updated`);
+  });
+}
+
+async function deleteItem(item) {
+  if (!confirm(`Delete item ${item.id} (${item.extension_code} ${item.type})?`)) {
+    return;
+  }
+  await run(async () => {
+    await api("DELETE", `/items/${item.id}`);
+    await loadItems();
+    showMessage(`Item ${item.id} deleted`);
+  });
+}
+
+async function addItem(event) {
+  event.preventDefault();
+  const form = event.target;
+  const data = Object.fromEntries(new FormData(form));
+  const body = {
+    extension_code: data.extension_code,
+    language_code: data.language_code,
+    type: data.type,
+  };
+  if (data.price !== "") {
+    body.price = Number(data.price);
+  }
+
+  await run(async () => {
+    const item = await api("POST", "/items", body);
+    form.elements.price.value = "";
+    showMessage(`Added item ${item.id}: ${item.extension_name} ${item.type} (${item.language_name})`);
+  });
+}
+
+// Stats
+
+function statsTable(title, groups) {
+  const wrapper = document.createElement("div");
+  const h2 = document.createElement("h2");
+  h2.textContent = title;
+  const table = document.createElement("table");
+  const head = document.createElement("tr");
+  head.append(cell(""), cell("Items", "num"), cell("Priced", "num"), cell("Value", "num"));
+  table.append(head);
+  for (const g of groups) {
+    const tr = document.createElement("tr");
+    tr.append(
+      cell(g.label ? `${g.key} - ${g.label}` : g.key),
+      cell(g.items, "num"),
+      cell(g.priced_items, "num"),
+      cell(g.total_value.toFixed(2), "num"),
+    );
+    table.append(tr);
+  }
+  wrapper.append(h2, table);
+  return wrapper;
+}
+
+async function loadStats() {
+  const stats = await api("GET", "/stats");
+  const totals = [
+    ["Items", stats.totals.items],
+    ["Priced", stats.totals.priced_items],
+    ["Value", stats.totals.total_value.toFixed(2)],
+  ].map(([label, value]) => {
+    const div = document.createElement("div");
+    const strong = document.createElement("strong");
+    strong.textContent = value;
+    div.append(strong, label);
+    return div;
+  });
+  $("#totals").replaceChildren(...totals);
+  $("#groups").replaceChildren(
+    statsTable("By block", stats.by_block),
+    statsTable("By extension", stats.by_extension),
+    statsTable("By language", stats.by_language),
+    statsTable("By type", stats.by_type),
+  );
+}
+
+// Navigation
+
+const views = {
+  items: loadItems,
+  add: async () => {},
+  stats: loadStats,
+};
+
+async function run(fn) {
+  try {
+    await fn();
+  } catch (err) {
+    showMessage(err.message, true);
+  }
+}
+
+async function show(view) {
+  for (const name of Object.keys(views)) {
+    $("#view-" + name).hidden = name !== view;
+    document.querySelector(`nav [data-view="${name}"]`).classList.toggle("active", name === view);
+  }
+  showMessage("");
+  await run(views[view]);
+}
+
+function currentView() {
+  return document.querySelector("nav .active").dataset.view;
+}
+
+async function start() {
+  showMessage("");
+  await run(async () => {
+    await loadReferenceData();
+    await views[currentView()]();
+  });
+}
+
+document.addEventListener("DOMContentLoaded", () => {
+  $("#token").value = localStorage.getItem(TOKEN_KEY) || "";
+  $("#token-form").addEventListener("submit", (event) => {
+    event.preventDefault();
+    localStorage.setItem(TOKEN_KEY, $("#token").value.trim());
+    start();
+  });
+
+  for (const b of document.querySelectorAll("nav button")) {
+    b.addEventListener("click", () => show(b.dataset.view));
+  }
+
+  $("#filters").addEventListener("submit", (event) => {
+    event.preventDefault();
+    run(loadItems);
+  });
+  // Form fields are only cleared after the reset event.
+  $("#filters").addEventListener("reset", () => setTimeout(() => run(async () => {
+    await narrowExtensions("");
+    await loadItems();
+  })));
+  $("#filters [name=block]").addEventListener("change", (event) => run(() => narrowExtensions(event.target.value)));
+  $("#add-form").addEventListener("submit", addItem);
+
+  start();
+});
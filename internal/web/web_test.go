@@ -0,0 +1,42 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		status      int
+		contentType string
+		contains    string
+	}{
+		{"index", http.MethodGet, "/", http.StatusOK, "text/html; charset=utf-8", `<script src="app.js"`},
+		{"script", http.MethodGet, "/app.js", http.StatusOK, "text/javascript; charset=utf-8", `const API = "/api/v1"`},
+		{"stylesheet", http.MethodGet, "/style.css", http.StatusOK, "text/css; charset=utf-8", ":root"},
+		{"head", http.MethodHead, "/", http.StatusOK, "text/html; charset=utf-8", ""},
+		{"unknown file", http.MethodGet, "/missing.js", http.StatusNotFound, "", ""},
+		{"write method", http.MethodPost, "/", http.StatusMethodNotAllowed, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Execute
+			rec := httptest.NewRecorder()
+			Handler().ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			// Assert
+			assert.Equal(t, tt.status, rec.Code)
+			if tt.contentType != "" {
+				assert.Equal(t, tt.contentType, rec.Header().Get("Content-Type"))
+			}
+			assert.Contains(t, rec.Body.String(), tt.contains)
+		})
+	}
+}
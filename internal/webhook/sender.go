@@ -0,0 +1,215 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/R4yL-dev/pkmc/internal/events"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+)
+
+const (
+	defaultPollInterval   = 2 * time.Second
+	defaultBatchSize      = 50
+	defaultMaxAttempts    = 8
+	defaultBaseBackoff    = 10 * time.Second
+	defaultMaxBackoff     = time.Hour
+	defaultRequestTimeout = 10 * time.Second
+
+	// maxErrorBody limits how much of a failed response is kept in the
+	// delivery log.
+	maxErrorBody = 512
+)
+
+// SenderOption configures a Sender.
+type SenderOption func(*Sender)
+
+// WithHTTPClient sets the client used to post deliveries.
+func WithHTTPClient(client *http.Client) SenderOption {
+	return func(s *Sender) {
+		if client != nil {
+			s.client = client
+		}
+	}
+}
+
+// WithPollInterval sets how often Run looks for due deliveries.
+func WithPollInterval(interval time.Duration) SenderOption {
+	return func(s *Sender) {
+		if interval > 0 {
+			s.pollInterval = interval
+		}
+	}
+}
+
+// WithRetry sets how many times a delivery is attempted before it is
+// marked failed, and the delay before the first retry, doubled for each
+// following one up to max.
+func WithRetry(attempts int, base, max time.Duration) SenderOption {
+	return func(s *Sender) {
+		if attempts > 0 {
+			s.maxAttempts = attempts
+		}
+		if base > 0 {
+			s.baseBackoff = base
+		}
+		if max >= s.baseBackoff {
+			s.maxBackoff = max
+		}
+	}
+}
+
+// WithLogOutput sets where failed attempts are reported.
+func WithLogOutput(w io.Writer) SenderOption {
+	return func(s *Sender) {
+		s.logger = log.New(w, "webhooks: ", log.LstdFlags)
+	}
+}
+
+// Sender posts the pending webhook deliveries.
+type Sender struct {
+	uow          repository.UnitOfWork
+	client       *http.Client
+	pollInterval time.Duration
+	batchSize    int
+	maxAttempts  int
+	baseBackoff  time.Duration
+	maxBackoff   time.Duration
+	logger       *log.Logger
+
+	// sending serialises SendPending so a delivery is not posted twice
+	// concurrently.
+	sending sync.Mutex
+}
+
+func NewSender(uow repository.UnitOfWork, opts ...SenderOption) *Sender {
+	s := &Sender{
+		uow:          uow,
+		client:       &http.Client{Timeout: defaultRequestTimeout},
+		pollInterval: defaultPollInterval,
+		batchSize:    defaultBatchSize,
+		maxAttempts:  defaultMaxAttempts,
+		baseBackoff:  defaultBaseBackoff,
+		maxBackoff:   defaultMaxBackoff,
+		logger:       log.New(io.Discard, "", 0),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Run posts due deliveries every poll interval until ctx is cancelled.
+func (s *Sender) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			n, err := s.SendPending(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					s.logger.Printf("send: %v", err)
+				}
+				break
+			}
+			if n < s.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendPending makes one attempt of each due delivery, up to the batch
+// size, records its outcome and returns how many deliveries it attempted.
+func (s *Sender) SendPending(ctx context.Context) (int, error) {
+	s.sending.Lock()
+	defer s.sending.Unlock()
+
+	due, err := s.uow.WebhookDeliveries().Due(ctx, time.Now(), s.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range due {
+		delivery := &due[i]
+
+		status, sendErr := s.post(ctx, delivery)
+		if ctx.Err() != nil {
+			// Interrupted attempts are not counted.
+			return i, ctx.Err()
+		}
+
+		now := time.Now()
+		delivery.Attempts++
+		delivery.ResponseStatus = status
+		if sendErr == nil {
+			delivery.Status = models.DeliverySucceeded
+			delivery.LastError = ""
+			delivery.DeliveredAt = &now
+		} else {
+			delivery.LastError = sendErr.Error()
+			if delivery.Attempts >= s.maxAttempts {
+				delivery.Status = models.DeliveryFailed
+				s.logger.Printf("giving up delivery %d to %s after %d attempts: %v", delivery.ID, delivery.Webhook.Name, delivery.Attempts, sendErr)
+			} else {
+				delivery.NextAttemptAt = now.Add(events.Backoff(delivery.Attempts, s.baseBackoff, s.maxBackoff))
+				s.logger.Printf("delivery %d to %s, attempt %d: %v", delivery.ID, delivery.Webhook.Name, delivery.Attempts, sendErr)
+			}
+		}
+
+		if err := s.uow.WebhookDeliveries().UpdateAttempt(ctx, delivery); err != nil {
+			return i + 1, err
+		}
+	}
+	return len(due), nil
+}
+
+// post sends a delivery and returns the response status, 0 when no
+// response was received. Any status outside 2xx is an error.
+func (s *Sender) post(ctx context.Context, delivery *models.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "pkmc-webhook")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(delivery.Webhook.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		if msg := string(bytes.TrimSpace(snippet)); msg != "" {
+			return resp.StatusCode, fmt.Errorf("HTTP %d: %s", resp.StatusCode, msg)
+		}
+		return resp.StatusCode, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	// Drain the body so the connection can be reused.
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBody))
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,139 @@
+// Package webhook posts domain events to the URLs of webhook
+// subscriptions.
+//
+// Enqueuer subscribes to the event dispatcher and turns each event into
+// one pending delivery per matching webhook. Sender then posts pending
+// deliveries, retrying failed ones with exponential backoff; each delivery
+// is retried on its own, so a receiver that is down does not cause
+// duplicates for the others.
+//
+// Every request carries the JSON body of Payload and is signed with the
+// webhook secret: SignatureHeader holds "sha256=" followed by the hex
+// HMAC-SHA256 of the TimestampHeader value, a dot and the body. Receivers
+// check it with Verify.
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/R4yL-dev/pkmc/internal/events"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+)
+
+// Request headers.
+const (
+	SignatureHeader = "X-Pkmc-Signature"
+	TimestampHeader = "X-Pkmc-Timestamp"
+	EventHeader     = "X-Pkmc-Event"
+	DeliveryHeader  = "X-Pkmc-Delivery"
+)
+
+// Payload is the JSON body posted to webhooks. Data is the payload of the
+// event type, as documented in the events package.
+type Payload struct {
+	EventID     uint            `json:"event_id"`
+	Type        string          `json:"type"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	Actor       string          `json:"actor"`
+	OperationID string          `json:"operation_id"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// Sign returns the signature of body sent at timestamp (Unix seconds).
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the signature of body sent at
+// timestamp, the raw values of the request headers.
+func Verify(secret, timestamp string, body []byte, signature string) bool {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(secret, ts, body)), []byte(signature))
+}
+
+// Enqueuer returns the event handler that stores a pending delivery of
+// each event for every webhook subscribed to its type. Deliveries of one
+// event are stored together, and storing them again when the dispatcher
+// redelivers the event has no effect.
+func Enqueuer(uow repository.UnitOfWork) events.Handler {
+	return func(ctx context.Context, event events.Event) error {
+		body, err := json.Marshal(Payload{
+			EventID:     event.ID,
+			Type:        event.Type,
+			OccurredAt:  event.OccurredAt,
+			Actor:       event.Actor,
+			OperationID: event.OperationID,
+			Data:        event.Payload,
+		})
+		if err != nil {
+			return fmt.Errorf("encode webhook payload: %w", err)
+		}
+
+		return uow.Do(ctx, func(uow repository.UnitOfWork) error {
+			webhooks, err := uow.Webhooks().FindAll(ctx)
+			if err != nil {
+				return err
+			}
+			for i := range webhooks {
+				webhook := &webhooks[i]
+				if !webhook.Subscribes(event.Type) {
+					continue
+				}
+				matches, err := passesThreshold(webhook, event)
+				if err != nil {
+					return err
+				}
+				if !matches {
+					continue
+				}
+
+				err = uow.WebhookDeliveries().Enqueue(ctx, &models.WebhookDelivery{
+					WebhookID: webhook.ID,
+					EventID:   event.ID,
+					EventType: event.Type,
+					Payload:   string(body),
+				})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+}
+
+// passesThreshold applies the price threshold of a webhook: price changes
+// only go through when the price moves from below the threshold to at or
+// above it, or back. A missing price is below any threshold. Other events
+// are not filtered.
+func passesThreshold(webhook *models.Webhook, event events.Event) (bool, error) {
+	if webhook.PriceThreshold == nil || event.Type != events.ItemPriceChanged {
+		return true, nil
+	}
+
+	var change events.ItemPriceChangedPayload
+	if err := event.Decode(&change); err != nil {
+		return false, err
+	}
+
+	threshold := *webhook.PriceThreshold
+	reached := func(price *float64) bool {
+		return price != nil && *price >= threshold
+	}
+	return reached(change.OldPrice) != reached(change.NewPrice), nil
+}
@@ -0,0 +1,283 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/R4yL-dev/pkmc/internal/events"
+	"github.com/R4yL-dev/pkmc/internal/models"
+	"github.com/R4yL-dev/pkmc/internal/repository"
+	"github.com/R4yL-dev/pkmc/internal/service"
+	"github.com/R4yL-dev/pkmc/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"type":"item.created"}`)
+	signature := Sign("whsec_test", 1700000000, body)
+
+	assert.Regexp(t, "^sha256=[0-9a-f]{64}$", signature)
+	assert.True(t, Verify("whsec_test", "1700000000", body, signature))
+	assert.False(t, Verify("whsec_other", "1700000000", body, signature))
+	assert.False(t, Verify("whsec_test", "1700000001", body, signature))
+	assert.False(t, Verify("whsec_test", "1700000000", []byte(`{}`), signature))
+	assert.False(t, Verify("whsec_test", "soon", body, signature))
+}
+
+// receiver records the requests of a local webhook endpoint and answers
+// with the queued statuses, then 204.
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.requests = append(rc.requests, r)
+	rc.bodies = append(rc.bodies, body)
+
+	status := http.StatusNoContent
+	if len(rc.statuses) > 0 {
+		status, rc.statuses = rc.statuses[0], rc.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+type fixture struct {
+	uow        repository.UnitOfWork
+	items      service.ItemService
+	webhooks   service.WebhookService
+	dispatcher *events.Dispatcher
+	sender     *Sender
+	receiver   *receiver
+	url        string
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	db := testutil.SetupTestDB(t)
+	t.Cleanup(func() { testutil.CleanupTestDB(t, db) })
+
+	rc := &receiver{}
+	srv := httptest.NewServer(rc)
+	t.Cleanup(srv.Close)
+
+	uow := repository.NewUnitOfWork(db)
+	dispatcher := events.NewDispatcher(uow)
+	dispatcher.Subscribe(events.AllEvents, "webhooks", Enqueuer(uow))
+
+	return &fixture{
+		uow:        uow,
+		items:      service.NewItemService(uow),
+		webhooks:   service.NewWebhookService(uow),
+		dispatcher: dispatcher,
+		sender:     NewSender(uow, WithHTTPClient(srv.Client()), WithRetry(3, time.Millisecond, time.Millisecond)),
+		receiver:   rc,
+		url:        srv.URL + "/hooks/pkmc",
+	}
+}
+
+// deliver dispatches the pending events and makes one attempt of each due
+// delivery.
+func (f *fixture) deliver(t *testing.T) {
+	t.Helper()
+
+	_, err := f.dispatcher.DispatchPending(context.Background())
+	require.NoError(t, err)
+	_, err = f.sender.SendPending(context.Background())
+	require.NoError(t, err)
+}
+
+func TestWebhook_DeliversSignedEvents(t *testing.T) {
+	// Setup
+	f := newFixture(t)
+	ctx := context.Background()
+
+	hook, err := f.webhooks.CreateWebhook(ctx, service.WebhookInput{URL: f.url, Events: []string{events.ItemCreated}})
+	require.NoError(t, err)
+
+	item, err := f.items.CreateItem(ctx, "DRI", "fr", "Display", testutil.FloatPtr(180))
+	require.NoError(t, err)
+	_, err = f.items.UpdateItem(ctx, item.ID, service.ItemUpdate{Price: testutil.FloatPtr(210)})
+	require.NoError(t, err)
+
+	// Execute
+	f.deliver(t)
+
+	// Assert: only the subscribed event is posted
+	require.Len(t, f.receiver.requests, 1)
+	req, body := f.receiver.requests[0], f.receiver.bodies[0]
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, "/hooks/pkmc", req.URL.Path)
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	assert.Equal(t, events.ItemCreated, req.Header.Get(EventHeader))
+	assert.True(t, Verify(hook.Secret, req.Header.Get(TimestampHeader), body, req.Header.Get(SignatureHeader)))
+
+	var payload Payload
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, events.ItemCreated, payload.Type)
+	var created events.ItemCreatedPayload
+	require.NoError(t, json.Unmarshal(payload.Data, &created))
+	assert.Equal(t, "DRI", created.Item.ExtensionCode)
+	assert.Equal(t, 180.0, *created.Item.Price)
+
+	deliveries, err := f.webhooks.ListDeliveries(ctx, repository.WebhookDeliveryFilter{WebhookID: hook.ID})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, models.DeliverySucceeded, deliveries[0].Status)
+	assert.Equal(t, http.StatusNoContent, deliveries[0].ResponseStatus)
+	assert.Equal(t, strconv.Itoa(int(deliveries[0].ID)), req.Header.Get(DeliveryHeader))
+	assert.NotNil(t, deliveries[0].DeliveredAt)
+
+	// Nothing is sent twice
+	f.deliver(t)
+	assert.Len(t, f.receiver.requests, 1)
+}
+
+func TestWebhook_RetriesWithBackoff(t *testing.T) {
+	// Setup
+	f := newFixture(t)
+	ctx := context.Background()
+	f.receiver.statuses = []int{http.StatusInternalServerError, http.StatusBadGateway}
+
+	hook, err := f.webhooks.CreateWebhook(ctx, service.WebhookInput{URL: f.url, Events: []string{"*"}})
+	require.NoError(t, err)
+	_, err = f.items.CreateItem(ctx, "DRI", "fr", "Display", nil)
+	require.NoError(t, err)
+
+	// Execute & Assert: a failed attempt is logged and retried later
+	f.deliver(t)
+	deliveries, err := f.webhooks.ListDeliveries(ctx, repository.WebhookDeliveryFilter{WebhookID: hook.ID})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, models.DeliveryPending, deliveries[0].Status)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	assert.Equal(t, http.StatusInternalServerError, deliveries[0].ResponseStatus)
+	assert.Equal(t, "HTTP 500", deliveries[0].LastError)
+
+	for i := 0; i < 2; i++ {
+		time.Sleep(5 * time.Millisecond)
+		f.deliver(t)
+	}
+
+	deliveries, err = f.webhooks.ListDeliveries(ctx, repository.WebhookDeliveryFilter{WebhookID: hook.ID})
+	require.NoError(t, err)
+	assert.Equal(t, models.DeliverySucceeded, deliveries[0].Status)
+	assert.Equal(t, 3, deliveries[0].Attempts)
+	assert.Empty(t, deliveries[0].LastError)
+	require.Len(t, f.receiver.bodies, 3)
+	assert.Equal(t, f.receiver.bodies[0], f.receiver.bodies[2], "every attempt sends the same body")
+}
+
+func TestWebhook_GivesUp(t *testing.T) {
+	// Setup
+	f := newFixture(t)
+	ctx := context.Background()
+	f.receiver.statuses = []int{http.StatusGone, http.StatusGone, http.StatusGone}
+
+	_, err := f.webhooks.CreateWebhook(ctx, service.WebhookInput{URL: f.url, Events: []string{events.ItemCreated}})
+	require.NoError(t, err)
+	_, err = f.items.CreateItem(ctx, "DRI", "fr", "Display", nil)
+	require.NoError(t, err)
+
+	// Execute
+	for i := 0; i < 4; i++ {
+		f.deliver(t)
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Assert
+	failed, err := f.webhooks.ListDeliveries(ctx, repository.WebhookDeliveryFilter{Status: models.DeliveryFailed})
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.Equal(t, 3, failed[0].Attempts)
+	assert.Len(t, f.receiver.requests, 3)
+}
+
+func TestWebhook_PriceThreshold(t *testing.T) {
+	// Setup
+	f := newFixture(t)
+	ctx := context.Background()
+
+	_, err := f.webhooks.CreateWebhook(ctx, service.WebhookInput{
+		URL:            f.url,
+		Events:         []string{events.ItemPriceChanged},
+		PriceThreshold: testutil.FloatPtr(200),
+	})
+	require.NoError(t, err)
+
+	item, err := f.items.CreateItem(ctx, "DRI", "fr", "Display", nil)
+	require.NoError(t, err)
+
+	// Execute: no price -> 150 -> 210 (crosses) -> 250 -> 190 (crosses back)
+	for _, price := range []float64{150, 210, 250, 190} {
+		_, err = f.items.UpdateItem(ctx, item.ID, service.ItemUpdate{Price: testutil.FloatPtr(price)})
+		require.NoError(t, err)
+	}
+	f.deliver(t)
+
+	// Assert
+	var prices []float64
+	for _, body := range f.receiver.bodies {
+		var payload Payload
+		require.NoError(t, json.Unmarshal(body, &payload))
+		var change events.ItemPriceChangedPayload
+		require.NoError(t, json.Unmarshal(payload.Data, &change))
+		prices = append(prices, *change.NewPrice)
+	}
+	assert.Equal(t, []float64{210, 190}, prices)
+}
+
+func TestWebhook_DeletedWebhookIsNotCalled(t *testing.T) {
+	// Setup
+	f := newFixture(t)
+	ctx := context.Background()
+
+	hook, err := f.webhooks.CreateWebhook(ctx, service.WebhookInput{URL: f.url, Events: []string{events.ItemCreated}})
+	require.NoError(t, err)
+	_, err = f.items.CreateItem(ctx, "DRI", "fr", "Display", nil)
+	require.NoError(t, err)
+	_, err = f.dispatcher.DispatchPending(ctx)
+	require.NoError(t, err)
+
+	// Execute
+	require.NoError(t, f.webhooks.DeleteWebhook(ctx, hook.ID))
+	_, err = f.sender.SendPending(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Empty(t, f.receiver.requests)
+}
+
+func TestEnqueuer_IsIdempotent(t *testing.T) {
+	// Setup
+	f := newFixture(t)
+	ctx := context.Background()
+
+	_, err := f.webhooks.CreateWebhook(ctx, service.WebhookInput{URL: f.url, Events: []string{events.ItemDeleted}})
+	require.NoError(t, err)
+	event := events.Event{ID: 7, Type: events.ItemDeleted, Payload: json.RawMessage(`{"item":{"id":1}}`)}
+
+	// Execute: the dispatcher hands the same event over twice
+	require.NoError(t, Enqueuer(f.uow)(ctx, event))
+	require.NoError(t, Enqueuer(f.uow)(ctx, event))
+
+	// Assert
+	deliveries, err := f.webhooks.ListDeliveries(ctx, repository.WebhookDeliveryFilter{})
+	require.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+}